@@ -9,7 +9,9 @@ import (
 	"syscall"   // OSシグナル処理のためにインポート
 	"time"      // タイムアウト処理のためにインポート
 
+	"github.com/soranjiro/axicalendar/internal/adapter/features"
 	repo "github.com/soranjiro/axicalendar/internal/adapter/persistence/dynamodb"
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
 	"github.com/soranjiro/axicalendar/internal/presentation/api"
 	"github.com/soranjiro/axicalendar/internal/presentation/api/handler"
 	"github.com/soranjiro/axicalendar/internal/usecase"
@@ -18,6 +20,11 @@ import (
 	"github.com/labstack/echo/v4/middleware" // ミドルウェアパッケージをインポート
 )
 
+const (
+	featureCacheBackendEnvVar = "FEATURE_CACHE_BACKEND"
+	featureCacheTTL           = 24 * time.Hour // Bounds staleness if an invalidation is missed
+)
+
 func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -34,9 +41,37 @@ func main() {
 	// Use the specific dynamodb package for New...Repository functions
 	themeRepo := repo.NewThemeRepository(dbClient)
 	entryRepo := repo.NewEntryRepository(dbClient)
+	snapshotRepo := repo.NewSnapshotRepository(dbClient)
+
+	// Initialize Feature Registry
+	featureRegistry := feature.NewInMemoryExecutorRegistry()
+	if err := features.RegisterBuiltins(featureRegistry); err != nil {
+		log.Fatalf("Failed to register features: %v", err)
+	}
+
+	// Initialize Feature Result Cache
+	// FEATURE_CACHE_BACKEND selects "memory" (default) or "dynamodb" (shared across instances)
+	var resultCache feature.ResultCache
+	switch backend := os.Getenv(featureCacheBackendEnvVar); backend {
+	case "", "memory":
+		resultCache = feature.NewInMemoryResultCache(featureCacheTTL)
+	case "dynamodb":
+		resultCache = repo.NewFeatureResultCache(dbClient, featureCacheTTL)
+	default:
+		log.Fatalf("Unknown %s: %s", featureCacheBackendEnvVar, backend)
+	}
+	featureCache := feature.NewMeteredResultCache(resultCache)
 
 	// Initialize Use Case (using the consolidated constructor)
-	uc := usecase.NewUseCase(themeRepo, entryRepo)
+	uc := usecase.NewUseCase(themeRepo, entryRepo, featureRegistry, featureCache, snapshotRepo)
+
+	// Seed the default themes (idempotent; only missing or changed definitions are written)
+	// A failure leaves the stored default themes as they are, so the server still starts
+	if seeded, err := uc.SeedDefaultThemes(ctx); err != nil {
+		log.Printf("ERROR: Failed to seed default themes: %v", err)
+	} else if seeded > 0 {
+		log.Printf("Seeded %d default themes", seeded)
+	}
 
 	// Initialize Handlers
 	// Pass the single use case interface
@@ -80,5 +115,7 @@ func main() {
 	if err := e.Shutdown(shutdownCtx); err != nil {
 		e.Logger.Fatal(err)
 	}
+	stats := featureCache.Stats()
+	log.Printf("Feature cache: %d hits, %d misses (hit ratio %.2f)", stats.Hits, stats.Misses, stats.HitRatio())
 	log.Println("Server gracefully stopped")
 }
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	repo "github.com/soranjiro/axicalendar/internal/adapter/persistence/dynamodb"
+)
+
+// The backfill command writes the index items theme listing relies on for themes stored before
+// they were kept: the owner's USER#<user_id> / THEME#<theme_id> link item of each custom theme and
+// the GSI1 keys of each default theme and of each custom theme with schedules. It scans the table
+// once and can safely be run again.
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	dbClient, err := repo.NewDynamoDBClient(ctx)
+	if err != nil {
+		log.Fatalf("Failed to initialize DynamoDB client: %v", err)
+	}
+
+	result, err := repo.BackfillThemeIndexes(ctx, dbClient)
+	log.Printf("Scanned %d themes: created %d user-theme links, indexed %d default themes and %d scheduled themes", result.Scanned, result.LinksCreated, result.DefaultsIndexed, result.ScheduledIndexed)
+	if err != nil {
+		log.Fatalf("Backfill failed: %v", err)
+	}
+}
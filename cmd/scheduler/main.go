@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/soranjiro/axicalendar/internal/adapter/features"
+	repo "github.com/soranjiro/axicalendar/internal/adapter/persistence/dynamodb"
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+	"github.com/soranjiro/axicalendar/internal/usecase"
+)
+
+// The scheduler runs the features themes schedule and stores their results as snapshots.
+// By default it checks for due schedules every minute until it is stopped; with -once it
+// runs the schedules due since their last recorded run and exits (for use from an external cron).
+func main() {
+	once := flag.Bool("once", false, "run the schedules due since their last recorded run and exit")
+	interval := flag.Duration("interval", time.Minute, "how often to check for due schedules")
+	flag.Parse()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	// --- Dependency Injection ---
+	dbClient, err := repo.NewDynamoDBClient(ctx)
+	if err != nil {
+		log.Fatalf("Failed to initialize DynamoDB client: %v", err)
+	}
+	themeRepo := repo.NewThemeRepository(dbClient)
+	entryRepo := repo.NewEntryRepository(dbClient)
+	snapshotRepo := repo.NewSnapshotRepository(dbClient)
+
+	featureRegistry := feature.NewInMemoryExecutorRegistry()
+	if err := features.RegisterBuiltins(featureRegistry); err != nil {
+		log.Fatalf("Failed to register features: %v", err)
+	}
+
+	scheduler := usecase.NewFeatureScheduler(themeRepo, entryRepo, featureRegistry, snapshotRepo, time.Now)
+
+	// --- Run ---
+	runDue := func() {
+		stored, err := scheduler.RunDue(ctx)
+		if err != nil {
+			log.Printf("ERROR: Scheduler run failed: %v", err)
+			return
+		}
+		if stored > 0 {
+			log.Printf("Stored %d feature snapshots", stored)
+		}
+	}
+
+	if *once {
+		runDue()
+		return
+	}
+
+	log.Printf("Feature scheduler started (checking every %s)", *interval)
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Feature scheduler stopped")
+			return
+		case <-ticker.C:
+			runDue()
+		}
+	}
+}
@@ -0,0 +1,358 @@
+package budget
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+// FeatureName is the identifier used to register and reference this feature.
+const FeatureName = "budget"
+
+// Field roles that can be mapped to theme fields in the feature's theme.FeatureConfig.
+const (
+	AmountRole   = "amount"   // Number-like field spent or achieved (see feature.AmountTypes)
+	CategoryRole = "category" // Select or text field limits are set per
+)
+
+// Options accepted in the feature's theme.FeatureConfig.
+const (
+	ModeOption         = "mode"          // Whether the amount is a spending limit or a goal
+	TargetOption       = "target"        // Monthly target for the total amount
+	LimitsOption       = "limits"        // Monthly limit per category
+	WarningRatioOption = "warning_ratio" // Share of a limit that raises a warning
+)
+
+// Values of ModeOption.
+const (
+	ModeLimit = "limit" // Stay below the target (e.g. spending)
+	ModeGoal  = "goal"  // Reach the target (e.g. study minutes)
+)
+
+// DefaultWarningRatio is the warning ratio used when the option is not set.
+const DefaultWarningRatio = 0.8
+
+// Executor implements the budget feature.
+// It compares the month's total (or the total per category) with the configured targets,
+// projects the end-of-month value from the pace so far and reports a status per target.
+type Executor struct {
+	now func() time.Time
+}
+
+// NewExecutor creates a new budget Executor.
+func NewExecutor() *Executor {
+	return &Executor{now: time.Now}
+}
+
+// Describe returns the metadata of the budget feature.
+func (e *Executor) Describe() feature.Feature {
+	return feature.Feature{
+		Name:        FeatureName,
+		DisplayName: "Budget",
+		Description: "Compares the month's total, or the total per category, with monthly limits or a goal, projects the end-of-month value and reports ok, warning or exceeded.",
+		Fields: []feature.FieldRole{
+			{
+				Role:         AmountRole,
+				Description:  "Field spent or achieved: a number, rating, duration (in minutes) or money field (in one currency).",
+				Types:        feature.AmountTypes,
+				Required:     true,
+				DefaultField: "amount",
+			},
+			{
+				Role:         CategoryRole,
+				Description:  "Field the '" + LimitsOption + "' option is keyed by. Only needed for per-category limits.",
+				Types:        []theme.FieldType{theme.FieldTypeSelect, theme.FieldTypeText},
+				Required:     false,
+				DefaultField: "category",
+			},
+		},
+		Options: []feature.Option{
+			{
+				Name:        ModeOption,
+				Type:        feature.OptionTypeString,
+				Description: "'limit' to stay below the targets (spending) or 'goal' to reach them (achievement).",
+				Default:     ModeLimit,
+				Enum:        []string{ModeLimit, ModeGoal},
+			},
+			{
+				Name:        TargetOption,
+				Type:        feature.OptionTypeNumber,
+				Description: "Monthly target for the total amount.",
+			},
+			{
+				Name:        LimitsOption,
+				Type:        feature.OptionTypeNumberMap,
+				Description: "Monthly target per category, e.g. {\"food\": 30000}.",
+			},
+			{
+				Name:        WarningRatioOption,
+				Type:        feature.OptionTypeNumber,
+				Description: "In limit mode, the share of a limit at which a warning is raised.",
+				Default:     DefaultWarningRatio,
+			},
+			feature.CurrencyOptionSpec(),
+		},
+	}
+}
+
+// MonthScoped marks the executor as working on a single month (see feature.MonthScoped).
+func (e *Executor) MonthScoped() {}
+
+// DateDependent marks the budget feature as depending on today's date:
+// the projection of the current month changes every day.
+func (e *Executor) DateDependent() {}
+
+// progress tracks the amount against a single target.
+type progress struct {
+	name   string
+	actual float64
+	target float64
+}
+
+// Execute compares the month's amounts with the configured targets.
+// At least one of the target and limits options must be set.
+func (e *Executor) Execute(ctx context.Context, input feature.Input, config theme.FeatureConfig) (feature.AnalysisResult, error) {
+	// 1. Resolve the fields and targets
+	amountField := config.FieldOr(AmountRole, "amount")
+	amounts, err := feature.NewAmounts(input.Theme, amountField, config, input.Entries)
+	if err != nil {
+		return nil, err
+	}
+	mode := config.StringOption(ModeOption, ModeLimit)
+	if mode != ModeLimit && mode != ModeGoal {
+		return nil, fmt.Errorf("%w: unknown mode '%s'", feature.ErrInvalidConfig, mode)
+	}
+	warningRatio := config.NumberOption(WarningRatioOption, DefaultWarningRatio)
+	_, hasTarget := feature.NumberValue(config.Options[TargetOption])
+	target := config.NumberOption(TargetOption, 0)
+	limits := config.NumberMapOption(LimitsOption)
+	if !hasTarget && len(limits) == 0 {
+		return nil, fmt.Errorf("%w: set '%s' or '%s'", feature.ErrInvalidConfig, TargetOption, LimitsOption)
+	}
+	categoryField := ""
+	if len(limits) > 0 {
+		categoryField = config.FieldOr(CategoryRole, "category")
+		if err := feature.CheckField(input.Theme, categoryField, theme.FieldTypeSelect, theme.FieldTypeText); err != nil {
+			return nil, err
+		}
+	}
+
+	// 2. Total the amounts, overall and per limited category
+	total := 0.0
+	perCategory := make(map[string]float64, len(limits))
+	for _, en := range input.Entries {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		amount, ok := amounts.Value(en.Data)
+		if !ok {
+			continue
+		}
+		total += amount
+		if categoryField != "" {
+			if category, ok := feature.StringValue(en.Data[categoryField]); ok {
+				perCategory[category] += amount
+			}
+		}
+	}
+
+	// 3. Work out how much of the month has passed for the projection
+	days := len(input.Window.Days())
+	elapsed := elapsedDays(input.Window, e.now())
+
+	// 4. Evaluate each target; the overall status is the worst of them
+	status := feature.AlertStatusOK
+	var alerts []string
+	result := feature.AnalysisResult{
+		"month":         input.Window.YearMonth(),
+		"mode":          mode,
+		"amount_field":  amountField,
+		"amount_unit":   amounts.Unit(),
+		"days_in_month": days,
+		"elapsed_days":  elapsed,
+		"total":         nil,
+		"categories":    []map[string]interface{}{},
+	}
+	if hasTarget {
+		p := progress{name: "total", actual: total, target: target}
+		report := p.report(mode, warningRatio, elapsed, days)
+		result["total"] = report
+		status = worse(status, report["status"].(feature.AlertStatus))
+		if report["status"] != feature.AlertStatusOK {
+			alerts = append(alerts, p.describe(mode, report["status"].(feature.AlertStatus)))
+		}
+	}
+	if len(limits) > 0 {
+		result["category_field"] = categoryField
+		names := make([]string, 0, len(limits))
+		for name := range limits {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		categories := make([]map[string]interface{}, 0, len(names))
+		for _, name := range names {
+			p := progress{name: name, actual: perCategory[name], target: limits[name]}
+			report := p.report(mode, warningRatio, elapsed, days)
+			report["category"] = name
+			categories = append(categories, report)
+			status = worse(status, report["status"].(feature.AlertStatus))
+			if report["status"] != feature.AlertStatusOK {
+				alerts = append(alerts, p.describe(mode, report["status"].(feature.AlertStatus)))
+			}
+		}
+		result["categories"] = categories
+	}
+
+	// Top-level status and message are plain strings so cached results decode the same way
+	result["status"] = string(status)
+	result["message"] = message(alerts)
+	result[feature.TableKey] = table(result)
+	return result, nil
+}
+
+// TotalLabel names the row of the overall target in the tabular result.
+const TotalLabel = "(total)"
+
+// table tabulates the reports of the targets, the overall target first.
+func table(result feature.AnalysisResult) *feature.Table {
+	var records []map[string]interface{}
+	if total, ok := result["total"].(map[string]interface{}); ok {
+		records = append(records, tableRecord(TotalLabel, total))
+	}
+	for _, report := range result["categories"].([]map[string]interface{}) {
+		records = append(records, tableRecord(report["category"].(string), report))
+	}
+	return feature.NewTableFromRecords([]feature.Column{
+		{Name: "name", Type: feature.ColumnTypeString},
+		{Name: "actual", Type: feature.ColumnTypeNumber},
+		{Name: "target", Type: feature.ColumnTypeNumber},
+		{Name: "remaining", Type: feature.ColumnTypeNumber},
+		{Name: "projected", Type: feature.ColumnTypeNumber},
+		{Name: "ratio", Type: feature.ColumnTypeNumber},
+		{Name: "status", Type: feature.ColumnTypeString},
+	}, records).WithChart(feature.Chart{Mark: "bar", X: "name", Y: "actual", Color: "status"})
+}
+
+// tableRecord converts a report to a table record named name.
+func tableRecord(name string, report map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"name":      name,
+		"actual":    report["actual"],
+		"target":    report["target"],
+		"remaining": report["remaining"],
+		"projected": report["projected"],
+		"ratio":     report["ratio"],
+		"status":    string(report["status"].(feature.AlertStatus)),
+	}
+}
+
+// Alert summarises a result of Execute for an alert badge.
+func (e *Executor) Alert(result feature.AnalysisResult) feature.Alert {
+	status, _ := feature.StringValue(result["status"])
+	msg, _ := feature.StringValue(result["message"])
+	return feature.Alert{Feature: FeatureName, Status: feature.AlertStatus(status), Message: msg}
+}
+
+// report returns the progress against the target with its projection and status.
+func (p progress) report(mode string, warningRatio float64, elapsed, days int) map[string]interface{} {
+	projected := p.actual
+	if elapsed > 0 && elapsed < days {
+		projected = p.actual / float64(elapsed) * float64(days)
+	}
+	var ratio interface{}
+	if p.target != 0 {
+		ratio = p.actual / p.target
+	}
+
+	status := feature.AlertStatusOK
+	switch mode {
+	case ModeLimit:
+		switch {
+		case p.actual > p.target:
+			status = feature.AlertStatusExceeded
+		case p.actual >= p.target*warningRatio || projected > p.target:
+			status = feature.AlertStatusWarning
+		}
+	case ModeGoal:
+		// Reaching a goal "exceeds" it; falling behind the pace needed to reach it is a warning
+		switch {
+		case p.actual >= p.target:
+			status = feature.AlertStatusExceeded
+		case projected < p.target:
+			status = feature.AlertStatusWarning
+		}
+	}
+
+	return map[string]interface{}{
+		"actual":    p.actual,
+		"target":    p.target,
+		"remaining": p.target - p.actual,
+		"projected": projected,
+		"ratio":     ratio,
+		"status":    status,
+	}
+}
+
+// describe returns a one-line explanation of a status other than ok.
+func (p progress) describe(mode string, status feature.AlertStatus) string {
+	switch {
+	case mode == ModeGoal && status == feature.AlertStatusExceeded:
+		return fmt.Sprintf("%s: goal of %g reached", p.name, p.target)
+	case mode == ModeGoal:
+		return fmt.Sprintf("%s: %g of %g, behind pace", p.name, p.actual, p.target)
+	case status == feature.AlertStatusExceeded:
+		return fmt.Sprintf("%s: %g of %g, over the limit", p.name, p.actual, p.target)
+	default:
+		return fmt.Sprintf("%s: %g of %g, close to the limit", p.name, p.actual, p.target)
+	}
+}
+
+// message joins the explanations of the targets that are not ok.
+func message(alerts []string) string {
+	if len(alerts) == 0 {
+		return "All targets are on track"
+	}
+	msg := alerts[0]
+	for _, a := range alerts[1:] {
+		msg += "; " + a
+	}
+	return msg
+}
+
+// severity orders statuses from best to worst.
+var severity = map[feature.AlertStatus]int{
+	feature.AlertStatusOK:       0,
+	feature.AlertStatusWarning:  1,
+	feature.AlertStatusExceeded: 2,
+}
+
+// worse returns the more severe of two statuses.
+func worse(a, b feature.AlertStatus) feature.AlertStatus {
+	if severity[b] > severity[a] {
+		return b
+	}
+	return a
+}
+
+// elapsedDays returns the number of days of the window up to and including today.
+// Past months are fully elapsed and future months have not started.
+func elapsedDays(w feature.Window, now time.Time) int {
+	today := now.UTC().Format("2006-01-02")
+	elapsed := 0
+	for _, date := range w.Days() {
+		if date <= today {
+			elapsed++
+		}
+	}
+	return elapsed
+}
+
+// Compile-time checks to ensure Executor implements feature.Alerting and feature.DateDependent.
+var (
+	_ feature.Alerting      = (*Executor)(nil)
+	_ feature.DateDependent = (*Executor)(nil)
+)
@@ -0,0 +1,82 @@
+package budget
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/soranjiro/axicalendar/internal/domain/entry"
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+var householdTheme = theme.Theme{ThemeName: "Household", Fields: []theme.ThemeField{
+	{Name: "category", Label: "Category", Type: theme.FieldTypeSelect},
+	{Name: "amount", Label: "Amount", Type: theme.FieldTypeNumber},
+}}
+
+func newTestExecutor(today time.Time) *Executor {
+	return &Executor{now: func() time.Time { return today }}
+}
+
+func TestExecutor_Execute_CategoryLimits(t *testing.T) {
+	// 10 of 30 days elapsed
+	exec := newTestExecutor(time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC))
+	entries := []entry.Entry{
+		{EntryDate: "2025-06-01", Data: map[string]interface{}{"category": "food", "amount": 5000.0}},
+		{EntryDate: "2025-06-05", Data: map[string]interface{}{"category": "food", "amount": 4000.0}},
+		{EntryDate: "2025-06-08", Data: map[string]interface{}{"category": "rent", "amount": 80000.0}},
+		{EntryDate: "2025-06-09", Data: map[string]interface{}{"category": "hobby", "amount": 1000.0}},
+	}
+	config := theme.FeatureConfig{Options: map[string]interface{}{
+		LimitsOption: map[string]interface{}{"food": 30000.0, "rent": 80000.0, "hobby": 20000.0},
+	}}
+
+	result, err := exec.Execute(context.Background(), feature.Input{Theme: householdTheme, Window: feature.MonthWindow(exec.now()), Entries: entries}, config)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 10, result["elapsed_days"])
+	categories := result["categories"].([]map[string]interface{})
+	assert.Len(t, categories, 3)
+	// Sorted by category name
+	assert.Equal(t, "food", categories[0]["category"])
+	assert.Equal(t, 27000.0, categories[0]["projected"])
+	assert.Equal(t, feature.AlertStatusOK, categories[0]["status"])
+	assert.Equal(t, feature.AlertStatusOK, categories[1]["status"])
+	// rent is at its limit and projected far above it
+	assert.Equal(t, feature.AlertStatusWarning, categories[2]["status"])
+	assert.Nil(t, result["total"])
+
+	alert := exec.Alert(result)
+	assert.Equal(t, FeatureName, alert.Feature)
+	assert.Equal(t, feature.AlertStatusWarning, alert.Status)
+	assert.Contains(t, alert.Message, "rent")
+}
+
+func TestExecutor_Execute_TargetExceededAndGoal(t *testing.T) {
+	exec := newTestExecutor(time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC))
+	entries := []entry.Entry{
+		{EntryDate: "2025-06-01", Data: map[string]interface{}{"amount": 600}},
+		{EntryDate: "2025-06-20", Data: map[string]interface{}{"amount": 500}},
+	}
+	input := feature.Input{Theme: householdTheme, Window: feature.MonthWindow(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)), Entries: entries}
+
+	result, err := exec.Execute(context.Background(), input, theme.FeatureConfig{Options: map[string]interface{}{TargetOption: 1000.0}})
+	assert.NoError(t, err)
+	total := result["total"].(map[string]interface{})
+	// June is over, so the projection is the actual value
+	assert.Equal(t, 1100.0, total["projected"])
+	assert.Equal(t, "exceeded", result["status"])
+
+	result, err = exec.Execute(context.Background(), input, theme.FeatureConfig{Options: map[string]interface{}{ModeOption: ModeGoal, TargetOption: 2000.0}})
+	assert.NoError(t, err)
+	assert.Equal(t, "warning", result["status"])
+}
+
+func TestExecutor_Execute_NoTarget(t *testing.T) {
+	_, err := NewExecutor().Execute(context.Background(), feature.Input{Theme: householdTheme, Window: feature.MonthWindow(time.Now())}, theme.FeatureConfig{})
+
+	assert.ErrorIs(t, err, feature.ErrInvalidConfig)
+}
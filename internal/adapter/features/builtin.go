@@ -0,0 +1,36 @@
+// Package features wires the built-in feature executors into an executor registry.
+package features
+
+import (
+	"fmt"
+
+	"github.com/soranjiro/axicalendar/internal/adapter/features/budget"
+	categoryaggregation "github.com/soranjiro/axicalendar/internal/adapter/features/category_aggregation"
+	customformula "github.com/soranjiro/axicalendar/internal/adapter/features/custom_formula"
+	dailycorrelation "github.com/soranjiro/axicalendar/internal/adapter/features/daily_correlation"
+	monthlysummary "github.com/soranjiro/axicalendar/internal/adapter/features/monthly_summary"
+	"github.com/soranjiro/axicalendar/internal/adapter/features/streak"
+	"github.com/soranjiro/axicalendar/internal/adapter/features/trend"
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+	"github.com/soranjiro/axicalendar/internal/domain/formula"
+)
+
+// RegisterBuiltins registers every built-in feature executor under its FeatureName.
+// The API and the scheduler share it so both know the same features.
+func RegisterBuiltins(registry feature.ExecutorRegistry) error {
+	builtins := map[string]feature.FeatureExecutor{
+		monthlysummary.FeatureName:      monthlysummary.NewExecutor(),
+		categoryaggregation.FeatureName: categoryaggregation.NewExecutor(categoryaggregation.DefaultConfig()),
+		dailycorrelation.FeatureName:    dailycorrelation.NewExecutor(),
+		streak.FeatureName:              streak.NewExecutor(),
+		trend.FeatureName:               trend.NewExecutor(),
+		budget.FeatureName:              budget.NewExecutor(),
+		customformula.FeatureName:       customformula.NewExecutor(formula.DefaultLimits()),
+	}
+	for name, executor := range builtins {
+		if err := registry.RegisterExecutor(name, executor); err != nil {
+			return fmt.Errorf("failed to register feature %s: %w", name, err)
+		}
+	}
+	return nil
+}
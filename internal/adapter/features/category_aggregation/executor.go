@@ -0,0 +1,163 @@
+package categoryaggregation
+
+import (
+	"context"
+	"sort"
+
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+// FeatureName is the identifier used to register and reference this feature.
+const FeatureName = "category_aggregation"
+
+// Field roles that can be mapped to theme fields in the feature's theme.FeatureConfig.
+const (
+	CategoryRole = "category" // Select or text field entries are grouped by
+	AmountRole   = "amount"   // Number-like field totalled per category (see feature.AmountTypes)
+)
+
+// UncategorizedLabel is the category reported for entries without a category value.
+const UncategorizedLabel = "uncategorized"
+
+// Config selects the theme fields the aggregation works on when a theme does not map them itself.
+type Config struct {
+	CategoryField string // Name of the select field entries are grouped by
+	AmountField   string // Name of the number-like field totalled per category
+}
+
+// DefaultConfig returns the configuration used by expense-tracker style themes.
+func DefaultConfig() Config {
+	return Config{
+		CategoryField: "category",
+		AmountField:   "amount",
+	}
+}
+
+// Executor implements the category_aggregation feature.
+// It groups entries by a category field and totals a number-like field per category.
+type Executor struct {
+	config Config
+}
+
+// NewExecutor creates a new category_aggregation Executor with the given field configuration.
+func NewExecutor(config Config) *Executor {
+	return &Executor{config: config}
+}
+
+// Describe returns the metadata of the category_aggregation feature.
+// Role defaults come from the executor's Config.
+func (e *Executor) Describe() feature.Feature {
+	return feature.Feature{
+		Name:        FeatureName,
+		DisplayName: "Category Aggregation",
+		Description: "Totals an amount per category and reports each category's share of the overall total.",
+		Fields: []feature.FieldRole{
+			{
+				Role:         CategoryRole,
+				Description:  "Field entries are grouped by. Entries without a value are reported as '" + UncategorizedLabel + "'.",
+				Types:        []theme.FieldType{theme.FieldTypeSelect, theme.FieldTypeText},
+				Required:     true,
+				DefaultField: e.config.CategoryField,
+			},
+			{
+				Role:         AmountRole,
+				Description:  "Field totalled per category: a number, rating, duration (in minutes) or money field (in one currency).",
+				Types:        feature.AmountTypes,
+				Required:     true,
+				DefaultField: e.config.AmountField,
+			},
+		},
+		Options: []feature.Option{feature.CurrencyOptionSpec()},
+	}
+}
+
+// categoryTotal accumulates the total for a single category.
+type categoryTotal struct {
+	category   string
+	total      float64
+	entryCount int
+}
+
+// Execute groups the input entries by category and totals the amount field.
+// Field roles mapped in config take precedence over the executor's default Config.
+func (e *Executor) Execute(ctx context.Context, input feature.Input, config theme.FeatureConfig) (feature.AnalysisResult, error) {
+	// 1. Resolve the fields and check they exist on the theme with suitable types
+	categoryField := config.FieldOr(CategoryRole, e.config.CategoryField)
+	amountField := config.FieldOr(AmountRole, e.config.AmountField)
+	if err := feature.CheckField(input.Theme, categoryField, theme.FieldTypeSelect, theme.FieldTypeText); err != nil {
+		return nil, err
+	}
+	amounts, err := feature.NewAmounts(input.Theme, amountField, config, input.Entries)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Aggregate entries per category
+	totals := make(map[string]*categoryTotal)
+	grandTotal := 0.0
+	for _, en := range input.Entries {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		category, ok := feature.StringValue(en.Data[categoryField])
+		if !ok || category == "" {
+			category = UncategorizedLabel
+		}
+		ct, exists := totals[category]
+		if !exists {
+			ct = &categoryTotal{category: category}
+			totals[category] = ct
+		}
+		ct.entryCount++
+		if amount, ok := amounts.Value(en.Data); ok {
+			ct.total += amount
+			grandTotal += amount
+		}
+	}
+
+	// 3. Sort categories by total (descending), then by name for stable output
+	sorted := make([]*categoryTotal, 0, len(totals))
+	for _, ct := range totals {
+		sorted = append(sorted, ct)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].total != sorted[j].total {
+			return sorted[i].total > sorted[j].total
+		}
+		return sorted[i].category < sorted[j].category
+	})
+
+	// 4. Build the result
+	categories := make([]map[string]interface{}, 0, len(sorted))
+	for _, ct := range sorted {
+		percentage := 0.0
+		if grandTotal != 0 {
+			percentage = ct.total / grandTotal * 100
+		}
+		categories = append(categories, map[string]interface{}{
+			"category":    ct.category,
+			"total":       ct.total,
+			"percentage":  percentage,
+			"entry_count": ct.entryCount,
+		})
+	}
+
+	return feature.AnalysisResult{
+		"category_field": categoryField,
+		"amount_field":   amountField,
+		"amount_unit":    amounts.Unit(),
+		"total_amount":   grandTotal,
+		"total_entries":  len(input.Entries),
+		"categories":     categories,
+		feature.TableKey: feature.NewTableFromRecords([]feature.Column{
+			{Name: "category", Type: feature.ColumnTypeString},
+			{Name: "total", Type: feature.ColumnTypeNumber},
+			{Name: "percentage", Type: feature.ColumnTypeNumber},
+			{Name: "entry_count", Type: feature.ColumnTypeNumber},
+		}, categories).WithChart(feature.Chart{Mark: "bar", X: "category", Y: "total"}),
+	}, nil
+}
+
+// Compile-time check to ensure Executor implements feature.FeatureExecutor.
+var _ feature.FeatureExecutor = (*Executor)(nil)
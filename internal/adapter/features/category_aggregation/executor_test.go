@@ -0,0 +1,194 @@
+package categoryaggregation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/soranjiro/axicalendar/internal/domain/entry"
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+var expenseTheme = theme.Theme{ThemeName: "Expenses", Fields: []theme.ThemeField{
+	{Name: "category", Label: "Category", Type: theme.FieldTypeSelect},
+	{Name: "amount", Label: "Amount", Type: theme.FieldTypeNumber},
+	{Name: "price", Label: "Price", Type: theme.FieldTypeMoney},
+	{Name: "note", Label: "Note", Type: theme.FieldTypeText},
+	{Name: "paid", Label: "Paid", Type: theme.FieldTypeBoolean},
+}}
+
+// share computes a category's percentage the way Execute does, in float64 arithmetic.
+func share(total, grandTotal float64) float64 {
+	return total / grandTotal * 100
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	entries := []entry.Entry{
+		{EntryDate: "2025-05-01", Data: map[string]interface{}{"category": "food", "amount": 1200.0, "note": "lunch"}},
+		{EntryDate: "2025-05-02", Data: map[string]interface{}{"category": "rent", "amount": 80000.0}},
+		{EntryDate: "2025-05-03", Data: map[string]interface{}{"category": "food", "amount": 800.0, "note": "lunch"}},
+		{EntryDate: "2025-05-04", Data: map[string]interface{}{"amount": 2000.0}},
+		{EntryDate: "2025-05-05", Data: map[string]interface{}{"category": 42, "amount": 0.0}},
+	}
+
+	tests := []struct {
+		name           string
+		config         Config
+		featureConfig  theme.FeatureConfig
+		entries        []entry.Entry
+		wantTotal      float64
+		wantCategories []map[string]interface{}
+	}{
+		{
+			name:      "grouped by category, largest total first",
+			config:    DefaultConfig(),
+			entries:   entries,
+			wantTotal: 84000,
+			wantCategories: []map[string]interface{}{
+				{"category": "rent", "total": 80000.0, "percentage": share(80000, 84000), "entry_count": 1},
+				// Categories tied on total are ordered by name
+				{"category": "food", "total": 2000.0, "percentage": share(2000, 84000), "entry_count": 2},
+				{"category": "uncategorized", "total": 2000.0, "percentage": share(2000, 84000), "entry_count": 2},
+			},
+		},
+		{
+			name:      "grouped by a text field",
+			config:    Config{CategoryField: "note", AmountField: "amount"},
+			entries:   entries[:3],
+			wantTotal: 82000,
+			wantCategories: []map[string]interface{}{
+				{"category": "uncategorized", "total": 80000.0, "percentage": share(80000, 82000), "entry_count": 1},
+				{"category": "lunch", "total": 2000.0, "percentage": share(2000, 82000), "entry_count": 2},
+			},
+		},
+		{
+			name:          "category role mapped in the feature config",
+			config:        DefaultConfig(),
+			featureConfig: theme.FeatureConfig{Fields: map[string]string{CategoryRole: "note"}},
+			entries:       entries[:3],
+			wantTotal:     82000,
+			wantCategories: []map[string]interface{}{
+				{"category": "uncategorized", "total": 80000.0, "percentage": share(80000, 82000), "entry_count": 1},
+				{"category": "lunch", "total": 2000.0, "percentage": share(2000, 82000), "entry_count": 2},
+			},
+		},
+		{
+			name:           "no entries",
+			config:         DefaultConfig(),
+			wantTotal:      0,
+			wantCategories: []map[string]interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := feature.Input{Theme: expenseTheme, Window: feature.MonthWindow(time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)), Entries: tt.entries}
+
+			result, err := NewExecutor(tt.config).Execute(context.Background(), input, tt.featureConfig)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTotal, result["total_amount"])
+			assert.Equal(t, len(tt.entries), result["total_entries"])
+			assert.Equal(t, tt.wantCategories, result["categories"])
+		})
+	}
+}
+
+func TestExecutor_Execute_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        Config
+		featureConfig theme.FeatureConfig
+	}{
+		{"missing category field", Config{CategoryField: "missing", AmountField: "amount"}, theme.FeatureConfig{}},
+		{"category field of the wrong type", Config{CategoryField: "paid", AmountField: "amount"}, theme.FeatureConfig{}},
+		{"missing amount field", Config{CategoryField: "category", AmountField: "missing"}, theme.FeatureConfig{}},
+		{"amount field of the wrong type", Config{CategoryField: "category", AmountField: "note"}, theme.FeatureConfig{}},
+		{"amount role mapped to a missing field", DefaultConfig(), theme.FeatureConfig{Fields: map[string]string{AmountRole: "missing"}}},
+		{"invalid currency option", DefaultConfig(), theme.FeatureConfig{
+			Fields:  map[string]string{AmountRole: "price"},
+			Options: map[string]interface{}{feature.CurrencyOption: "dollars"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := feature.Input{Theme: expenseTheme, Window: feature.MonthWindow(time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC))}
+
+			_, err := NewExecutor(tt.config).Execute(context.Background(), input, tt.featureConfig)
+
+			assert.ErrorIs(t, err, feature.ErrInvalidConfig)
+		})
+	}
+}
+
+func TestExecutor_Execute_MoneyInSeveralCurrencies(t *testing.T) {
+	entries := []entry.Entry{
+		{EntryDate: "2025-05-01", Data: map[string]interface{}{"category": "food", "price": map[string]interface{}{"amount": 12.5, "currency": "USD"}}},
+		{EntryDate: "2025-05-02", Data: map[string]interface{}{"category": "food", "price": map[string]interface{}{"amount": 1200.0, "currency": "JPY"}}},
+	}
+	input := feature.Input{Theme: expenseTheme, Window: feature.MonthWindow(time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)), Entries: entries}
+	config := theme.FeatureConfig{Fields: map[string]string{AmountRole: "price"}}
+
+	// The amounts cannot be added up without choosing a currency
+	_, err := NewExecutor(DefaultConfig()).Execute(context.Background(), input, config)
+	assert.ErrorIs(t, err, feature.ErrInvalidConfig)
+
+	config.Options = map[string]interface{}{feature.CurrencyOption: "JPY"}
+	result, err := NewExecutor(DefaultConfig()).Execute(context.Background(), input, config)
+	assert.NoError(t, err)
+	assert.Equal(t, 1200.0, result["total_amount"])
+	assert.Equal(t, 2, result["total_entries"])
+}
+
+func TestExecutor_Describe(t *testing.T) {
+	meta := NewExecutor(Config{CategoryField: "kind", AmountField: "cost"}).Describe()
+
+	assert.Equal(t, FeatureName, meta.Name)
+	assert.Len(t, meta.Fields, 2)
+	assert.Equal(t, CategoryRole, meta.Fields[0].Role)
+	assert.Equal(t, "kind", meta.Fields[0].DefaultField)
+	assert.True(t, meta.Fields[0].Required)
+	assert.Equal(t, AmountRole, meta.Fields[1].Role)
+	assert.Equal(t, "cost", meta.Fields[1].DefaultField)
+	assert.Equal(t, feature.AmountTypes, meta.Fields[1].Types)
+	assert.Equal(t, []feature.Option{feature.CurrencyOptionSpec()}, meta.Options)
+}
+
+func TestExecutor_ValidateSupportedFeatures(t *testing.T) {
+	registry := feature.NewInMemoryExecutorRegistry()
+	assert.NoError(t, registry.RegisterExecutor(FeatureName, NewExecutor(DefaultConfig())))
+	withConfig := func(config theme.FeatureConfig) theme.Theme {
+		th := expenseTheme
+		th.SupportedFeatures = []theme.SupportedFeature{{Name: FeatureName, Config: config}}
+		return th
+	}
+
+	tests := []struct {
+		name    string
+		config  theme.FeatureConfig
+		wantErr bool
+	}{
+		{"default fields", theme.FeatureConfig{}, false},
+		{"category role mapped to a text field", theme.FeatureConfig{Fields: map[string]string{CategoryRole: "note"}}, false},
+		{"currency option", theme.FeatureConfig{Fields: map[string]string{AmountRole: "price"}, Options: map[string]interface{}{feature.CurrencyOption: "JPY"}}, false},
+		{"unknown option", theme.FeatureConfig{Options: map[string]interface{}{"limit": 10.0}}, true},
+		{"currency option that is not a string", theme.FeatureConfig{Options: map[string]interface{}{feature.CurrencyOption: 1.0}}, true},
+		{"unknown field role", theme.FeatureConfig{Fields: map[string]string{"label": "note"}}, true},
+		{"category role mapped to a boolean field", theme.FeatureConfig{Fields: map[string]string{CategoryRole: "paid"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := feature.ValidateSupportedFeatures(registry, withConfig(tt.config))
+			if tt.wantErr {
+				assert.ErrorIs(t, err, feature.ErrInvalidConfig)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package customformula
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+	"github.com/soranjiro/axicalendar/internal/domain/formula"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+// FeatureName is the identifier used to register and reference this feature.
+const FeatureName = "custom_formula"
+
+// FormulasOption is the option holding the theme's formulas by name.
+const FormulasOption = "formulas"
+
+// MaxFormulas is the maximum number of formulas a theme can define.
+const MaxFormulas = 10
+
+// Executor implements the custom formula feature.
+// It evaluates user-defined formulas (see package formula) over the entries of the window,
+// each bounded by the executor's limits.
+type Executor struct {
+	limits formula.Limits
+}
+
+// NewExecutor creates a new custom formula Executor that evaluates formulas within limits.
+func NewExecutor(limits formula.Limits) *Executor {
+	return &Executor{limits: limits}
+}
+
+// Describe returns the metadata of the custom formula feature.
+func (e *Executor) Describe() feature.Feature {
+	return feature.Feature{
+		Name:        FeatureName,
+		DisplayName: "Custom Formulas",
+		Description: "Evaluates user-defined formulas over the theme's fields, e.g. sum(amount where category == \"food\") / count(days) or avg(minutes) by subject.",
+		Options: []feature.Option{
+			{
+				Name:        FormulasOption,
+				Type:        feature.OptionTypeStringMap,
+				Description: fmt.Sprintf("Formulas by name, e.g. {\"food_per_day\": \"sum(amount where category == 'food') / count(days)\"}. At most %d.", MaxFormulas),
+			},
+		},
+	}
+}
+
+// ValidateConfig compiles the formulas against the theme's fields so mistakes are reported when the theme is saved.
+func (e *Executor) ValidateConfig(th theme.Theme, config theme.FeatureConfig) error {
+	_, err := e.compile(th, config)
+	return err
+}
+
+// namedProgram is a compiled formula and its name.
+type namedProgram struct {
+	name    string
+	program *formula.Program
+}
+
+// compile compiles the configured formulas in name order.
+func (e *Executor) compile(th theme.Theme, config theme.FeatureConfig) ([]namedProgram, error) {
+	formulas := config.StringMapOption(FormulasOption)
+	if len(formulas) == 0 {
+		return nil, fmt.Errorf("%w: option '%s' must define at least one formula", feature.ErrInvalidConfig, FormulasOption)
+	}
+	if len(formulas) > MaxFormulas {
+		return nil, fmt.Errorf("%w: option '%s' defines %d formulas, at most %d are allowed", feature.ErrInvalidConfig, FormulasOption, len(formulas), MaxFormulas)
+	}
+
+	names := make([]string, 0, len(formulas))
+	for name := range formulas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	schema := formula.SchemaFromFields(th.Fields)
+	programs := make([]namedProgram, 0, len(names))
+	for _, name := range names {
+		program, err := formula.Compile(formulas[name], schema, e.limits)
+		if err != nil {
+			return nil, fmt.Errorf("%w: formula '%s': %v", feature.ErrInvalidConfig, name, err)
+		}
+		programs = append(programs, namedProgram{name: name, program: program})
+	}
+	return programs, nil
+}
+
+// Execute evaluates each formula over the window's entries.
+// Grouped formulas report a value per group instead of a single value.
+func (e *Executor) Execute(ctx context.Context, input feature.Input, config theme.FeatureConfig) (feature.AnalysisResult, error) {
+	// 1. Compile the formulas against the theme's current fields
+	programs, err := e.compile(input.Theme, config)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Convert the entries to rows, adding the entry date unless a field shadows it
+	rows := make([]formula.Row, len(input.Entries))
+	for i, en := range input.Entries {
+		rows[i] = formula.RowFromData(input.Theme.Fields, en.Data, en.EntryDate)
+	}
+	days := input.Window.Days()
+
+	// 3. Evaluate each formula
+	results := make([]map[string]interface{}, 0, len(programs))
+	for _, p := range programs {
+		res, err := p.program.Evaluate(ctx, rows, days)
+		if err != nil {
+			if errors.Is(err, formula.ErrLimitExceeded) {
+				return nil, fmt.Errorf("%w: formula '%s': %v", feature.ErrResourceLimit, p.name, err)
+			}
+			return nil, err
+		}
+		item := map[string]interface{}{
+			"name":    p.name,
+			"formula": p.program.Source(),
+			"type":    string(p.program.Type()),
+		}
+		if groupBy := p.program.GroupBy(); groupBy != "" {
+			groups := make([]map[string]interface{}, 0, len(res.Groups))
+			for _, g := range res.Groups {
+				groups = append(groups, map[string]interface{}{"group": g.Key, "value": g.Value})
+			}
+			item["group_by"] = groupBy
+			item["groups"] = groups
+		} else {
+			item["value"] = res.Value
+		}
+		results = append(results, item)
+	}
+
+	return feature.AnalysisResult{
+		"start_date":     input.Window.Start.Format("2006-01-02"),
+		"end_date":       input.Window.End.Format("2006-01-02"),
+		"formulas":       results,
+		feature.TableKey: table(results),
+	}, nil
+}
+
+// table tabulates the formula results with one row per formula, or per group of grouped formulas.
+// The value column is numeric unless a formula has a string or boolean value.
+func table(results []map[string]interface{}) *feature.Table {
+	valueType := feature.ColumnTypeNumber
+	grouped := false
+	for _, item := range results {
+		if item["type"] != string(formula.TypeNumber) {
+			valueType = feature.ColumnTypeString
+		}
+		if _, ok := item["groups"]; ok {
+			grouped = true
+		}
+	}
+	value := func(v interface{}) interface{} {
+		if v == nil || valueType == feature.ColumnTypeNumber {
+			return v
+		}
+		return fmt.Sprint(v)
+	}
+
+	var records []map[string]interface{}
+	for _, item := range results {
+		groups, ok := item["groups"].([]map[string]interface{})
+		if !ok {
+			records = append(records, map[string]interface{}{"formula": item["name"], "value": value(item["value"])})
+			continue
+		}
+		for _, g := range groups {
+			records = append(records, map[string]interface{}{"formula": item["name"], "group": g["group"], "value": value(g["value"])})
+		}
+	}
+
+	chart := feature.Chart{Mark: "bar", X: "formula", Y: "value"}
+	if grouped {
+		chart = feature.Chart{Mark: "bar", X: "group", Y: "value", Color: "formula"}
+	}
+	return feature.NewTableFromRecords([]feature.Column{
+		{Name: "formula", Type: feature.ColumnTypeString},
+		{Name: "group", Type: feature.ColumnTypeString},
+		{Name: "value", Type: valueType},
+	}, records).WithChart(chart)
+}
+
+// Compile-time check to ensure Executor implements feature.ConfigValidator.
+var _ feature.ConfigValidator = (*Executor)(nil)
@@ -0,0 +1,96 @@
+package customformula
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/soranjiro/axicalendar/internal/domain/entry"
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+	"github.com/soranjiro/axicalendar/internal/domain/formula"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+var householdTheme = theme.Theme{ThemeName: "Household", Fields: []theme.ThemeField{
+	{Name: "category", Label: "Category", Type: theme.FieldTypeSelect},
+	{Name: "amount", Label: "Amount", Type: theme.FieldTypeNumber},
+}}
+
+func TestExecutor_Execute(t *testing.T) {
+	window, _ := feature.NewWindow(time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 5, 10, 0, 0, 0, 0, time.UTC))
+	entries := []entry.Entry{
+		{EntryDate: "2025-05-01", Data: map[string]interface{}{"category": "food", "amount": 1500.0}},
+		{EntryDate: "2025-05-03", Data: map[string]interface{}{"category": "food", "amount": 2500.0}},
+		{EntryDate: "2025-05-03", Data: map[string]interface{}{"category": "rent", "amount": 60000.0}},
+	}
+	config := theme.FeatureConfig{Options: map[string]interface{}{FormulasOption: map[string]interface{}{
+		"food_per_day":      `sum(amount where category == "food") / count(days)`,
+		"spent_by_category": `sum(amount) by category`,
+	}}}
+
+	result, err := NewExecutor(formula.DefaultLimits()).Execute(context.Background(), feature.Input{Theme: householdTheme, Window: window, Entries: entries}, config)
+
+	assert.NoError(t, err)
+	formulas := result["formulas"].([]map[string]interface{})
+	assert.Len(t, formulas, 2)
+	assert.Equal(t, "food_per_day", formulas[0]["name"])
+	assert.Equal(t, "number", formulas[0]["type"])
+	assert.Equal(t, 400.0, formulas[0]["value"])
+	assert.Equal(t, "category", formulas[1]["group_by"])
+	assert.Equal(t, []map[string]interface{}{
+		{"group": "food", "value": 4000.0},
+		{"group": "rent", "value": 60000.0},
+	}, formulas[1]["groups"])
+}
+
+func TestExecutor_Execute_MoneyAndDuration(t *testing.T) {
+	th := theme.Theme{ThemeName: "Travel", Fields: []theme.ThemeField{
+		{Name: "price", Label: "Price", Type: theme.FieldTypeMoney},
+		{Name: "time_spent", Label: "Time spent", Type: theme.FieldTypeDuration},
+	}}
+	entries := []entry.Entry{
+		{EntryDate: "2025-05-01", Data: map[string]interface{}{"price": map[string]interface{}{"amount": 1200.0, "currency": "JPY"}, "time_spent": "PT1H30M"}},
+		{EntryDate: "2025-05-02", Data: map[string]interface{}{"price": map[string]interface{}{"amount": 15.5, "currency": "EUR"}, "time_spent": "PT30M"}},
+		{EntryDate: "2025-05-02", Data: map[string]interface{}{"price": map[string]interface{}{"amount": 800.0, "currency": "JPY"}}},
+	}
+	config := theme.FeatureConfig{Options: map[string]interface{}{FormulasOption: map[string]interface{}{
+		"minutes":   "sum(time_spent)",
+		"spent_jpy": `sum(price where price_currency == "JPY")`,
+	}}}
+
+	result, err := NewExecutor(formula.DefaultLimits()).Execute(context.Background(), feature.Input{Theme: th, Window: feature.MonthWindow(time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)), Entries: entries}, config)
+
+	assert.NoError(t, err)
+	formulas := result["formulas"].([]map[string]interface{})
+	assert.Equal(t, 120.0, formulas[0]["value"])
+	assert.Equal(t, 2000.0, formulas[1]["value"])
+}
+
+func TestExecutor_ValidateConfig(t *testing.T) {
+	exec := NewExecutor(formula.DefaultLimits())
+	withFormulas := func(formulas map[string]interface{}) theme.FeatureConfig {
+		return theme.FeatureConfig{Options: map[string]interface{}{FormulasOption: formulas}}
+	}
+
+	assert.NoError(t, exec.ValidateConfig(householdTheme, withFormulas(map[string]interface{}{"total": "sum(amount)"})))
+	assert.ErrorIs(t, exec.ValidateConfig(householdTheme, theme.FeatureConfig{}), feature.ErrInvalidConfig)
+	assert.ErrorIs(t, exec.ValidateConfig(householdTheme, withFormulas(map[string]interface{}{"total": "sum(category)"})), feature.ErrInvalidConfig)
+	assert.ErrorIs(t, exec.ValidateConfig(householdTheme, withFormulas(map[string]interface{}{"total": "sum(price)"})), feature.ErrInvalidConfig)
+}
+
+func TestExecutor_Execute_ResourceLimit(t *testing.T) {
+	limits := formula.DefaultLimits()
+	limits.MaxSteps = 100
+	entries := make([]entry.Entry, 100)
+	for i := range entries {
+		entries[i] = entry.Entry{EntryDate: "2025-05-01", Data: map[string]interface{}{"category": strings.Repeat("x", i%3), "amount": 1.0}}
+	}
+	config := theme.FeatureConfig{Options: map[string]interface{}{FormulasOption: map[string]interface{}{"total": "sum(amount)"}}}
+
+	_, err := NewExecutor(limits).Execute(context.Background(), feature.Input{Theme: householdTheme, Window: feature.MonthWindow(time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)), Entries: entries}, config)
+
+	assert.ErrorIs(t, err, feature.ErrResourceLimit)
+}
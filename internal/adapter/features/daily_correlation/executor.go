@@ -0,0 +1,289 @@
+package dailycorrelation
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+// FeatureName is the identifier used to register and reference this feature.
+const FeatureName = "daily_correlation"
+
+// ValueRole is the field role whose per-day value is correlated across themes.
+// Number-like fields (see feature.AmountTypes) are summed per day and boolean fields count completed entries;
+// when the role is not mapped, the number of entries per day is used.
+const ValueRole = "value"
+
+// MissingAsZeroOption controls whether days without entries count as 0 or are skipped.
+const MissingAsZeroOption = "missing_as_zero"
+
+// Metrics reported per theme, depending on the value field.
+const (
+	MetricCount     = "count"     // Entries per day
+	MetricSum       = "sum"       // Sum of a number-like field per day
+	MetricCompleted = "completed" // Entries per day with a boolean field set to true
+)
+
+// Executor implements the daily_correlation feature.
+// It builds a per-day series for each theme and correlates every pair of themes.
+type Executor struct{}
+
+// NewExecutor creates a new daily_correlation Executor.
+func NewExecutor() *Executor {
+	return &Executor{}
+}
+
+// Describe returns the metadata of the daily_correlation feature.
+func (e *Executor) Describe() feature.Feature {
+	return feature.Feature{
+		Name:        FeatureName,
+		DisplayName: "Daily Correlation",
+		Description: "Correlates per-day values of several themes, e.g. habit completion vs. spending per day.",
+		Fields: []feature.FieldRole{
+			{
+				Role:        ValueRole,
+				Description: "Field whose per-day value is compared. Number, rating, duration (in minutes) and money (in one currency) fields are summed and boolean fields count completed entries; without a mapping the number of entries per day is used.",
+				Types:       append(append([]theme.FieldType{}, feature.AmountTypes...), theme.FieldTypeBoolean),
+			},
+		},
+		Options: []feature.Option{
+			{
+				Name:        MissingAsZeroOption,
+				Type:        feature.OptionTypeBoolean,
+				Description: "Count days without entries as 0. When false, days without entries in a theme are left out of that theme's comparisons.",
+				Default:     true,
+			},
+			feature.CurrencyOptionSpec(),
+		},
+	}
+}
+
+// Execute is not supported for a single theme; the feature needs at least two themes to compare.
+func (e *Executor) Execute(ctx context.Context, input feature.Input, config theme.FeatureConfig) (feature.AnalysisResult, error) {
+	return nil, fmt.Errorf("%w: feature '%s' compares several themes and must be run through the multi-theme endpoint", feature.ErrInvalidConfig, FeatureName)
+}
+
+// series is the per-day value of one theme.
+type series struct {
+	field         string
+	metric        string
+	amounts       feature.Amounts    // Reads the field's values for MetricSum
+	values        map[string]float64 // Date -> value, only days with entries
+	missingAsZero bool
+}
+
+// value returns the series value on a date and whether it takes part in comparisons.
+func (s series) value(date string) (float64, bool) {
+	v, ok := s.values[date]
+	if !ok && s.missingAsZero {
+		return 0, true
+	}
+	return v, ok
+}
+
+// entryValue returns the contribution of one entry to its day's value.
+func (s series) entryValue(data map[string]interface{}) float64 {
+	switch s.metric {
+	case MetricSum:
+		v, _ := s.amounts.Value(data)
+		return v
+	case MetricCompleted:
+		if v, ok := feature.BoolValue(data[s.field]); ok && v {
+			return 1
+		}
+		return 0
+	default:
+		return 1
+	}
+}
+
+// ExecuteMulti builds the per-day series of every theme and correlates each pair.
+func (e *Executor) ExecuteMulti(ctx context.Context, input feature.MultiThemeInput) (feature.AnalysisResult, error) {
+	if len(input.Themes) < 2 {
+		return nil, fmt.Errorf("%w: feature '%s' needs at least two themes", feature.ErrInvalidConfig, FeatureName)
+	}
+
+	// 1. Build one series per theme
+	all := make([]series, len(input.Themes))
+	themes := make([]map[string]interface{}, len(input.Themes))
+	for i, ti := range input.Themes {
+		s, err := buildSeries(ctx, ti)
+		if err != nil {
+			return nil, err
+		}
+		all[i] = s
+		themes[i] = map[string]interface{}{
+			"theme_id":   ti.Theme.ThemeID,
+			"theme_name": ti.Theme.ThemeName,
+			"field":      s.field,
+			"metric":     s.metric,
+			"unit":       s.amounts.Unit(),
+		}
+	}
+
+	// 2. Build the per-day table (nil marks a value left out of comparisons)
+	dates := input.Window.Days()
+	days := make([]map[string]interface{}, 0, len(dates))
+	for _, date := range dates {
+		values := make([]interface{}, len(all))
+		for i, s := range all {
+			if v, ok := s.value(date); ok {
+				values[i] = v
+			}
+		}
+		days = append(days, map[string]interface{}{"date": date, "values": values})
+	}
+
+	// 3. Tabulate the series in long form (one row per day and theme) for exports
+	long := make([]map[string]interface{}, 0, len(dates)*len(all))
+	for _, day := range days {
+		for i, v := range day["values"].([]interface{}) {
+			long = append(long, map[string]interface{}{"date": day["date"], "theme": input.Themes[i].Theme.ThemeName, "value": v})
+		}
+	}
+
+	// 4. Compare every pair of themes on the days both have a value
+	pairs := make([]map[string]interface{}, 0, len(all)*(len(all)-1)/2)
+	for a := 0; a < len(all); a++ {
+		for b := a + 1; b < len(all); b++ {
+			pairs = append(pairs, comparePair(input, all, dates, a, b))
+		}
+	}
+
+	return feature.AnalysisResult{
+		"start_date": input.Window.Start.Format("2006-01-02"),
+		"end_date":   input.Window.End.Format("2006-01-02"),
+		"themes":     themes,
+		"days":       days,
+		"pairs":      pairs,
+		feature.TableKey: feature.NewTableFromRecords([]feature.Column{
+			{Name: "date", Type: feature.ColumnTypeDate},
+			{Name: "theme", Type: feature.ColumnTypeString},
+			{Name: "value", Type: feature.ColumnTypeNumber},
+		}, long).WithChart(feature.Chart{Mark: "line", X: "date", Y: "value", Color: "theme"}),
+	}, nil
+}
+
+// buildSeries computes the per-day value of one theme according to its config.
+func buildSeries(ctx context.Context, ti feature.ThemeInput) (series, error) {
+	s := series{
+		field:         ti.Config.Field(ValueRole),
+		metric:        MetricCount,
+		values:        make(map[string]float64),
+		missingAsZero: true,
+	}
+	if v, ok := feature.BoolValue(ti.Config.Options[MissingAsZeroOption]); ok {
+		s.missingAsZero = v
+	}
+	if s.field != "" {
+		fieldType, err := fieldType(ti.Theme, s.field)
+		if err != nil {
+			return series{}, err
+		}
+		if fieldType == theme.FieldTypeBoolean {
+			s.metric = MetricCompleted
+		} else {
+			amounts, err := feature.NewAmounts(ti.Theme, s.field, ti.Config, ti.Entries)
+			if err != nil {
+				return series{}, fmt.Errorf("theme '%s': %w", ti.Theme.ThemeName, err)
+			}
+			s.metric = MetricSum
+			s.amounts = amounts
+		}
+	}
+
+	for _, en := range ti.Entries {
+		if err := ctx.Err(); err != nil {
+			return series{}, err
+		}
+		// A day with an entry always gets a value, even if the field is empty
+		s.values[en.EntryDate] += s.entryValue(en.Data)
+	}
+	return s, nil
+}
+
+// comparePair correlates the series of themes a and b.
+// Besides the Pearson coefficient, it reports the average of b on days where a is active (> 0)
+// and inactive, which answers questions like "are more tasks finished on days with events".
+func comparePair(input feature.MultiThemeInput, all []series, dates []string, a, b int) map[string]interface{} {
+	var xs, ys []float64
+	var activeSum, inactiveSum float64
+	var activeDays, inactiveDays int
+	for _, date := range dates {
+		x, okA := all[a].value(date)
+		y, okB := all[b].value(date)
+		if !okA || !okB {
+			continue
+		}
+		xs = append(xs, x)
+		ys = append(ys, y)
+		if x > 0 {
+			activeSum += y
+			activeDays++
+		} else {
+			inactiveSum += y
+			inactiveDays++
+		}
+	}
+
+	return map[string]interface{}{
+		"theme_a":                input.Themes[a].Theme.ThemeID,
+		"theme_b":                input.Themes[b].Theme.ThemeID,
+		"days":                   len(xs),
+		"pearson":                pearson(xs, ys),
+		"mean_b_when_a_active":   mean(activeSum, activeDays),
+		"mean_b_when_a_inactive": mean(inactiveSum, inactiveDays),
+		"days_a_active":          activeDays,
+		"days_a_inactive":        inactiveDays,
+	}
+}
+
+// pearson returns the Pearson correlation coefficient of xs and ys,
+// or nil when it is undefined (fewer than two days or a constant series).
+func pearson(xs, ys []float64) interface{} {
+	n := float64(len(xs))
+	if len(xs) < 2 {
+		return nil
+	}
+	var sumX, sumY float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+	}
+	meanX, meanY := sumX/n, sumY/n
+	var cov, varX, varY float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return nil
+	}
+	return cov / math.Sqrt(varX*varY)
+}
+
+// mean returns sum/count, or nil when count is zero.
+func mean(sum float64, count int) interface{} {
+	if count == 0 {
+		return nil
+	}
+	return sum / float64(count)
+}
+
+// fieldType returns the type of the named theme field.
+func fieldType(th theme.Theme, name string) (theme.FieldType, error) {
+	for _, f := range th.Fields {
+		if f.Name == name {
+			return f.Type, nil
+		}
+	}
+	return "", fmt.Errorf("%w: theme '%s' has no field named '%s'", feature.ErrInvalidConfig, th.ThemeName, name)
+}
+
+// Compile-time check to ensure Executor implements feature.MultiTheme.
+var _ feature.MultiTheme = (*Executor)(nil)
@@ -0,0 +1,61 @@
+package dailycorrelation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/soranjiro/axicalendar/internal/domain/entry"
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+func TestExecutor_ExecuteMulti_HabitVsSpending(t *testing.T) {
+	exec := NewExecutor()
+	habit := theme.Theme{ThemeID: uuid.New(), ThemeName: "Workout", Fields: []theme.ThemeField{{Name: "done", Label: "Done", Type: theme.FieldTypeBoolean}}}
+	spending := theme.Theme{ThemeID: uuid.New(), ThemeName: "Expenses", Fields: []theme.ThemeField{{Name: "amount", Label: "Amount", Type: theme.FieldTypeNumber}}}
+	window, _ := feature.NewWindow(time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 5, 4, 0, 0, 0, 0, time.UTC))
+
+	input := feature.MultiThemeInput{
+		Window: window,
+		Themes: []feature.ThemeInput{
+			{
+				Theme:  habit,
+				Config: theme.FeatureConfig{Fields: map[string]string{ValueRole: "done"}},
+				Entries: []entry.Entry{
+					{EntryDate: "2025-05-01", Data: map[string]interface{}{"done": true}},
+					{EntryDate: "2025-05-03", Data: map[string]interface{}{"done": true}},
+				},
+			},
+			{
+				Theme:  spending,
+				Config: theme.FeatureConfig{Fields: map[string]string{ValueRole: "amount"}},
+				Entries: []entry.Entry{
+					{EntryDate: "2025-05-01", Data: map[string]interface{}{"amount": 10.0}},
+					{EntryDate: "2025-05-02", Data: map[string]interface{}{"amount": 50.0}},
+					{EntryDate: "2025-05-02", Data: map[string]interface{}{"amount": 30.0}},
+					{EntryDate: "2025-05-03", Data: map[string]interface{}{"amount": 20.0}},
+				},
+			},
+		},
+	}
+
+	result, err := exec.ExecuteMulti(context.Background(), input)
+
+	assert.NoError(t, err)
+	assert.Len(t, result["days"], 4)
+	pair := result["pairs"].([]map[string]interface{})[0]
+	assert.Equal(t, 4, pair["days"]) // Missing days count as 0 by default
+	assert.Equal(t, 15.0, pair["mean_b_when_a_active"])
+	assert.Equal(t, 40.0, pair["mean_b_when_a_inactive"])
+	assert.Less(t, pair["pearson"].(float64), 0.0)
+}
+
+func TestExecutor_Execute_SingleThemeRejected(t *testing.T) {
+	_, err := NewExecutor().Execute(context.Background(), feature.Input{}, theme.FeatureConfig{})
+
+	assert.ErrorIs(t, err, feature.ErrInvalidConfig)
+}
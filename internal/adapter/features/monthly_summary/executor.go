@@ -0,0 +1,192 @@
+package monthlysummary
+
+import (
+	"context"
+	"math"
+
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+// FeatureName is the identifier used to register and reference this feature.
+const FeatureName = "monthly_summary"
+
+// Executor implements the monthly_summary feature.
+// It reports entry counts per day and aggregates number-like and boolean fields for one calendar month.
+type Executor struct{}
+
+// NewExecutor creates a new monthly_summary Executor.
+func NewExecutor() *Executor {
+	return &Executor{}
+}
+
+// Describe returns the metadata of the monthly_summary feature.
+// It has no field roles or options: every number-like and boolean field of the theme is summarised.
+func (e *Executor) Describe() feature.Feature {
+	return feature.Feature{
+		Name:        FeatureName,
+		DisplayName: "Monthly Summary",
+		Description: "Counts entries per day and summarises number, rating, duration, money and boolean fields over one calendar month. Money is summarised per currency.",
+		Fields:      []feature.FieldRole{},
+		Options:     []feature.Option{},
+	}
+}
+
+// MonthScoped marks the executor as working on a single month (see feature.MonthScoped).
+func (e *Executor) MonthScoped() {}
+
+// numberStats accumulates statistics for a number-like field.
+type numberStats struct {
+	amounts feature.Amounts
+	count   int
+	sum     float64
+	min     float64
+	max     float64
+}
+
+// newNumberStats returns empty statistics.
+func newNumberStats() *numberStats {
+	return &numberStats{min: math.Inf(1), max: math.Inf(-1)}
+}
+
+// add adds a value to the statistics.
+func (s *numberStats) add(v float64) {
+	s.count++
+	s.sum += v
+	s.min = math.Min(s.min, v)
+	s.max = math.Max(s.max, v)
+}
+
+// report returns the statistics, with nulls for the minimum, maximum and average of an empty field.
+func (s *numberStats) report() map[string]interface{} {
+	if s.count == 0 {
+		return map[string]interface{}{"count": 0, "sum": 0.0, "min": nil, "max": nil, "average": nil}
+	}
+	return map[string]interface{}{
+		"count":   s.count,
+		"sum":     s.sum,
+		"min":     s.min,
+		"max":     s.max,
+		"average": s.sum / float64(s.count),
+	}
+}
+
+// booleanStats accumulates completion counts for a boolean field.
+type booleanStats struct {
+	count     int
+	trueCount int
+}
+
+// Execute summarises the entries of the month described by input.Window.
+// The feature has no configuration; every number-like and boolean field of the theme is summarised.
+// Durations are summarised in minutes and money per currency, so amounts in different currencies are never added up.
+func (e *Executor) Execute(ctx context.Context, input feature.Input, _ theme.FeatureConfig) (feature.AnalysisResult, error) {
+	// 1. Initialise per-day counts so days without entries are reported as zero
+	dailyCounts := make(map[string]int)
+	for _, day := range input.Window.Days() {
+		dailyCounts[day] = 0
+	}
+
+	// 2. Prepare accumulators for number-like and boolean fields of the theme
+	numbers := make(map[string]*numberStats)
+	money := make(map[string]map[string]*numberStats) // Field -> currency -> statistics
+	booleans := make(map[string]*booleanStats)
+	for _, field := range input.Theme.Fields {
+		switch field.Type {
+		case theme.FieldTypeNumber, theme.FieldTypeRating, theme.FieldTypeDuration:
+			stats := newNumberStats()
+			stats.amounts = feature.Amounts{Field: field.Name, Type: field.Type}
+			numbers[field.Name] = stats
+		case theme.FieldTypeMoney:
+			money[field.Name] = make(map[string]*numberStats)
+		case theme.FieldTypeBoolean:
+			booleans[field.Name] = &booleanStats{}
+		}
+	}
+
+	// 3. Aggregate entries
+	for _, en := range input.Entries {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		dailyCounts[en.EntryDate]++
+
+		for _, stats := range numbers {
+			if v, ok := stats.amounts.Value(en.Data); ok {
+				stats.add(v)
+			}
+		}
+		for name, perCurrency := range money {
+			m, err := theme.ParseMoney(en.Data[name])
+			if err != nil {
+				continue
+			}
+			stats, ok := perCurrency[m.Currency]
+			if !ok {
+				stats = newNumberStats()
+				perCurrency[m.Currency] = stats
+			}
+			stats.add(m.Amount)
+		}
+		for name, stats := range booleans {
+			// Entries without a value count as not completed
+			stats.count++
+			if v, ok := feature.BoolValue(en.Data[name]); ok && v {
+				stats.trueCount++
+			}
+		}
+	}
+
+	// 4. Build the result
+	numberResults := make(map[string]interface{}, len(numbers))
+	for name, stats := range numbers {
+		report := stats.report()
+		if unit := stats.amounts.Unit(); unit != "" {
+			report["unit"] = unit
+		}
+		numberResults[name] = report
+	}
+	moneyResults := make(map[string]interface{}, len(money))
+	for name, perCurrency := range money {
+		reports := make(map[string]interface{}, len(perCurrency))
+		for currency, stats := range perCurrency {
+			reports[currency] = stats.report()
+		}
+		moneyResults[name] = reports
+	}
+	booleanResults := make(map[string]interface{}, len(booleans))
+	for name, stats := range booleans {
+		ratio := 0.0
+		if stats.count > 0 {
+			ratio = float64(stats.trueCount) / float64(stats.count)
+		}
+		booleanResults[name] = map[string]interface{}{
+			"count":            stats.count,
+			"completed":        stats.trueCount,
+			"completion_ratio": ratio,
+		}
+	}
+
+	// 5. Tabulate the daily counts for exports
+	days := make([]map[string]interface{}, 0, len(dailyCounts))
+	for _, day := range input.Window.Days() {
+		days = append(days, map[string]interface{}{"date": day, "entries": dailyCounts[day]})
+	}
+	table := feature.NewTableFromRecords([]feature.Column{
+		{Name: "date", Type: feature.ColumnTypeDate},
+		{Name: "entries", Type: feature.ColumnTypeNumber},
+	}, days).WithChart(feature.Chart{Mark: "bar", X: "date", Y: "entries"})
+
+	return feature.AnalysisResult{
+		"month":          input.Window.YearMonth(),
+		"total_entries":  len(input.Entries),
+		"daily_counts":   dailyCounts,
+		"number_fields":  numberResults,
+		"money_fields":   moneyResults,
+		"boolean_fields": booleanResults,
+		feature.TableKey: table,
+	}, nil
+}
+
+// Compile-time check to ensure Executor implements feature.MonthScoped.
+var _ feature.MonthScoped = (*Executor)(nil)
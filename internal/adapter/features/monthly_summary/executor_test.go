@@ -0,0 +1,72 @@
+package monthlysummary
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/soranjiro/axicalendar/internal/domain/entry"
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+func TestExecutor_Execute_Success(t *testing.T) {
+	exec := NewExecutor()
+	ctx := context.Background()
+	testTheme := theme.Theme{
+		ThemeID:   uuid.New(),
+		ThemeName: "Study Log",
+		Fields: []theme.ThemeField{
+			{Name: "subject", Label: "Subject", Type: theme.FieldTypeText},
+			{Name: "minutes", Label: "Minutes", Type: theme.FieldTypeNumber},
+			{Name: "done", Label: "Done", Type: theme.FieldTypeBoolean},
+		},
+	}
+	window := feature.MonthWindow(time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC))
+	entries := []entry.Entry{
+		{EntryDate: "2024-02-01", Data: map[string]interface{}{"subject": "math", "minutes": 30.0, "done": true}},
+		{EntryDate: "2024-02-01", Data: map[string]interface{}{"subject": "go", "minutes": 90, "done": false}},
+		{EntryDate: "2024-02-15", Data: map[string]interface{}{"subject": "go"}},
+	}
+
+	result, err := exec.Execute(ctx, feature.Input{Theme: testTheme, Window: window, Entries: entries}, theme.FeatureConfig{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-02", result["month"])
+	assert.Equal(t, 3, result["total_entries"])
+
+	dailyCounts := result["daily_counts"].(map[string]int)
+	assert.Len(t, dailyCounts, 29) // Leap year February, every day is reported
+	assert.Equal(t, 2, dailyCounts["2024-02-01"])
+	assert.Equal(t, 1, dailyCounts["2024-02-15"])
+	assert.Equal(t, 0, dailyCounts["2024-02-29"])
+
+	minutes := result["number_fields"].(map[string]interface{})["minutes"].(map[string]interface{})
+	assert.Equal(t, 2, minutes["count"])
+	assert.Equal(t, 120.0, minutes["sum"])
+	assert.Equal(t, 30.0, minutes["min"])
+	assert.Equal(t, 90.0, minutes["max"])
+	assert.Equal(t, 60.0, minutes["average"])
+
+	done := result["boolean_fields"].(map[string]interface{})["done"].(map[string]interface{})
+	assert.Equal(t, 3, done["count"])
+	assert.Equal(t, 1, done["completed"])
+	assert.InDelta(t, 1.0/3.0, done["completion_ratio"], 1e-9)
+}
+
+func TestExecutor_Execute_NoEntries(t *testing.T) {
+	exec := NewExecutor()
+	testTheme := theme.Theme{Fields: []theme.ThemeField{{Name: "amount", Label: "Amount", Type: theme.FieldTypeNumber}}}
+	window := feature.MonthWindow(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC))
+
+	result, err := exec.Execute(context.Background(), feature.Input{Theme: testTheme, Window: window}, theme.FeatureConfig{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result["total_entries"])
+	amount := result["number_fields"].(map[string]interface{})["amount"].(map[string]interface{})
+	assert.Equal(t, 0, amount["count"])
+	assert.Nil(t, amount["average"])
+}
@@ -0,0 +1,242 @@
+package streak
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+// FeatureName is the identifier used to register and reference this feature.
+const FeatureName = "streak"
+
+// DoneRole is the field role that marks a day as done.
+// A boolean field marks the entry's day as done when true;
+// a date field marks the date it holds as done.
+const DoneRole = "done"
+
+// WeekStartOption selects the first day of the week used for weekly completion rates.
+const WeekStartOption = "week_start"
+
+// Values of WeekStartOption.
+const (
+	WeekStartMonday = "monday"
+	WeekStartSunday = "sunday"
+)
+
+// Day statuses reported in the heatmap.
+const (
+	StatusDone     = "done"
+	StatusMissed   = "missed"
+	StatusUpcoming = "upcoming" // After today; not counted as a miss
+)
+
+// Executor implements the streak feature.
+// It treats every day in the window without a done entry as a miss and reports
+// streaks, completion rates and a calendar heatmap.
+type Executor struct {
+	now func() time.Time
+}
+
+// NewExecutor creates a new streak Executor.
+func NewExecutor() *Executor {
+	return &Executor{now: time.Now}
+}
+
+// Describe returns the metadata of the streak feature.
+func (e *Executor) Describe() feature.Feature {
+	return feature.Feature{
+		Name:        FeatureName,
+		DisplayName: "Streak",
+		Description: "Tracks habit streaks: current and longest streak, weekly and monthly completion rates and a calendar heatmap. Days without a done entry count as misses.",
+		Fields: []feature.FieldRole{
+			{
+				Role:         DoneRole,
+				Description:  "Field marking a day as done. A boolean field counts the entry's day when true; a date field counts the date it holds.",
+				Types:        []theme.FieldType{theme.FieldTypeBoolean, theme.FieldTypeDate},
+				Required:     true,
+				DefaultField: "done",
+			},
+		},
+		Options: []feature.Option{
+			{
+				Name:        WeekStartOption,
+				Type:        feature.OptionTypeString,
+				Description: "First day of the week for weekly completion rates.",
+				Default:     WeekStartMonday,
+				Enum:        []string{WeekStartMonday, WeekStartSunday},
+			},
+		},
+	}
+}
+
+// DateDependent marks the streak feature as depending on today's date:
+// the current streak and the days counted as missed change every day.
+func (e *Executor) DateDependent() {}
+
+// period accumulates the completion of a week or month.
+type period struct {
+	key       string
+	days      int // Elapsed days of the period within the window
+	completed int
+}
+
+// result returns the period's completion, keyed by label.
+func (p *period) result(label string) map[string]interface{} {
+	rate := 0.0
+	if p.days > 0 {
+		rate = float64(p.completed) / float64(p.days)
+	}
+	return map[string]interface{}{
+		label:       p.key,
+		"days":      p.days,
+		"completed": p.completed,
+		"rate":      rate,
+	}
+}
+
+// Execute computes streaks and completion rates over the input window.
+// Days after today are reported as upcoming and are not counted as misses.
+func (e *Executor) Execute(ctx context.Context, input feature.Input, config theme.FeatureConfig) (feature.AnalysisResult, error) {
+	// 1. Resolve the done field and options
+	doneField := config.FieldOr(DoneRole, "done")
+	fieldType, err := fieldType(input.Theme, doneField)
+	if err != nil {
+		return nil, err
+	}
+	if fieldType != theme.FieldTypeBoolean && fieldType != theme.FieldTypeDate {
+		return nil, fmt.Errorf("%w: field '%s' has type '%s', expected boolean or date", feature.ErrInvalidConfig, doneField, fieldType)
+	}
+	weekStart := time.Monday
+	if config.StringOption(WeekStartOption, WeekStartMonday) == WeekStartSunday {
+		weekStart = time.Sunday
+	}
+
+	// 2. Collect the done days
+	done := make(map[string]int) // Date -> number of done entries
+	for _, en := range input.Entries {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		switch fieldType {
+		case theme.FieldTypeBoolean:
+			if v, ok := feature.BoolValue(en.Data[doneField]); ok && v {
+				done[en.EntryDate]++
+			}
+		case theme.FieldTypeDate:
+			if v, ok := feature.StringValue(en.Data[doneField]); ok && input.Window.Contains(v) {
+				done[v]++
+			}
+		}
+	}
+
+	// 3. Walk the window day by day
+	today := e.now().UTC().Format("2006-01-02")
+	var (
+		heatmap       []map[string]interface{}
+		weeks, months []*period
+		run, longest  int
+		completed     int
+		elapsed       int
+	)
+	for _, date := range input.Window.Days() {
+		count := done[date]
+		status := StatusMissed
+		switch {
+		case count > 0:
+			status = StatusDone
+		case date > today:
+			status = StatusUpcoming
+		}
+		heatmap = append(heatmap, map[string]interface{}{"date": date, "count": count, "status": status})
+		if status == StatusUpcoming {
+			continue
+		}
+
+		day, _ := time.Parse("2006-01-02", date)
+		weeks = addToPeriod(weeks, weekKey(day, weekStart), count > 0)
+		months = addToPeriod(months, day.Format("2006-01"), count > 0)
+		elapsed++
+		if count > 0 {
+			completed++
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else if date != today {
+			// Today only breaks the streak once it is over
+			run = 0
+		}
+	}
+
+	// 4. Build the result
+	weekly := make([]map[string]interface{}, 0, len(weeks))
+	for _, w := range weeks {
+		weekly = append(weekly, w.result("week_start"))
+	}
+	monthly := make([]map[string]interface{}, 0, len(months))
+	for _, m := range months {
+		monthly = append(monthly, m.result("month"))
+	}
+	rate := 0.0
+	if elapsed > 0 {
+		rate = float64(completed) / float64(elapsed)
+	}
+	if heatmap == nil {
+		heatmap = []map[string]interface{}{}
+	}
+
+	return feature.AnalysisResult{
+		"done_field":      doneField,
+		"start_date":      input.Window.Start.Format("2006-01-02"),
+		"end_date":        input.Window.End.Format("2006-01-02"),
+		"current_streak":  run,
+		"longest_streak":  longest,
+		"completed_days":  completed,
+		"elapsed_days":    elapsed,
+		"completion_rate": rate,
+		"weekly":          weekly,
+		"monthly":         monthly,
+		"heatmap":         heatmap,
+		feature.TableKey: feature.NewTableFromRecords([]feature.Column{
+			{Name: "date", Type: feature.ColumnTypeDate},
+			{Name: "count", Type: feature.ColumnTypeNumber},
+			{Name: "status", Type: feature.ColumnTypeString},
+		}, heatmap).WithChart(feature.Chart{Mark: "bar", X: "date", Y: "count", Color: "status"}),
+	}, nil
+}
+
+// addToPeriod counts a day towards the period with key, appending the period if it is new.
+// Days are visited in order, so a new key always starts a new period.
+func addToPeriod(periods []*period, key string, isDone bool) []*period {
+	if len(periods) == 0 || periods[len(periods)-1].key != key {
+		periods = append(periods, &period{key: key})
+	}
+	p := periods[len(periods)-1]
+	p.days++
+	if isDone {
+		p.completed++
+	}
+	return periods
+}
+
+// weekKey returns the date (YYYY-MM-DD) of the first day of the week containing day.
+func weekKey(day time.Time, weekStart time.Weekday) string {
+	offset := (int(day.Weekday()) - int(weekStart) + 7) % 7
+	return day.AddDate(0, 0, -offset).Format("2006-01-02")
+}
+
+// fieldType returns the type of the named theme field.
+func fieldType(th theme.Theme, name string) (theme.FieldType, error) {
+	for _, f := range th.Fields {
+		if f.Name == name {
+			return f.Type, nil
+		}
+	}
+	return "", fmt.Errorf("%w: theme '%s' has no field named '%s'", feature.ErrInvalidConfig, th.ThemeName, name)
+}
+
+// Compile-time check to ensure Executor implements feature.DateDependent.
+var _ feature.DateDependent = (*Executor)(nil)
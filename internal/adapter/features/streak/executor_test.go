@@ -0,0 +1,79 @@
+package streak
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/soranjiro/axicalendar/internal/domain/entry"
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+func newTestExecutor(today time.Time) *Executor {
+	return &Executor{now: func() time.Time { return today }}
+}
+
+func TestExecutor_Execute_BooleanField(t *testing.T) {
+	exec := newTestExecutor(time.Date(2025, 5, 8, 12, 0, 0, 0, time.UTC))
+	th := theme.Theme{ThemeName: "Workout", Fields: []theme.ThemeField{{Name: "done", Label: "Done", Type: theme.FieldTypeBoolean}}}
+	window, _ := feature.NewWindow(time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 5, 10, 0, 0, 0, 0, time.UTC))
+	entries := []entry.Entry{
+		{EntryDate: "2025-05-01", Data: map[string]interface{}{"done": true}},
+		{EntryDate: "2025-05-02", Data: map[string]interface{}{"done": true}},
+		{EntryDate: "2025-05-03", Data: map[string]interface{}{"done": true}},
+		{EntryDate: "2025-05-04", Data: map[string]interface{}{"done": false}},
+		// 2025-05-05 has no entry and counts as a miss
+		{EntryDate: "2025-05-06", Data: map[string]interface{}{"done": true}},
+		{EntryDate: "2025-05-07", Data: map[string]interface{}{"done": true}},
+		// Today (2025-05-08) is not done yet and does not break the streak
+	}
+
+	result, err := exec.Execute(context.Background(), feature.Input{Theme: th, Window: window, Entries: entries}, theme.FeatureConfig{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result["current_streak"])
+	assert.Equal(t, 3, result["longest_streak"])
+	assert.Equal(t, 5, result["completed_days"])
+	assert.Equal(t, 8, result["elapsed_days"])
+
+	heatmap := result["heatmap"].([]map[string]interface{})
+	assert.Len(t, heatmap, 10)
+	assert.Equal(t, StatusMissed, heatmap[4]["status"])
+	assert.Equal(t, StatusUpcoming, heatmap[9]["status"])
+
+	// 2025-05-01 is a Thursday, so the first Monday-based week starts on 2025-04-28
+	weekly := result["weekly"].([]map[string]interface{})
+	assert.Len(t, weekly, 2)
+	assert.Equal(t, "2025-04-28", weekly[0]["week_start"])
+	assert.Equal(t, 4, weekly[0]["days"])
+	assert.Equal(t, 0.75, weekly[0]["rate"])
+}
+
+func TestExecutor_Execute_DateFieldAndMapping(t *testing.T) {
+	exec := newTestExecutor(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+	th := theme.Theme{ThemeName: "Reading", Fields: []theme.ThemeField{{Name: "finished_on", Label: "Finished on", Type: theme.FieldTypeDate}}}
+	window, _ := feature.NewWindow(time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 5, 3, 0, 0, 0, 0, time.UTC))
+	entries := []entry.Entry{
+		{EntryDate: "2025-05-01", Data: map[string]interface{}{"finished_on": "2025-05-02"}},
+		{EntryDate: "2025-05-03", Data: map[string]interface{}{"finished_on": "2025-05-03"}},
+	}
+	config := theme.FeatureConfig{Fields: map[string]string{DoneRole: "finished_on"}}
+
+	result, err := exec.Execute(context.Background(), feature.Input{Theme: th, Window: window, Entries: entries}, config)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result["current_streak"])
+	assert.Equal(t, 2, result["completed_days"])
+	assert.InDelta(t, 2.0/3.0, result["completion_rate"], 1e-9)
+}
+
+func TestExecutor_Execute_WrongFieldType(t *testing.T) {
+	th := theme.Theme{ThemeName: "Notes", Fields: []theme.ThemeField{{Name: "done", Label: "Done", Type: theme.FieldTypeText}}}
+
+	_, err := NewExecutor().Execute(context.Background(), feature.Input{Theme: th, Window: feature.MonthWindow(time.Now())}, theme.FeatureConfig{})
+
+	assert.ErrorIs(t, err, feature.ErrInvalidConfig)
+}
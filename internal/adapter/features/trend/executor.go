@@ -0,0 +1,287 @@
+package trend
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+// FeatureName is the identifier used to register and reference this feature.
+const FeatureName = "trend"
+
+// ValueRole is the field role holding the number-like value tracked over time (see feature.AmountTypes).
+const ValueRole = "value"
+
+// Options accepted in the feature's theme.FeatureConfig.
+const (
+	BucketOption    = "bucket"    // Size of each point in the series
+	WindowOption    = "window"    // Number of buckets in the moving average
+	AggregateOption = "aggregate" // How the values of a bucket are combined
+)
+
+// Values of BucketOption.
+const (
+	BucketDaily   = "daily"
+	BucketWeekly  = "weekly" // Weeks start on Monday
+	BucketMonthly = "monthly"
+)
+
+// Values of AggregateOption.
+const (
+	AggregateAverage = "average" // e.g. body weight
+	AggregateSum     = "sum"     // e.g. study minutes
+)
+
+// DefaultWindow is the moving average window used when the option is not set.
+const DefaultWindow = 7
+
+// Executor implements the trend feature.
+// It buckets a number field by day, week or month and reports moving averages,
+// period-over-period deltas and the slope of a linear regression over the series.
+type Executor struct{}
+
+// NewExecutor creates a new trend Executor.
+func NewExecutor() *Executor {
+	return &Executor{}
+}
+
+// Describe returns the metadata of the trend feature.
+func (e *Executor) Describe() feature.Feature {
+	return feature.Feature{
+		Name:        FeatureName,
+		DisplayName: "Trend",
+		Description: "Plots a number field as a daily, weekly or monthly series with moving averages, period-over-period deltas and a linear-regression slope.",
+		Fields: []feature.FieldRole{
+			{
+				Role:         ValueRole,
+				Description:  "Field tracked over time: a number, rating, duration (in minutes) or money field (in one currency).",
+				Types:        feature.AmountTypes,
+				Required:     true,
+				DefaultField: "value",
+			},
+		},
+		Options: []feature.Option{
+			{
+				Name:        BucketOption,
+				Type:        feature.OptionTypeString,
+				Description: "Size of each point in the series. Weekly buckets start on Monday.",
+				Default:     BucketDaily,
+				Enum:        []string{BucketDaily, BucketWeekly, BucketMonthly},
+			},
+			{
+				Name:        WindowOption,
+				Type:        feature.OptionTypeNumber,
+				Description: "Number of buckets averaged by the moving average.",
+				Default:     DefaultWindow,
+			},
+			{
+				Name:        AggregateOption,
+				Type:        feature.OptionTypeString,
+				Description: "How the values within a bucket are combined.",
+				Default:     AggregateAverage,
+				Enum:        []string{AggregateAverage, AggregateSum},
+			},
+			feature.CurrencyOptionSpec(),
+		},
+	}
+}
+
+// bucket accumulates the values of one point in the series.
+type bucket struct {
+	key   string
+	count int
+	sum   float64
+}
+
+// value returns the bucket's aggregated value, or false if it has no entries.
+func (b *bucket) value(aggregate string) (float64, bool) {
+	if b.count == 0 {
+		return 0, false
+	}
+	if aggregate == AggregateSum {
+		return b.sum, true
+	}
+	return b.sum / float64(b.count), true
+}
+
+// Execute builds the series over the input window.
+// Buckets without entries are kept in the series with a null value so the x axis is continuous.
+func (e *Executor) Execute(ctx context.Context, input feature.Input, config theme.FeatureConfig) (feature.AnalysisResult, error) {
+	// 1. Resolve the value field and options
+	valueField := config.FieldOr(ValueRole, "value")
+	values, err := feature.NewAmounts(input.Theme, valueField, config, input.Entries)
+	if err != nil {
+		return nil, err
+	}
+	bucketSize := config.StringOption(BucketOption, BucketDaily)
+	keyOf, err := bucketKeyFunc(bucketSize)
+	if err != nil {
+		return nil, err
+	}
+	window := config.IntOption(WindowOption, DefaultWindow)
+	if window < 1 {
+		return nil, fmt.Errorf("%w: option '%s' must be at least 1, got %d", feature.ErrInvalidConfig, WindowOption, window)
+	}
+	aggregate := config.StringOption(AggregateOption, AggregateAverage)
+
+	// 2. Create a bucket for every period in the window, in order
+	var buckets []*bucket
+	index := make(map[string]*bucket)
+	for _, date := range input.Window.Days() {
+		day, _ := time.Parse("2006-01-02", date)
+		key := keyOf(day)
+		if _, exists := index[key]; !exists {
+			b := &bucket{key: key}
+			buckets = append(buckets, b)
+			index[key] = b
+		}
+	}
+
+	// 3. Add entry values to their bucket
+	for _, en := range input.Entries {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		v, ok := values.Value(en.Data)
+		if !ok {
+			continue
+		}
+		day, err := time.Parse("2006-01-02", en.EntryDate)
+		if err != nil {
+			continue
+		}
+		b, exists := index[keyOf(day)]
+		if !exists {
+			continue
+		}
+		b.count++
+		b.sum += v
+	}
+
+	// 4. Build the series with moving averages and deltas
+	series := make([]map[string]interface{}, 0, len(buckets))
+	var (
+		xs, ys   []float64
+		previous *float64
+	)
+	for i, b := range buckets {
+		point := map[string]interface{}{
+			"period":         b.key,
+			"count":          b.count,
+			"value":          nil,
+			"moving_average": nil,
+			"delta":          nil,
+			"delta_percent":  nil,
+		}
+		v, ok := b.value(aggregate)
+		if ok {
+			point["value"] = v
+			xs = append(xs, float64(i))
+			ys = append(ys, v)
+			if previous != nil {
+				point["delta"] = v - *previous
+				if *previous != 0 {
+					point["delta_percent"] = (v - *previous) / *previous * 100
+				}
+			}
+			previous = &v
+		}
+		if avg, ok := movingAverage(buckets, i, window, aggregate); ok {
+			point["moving_average"] = avg
+		}
+		series = append(series, point)
+	}
+
+	// 5. Fit a line through the buckets that have a value
+	result := feature.AnalysisResult{
+		"value_field": valueField,
+		"value_unit":  values.Unit(),
+		"bucket":      bucketSize,
+		"window":      window,
+		"aggregate":   aggregate,
+		"start_date":  input.Window.Start.Format("2006-01-02"),
+		"end_date":    input.Window.End.Format("2006-01-02"),
+		"series":      series,
+		"slope":       nil,
+		"intercept":   nil,
+		feature.TableKey: feature.NewTableFromRecords([]feature.Column{
+			{Name: "period", Type: feature.ColumnTypeDate},
+			{Name: "count", Type: feature.ColumnTypeNumber},
+			{Name: "value", Type: feature.ColumnTypeNumber},
+			{Name: "moving_average", Type: feature.ColumnTypeNumber},
+			{Name: "delta", Type: feature.ColumnTypeNumber},
+			{Name: "delta_percent", Type: feature.ColumnTypeNumber},
+		}, series).WithChart(feature.Chart{Mark: "line", X: "period", Y: "value"}),
+	}
+	if slope, intercept, ok := linearRegression(xs, ys); ok {
+		result["slope"] = slope
+		result["intercept"] = intercept
+	}
+	return result, nil
+}
+
+// movingAverage averages the values of the window buckets ending at index i.
+// Buckets without entries are skipped; ok is false if none of them has a value.
+func movingAverage(buckets []*bucket, i, window int, aggregate string) (float64, bool) {
+	start := i - window + 1
+	if start < 0 {
+		start = 0
+	}
+	sum, n := 0.0, 0
+	for _, b := range buckets[start : i+1] {
+		if v, ok := b.value(aggregate); ok {
+			sum += v
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, false
+	}
+	return sum / float64(n), true
+}
+
+// linearRegression fits y = slope*x + intercept by least squares.
+// ok is false if there are fewer than two distinct x values.
+func linearRegression(xs, ys []float64) (slope, intercept float64, ok bool) {
+	n := float64(len(xs))
+	if len(xs) < 2 {
+		return 0, 0, false
+	}
+	var sumX, sumY, sumXY, sumXX float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+		sumXY += xs[i] * ys[i]
+		sumXX += xs[i] * xs[i]
+	}
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0, 0, false
+	}
+	slope = (n*sumXY - sumX*sumY) / denominator
+	intercept = (sumY - slope*sumX) / n
+	return slope, intercept, true
+}
+
+// bucketKeyFunc returns the function mapping a day to the key of its bucket.
+func bucketKeyFunc(size string) (func(time.Time) string, error) {
+	switch size {
+	case BucketDaily:
+		return func(day time.Time) string { return day.Format("2006-01-02") }, nil
+	case BucketWeekly:
+		return func(day time.Time) string {
+			offset := (int(day.Weekday()) - int(time.Monday) + 7) % 7
+			return day.AddDate(0, 0, -offset).Format("2006-01-02")
+		}, nil
+	case BucketMonthly:
+		return func(day time.Time) string { return day.Format("2006-01") }, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown bucket '%s'", feature.ErrInvalidConfig, size)
+	}
+}
+
+// Compile-time check to ensure Executor implements feature.FeatureExecutor.
+var _ feature.FeatureExecutor = (*Executor)(nil)
@@ -0,0 +1,86 @@
+package trend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/soranjiro/axicalendar/internal/domain/entry"
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+var weightTheme = theme.Theme{ThemeName: "Weight", Fields: []theme.ThemeField{{Name: "weight", Label: "Weight", Type: theme.FieldTypeNumber}}}
+
+func TestExecutor_Execute_DailyMovingAverageAndDeltas(t *testing.T) {
+	window, _ := feature.NewWindow(time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 5, 4, 0, 0, 0, 0, time.UTC))
+	entries := []entry.Entry{
+		{EntryDate: "2025-05-01", Data: map[string]interface{}{"weight": 70.0}},
+		{EntryDate: "2025-05-02", Data: map[string]interface{}{"weight": 71.0}},
+		{EntryDate: "2025-05-02", Data: map[string]interface{}{"weight": 73.0}},
+		// 2025-05-03 has no entry
+		{EntryDate: "2025-05-04", Data: map[string]interface{}{"weight": 74.0}},
+	}
+	config := theme.FeatureConfig{
+		Fields:  map[string]string{ValueRole: "weight"},
+		Options: map[string]interface{}{WindowOption: 2.0},
+	}
+
+	result, err := NewExecutor().Execute(context.Background(), feature.Input{Theme: weightTheme, Window: window, Entries: entries}, config)
+
+	assert.NoError(t, err)
+	series := result["series"].([]map[string]interface{})
+	assert.Len(t, series, 4)
+	assert.Equal(t, 72.0, series[1]["value"])
+	assert.Equal(t, 71.0, series[1]["moving_average"])
+	assert.Equal(t, 2.0, series[1]["delta"])
+	assert.Nil(t, series[2]["value"])
+	assert.Equal(t, 72.0, series[2]["moving_average"])
+	// Deltas compare with the previous bucket that has a value
+	assert.Equal(t, 2.0, series[3]["delta"])
+	// Points (0, 70), (1, 72), (3, 74)
+	assert.InDelta(t, 9.0/7.0, result["slope"], 1e-9)
+
+	table, ok, err := result.Table()
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "period", table.Columns[0].Name)
+	assert.Equal(t, feature.ColumnTypeDate, table.Columns[0].Type)
+	assert.Equal(t, []interface{}{"2025-05-02", 2, 72.0, 71.0, 2.0}, table.Rows[1][:5])
+}
+
+func TestExecutor_Execute_WeeklySum(t *testing.T) {
+	window, _ := feature.NewWindow(time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 5, 14, 0, 0, 0, 0, time.UTC))
+	entries := []entry.Entry{
+		{EntryDate: "2025-05-01", Data: map[string]interface{}{"weight": 30}},
+		{EntryDate: "2025-05-04", Data: map[string]interface{}{"weight": 20}},
+		{EntryDate: "2025-05-05", Data: map[string]interface{}{"weight": 60}},
+	}
+	config := theme.FeatureConfig{
+		Fields:  map[string]string{ValueRole: "weight"},
+		Options: map[string]interface{}{BucketOption: BucketWeekly, AggregateOption: AggregateSum},
+	}
+
+	result, err := NewExecutor().Execute(context.Background(), feature.Input{Theme: weightTheme, Window: window, Entries: entries}, config)
+
+	assert.NoError(t, err)
+	series := result["series"].([]map[string]interface{})
+	assert.Len(t, series, 3)
+	assert.Equal(t, "2025-04-28", series[0]["period"])
+	assert.Equal(t, 50.0, series[0]["value"])
+	assert.Equal(t, 60.0, series[1]["value"])
+	assert.Equal(t, 20.0, series[1]["delta_percent"])
+}
+
+func TestExecutor_Execute_InvalidConfig(t *testing.T) {
+	input := feature.Input{Theme: weightTheme, Window: feature.MonthWindow(time.Now())}
+
+	_, err := NewExecutor().Execute(context.Background(), input, theme.FeatureConfig{})
+	assert.ErrorIs(t, err, feature.ErrInvalidConfig)
+
+	config := theme.FeatureConfig{Fields: map[string]string{ValueRole: "weight"}, Options: map[string]interface{}{WindowOption: 0.0}}
+	_, err = NewExecutor().Execute(context.Background(), input, config)
+	assert.ErrorIs(t, err, feature.ErrInvalidConfig)
+}
@@ -0,0 +1,145 @@
+package dynamodbrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// ThemeIndexBackfill reports what BackfillThemeIndexes wrote.
+type ThemeIndexBackfill struct {
+	Scanned          int // Theme metadata items read
+	LinksCreated     int // USER#<user_id> / THEME#<theme_id> link items created for custom themes
+	DefaultsIndexed  int // Default themes given their GSI1 keys
+	ScheduledIndexed int // Custom themes with schedules given their GSI1 keys
+}
+
+// BackfillThemeIndexes writes the items ListThemes relies on for themes stored before they were kept:
+// the owner's link item of every custom theme, and the GSI1 keys of every default theme and of every
+// custom theme with schedules (see ListScheduledThemes).
+// It scans the theme metadata items once and is meant to be run from an admin command, not per request.
+// Items already in place are left untouched, so it can be run repeatedly.
+func BackfillThemeIndexes(ctx context.Context, dbClient *DynamoDBClient) (ThemeIndexBackfill, error) {
+	var result ThemeIndexBackfill
+	scanInput := &dynamodb.ScanInput{
+		TableName:        aws.String(dbClient.TableName),
+		FilterExpression: aws.String("SK = :md"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":md": &types.AttributeValueMemberS{Value: themeMetadataSK()},
+		},
+	}
+	paginator := dynamodb.NewScanPaginator(dbClient.Client, scanInput)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return result, fmt.Errorf("failed to scan themes: %w", err)
+		}
+		for _, item := range page.Items {
+			var t theme.Theme
+			if err := unmarshalTheme(item, &t); err != nil {
+				return result, fmt.Errorf("failed to unmarshal themes: %w", err)
+			}
+			result.Scanned++
+			switch {
+			case t.IsDefault && t.GSI1PK == "":
+				if err := indexDefaultTheme(ctx, dbClient, t); err != nil {
+					return result, err
+				}
+				result.DefaultsIndexed++
+			case !t.IsDefault && t.OwnerUserID != nil:
+				created, err := createOwnerLink(ctx, dbClient, t)
+				if err != nil {
+					return result, err
+				}
+				if created {
+					result.LinksCreated++
+				}
+				if t.HasSchedules() && t.GSI1PK == "" {
+					if err := indexScheduledTheme(ctx, dbClient, t); err != nil {
+						return result, err
+					}
+					result.ScheduledIndexed++
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+// indexDefaultTheme sets the GSI1 keys listing a default theme (see ListDefaultThemes).
+func indexDefaultTheme(ctx context.Context, dbClient *DynamoDBClient, t theme.Theme) error {
+	if _, err := dbClient.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(dbClient.TableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: themePK(t.ThemeID.String())},
+			"SK": &types.AttributeValueMemberS{Value: themeMetadataSK()},
+		},
+		UpdateExpression:    aws.String("SET GSI1PK = :gsi1pk, GSI1SK = :gsi1sk"),
+		ConditionExpression: aws.String("attribute_exists(PK) AND IsDefault = :true"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":gsi1pk": &types.AttributeValueMemberS{Value: defaultThemesGSI1PK},
+			":gsi1sk": &types.AttributeValueMemberS{Value: defaultThemeGSI1SK(t.ThemeID.String())},
+			":true":   &types.AttributeValueMemberBOOL{Value: true},
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to index default theme %s: %w", t.ThemeID, err)
+	}
+	return nil
+}
+
+// indexScheduledTheme sets the GSI1 keys listing a custom theme with schedules (see ListScheduledThemes).
+func indexScheduledTheme(ctx context.Context, dbClient *DynamoDBClient, t theme.Theme) error {
+	if _, err := dbClient.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(dbClient.TableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: themePK(t.ThemeID.String())},
+			"SK": &types.AttributeValueMemberS{Value: themeMetadataSK()},
+		},
+		UpdateExpression:    aws.String("SET GSI1PK = :gsi1pk, GSI1SK = :gsi1sk"),
+		ConditionExpression: aws.String("attribute_exists(PK) AND attribute_not_exists(GSI1PK)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":gsi1pk": &types.AttributeValueMemberS{Value: scheduledThemesGSI1PK},
+			":gsi1sk": &types.AttributeValueMemberS{Value: scheduledThemeGSI1SK(t.ThemeID.String())},
+		},
+	}); err != nil {
+		var condCheckFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &condCheckFailed) {
+			return nil // Indexed by a concurrent UpdateTheme
+		}
+		return fmt.Errorf("failed to index scheduled theme %s: %w", t.ThemeID, err)
+	}
+	return nil
+}
+
+// createOwnerLink writes the owner's link item of a custom theme unless it exists, reporting whether it did.
+func createOwnerLink(ctx context.Context, dbClient *DynamoDBClient, t theme.Theme) (bool, error) {
+	link := theme.UserThemeLink{
+		UserID:  *t.OwnerUserID,
+		ThemeID: t.ThemeID,
+		Role:    theme.RoleOwner,
+	}
+	setUserThemeLinkKeys(&link)
+	av, err := attributevalue.MarshalMap(link)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal user-theme link: %w", err)
+	}
+	if _, err := dbClient.Client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(dbClient.TableName),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(PK)"),
+	}); err != nil {
+		var condCheckFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &condCheckFailed) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to create user-theme link for theme %s: %w", t.ThemeID, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,62 @@
+package dynamodbrepo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+func TestBackfillThemeIndexes(t *testing.T) {
+	mockDB := new(MockDynamoDBAPI)
+	dbClient := &DynamoDBClient{Client: mockDB, TableName: "test-table"}
+	ctx := context.Background()
+	ownerID := uuid.New()
+
+	legacyDefault := theme.Theme{ThemeID: uuid.New(), ThemeName: "Legacy default", IsDefault: true}
+	indexedDefault := theme.Theme{ThemeID: uuid.New(), ThemeName: "Seeded default", IsDefault: true, GSI1PK: "DEFAULT#THEME", GSI1SK: "THEME#x"}
+	unlinked := theme.Theme{ThemeID: uuid.New(), ThemeName: "Unlinked", OwnerUserID: &ownerID}
+	linked := theme.Theme{ThemeID: uuid.New(), ThemeName: "Linked", OwnerUserID: &ownerID,
+		SupportedFeatures: []theme.SupportedFeature{{Name: "summary", Schedule: &theme.FeatureSchedule{Cron: "@monthly"}}}}
+	var items []map[string]types.AttributeValue
+	for _, th := range []theme.Theme{legacyDefault, indexedDefault, unlinked, linked} {
+		item, _ := attributevalue.MarshalMap(th)
+		items = append(items, item)
+	}
+
+	mockDB.On("Scan", ctx, mock.MatchedBy(func(input *dynamodb.ScanInput) bool {
+		return *input.FilterExpression == "SK = :md"
+	})).Return(&dynamodb.ScanOutput{Items: items}, nil).Once()
+	mockDB.On("UpdateItem", ctx, mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
+		pk, _ := input.Key["PK"].(*types.AttributeValueMemberS)
+		gsi1pk, _ := input.ExpressionAttributeValues[":gsi1pk"].(*types.AttributeValueMemberS)
+		return pk != nil && pk.Value == "THEME#"+legacyDefault.ThemeID.String() && gsi1pk != nil && gsi1pk.Value == "DEFAULT#THEME"
+	})).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+	mockDB.On("UpdateItem", ctx, mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
+		pk, _ := input.Key["PK"].(*types.AttributeValueMemberS)
+		gsi1pk, _ := input.ExpressionAttributeValues[":gsi1pk"].(*types.AttributeValueMemberS)
+		return pk != nil && pk.Value == "THEME#"+linked.ThemeID.String() && gsi1pk != nil && gsi1pk.Value == "SCHEDULED#THEME"
+	})).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+	linkFor := func(th theme.Theme) interface{} {
+		return mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+			var link theme.UserThemeLink
+			_ = attributevalue.UnmarshalMap(input.Item, &link)
+			return link.PK == "USER#"+ownerID.String() && link.SK == "THEME#"+th.ThemeID.String() && *input.ConditionExpression == "attribute_not_exists(PK)"
+		})
+	}
+	mockDB.On("PutItem", ctx, linkFor(unlinked)).Return(&dynamodb.PutItemOutput{}, nil).Once()
+	mockDB.On("PutItem", ctx, linkFor(linked)).Return(nil, &types.ConditionalCheckFailedException{}).Once()
+
+	result, err := BackfillThemeIndexes(ctx, dbClient)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ThemeIndexBackfill{Scanned: 4, LinksCreated: 1, DefaultsIndexed: 1, ScheduledIndexed: 1}, result)
+	mockDB.AssertExpectations(t)
+}
@@ -2,11 +2,14 @@ package dynamodbrepo
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
 
 const (
@@ -25,6 +28,8 @@ type DynamoDBAPI interface {
 	Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
 	Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)
 	TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error)
+	BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error)
+	BatchGetItem(ctx context.Context, params *dynamodb.BatchGetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error)
 }
 
 // DynamoDBClient encapsulates the DynamoDB client and table name.
@@ -57,3 +62,75 @@ func NewDynamoDBClient(ctx context.Context) (*DynamoDBClient, error) {
 		TableName: tableName,
 	}, nil
 }
+
+// Limits of BatchWriteItem: items per request, and attempts at writing the items DynamoDB leaves unprocessed.
+const (
+	batchWriteSize        = 25
+	batchWriteMaxAttempts = 5
+)
+
+// batchWrite sends one BatchWriteItem request and resends the items left unprocessed.
+func batchWrite(ctx context.Context, dbClient *DynamoDBClient, requests []types.WriteRequest) error {
+	for attempt := 1; len(requests) > 0; attempt++ {
+		if attempt > batchWriteMaxAttempts {
+			return fmt.Errorf("%d items left unprocessed after %d attempts", len(requests), batchWriteMaxAttempts)
+		}
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(attempt*attempt) * 50 * time.Millisecond):
+			}
+		}
+		out, err := dbClient.Client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]types.WriteRequest{dbClient.TableName: requests},
+		})
+		if err != nil {
+			log.Printf("Error batch writing %d items: %v", len(requests), err)
+			return fmt.Errorf("failed to batch write items: %w", err)
+		}
+		requests = out.UnprocessedItems[dbClient.TableName]
+	}
+	return nil
+}
+
+// Limits of BatchGetItem: keys per request, and attempts at reading the keys DynamoDB leaves unprocessed.
+const (
+	batchGetSize        = 100
+	batchGetMaxAttempts = 5
+)
+
+// batchGet reads the items with the given keys in requests of batchGetSize, resending the keys left
+// unprocessed. Items that do not exist are left out; the order of the result is unspecified.
+func batchGet(ctx context.Context, dbClient *DynamoDBClient, keys []map[string]types.AttributeValue) ([]map[string]types.AttributeValue, error) {
+	var items []map[string]types.AttributeValue
+	for start := 0; start < len(keys); start += batchGetSize {
+		end := start + batchGetSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		pending := keys[start:end]
+		for attempt := 1; len(pending) > 0; attempt++ {
+			if attempt > batchGetMaxAttempts {
+				return nil, fmt.Errorf("%d keys left unprocessed after %d attempts", len(pending), batchGetMaxAttempts)
+			}
+			if attempt > 1 {
+				select {
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				case <-time.After(time.Duration(attempt*attempt) * 50 * time.Millisecond):
+				}
+			}
+			out, err := dbClient.Client.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{
+				RequestItems: map[string]types.KeysAndAttributes{dbClient.TableName: {Keys: pending}},
+			})
+			if err != nil {
+				log.Printf("Error batch getting %d items: %v", len(pending), err)
+				return nil, fmt.Errorf("failed to batch get items: %w", err)
+			}
+			items = append(items, out.Responses[dbClient.TableName]...)
+			pending = out.UnprocessedKeys[dbClient.TableName].Keys
+		}
+	}
+	return items, nil
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -13,6 +15,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/google/uuid"
 
+	"github.com/soranjiro/axicalendar/internal/domain"
 	"github.com/soranjiro/axicalendar/internal/domain/entry"
 )
 
@@ -82,7 +85,7 @@ func (r *dynamoDBEntryRepository) GetEntryByID(ctx context.Context, userID uuid.
 
 	if foundEntry == nil {
 		log.Printf("Entry %s not found for user %s", entryID, userID)
-		return nil, errors.New("entry not found") // Consider specific error type
+		return nil, domain.ErrEntryNotFound
 	}
 
 	log.Printf("Successfully retrieved entry %s for user %s", entryID, userID)
@@ -92,7 +95,10 @@ func (r *dynamoDBEntryRepository) GetEntryByID(ctx context.Context, userID uuid.
 // ListEntriesByDateRange retrieves entries for a user within a specific date range.
 // Uses GSI1 (PK=USER#<user_id>, SK between ENTRY_DATE#<start_date> and ENTRY_DATE#<end_date>)
 // Filters by a mandatory theme ID (uses the first from the slice).
-func (r *dynamoDBEntryRepository) ListEntriesByDateRange(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time, themeID uuid.UUID) ([]entry.Entry, error) {
+// Each contains filter adds a contains() condition on a list attribute of Data. Each geo filter adds
+// begins_with() conditions on the field's geohash for the cells covering its area, and matches are then
+// checked exactly, so only matching entries are returned.
+func (r *dynamoDBEntryRepository) ListEntriesByDateRange(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time, themeID uuid.UUID, filters ...entry.Filter) ([]entry.Entry, error) {
 	gsi1pk := userGSI1PK(userID.String())
 	startSK := entryDateSKPrefix(startDate.Format("2006-01-02")) // ENTRY_DATE#YYYY-MM-DD
 	endSK := entryDateSKPrefix(endDate.Format("2006-01-02"))     // ENTRY_DATE#YYYY-MM-DD
@@ -112,12 +118,49 @@ func (r *dynamoDBEntryRepository) ListEntriesByDateRange(ctx context.Context, us
 		":endsk":   &types.AttributeValueMemberS{Value: endSK + "\uffff"}, // Use high-codepoint char for inclusive end range
 		":themeId": &types.AttributeValueMemberB{Value: themeID[:]},
 	}
+	var exprAttrNames map[string]string
+	var geoFilters []entry.GeoFilter
+	if len(filters) > 0 {
+		exprAttrNames = map[string]string{"#data": "Data"}
+		for i, filter := range filters {
+			name := fmt.Sprintf("#f%d", i)
+			switch f := filter.(type) {
+			case entry.ContainsFilter:
+				av, err := attributevalue.Marshal(f.Value)
+				if err != nil {
+					return nil, fmt.Errorf("failed to marshal filter value for field %s: %w", f.Field, err)
+				}
+				value := fmt.Sprintf(":v%d", i)
+				exprAttrNames[name] = f.Field
+				exprAttrValues[value] = av
+				filterExprStr += fmt.Sprintf(" AND contains(#data.%s, %s)", name, value)
+			case entry.GeoFilter:
+				exprAttrNames[name] = f.Field
+				geoFilters = append(geoFilters, f)
+				prefixes := f.GeohashPrefixes()
+				if len(prefixes) == 0 {
+					filterExprStr += fmt.Sprintf(" AND attribute_exists(Geohashes.%s)", name)
+					continue
+				}
+				conds := make([]string, len(prefixes))
+				for j, prefix := range prefixes {
+					value := fmt.Sprintf(":v%d_%d", i, j)
+					exprAttrValues[value] = &types.AttributeValueMemberS{Value: prefix}
+					conds[j] = fmt.Sprintf("begins_with(Geohashes.%s, %s)", name, value)
+				}
+				filterExprStr += " AND (" + strings.Join(conds, " OR ") + ")"
+			default:
+				return nil, fmt.Errorf("unsupported entry filter %T", filter)
+			}
+		}
+	}
 
 	queryInput := &dynamodb.QueryInput{
 		TableName:                 aws.String(r.dbClient.TableName),
 		IndexName:                 aws.String("GSI1"),
 		KeyConditionExpression:    aws.String(keyCondExpr),
 		FilterExpression:          aws.String(filterExprStr),
+		ExpressionAttributeNames:  exprAttrNames,
 		ExpressionAttributeValues: exprAttrValues,
 	}
 
@@ -137,7 +180,16 @@ func (r *dynamoDBEntryRepository) ListEntriesByDateRange(ctx context.Context, us
 			log.Printf("Error unmarshalling entries for user %s in date range: %v", userID, err)
 			return nil, fmt.Errorf("failed to unmarshal entry data: %w", err)
 		}
-		entries = append(entries, pageEntries...)
+		// Geohash cells cover more than the filters' areas; keep only entries within them
+		for _, e := range pageEntries {
+			matches := true
+			for _, f := range geoFilters {
+				matches = matches && f.Matches(e.Data[f.Field])
+			}
+			if matches {
+				entries = append(entries, e)
+			}
+		}
 	}
 
 	log.Printf("Successfully listed %d entries for user %s in date range", len(entries), userID)
@@ -286,14 +338,15 @@ func (r *dynamoDBEntryRepository) updateItem(ctx context.Context, entry *entry.E
 	// Construct UpdateExpression
 	// Update Data, UpdatedAt, and potentially GSI1SK if ThemeID changed (though API prevents this)
 	// Also update EntryDate attribute itself if it changed (even though SK uses original)
-	updateExpr := "SET #data = :data, UpdatedAt = :updatedAt, GSI1SK = :gsi1sk, EntryDate = :entryDate"
+	updateExpr := "SET #data = :data, UpdatedAt = :updatedAt, GSI1SK = :gsi1sk, EntryDate = :entryDate, SchemaVersion = :schemaVersion"
 	exprAttrNames := map[string]string{
 		"#data": "Data", // "Data" is not a reserved word, but good practice
 	}
 	exprAttrValues := map[string]types.AttributeValue{
-		":updatedAt": &types.AttributeValueMemberS{Value: now.Format(time.RFC3339Nano)},
-		":gsi1sk":    &types.AttributeValueMemberS{Value: entryGSI1SK(entry.EntryDate, entry.ThemeID.String())},
-		":entryDate": &types.AttributeValueMemberS{Value: entry.EntryDate},
+		":updatedAt":     &types.AttributeValueMemberS{Value: now.Format(time.RFC3339Nano)},
+		":gsi1sk":        &types.AttributeValueMemberS{Value: entryGSI1SK(entry.EntryDate, entry.ThemeID.String())},
+		":entryDate":     &types.AttributeValueMemberS{Value: entry.EntryDate},
+		":schemaVersion": &types.AttributeValueMemberN{Value: strconv.Itoa(entry.SchemaVersion)},
 	}
 
 	dataAV, err := attributevalue.MarshalMap(entry.Data)
@@ -303,6 +356,34 @@ func (r *dynamoDBEntryRepository) updateItem(ctx context.Context, entry *entry.E
 	}
 	exprAttrValues[":data"] = &types.AttributeValueMemberM{Value: dataAV}
 
+	// Keep the backlink and location indexes in step with the data; empty ones are removed
+	var removes []string
+	if len(entry.ReferencedEntryIDs) > 0 {
+		refsAV, err := attributevalue.Marshal(entry.ReferencedEntryIDs)
+		if err != nil {
+			log.Printf("Error marshalling referenced entry IDs for update %s: %v", entry.EntryID, err)
+			return fmt.Errorf("failed to marshal referenced entry IDs: %w", err)
+		}
+		updateExpr += ", ReferencedEntryIDs = :refs"
+		exprAttrValues[":refs"] = refsAV
+	} else {
+		removes = append(removes, "ReferencedEntryIDs")
+	}
+	if len(entry.Geohashes) > 0 {
+		geoAV, err := attributevalue.Marshal(entry.Geohashes)
+		if err != nil {
+			log.Printf("Error marshalling geohashes for update %s: %v", entry.EntryID, err)
+			return fmt.Errorf("failed to marshal geohashes: %w", err)
+		}
+		updateExpr += ", Geohashes = :geohashes"
+		exprAttrValues[":geohashes"] = geoAV
+	} else {
+		removes = append(removes, "Geohashes")
+	}
+	if len(removes) > 0 {
+		updateExpr += " REMOVE " + strings.Join(removes, ", ")
+	}
+
 	log.Printf("Updating item: PK=%s, SK=%s", pk, sk)
 
 	updateInput := &dynamodb.UpdateItemInput{
@@ -323,7 +404,7 @@ func (r *dynamoDBEntryRepository) updateItem(ctx context.Context, entry *entry.E
 		var condCheckFailed *types.ConditionalCheckFailedException
 		if errors.As(err, &condCheckFailed) {
 			log.Printf("Conditional check failed updating item %s: %v", entry.EntryID, err)
-			return errors.New("entry not found")
+			return domain.ErrEntryNotFound
 		}
 		log.Printf("Error updating item %s: %v", entry.EntryID, err)
 		return fmt.Errorf("failed to update entry item: %w", err)
@@ -436,7 +517,7 @@ func (r *dynamoDBEntryRepository) DeleteEntry(ctx context.Context, userID uuid.U
 		var condCheckFailed *types.ConditionalCheckFailedException
 		if errors.As(err, &condCheckFailed) {
 			log.Printf("Conditional check failed deleting entry %s: %v", entryID, err)
-			return errors.New("entry not found")
+			return domain.ErrEntryNotFound
 		}
 		log.Printf("Error deleting entry %s: %v", entryID, err)
 		return fmt.Errorf("failed to delete entry: %w", err)
@@ -445,3 +526,129 @@ func (r *dynamoDBEntryRepository) DeleteEntry(ctx context.Context, userID uuid.U
 	log.Printf("Successfully deleted entry %s for user %s", entryID, userID)
 	return nil
 }
+
+// ListBacklinks retrieves the user's entries whose reference fields point at the entry.
+// Uses GSI1 (PK=USER#<user_id>) filtered on the ReferencedEntryIDs index kept on each entry.
+func (r *dynamoDBEntryRepository) ListBacklinks(ctx context.Context, userID uuid.UUID, entryID uuid.UUID) ([]entry.Entry, error) {
+	if userID == uuid.Nil || entryID == uuid.Nil {
+		return nil, errors.New("user ID and entry ID are required to list backlinks")
+	}
+	log.Printf("Listing backlinks of entry %s for user %s", entryID, userID)
+
+	queryInput := &dynamodb.QueryInput{
+		TableName:              aws.String(r.dbClient.TableName),
+		IndexName:              aws.String("GSI1"),
+		KeyConditionExpression: aws.String("GSI1PK = :pkval"),
+		FilterExpression:       aws.String("contains(ReferencedEntryIDs, :entryId)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pkval":   &types.AttributeValueMemberS{Value: userGSI1PK(userID.String())},
+			":entryId": &types.AttributeValueMemberS{Value: entryID.String()},
+		},
+	}
+
+	paginator := dynamodb.NewQueryPaginator(r.dbClient.Client, queryInput)
+
+	var entries []entry.Entry
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Error querying backlinks of entry %s, user %s: %v", entryID, userID, err)
+			return nil, fmt.Errorf("failed to query backlinks: %w", err)
+		}
+
+		var pageEntries []entry.Entry
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &pageEntries); err != nil {
+			log.Printf("Error unmarshalling backlinks of entry %s, user %s: %v", entryID, userID, err)
+			return nil, fmt.Errorf("failed to unmarshal entry data: %w", err)
+		}
+		entries = append(entries, pageEntries...)
+	}
+
+	log.Printf("Found %d backlinks of entry %s for user %s", len(entries), entryID, userID)
+	return entries, nil
+}
+
+// ListEntriesBelowSchemaVersion retrieves a theme's entries written with a schema version older than version.
+// Uses GSI1 (PK=USER#<user_id>, SK starts with ENTRY_DATE#) filtered by ThemeID and SchemaVersion;
+// entries written before versioning have no SchemaVersion and count as version 0.
+func (r *dynamoDBEntryRepository) ListEntriesBelowSchemaVersion(ctx context.Context, userID uuid.UUID, themeID uuid.UUID, version int) ([]entry.Entry, error) {
+	if userID == uuid.Nil || themeID == uuid.Nil {
+		return nil, errors.New("user ID and theme ID are required to list entries to migrate")
+	}
+	log.Printf("Listing entries of theme %s for user %s below schema version %d", themeID, userID, version)
+
+	queryInput := &dynamodb.QueryInput{
+		TableName:              aws.String(r.dbClient.TableName),
+		IndexName:              aws.String("GSI1"),
+		KeyConditionExpression: aws.String("GSI1PK = :pkval AND begins_with(GSI1SK, :skprefix)"),
+		FilterExpression:       aws.String("ThemeID = :themeId AND (attribute_not_exists(SchemaVersion) OR SchemaVersion < :version)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pkval":    &types.AttributeValueMemberS{Value: userGSI1PK(userID.String())},
+			":skprefix": &types.AttributeValueMemberS{Value: entryDateSKPrefix("")},
+			":themeId":  &types.AttributeValueMemberB{Value: themeID[:]},
+			":version":  &types.AttributeValueMemberN{Value: strconv.Itoa(version)},
+		},
+	}
+
+	paginator := dynamodb.NewQueryPaginator(r.dbClient.Client, queryInput)
+
+	var entries []entry.Entry
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Error querying entries of theme %s below schema version %d: %v", themeID, version, err)
+			return nil, fmt.Errorf("failed to query entries to migrate: %w", err)
+		}
+
+		var pageEntries []entry.Entry
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &pageEntries); err != nil {
+			log.Printf("Error unmarshalling entries of theme %s to migrate: %v", themeID, err)
+			return nil, fmt.Errorf("failed to unmarshal entry data: %w", err)
+		}
+		entries = append(entries, pageEntries...)
+	}
+
+	log.Printf("Found %d entries of theme %s below schema version %d", len(entries), themeID, version)
+	return entries, nil
+}
+
+// PutMigratedEntries writes entries migrated to a newer schema version one by one, each on condition
+// that the stored entry still exists and is below the entry's new version. Entries deleted or rewritten
+// at the new version since they were read are skipped rather than recreated or overwritten.
+// The entries must have been read from the table, so their keys are set.
+// Returns the number of entries written.
+func (r *dynamoDBEntryRepository) PutMigratedEntries(ctx context.Context, entries []entry.Entry) (int, error) {
+	now := time.Now()
+	written := 0
+	for _, e := range entries {
+		if e.PK == "" || e.SK == "" {
+			return written, fmt.Errorf("entry %s has no keys", e.EntryID)
+		}
+		e.UpdatedAt = now
+		item, err := attributevalue.MarshalMap(e)
+		if err != nil {
+			log.Printf("Error marshalling migrated entry %s: %v", e.EntryID, err)
+			return written, fmt.Errorf("failed to marshal entry: %w", err)
+		}
+		_, err = r.dbClient.Client.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName:           aws.String(r.dbClient.TableName),
+			Item:                item,
+			ConditionExpression: aws.String("attribute_exists(PK) AND (attribute_not_exists(SchemaVersion) OR SchemaVersion < :version)"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":version": &types.AttributeValueMemberN{Value: strconv.Itoa(e.SchemaVersion)},
+			},
+		})
+		if err != nil {
+			var condCheckFailed *types.ConditionalCheckFailedException
+			if errors.As(err, &condCheckFailed) {
+				log.Printf("Skipped migrating entry %s: deleted or already migrated", e.EntryID)
+				continue
+			}
+			log.Printf("Error writing migrated entry %s: %v", e.EntryID, err)
+			return written, fmt.Errorf("failed to write migrated entry %s: %w", e.EntryID, err)
+		}
+		written++
+	}
+	log.Printf("Successfully wrote %d of %d migrated entries", written, len(entries))
+	return written, nil
+}
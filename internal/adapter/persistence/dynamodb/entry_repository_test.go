@@ -3,6 +3,7 @@ package dynamodbrepo
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -159,6 +160,71 @@ func TestDynamoDBEntryRepository_ListEntriesByDateRange_EmptyThemeIDs(t *testing
 	assert.EqualError(t, err, "theme ID is required to filter entries")
 }
 
+func TestDynamoDBEntryRepository_ListEntriesByDateRange_ContainsFilters(t *testing.T) {
+	repo, mockDB := setupEntryRepoTest()
+	ctx := context.Background()
+	testUserID := uuid.New()
+	themeID := uuid.New()
+	startDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	entry1 := entry.Entry{EntryID: uuid.New(), UserID: testUserID, EntryDate: "2024-01-10", ThemeID: themeID,
+		Data: map[string]interface{}{"tags": []interface{}{"home", "urgent"}, "scores": []interface{}{3.0}}}
+	item1, _ := attributevalue.MarshalMap(entry1)
+
+	mockDB.On("Query", ctx, mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+		return *input.FilterExpression == "ThemeID = :themeId AND contains(#data.#f0, :v0) AND contains(#data.#f1, :v1)" &&
+			assert.ObjectsAreEqual(map[string]string{"#data": "Data", "#f0": "tags", "#f1": "scores"}, input.ExpressionAttributeNames) &&
+			assert.ObjectsAreEqual(&types.AttributeValueMemberS{Value: "urgent"}, input.ExpressionAttributeValues[":v0"]) &&
+			assert.ObjectsAreEqual(&types.AttributeValueMemberN{Value: "3"}, input.ExpressionAttributeValues[":v1"])
+	})).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{item1}, Count: 1}, nil)
+
+	entries, err := repo.ListEntriesByDateRange(ctx, testUserID, startDate, endDate, themeID,
+		entry.ContainsFilter{Field: "tags", Value: "urgent"}, entry.ContainsFilter{Field: "scores", Value: 3.0})
+
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	// List values are stored as DynamoDB lists and read back as []interface{}
+	assert.IsType(t, &types.AttributeValueMemberL{}, item1["Data"].(*types.AttributeValueMemberM).Value["tags"])
+	assert.Equal(t, []interface{}{"home", "urgent"}, entries[0].Data["tags"])
+	mockDB.AssertExpectations(t)
+}
+
+func TestDynamoDBEntryRepository_ListEntriesByDateRange_GeoFilters(t *testing.T) {
+	repo, mockDB := setupEntryRepoTest()
+	ctx := context.Background()
+	testUserID := uuid.New()
+	themeID := uuid.New()
+	startDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	// Geohash cells only narrow the query; entries it returns outside the radius are left out
+	near := entry.Entry{EntryID: uuid.New(), UserID: testUserID, EntryDate: "2024-01-10", ThemeID: themeID,
+		Data: map[string]interface{}{"place": map[string]interface{}{"lat": 35.6812, "lng": 139.7671, "label": "Tokyo Station"}}}
+	far := entry.Entry{EntryID: uuid.New(), UserID: testUserID, EntryDate: "2024-01-11", ThemeID: themeID,
+		Data: map[string]interface{}{"place": map[string]interface{}{"lat": 35.6896, "lng": 139.7006}}}
+	var items []map[string]types.AttributeValue
+	for _, e := range []entry.Entry{near, far} {
+		item, _ := attributevalue.MarshalMap(e)
+		items = append(items, item)
+	}
+	filter, err := entry.NewRadiusFilter("place", 35.6812, 139.7671, 1000)
+	assert.NoError(t, err)
+
+	mockDB.On("Query", ctx, mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+		return strings.HasPrefix(*input.FilterExpression, "ThemeID = :themeId AND (begins_with(Geohashes.#f0, :v0_0) OR ") &&
+			input.ExpressionAttributeNames["#f0"] == "place"
+	})).Return(&dynamodb.QueryOutput{Items: items, Count: 2}, nil)
+
+	entries, err := repo.ListEntriesByDateRange(ctx, testUserID, startDate, endDate, themeID, filter)
+
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, near.EntryID, entries[0].EntryID)
+	}
+	mockDB.AssertExpectations(t)
+}
+
 func TestDynamoDBEntryRepository_CreateEntry_Success(t *testing.T) {
 	repo, mockDB := setupEntryRepoTest()
 	ctx := context.Background()
@@ -200,4 +266,115 @@ func TestDynamoDBEntryRepository_CreateEntry_AlreadyExists(t *testing.T) {
 	mockDB.AssertExpectations(t)
 }
 
+func TestDynamoDBEntryRepository_UpdateEntry_IndexesReferencesAndGeohashes(t *testing.T) {
+	repo, mockDB := setupEntryRepoTest()
+	ctx := context.Background()
+	testUserID := uuid.New()
+	bookID := uuid.New().String()
+	existing := entry.Entry{EntryID: uuid.New(), UserID: testUserID, ThemeID: uuid.New(), EntryDate: "2024-01-15"}
+	item, _ := attributevalue.MarshalMap(existing)
+
+	mockDB.On("Query", ctx, mock.AnythingOfType("*dynamodb.QueryInput")).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{item}, Count: 1}, nil)
+	mockDB.On("UpdateItem", ctx, mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
+		return *input.UpdateExpression == "SET #data = :data, UpdatedAt = :updatedAt, GSI1SK = :gsi1sk, EntryDate = :entryDate, SchemaVersion = :schemaVersion, ReferencedEntryIDs = :refs, Geohashes = :geohashes" &&
+			assert.ObjectsAreEqual(&types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberS{Value: bookID}}}, input.ExpressionAttributeValues[":refs"]) &&
+			assert.ObjectsAreEqual(&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{"place": &types.AttributeValueMemberS{Value: "xn76urx6q"}}}, input.ExpressionAttributeValues[":geohashes"])
+	})).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+	mockDB.On("UpdateItem", ctx, mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
+		return *input.UpdateExpression == "SET #data = :data, UpdatedAt = :updatedAt, GSI1SK = :gsi1sk, EntryDate = :entryDate, SchemaVersion = :schemaVersion REMOVE ReferencedEntryIDs, Geohashes"
+	})).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+
+	updated := existing
+	updated.Data = map[string]interface{}{"book": bookID}
+	updated.ReferencedEntryIDs = []string{bookID}
+	updated.Geohashes = map[string]string{"place": "xn76urx6q"}
+	assert.NoError(t, repo.UpdateEntry(ctx, &updated))
+
+	updated.Data = map[string]interface{}{"book": nil}
+	updated.ReferencedEntryIDs = nil
+	updated.Geohashes = nil
+	assert.NoError(t, repo.UpdateEntry(ctx, &updated))
+	mockDB.AssertExpectations(t)
+}
+
+func TestDynamoDBEntryRepository_ListBacklinks(t *testing.T) {
+	repo, mockDB := setupEntryRepoTest()
+	ctx := context.Background()
+	testUserID := uuid.New()
+	bookID := uuid.New()
+
+	log := entry.Entry{EntryID: uuid.New(), UserID: testUserID, EntryDate: "2024-01-10", ThemeID: uuid.New(),
+		Data: map[string]interface{}{"book": bookID.String()}, ReferencedEntryIDs: []string{bookID.String()}}
+	item, _ := attributevalue.MarshalMap(log)
+
+	mockDB.On("Query", ctx, mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+		return *input.IndexName == "GSI1" &&
+			*input.FilterExpression == "contains(ReferencedEntryIDs, :entryId)" &&
+			assert.ObjectsAreEqual(&types.AttributeValueMemberS{Value: bookID.String()}, input.ExpressionAttributeValues[":entryId"])
+	})).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{item}, Count: 1}, nil)
+
+	entries, err := repo.ListBacklinks(ctx, testUserID, bookID)
+
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, log.EntryID, entries[0].EntryID)
+	assert.Equal(t, []string{bookID.String()}, entries[0].ReferencedEntryIDs)
+	mockDB.AssertExpectations(t)
+}
+
+func TestDynamoDBEntryRepository_ListEntriesBelowSchemaVersion(t *testing.T) {
+	repo, mockDB := setupEntryRepoTest()
+	ctx := context.Background()
+	testUserID := uuid.New()
+	testThemeID := uuid.New()
+
+	stale := entry.Entry{EntryID: uuid.New(), UserID: testUserID, ThemeID: testThemeID, EntryDate: "2024-01-10", SchemaVersion: 1}
+	item, _ := attributevalue.MarshalMap(stale)
+
+	mockDB.On("Query", ctx, mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+		return *input.IndexName == "GSI1" &&
+			*input.FilterExpression == "ThemeID = :themeId AND (attribute_not_exists(SchemaVersion) OR SchemaVersion < :version)" &&
+			assert.ObjectsAreEqual(&types.AttributeValueMemberN{Value: "2"}, input.ExpressionAttributeValues[":version"])
+	})).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{item}, Count: 1}, nil)
+
+	entries, err := repo.ListEntriesBelowSchemaVersion(ctx, testUserID, testThemeID, 2)
+
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, 1, entries[0].SchemaVersion)
+	mockDB.AssertExpectations(t)
+}
+
+func TestDynamoDBEntryRepository_PutMigratedEntries(t *testing.T) {
+	repo, mockDB := setupEntryRepoTest()
+	ctx := context.Background()
+	testUserID := uuid.New()
+
+	entries := make([]entry.Entry, 3)
+	for i := range entries {
+		id := uuid.New()
+		entries[i] = entry.Entry{EntryID: id, UserID: testUserID, EntryDate: "2024-01-10", SchemaVersion: 2,
+			PK: "USER#" + testUserID.String(), SK: "ENTRY#2024-01-10#" + id.String()}
+	}
+	putOf := func(e entry.Entry) interface{} {
+		return mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+			sk, _ := input.Item["SK"].(*types.AttributeValueMemberS)
+			version, _ := input.ExpressionAttributeValues[":version"].(*types.AttributeValueMemberN)
+			return sk != nil && sk.Value == e.SK && version != nil && version.Value == "2" &&
+				*input.ConditionExpression == "attribute_exists(PK) AND (attribute_not_exists(SchemaVersion) OR SchemaVersion < :version)"
+		})
+	}
+
+	// The second entry was deleted by its user during the migration pass, so its write fails the condition
+	mockDB.On("PutItem", ctx, putOf(entries[0])).Return(&dynamodb.PutItemOutput{}, nil).Once()
+	mockDB.On("PutItem", ctx, putOf(entries[1])).Return(nil, &types.ConditionalCheckFailedException{}).Once()
+	mockDB.On("PutItem", ctx, putOf(entries[2])).Return(&dynamodb.PutItemOutput{}, nil).Once()
+
+	written, err := repo.PutMigratedEntries(ctx, entries)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, written)
+	mockDB.AssertExpectations(t)
+}
+
 // --- Add tests for UpdateEntry (date change and no date change), DeleteEntry ---
@@ -0,0 +1,156 @@
+package dynamodbrepo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+)
+
+// featureCacheItem is the stored form of a cached feature result.
+// Results are kept as JSON because their structure depends on the feature.
+type featureCacheItem struct {
+	PK          string `dynamodbav:"PK"` // USER#<user_id>
+	SK          string `dynamodbav:"SK"` // FEATURE_CACHE#<theme_id>#<feature_name>#<start_date>#<end_date>#<config_hash>
+	ThemeID     string `dynamodbav:"ThemeID"`
+	FeatureName string `dynamodbav:"FeatureName"`
+	WindowStart string `dynamodbav:"WindowStart"`         // YYYY-MM-DD, used to find results to invalidate
+	WindowEnd   string `dynamodbav:"WindowEnd"`           // YYYY-MM-DD
+	Result      string `dynamodbav:"Result"`              // JSON-encoded feature.AnalysisResult
+	ExpiresAt   int64  `dynamodbav:"ExpiresAt,omitempty"` // Unix seconds, used as the table's TTL attribute
+}
+
+// dynamoDBFeatureCache implements the feature.ResultCache interface using DynamoDB.
+type dynamoDBFeatureCache struct {
+	dbClient *DynamoDBClient
+	ttl      time.Duration
+}
+
+// NewFeatureResultCache creates a new DynamoDB-backed feature.ResultCache.
+// Results expire after ttl; a ttl of zero keeps them until invalidated.
+func NewFeatureResultCache(dbClient *DynamoDBClient, ttl time.Duration) feature.ResultCache {
+	return &dynamoDBFeatureCache{dbClient: dbClient, ttl: ttl}
+}
+
+// cacheItemSK generates the SK of the item holding the result for key.
+func cacheItemSK(key feature.CacheKey) string {
+	return featureCacheSK(key.ThemeID.String(), key.FeatureName,
+		key.Window.Start.Format("2006-01-02"), key.Window.End.Format("2006-01-02"), key.ConfigHash)
+}
+
+// Get returns the cached result for key.
+// Expired items are treated as misses, since DynamoDB TTL deletion is not immediate.
+func (c *dynamoDBFeatureCache) Get(ctx context.Context, key feature.CacheKey) (feature.AnalysisResult, bool, error) {
+	out, err := c.dbClient.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(c.dbClient.TableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: userPK(key.UserID.String())},
+			"SK": &types.AttributeValueMemberS{Value: cacheItemSK(key)},
+		},
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get cached feature result: %w", err)
+	}
+	if out.Item == nil {
+		return nil, false, nil
+	}
+	var item featureCacheItem
+	if err := attributevalue.UnmarshalMap(out.Item, &item); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal cached feature result: %w", err)
+	}
+	if item.ExpiresAt > 0 && time.Now().Unix() >= item.ExpiresAt {
+		return nil, false, nil
+	}
+	var result feature.AnalysisResult
+	if err := json.Unmarshal([]byte(item.Result), &result); err != nil {
+		return nil, false, fmt.Errorf("failed to decode cached feature result: %w", err)
+	}
+	return result, true, nil
+}
+
+// Set stores the result for key.
+func (c *dynamoDBFeatureCache) Set(ctx context.Context, key feature.CacheKey, result feature.AnalysisResult) error {
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode feature result: %w", err)
+	}
+	item := featureCacheItem{
+		PK:          userPK(key.UserID.String()),
+		SK:          cacheItemSK(key),
+		ThemeID:     key.ThemeID.String(),
+		FeatureName: key.FeatureName,
+		WindowStart: key.Window.Start.Format("2006-01-02"),
+		WindowEnd:   key.Window.End.Format("2006-01-02"),
+		Result:      string(encoded),
+	}
+	if c.ttl > 0 {
+		item.ExpiresAt = time.Now().Add(c.ttl).Unix()
+	}
+	av, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cached feature result: %w", err)
+	}
+	if _, err := c.dbClient.Client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(c.dbClient.TableName),
+		Item:      av,
+	}); err != nil {
+		return fmt.Errorf("failed to store cached feature result: %w", err)
+	}
+	return nil
+}
+
+// Invalidate removes the cached results of the theme whose window contains date.
+func (c *dynamoDBFeatureCache) Invalidate(ctx context.Context, userID, themeID uuid.UUID, date string) error {
+	return c.deleteResults(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(c.dbClient.TableName),
+		KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :skPrefix)"),
+		FilterExpression:       aws.String("WindowStart <= :date AND WindowEnd >= :date"),
+		ProjectionExpression:   aws.String("PK, SK"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":       &types.AttributeValueMemberS{Value: userPK(userID.String())},
+			":skPrefix": &types.AttributeValueMemberS{Value: featureCacheThemePrefix(themeID.String())},
+			":date":     &types.AttributeValueMemberS{Value: date},
+		},
+	})
+}
+
+// InvalidateTheme removes all cached results of the theme.
+func (c *dynamoDBFeatureCache) InvalidateTheme(ctx context.Context, userID, themeID uuid.UUID) error {
+	return c.deleteResults(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(c.dbClient.TableName),
+		KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :skPrefix)"),
+		ProjectionExpression:   aws.String("PK, SK"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":       &types.AttributeValueMemberS{Value: userPK(userID.String())},
+			":skPrefix": &types.AttributeValueMemberS{Value: featureCacheThemePrefix(themeID.String())},
+		},
+	})
+}
+
+// deleteResults deletes the cached results queryInput finds.
+func (c *dynamoDBFeatureCache) deleteResults(ctx context.Context, queryInput *dynamodb.QueryInput) error {
+	paginator := dynamodb.NewQueryPaginator(c.dbClient.Client, queryInput)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to query cached feature results: %w", err)
+		}
+		for _, item := range page.Items {
+			if _, err := c.dbClient.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+				TableName: aws.String(c.dbClient.TableName),
+				Key:       map[string]types.AttributeValue{"PK": item["PK"], "SK": item["SK"]},
+			}); err != nil {
+				return fmt.Errorf("failed to delete cached feature result: %w", err)
+			}
+		}
+	}
+	return nil
+}
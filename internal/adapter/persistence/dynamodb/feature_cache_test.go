@@ -0,0 +1,121 @@
+package dynamodbrepo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+)
+
+func setupFeatureCacheTest() (feature.ResultCache, *MockDynamoDBAPI) {
+	mockDB := new(MockDynamoDBAPI)
+	dbClient := &DynamoDBClient{Client: mockDB, TableName: "test-table"}
+	return NewFeatureResultCache(dbClient, time.Hour), mockDB
+}
+
+func TestDynamoDBFeatureCache_SetAndGet(t *testing.T) {
+	cache, mockDB := setupFeatureCacheTest()
+	ctx := context.Background()
+	key := feature.CacheKey{
+		UserID:      uuid.New(),
+		ThemeID:     uuid.New(),
+		FeatureName: "monthly_summary",
+		ConfigHash:  "abc123",
+		Window:      feature.MonthWindow(time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)),
+	}
+	expectedSK := "FEATURE_CACHE#" + key.ThemeID.String() + "#monthly_summary#2025-05-01#2025-05-31#abc123"
+
+	var stored map[string]types.AttributeValue
+	mockDB.On("PutItem", ctx, mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+		var item featureCacheItem
+		if err := attributevalue.UnmarshalMap(input.Item, &item); err != nil {
+			return false
+		}
+		stored = input.Item
+		return item.PK == userPK(key.UserID.String()) &&
+			item.SK == expectedSK &&
+			item.WindowStart == "2025-05-01" && item.WindowEnd == "2025-05-31" &&
+			item.ExpiresAt > time.Now().Unix()
+	})).Return(&dynamodb.PutItemOutput{}, nil).Once()
+
+	err := cache.Set(ctx, key, feature.AnalysisResult{"total_entries": 3})
+	assert.NoError(t, err)
+
+	mockDB.On("GetItem", ctx, mock.MatchedBy(func(input *dynamodb.GetItemInput) bool {
+		sk, ok := input.Key["SK"].(*types.AttributeValueMemberS)
+		return ok && sk.Value == expectedSK
+	})).Return(&dynamodb.GetItemOutput{Item: stored}, nil).Once() // Return the item written by Set
+
+	result, ok, err := cache.Get(ctx, key)
+
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 3.0, result["total_entries"]) // Results round-trip through JSON
+	mockDB.AssertExpectations(t)
+}
+
+func TestDynamoDBFeatureCache_Invalidate(t *testing.T) {
+	cache, mockDB := setupFeatureCacheTest()
+	ctx := context.Background()
+	userID := uuid.New()
+	themeID := uuid.New()
+	cachedSK := "FEATURE_CACHE#" + themeID.String() + "#monthly_summary#2025-05-01#2025-05-31#abc123"
+
+	mockDB.On("Query", ctx, mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+		prefix, ok := input.ExpressionAttributeValues[":skPrefix"].(*types.AttributeValueMemberS)
+		date, dateOk := input.ExpressionAttributeValues[":date"].(*types.AttributeValueMemberS)
+		return ok && prefix.Value == "FEATURE_CACHE#"+themeID.String()+"#" &&
+			dateOk && date.Value == "2025-05-20"
+	})).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{{
+		"PK": &types.AttributeValueMemberS{Value: userPK(userID.String())},
+		"SK": &types.AttributeValueMemberS{Value: cachedSK},
+	}}}, nil).Once()
+	mockDB.On("DeleteItem", ctx, mock.MatchedBy(func(input *dynamodb.DeleteItemInput) bool {
+		sk, ok := input.Key["SK"].(*types.AttributeValueMemberS)
+		return ok && sk.Value == cachedSK
+	})).Return(&dynamodb.DeleteItemOutput{}, nil).Once()
+
+	err := cache.Invalidate(ctx, userID, themeID, "2025-05-20")
+
+	assert.NoError(t, err)
+	mockDB.AssertExpectations(t)
+}
+
+func TestDynamoDBFeatureCache_InvalidateTheme(t *testing.T) {
+	cache, mockDB := setupFeatureCacheTest()
+	ctx := context.Background()
+	userID := uuid.New()
+	themeID := uuid.New()
+	cachedSKs := []string{
+		"FEATURE_CACHE#" + themeID.String() + "#monthly_summary#2025-05-01#2025-05-31#abc123",
+		"FEATURE_CACHE#" + themeID.String() + "#monthly_summary#2025-06-01#2025-06-30#abc123",
+	}
+
+	mockDB.On("Query", ctx, mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+		prefix, ok := input.ExpressionAttributeValues[":skPrefix"].(*types.AttributeValueMemberS)
+		return ok && prefix.Value == "FEATURE_CACHE#"+themeID.String()+"#" && input.FilterExpression == nil
+	})).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{
+		{"PK": &types.AttributeValueMemberS{Value: userPK(userID.String())}, "SK": &types.AttributeValueMemberS{Value: cachedSKs[0]}},
+		{"PK": &types.AttributeValueMemberS{Value: userPK(userID.String())}, "SK": &types.AttributeValueMemberS{Value: cachedSKs[1]}},
+	}}, nil).Once()
+	for _, cachedSK := range cachedSKs {
+		cachedSK := cachedSK
+		mockDB.On("DeleteItem", ctx, mock.MatchedBy(func(input *dynamodb.DeleteItemInput) bool {
+			sk, ok := input.Key["SK"].(*types.AttributeValueMemberS)
+			return ok && sk.Value == cachedSK
+		})).Return(&dynamodb.DeleteItemOutput{}, nil).Once()
+	}
+
+	err := cache.InvalidateTheme(ctx, userID, themeID)
+
+	assert.NoError(t, err)
+	mockDB.AssertExpectations(t)
+}
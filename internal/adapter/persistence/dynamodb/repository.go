@@ -2,6 +2,7 @@ package dynamodbrepo
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/soranjiro/axicalendar/internal/domain/entry"
@@ -13,28 +14,62 @@ import (
 // EntryRepository defines the interface for entry data operations.
 type EntryRepository interface {
 	GetEntryByID(ctx context.Context, userID uuid.UUID, entryID uuid.UUID) (*entry.Entry, error)
-	ListEntriesByDateRange(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time, themeID uuid.UUID) ([]entry.Entry, error)
+	// ListEntriesByDateRange retrieves a theme's entries in the range whose list fields hold all the filters' items.
+	ListEntriesByDateRange(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time, themeID uuid.UUID, filters ...entry.Filter) ([]entry.Entry, error)
 	// GetEntriesForSummary retrieves entries for a specific user, theme, and year-month (YYYY-MM).
 	GetEntriesForSummary(ctx context.Context, userID uuid.UUID, themeID uuid.UUID, yearMonth string) ([]entry.Entry, error)
 	CreateEntry(ctx context.Context, entry *entry.Entry) error
 	UpdateEntry(ctx context.Context, entry *entry.Entry) error
 	// DeleteEntry requires entryDate because it's part of the SK.
 	DeleteEntry(ctx context.Context, userID uuid.UUID, entryID uuid.UUID, entryDate string) error
+	// ListBacklinks retrieves the user's entries whose reference fields point at the entry.
+	ListBacklinks(ctx context.Context, userID uuid.UUID, entryID uuid.UUID) ([]entry.Entry, error)
+	// ListEntriesBelowSchemaVersion retrieves a theme's entries written with a schema version older than version.
+	ListEntriesBelowSchemaVersion(ctx context.Context, userID uuid.UUID, themeID uuid.UUID, version int) ([]entry.Entry, error)
+	// PutMigratedEntries writes entries migrated to a newer schema version, skipping those deleted or
+	// already migrated since they were read. Returns the number of entries written.
+	PutMigratedEntries(ctx context.Context, entries []entry.Entry) (int, error)
 }
 
 // ThemeRepository defines the interface for theme data operations.
 type ThemeRepository interface {
 	GetThemeByID(ctx context.Context, userID uuid.UUID, themeID uuid.UUID) (*theme.Theme, error)
 	ListThemes(ctx context.Context, userID uuid.UUID) ([]theme.Theme, error)
+	// ListDefaultThemes retrieves the default themes offered to every user.
+	ListDefaultThemes(ctx context.Context) ([]theme.Theme, error)
+	// PutDefaultTheme seeds a default theme as revision theme.Revision, which must follow the stored one
+	// (theme.InitialRevision if the theme is not stored yet). Returns domain.ErrConflict otherwise.
+	PutDefaultTheme(ctx context.Context, theme *theme.Theme) error
+	// ListScheduledThemes retrieves the custom themes of all users that schedule at least one feature.
+	ListScheduledThemes(ctx context.Context) ([]theme.Theme, error)
 	CreateTheme(ctx context.Context, theme *theme.Theme) error
+	// UpdateTheme saves the theme as its revision theme.Revision, which must follow the stored one.
+	// Returns domain.ErrConflict if the theme was updated since it was read.
 	UpdateTheme(ctx context.Context, theme *theme.Theme) error
 	DeleteTheme(ctx context.Context, userID uuid.UUID, themeID uuid.UUID) error
+	// ListThemeRevisions retrieves the revisions of a theme, newest first.
+	ListThemeRevisions(ctx context.Context, themeID uuid.UUID) ([]theme.Revision, error)
+	// GetThemeRevision retrieves one revision of a theme.
+	GetThemeRevision(ctx context.Context, themeID uuid.UUID, revision int) (*theme.Revision, error)
+	// PutThemeRevision stores a revision. Returns domain.ErrAlreadyExists if the revision is already stored.
+	PutThemeRevision(ctx context.Context, revision *theme.Revision) error
 	// AddUserThemeLink creates a link item allowing a user to access a theme.
 	AddUserThemeLink(ctx context.Context, link *theme.UserThemeLink) error
 	// RemoveUserThemeLink removes the link item, revoking user access to a theme.
 	RemoveUserThemeLink(ctx context.Context, userID, themeID uuid.UUID) error
 	// ListUserThemes retrieves the UserThemeLink items for a user.
 	ListUserThemes(ctx context.Context, userID uuid.UUID) ([]theme.UserThemeLink, error)
+	// ListThemeMembers retrieves the UserThemeLink items of a theme, one per user it is linked to.
+	ListThemeMembers(ctx context.Context, themeID uuid.UUID) ([]theme.UserThemeLink, error)
+	// PutThemeInvitation stores an invitation, replacing a pending one for the same user and theme.
+	PutThemeInvitation(ctx context.Context, invitation *theme.Invitation) error
+	// ListThemeInvitations retrieves the pending invitations of a user.
+	ListThemeInvitations(ctx context.Context, userID uuid.UUID) ([]theme.Invitation, error)
+	// AcceptThemeInvitation replaces the user's invitation to the theme with a link granting its role.
+	// Returns domain.ErrNotFound if there is no such invitation.
+	AcceptThemeInvitation(ctx context.Context, userID, themeID uuid.UUID) (*theme.UserThemeLink, error)
+	// DeleteThemeInvitation removes an invitation. Removing one that does not exist is not an error.
+	DeleteThemeInvitation(ctx context.Context, userID, themeID uuid.UUID) error
 }
 
 // --- Helper Functions for Key Generation ---
@@ -85,8 +120,97 @@ func themeMetadataSK() string {
 	return "METADATA"
 }
 
+// themeRevisionSKPrefix is the SK prefix of a theme's revision items.
+const themeRevisionSKPrefix = "REV#"
+
+// themeRevisionSK generates the SK for a theme revision item.
+// The number is zero-padded so revisions sort numerically.
+// SK: REV#<revision>
+func themeRevisionSK(revision int) string {
+	return fmt.Sprintf("%s%010d", themeRevisionSKPrefix, revision)
+}
+
+// defaultThemesGSI1PK is the GSI1PK shared by the metadata items of default themes,
+// so they can be listed with a query instead of a scan.
+const defaultThemesGSI1PK = "DEFAULT#THEME"
+
+// defaultThemeGSI1SK generates the GSI1SK for a default theme's metadata item.
+// GSI1SK: THEME#<theme_id>
+func defaultThemeGSI1SK(themeID string) string {
+	return "THEME#" + themeID
+}
+
+// scheduledThemesGSI1PK is the GSI1PK of the metadata items of custom themes that schedule a feature,
+// so the scheduler can list them with a query instead of a scan.
+const scheduledThemesGSI1PK = "SCHEDULED#THEME"
+
+// scheduledThemeGSI1SK generates the GSI1SK for a scheduled custom theme's metadata item.
+// GSI1SK: THEME#<theme_id>
+func scheduledThemeGSI1SK(themeID string) string {
+	return "THEME#" + themeID
+}
+
+// userThemeLinkSKPrefix is the SK prefix of a user's theme link items.
+const userThemeLinkSKPrefix = "THEME#"
+
 // userThemeLinkSK generates the SK for a user-theme link item.
 // SK: THEME#<theme_id>
 func userThemeLinkSK(themeID string) string {
-	return "THEME#" + themeID
+	return userThemeLinkSKPrefix + themeID
+}
+
+// themeMemberGSI1PK generates the GSI1PK for a user-theme link item, listing the theme's members.
+// GSI1PK: THEME#<theme_id>
+func themeMemberGSI1PK(themeID string) string {
+	return themePK(themeID)
+}
+
+// themeMemberGSI1SK generates the GSI1SK for a user-theme link item.
+// GSI1SK: MEMBER#<user_id>
+func themeMemberGSI1SK(userID string) string {
+	return "MEMBER#" + userID
+}
+
+// invitationSKPrefix is the SK prefix of a user's pending theme invitations.
+const invitationSKPrefix = "INVITE#"
+
+// invitationSK generates the SK for a theme invitation item, stored in the invitee's partition.
+// SK: INVITE#<theme_id>
+func invitationSK(themeID string) string {
+	return invitationSKPrefix + themeID
+}
+
+// --- Feature Cache Key Functions ---
+
+// featureCacheThemePrefix generates the SK prefix of the cached feature results of a theme.
+// SK prefix: FEATURE_CACHE#<theme_id>#
+func featureCacheThemePrefix(themeID string) string {
+	return "FEATURE_CACHE#" + themeID + "#"
+}
+
+// featureCacheSK generates the SK for a cached feature result item.
+// SK: FEATURE_CACHE#<theme_id>#<feature_name>#<start_date>#<end_date>#<config_hash>
+func featureCacheSK(themeID, featureName, startDate, endDate, configHash string) string {
+	return featureCacheThemePrefix(themeID) + featureName + "#" + startDate + "#" + endDate + "#" + configHash
+}
+
+// --- Feature Snapshot Key Functions ---
+
+// featureSnapshotPrefix generates the SK prefix of the snapshots of a theme's feature.
+// SK prefix: SNAPSHOT#<theme_id>#<feature_name>#
+func featureSnapshotPrefix(themeID, featureName string) string {
+	return "SNAPSHOT#" + themeID + "#" + featureName + "#"
+}
+
+// featureSnapshotSK generates the SK for a feature snapshot item.
+// The window end comes first so snapshots sort chronologically.
+// SK: SNAPSHOT#<theme_id>#<feature_name>#<end_date>#<start_date>
+func featureSnapshotSK(themeID, featureName, startDate, endDate string) string {
+	return featureSnapshotPrefix(themeID, featureName) + endDate + "#" + startDate
+}
+
+// featureScheduleRunSK generates the SK for the item recording the last scheduled run of a theme's feature.
+// SK: SCHEDULE_RUN#<theme_id>#<feature_name>
+func featureScheduleRunSK(themeID, featureName string) string {
+	return "SCHEDULE_RUN#" + themeID + "#" + featureName
 }
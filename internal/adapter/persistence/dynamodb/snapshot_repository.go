@@ -0,0 +1,186 @@
+package dynamodbrepo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+)
+
+// featureSnapshotItem is the stored form of a feature snapshot.
+// Results are kept as JSON because their structure depends on the feature.
+type featureSnapshotItem struct {
+	PK          string    `dynamodbav:"PK"` // USER#<user_id>
+	SK          string    `dynamodbav:"SK"` // SNAPSHOT#<theme_id>#<feature_name>#<end_date>#<start_date>
+	ThemeID     string    `dynamodbav:"ThemeID"`
+	FeatureName string    `dynamodbav:"FeatureName"`
+	WindowStart string    `dynamodbav:"WindowStart"` // YYYY-MM-DD
+	WindowEnd   string    `dynamodbav:"WindowEnd"`   // YYYY-MM-DD
+	Result      string    `dynamodbav:"Result"`      // JSON-encoded feature.AnalysisResult
+	CreatedAt   time.Time `dynamodbav:"CreatedAt"`
+}
+
+// featureScheduleRunItem records the last scheduled run of a theme's feature the scheduler processed,
+// so a restarted scheduler neither repeats nor skips runs.
+type featureScheduleRunItem struct {
+	PK          string    `dynamodbav:"PK"` // USER#<user_id>
+	SK          string    `dynamodbav:"SK"` // SCHEDULE_RUN#<theme_id>#<feature_name>
+	ThemeID     string    `dynamodbav:"ThemeID"`
+	FeatureName string    `dynamodbav:"FeatureName"`
+	LastRunAt   time.Time `dynamodbav:"LastRunAt"`
+}
+
+// dynamoDBSnapshotRepository implements the feature.SnapshotRepository interface using DynamoDB.
+type dynamoDBSnapshotRepository struct {
+	dbClient *DynamoDBClient
+}
+
+// NewSnapshotRepository creates a new DynamoDB-backed feature.SnapshotRepository.
+func NewSnapshotRepository(dbClient *DynamoDBClient) feature.SnapshotRepository {
+	return &dynamoDBSnapshotRepository{dbClient: dbClient}
+}
+
+// SaveSnapshot stores a snapshot in the user's partition.
+// The SK is derived from the window, so re-running a schedule for the same window replaces the snapshot.
+func (r *dynamoDBSnapshotRepository) SaveSnapshot(ctx context.Context, snapshot *feature.Snapshot) error {
+	encoded, err := json.Marshal(snapshot.Result)
+	if err != nil {
+		return fmt.Errorf("failed to encode feature result: %w", err)
+	}
+	start := snapshot.Window.Start.Format("2006-01-02")
+	end := snapshot.Window.End.Format("2006-01-02")
+	item := featureSnapshotItem{
+		PK:          userPK(snapshot.UserID.String()),
+		SK:          featureSnapshotSK(snapshot.ThemeID.String(), snapshot.FeatureName, start, end),
+		ThemeID:     snapshot.ThemeID.String(),
+		FeatureName: snapshot.FeatureName,
+		WindowStart: start,
+		WindowEnd:   end,
+		Result:      string(encoded),
+		CreatedAt:   snapshot.CreatedAt,
+	}
+	av, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return fmt.Errorf("failed to marshal feature snapshot: %w", err)
+	}
+	if _, err := r.dbClient.Client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.dbClient.TableName),
+		Item:      av,
+	}); err != nil {
+		return fmt.Errorf("failed to store feature snapshot: %w", err)
+	}
+	return nil
+}
+
+// ListSnapshots returns the snapshots of a theme's feature, newest window first.
+func (r *dynamoDBSnapshotRepository) ListSnapshots(ctx context.Context, userID, themeID uuid.UUID, featureName string) ([]feature.Snapshot, error) {
+	queryInput := &dynamodb.QueryInput{
+		TableName:              aws.String(r.dbClient.TableName),
+		KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :skPrefix)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":       &types.AttributeValueMemberS{Value: userPK(userID.String())},
+			":skPrefix": &types.AttributeValueMemberS{Value: featureSnapshotPrefix(themeID.String(), featureName)},
+		},
+		ScanIndexForward: aws.Bool(false),
+	}
+	paginator := dynamodb.NewQueryPaginator(r.dbClient.Client, queryInput)
+	snapshots := []feature.Snapshot{}
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query feature snapshots: %w", err)
+		}
+		var items []featureSnapshotItem
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &items); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal feature snapshots: %w", err)
+		}
+		for _, item := range items {
+			snapshot, err := item.toSnapshot(userID, themeID)
+			if err != nil {
+				return nil, err
+			}
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	return snapshots, nil
+}
+
+// LastScheduledRun returns the time of the latest processed scheduled run of a theme's feature,
+// or the zero time if there is no record of one.
+func (r *dynamoDBSnapshotRepository) LastScheduledRun(ctx context.Context, userID, themeID uuid.UUID, featureName string) (time.Time, error) {
+	out, err := r.dbClient.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.dbClient.TableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: userPK(userID.String())},
+			"SK": &types.AttributeValueMemberS{Value: featureScheduleRunSK(themeID.String(), featureName)},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get last scheduled run: %w", err)
+	}
+	if out.Item == nil {
+		return time.Time{}, nil
+	}
+	var item featureScheduleRunItem
+	if err := attributevalue.UnmarshalMap(out.Item, &item); err != nil {
+		return time.Time{}, fmt.Errorf("failed to unmarshal last scheduled run: %w", err)
+	}
+	return item.LastRunAt, nil
+}
+
+// SetLastScheduledRun records the time of the latest processed scheduled run of a theme's feature in the user's partition.
+func (r *dynamoDBSnapshotRepository) SetLastScheduledRun(ctx context.Context, userID, themeID uuid.UUID, featureName string, at time.Time) error {
+	av, err := attributevalue.MarshalMap(featureScheduleRunItem{
+		PK:          userPK(userID.String()),
+		SK:          featureScheduleRunSK(themeID.String(), featureName),
+		ThemeID:     themeID.String(),
+		FeatureName: featureName,
+		LastRunAt:   at.UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal last scheduled run: %w", err)
+	}
+	if _, err := r.dbClient.Client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.dbClient.TableName),
+		Item:      av,
+	}); err != nil {
+		return fmt.Errorf("failed to store last scheduled run: %w", err)
+	}
+	return nil
+}
+
+// toSnapshot decodes the stored item.
+func (i featureSnapshotItem) toSnapshot(userID, themeID uuid.UUID) (feature.Snapshot, error) {
+	start, err := time.Parse("2006-01-02", i.WindowStart)
+	if err != nil {
+		return feature.Snapshot{}, fmt.Errorf("invalid snapshot window start '%s': %w", i.WindowStart, err)
+	}
+	end, err := time.Parse("2006-01-02", i.WindowEnd)
+	if err != nil {
+		return feature.Snapshot{}, fmt.Errorf("invalid snapshot window end '%s': %w", i.WindowEnd, err)
+	}
+	var result feature.AnalysisResult
+	if err := json.Unmarshal([]byte(i.Result), &result); err != nil {
+		return feature.Snapshot{}, fmt.Errorf("failed to decode feature snapshot: %w", err)
+	}
+	return feature.Snapshot{
+		UserID:      userID,
+		ThemeID:     themeID,
+		FeatureName: i.FeatureName,
+		Window:      feature.Window{Start: start, End: end},
+		Result:      result,
+		CreatedAt:   i.CreatedAt,
+	}, nil
+}
+
+// Compile-time check to ensure dynamoDBSnapshotRepository implements feature.SnapshotRepository.
+var _ feature.SnapshotRepository = (*dynamoDBSnapshotRepository)(nil)
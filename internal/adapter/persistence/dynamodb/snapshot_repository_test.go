@@ -0,0 +1,95 @@
+package dynamodbrepo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+)
+
+func setupSnapshotRepoTest() (feature.SnapshotRepository, *MockDynamoDBAPI) {
+	mockDB := new(MockDynamoDBAPI)
+	dbClient := &DynamoDBClient{Client: mockDB, TableName: "test-table"}
+	return NewSnapshotRepository(dbClient), mockDB
+}
+
+func TestDynamoDBSnapshotRepository_SaveAndList(t *testing.T) {
+	repo, mockDB := setupSnapshotRepoTest()
+	ctx := context.Background()
+	snapshot := &feature.Snapshot{
+		UserID:      uuid.New(),
+		ThemeID:     uuid.New(),
+		FeatureName: "monthly_summary",
+		Window:      feature.MonthWindow(time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)),
+		Result:      feature.AnalysisResult{"total_entries": 4},
+		CreatedAt:   time.Date(2025, 6, 1, 0, 0, 5, 0, time.UTC),
+	}
+	expectedSK := "SNAPSHOT#" + snapshot.ThemeID.String() + "#monthly_summary#2025-05-31#2025-05-01"
+
+	var stored map[string]types.AttributeValue
+	mockDB.On("PutItem", ctx, mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+		var item featureSnapshotItem
+		if err := attributevalue.UnmarshalMap(input.Item, &item); err != nil {
+			return false
+		}
+		stored = input.Item
+		return item.PK == userPK(snapshot.UserID.String()) && item.SK == expectedSK
+	})).Return(&dynamodb.PutItemOutput{}, nil).Once()
+
+	err := repo.SaveSnapshot(ctx, snapshot)
+	assert.NoError(t, err)
+
+	mockDB.On("Query", ctx, mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+		prefix, ok := input.ExpressionAttributeValues[":skPrefix"].(*types.AttributeValueMemberS)
+		return ok && prefix.Value == "SNAPSHOT#"+snapshot.ThemeID.String()+"#monthly_summary#" &&
+			input.ScanIndexForward != nil && !*input.ScanIndexForward
+	})).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{stored}}, nil).Once()
+
+	snapshots, err := repo.ListSnapshots(ctx, snapshot.UserID, snapshot.ThemeID, "monthly_summary")
+
+	assert.NoError(t, err)
+	assert.Len(t, snapshots, 1)
+	assert.Equal(t, snapshot.Window, snapshots[0].Window)
+	assert.Equal(t, snapshot.CreatedAt, snapshots[0].CreatedAt)
+	assert.Equal(t, 4.0, snapshots[0].Result["total_entries"]) // Results round-trip through JSON
+	mockDB.AssertExpectations(t)
+}
+
+func TestDynamoDBSnapshotRepository_LastScheduledRun(t *testing.T) {
+	repo, mockDB := setupSnapshotRepoTest()
+	ctx := context.Background()
+	ownerID, themeID := uuid.New(), uuid.New()
+	at := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	key := func(input *dynamodb.GetItemInput) bool {
+		sk, _ := input.Key["SK"].(*types.AttributeValueMemberS)
+		return sk != nil && sk.Value == "SCHEDULE_RUN#"+themeID.String()+"#monthly_summary" && *input.ConsistentRead
+	}
+
+	// No run has been recorded yet
+	mockDB.On("GetItem", ctx, mock.MatchedBy(key)).Return(&dynamodb.GetItemOutput{}, nil).Once()
+	last, err := repo.LastScheduledRun(ctx, ownerID, themeID, "monthly_summary")
+	assert.NoError(t, err)
+	assert.True(t, last.IsZero())
+
+	var stored map[string]types.AttributeValue
+	mockDB.On("PutItem", ctx, mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+		stored = input.Item
+		pk, _ := input.Item["PK"].(*types.AttributeValueMemberS)
+		return pk != nil && pk.Value == userPK(ownerID.String())
+	})).Return(&dynamodb.PutItemOutput{}, nil).Once()
+	assert.NoError(t, repo.SetLastScheduledRun(ctx, ownerID, themeID, "monthly_summary", at))
+
+	mockDB.On("GetItem", ctx, mock.MatchedBy(key)).Return(&dynamodb.GetItemOutput{Item: stored}, nil).Once()
+	last, err = repo.LastScheduledRun(ctx, ownerID, themeID, "monthly_summary")
+	assert.NoError(t, err)
+	assert.True(t, at.Equal(last))
+	mockDB.AssertExpectations(t)
+}
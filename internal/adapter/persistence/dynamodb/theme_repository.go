@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/soranjiro/axicalendar/internal/domain"
@@ -27,7 +29,9 @@ func NewThemeRepository(dbClient *DynamoDBClient) ThemeRepository {
 	return &dynamoDBThemeRepository{dbClient: dbClient}
 }
 
-// GetThemeByID retrieves theme definition by theme ID and ensures access (default or owner).
+// GetThemeByID retrieves theme definition by theme ID and ensures access: default themes and the user's
+// own themes are accessible, other custom themes only through the user's link item.
+// The returned theme's Role is the user's role on it.
 func (r *dynamoDBThemeRepository) GetThemeByID(ctx context.Context, userID uuid.UUID, themeID uuid.UUID) (*theme.Theme, error) {
 	// Fetch metadata item
 	pk := "THEME#" + themeID.String()
@@ -46,50 +50,141 @@ func (r *dynamoDBThemeRepository) GetThemeByID(ctx context.Context, userID uuid.
 	if result.Item == nil {
 		return nil, domain.ErrNotFound // Use domain error
 	}
-	var theme theme.Theme
-	if err := attributevalue.UnmarshalMap(result.Item, &theme); err != nil {
+	var th theme.Theme
+	if err := unmarshalTheme(result.Item, &th); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal theme metadata: %w", err)
 	}
-	// Access check: default or owned
-	if !theme.IsDefault {
-		if theme.OwnerUserID == nil || *theme.OwnerUserID != userID {
+	// Access check: default, owned, or shared with the user
+	th.Role = th.OwnRole(userID)
+	if th.Role == "" {
+		link, err := r.getUserThemeLink(ctx, userID, themeID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get theme membership: %w", err)
+		}
+		if link == nil || link.Role == "" {
 			return nil, domain.ErrForbidden // Use domain error
 		}
+		th.Role = link.Role
 	}
-	return &theme, nil
+	return &th, nil
 }
 
-// ListThemes retrieves all themes available to a user (default + custom).
+// ListThemes retrieves all themes available to a user (default + own + shared), with the user's role on each.
+// Default themes are queried from GSI1, and custom themes are read through the user's
+// USER#<user_id> / THEME#<theme_id> link items, so no scan is needed.
 func (r *dynamoDBThemeRepository) ListThemes(ctx context.Context, userID uuid.UUID) ([]theme.Theme, error) {
-	// Scan metadata items
-	scanInput := &dynamodb.ScanInput{
-		TableName:        aws.String(r.dbClient.TableName),
-		FilterExpression: aws.String("SK = :md"),
+	themes, err := r.ListDefaultThemes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range themes {
+		themes[i].Role = theme.RoleContributor
+	}
+	links, err := r.ListUserThemes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]map[string]types.AttributeValue, len(links))
+	for i, link := range links {
+		keys[i] = map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: themePK(link.ThemeID.String())},
+			"SK": &types.AttributeValueMemberS{Value: themeMetadataSK()},
+		}
+	}
+	items, err := batchGet(ctx, r.dbClient, keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get linked themes: %w", err)
+	}
+	linked := make(map[uuid.UUID]theme.Theme, len(items))
+	for _, item := range items {
+		var t theme.Theme
+		if err := unmarshalTheme(item, &t); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal themes: %w", err)
+		}
+		linked[t.ThemeID] = t
+	}
+	// Keep the order of the links; skip links whose theme is gone or not accessible
+	for _, link := range links {
+		t, ok := linked[link.ThemeID]
+		if !ok {
+			log.Printf("WARN: User %s is linked to missing theme %s", userID, link.ThemeID)
+			continue
+		}
+		if t.IsDefault {
+			continue
+		}
+		t.Role = t.OwnRole(userID)
+		if t.Role == "" {
+			t.Role = link.Role
+		}
+		if t.Role == "" {
+			continue
+		}
+		themes = append(themes, t)
+	}
+	return themes, nil
+}
+
+// ListDefaultThemes retrieves the default themes by querying GSI1 (PK=DEFAULT#THEME),
+// which only the metadata items of default themes are written to.
+func (r *dynamoDBThemeRepository) ListDefaultThemes(ctx context.Context) ([]theme.Theme, error) {
+	queryInput := &dynamodb.QueryInput{
+		TableName:              aws.String(r.dbClient.TableName),
+		IndexName:              aws.String("GSI1"),
+		KeyConditionExpression: aws.String("GSI1PK = :pk"),
 		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":md": &types.AttributeValueMemberS{Value: "METADATA"},
+			":pk": &types.AttributeValueMemberS{Value: defaultThemesGSI1PK},
 		},
 	}
-	paginator := dynamodb.NewScanPaginator(r.dbClient.Client, scanInput)
+	paginator := dynamodb.NewQueryPaginator(r.dbClient.Client, queryInput)
 	var themes []theme.Theme
 	for paginator.HasMorePages() {
 		page, err := paginator.NextPage(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan themes: %w", err)
+			return nil, fmt.Errorf("failed to query default themes: %w", err)
 		}
-		var pageThemes []theme.Theme
-		if err := attributevalue.UnmarshalListOfMaps(page.Items, &pageThemes); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal themes: %w", err)
+		for _, item := range page.Items {
+			var t theme.Theme
+			if err := unmarshalTheme(item, &t); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal default themes: %w", err)
+			}
+			themes = append(themes, t)
 		}
-		for _, t := range pageThemes {
-			if t.IsDefault || (t.OwnerUserID != nil && *t.OwnerUserID == userID) {
-				themes = append(themes, t)
+	}
+	return themes, nil
+}
+
+// ListScheduledThemes retrieves the custom themes of all users that schedule at least one feature by
+// querying GSI1 (PK=SCHEDULED#THEME), which only their metadata items are written to.
+// It is meant for the feature scheduler, not for request handling.
+func (r *dynamoDBThemeRepository) ListScheduledThemes(ctx context.Context) ([]theme.Theme, error) {
+	queryInput := &dynamodb.QueryInput{
+		TableName:              aws.String(r.dbClient.TableName),
+		IndexName:              aws.String("GSI1"),
+		KeyConditionExpression: aws.String("GSI1PK = :pk"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: scheduledThemesGSI1PK},
+		},
+	}
+	paginator := dynamodb.NewQueryPaginator(r.dbClient.Client, queryInput)
+	var themes []theme.Theme
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query scheduled themes: %w", err)
+		}
+		for _, item := range page.Items {
+			var t theme.Theme
+			if err := unmarshalTheme(item, &t); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal scheduled themes: %w", err)
 			}
+			themes = append(themes, t)
 		}
 	}
 	return themes, nil
 }
 
-// CreateTheme creates a new custom theme (metadata + user link).
+// CreateTheme creates a new custom theme (metadata + user link + first revision).
 func (r *dynamoDBThemeRepository) CreateTheme(ctx context.Context, inputTheme *theme.Theme) error {
 	if inputTheme.ThemeID == uuid.Nil {
 		inputTheme.ThemeID = uuid.New()
@@ -101,35 +196,33 @@ func (r *dynamoDBThemeRepository) CreateTheme(ctx context.Context, inputTheme *t
 	inputTheme.CreatedAt = now
 	inputTheme.UpdatedAt = now
 	inputTheme.IsDefault = false
+	inputTheme.Revision = theme.InitialRevision
+	inputTheme.UpdatedBy = inputTheme.OwnerUserID
 	// Ensure SupportedFeatures is not nil (initialize if needed)
-	if inputTheme.SupportedFeatures == nil {
-		inputTheme.SupportedFeatures = []string{}
-	}
+	inputTheme.SupportedFeatures = supportedFeaturesOrEmpty(inputTheme.SupportedFeatures)
 	// Metadata item
 	meta := *inputTheme
 	meta.PK = themePK(inputTheme.ThemeID.String())
 	meta.SK = themeMetadataSK()
+	if inputTheme.HasSchedules() {
+		meta.GSI1PK = scheduledThemesGSI1PK
+		meta.GSI1SK = scheduledThemeGSI1SK(inputTheme.ThemeID.String())
+	}
 	metaAV, err := attributevalue.MarshalMap(meta)
 	if err != nil {
 		return fmt.Errorf("failed to marshal theme metadata: %w", err)
 	}
 	// User link item
 	link := theme.UserThemeLink{
-		PK:      userPK(inputTheme.OwnerUserID.String()),
-		SK:      userThemeLinkSK(inputTheme.ThemeID.String()),
 		UserID:  *inputTheme.OwnerUserID,
 		ThemeID: inputTheme.ThemeID,
-		// ThemeName and CreatedAt are not part of UserThemeLink struct
-	}
-	linkAV, err := attributevalue.MarshalMap(link)
-	if err != nil {
-		return fmt.Errorf("failed to marshal user-theme link: %w", err)
+		Role:    theme.RoleOwner,
 	}
 	// Write metadata then link
 	if _, err := r.dbClient.Client.PutItem(ctx, &dynamodb.PutItemInput{TableName: aws.String(r.dbClient.TableName), Item: metaAV}); err != nil {
 		return fmt.Errorf("failed to create theme metadata: %w", err)
 	}
-	if _, err := r.dbClient.Client.PutItem(ctx, &dynamodb.PutItemInput{TableName: aws.String(r.dbClient.TableName), Item: linkAV}); err != nil {
+	if err := r.AddUserThemeLink(ctx, &link); err != nil {
 		// Attempt to roll back metadata creation on link failure
 		log.Printf("WARN: Failed to create user-theme link for theme %s, attempting metadata rollback: %v", inputTheme.ThemeID, err)
 		rollbackInput := &dynamodb.DeleteItemInput{
@@ -144,44 +237,98 @@ func (r *dynamoDBThemeRepository) CreateTheme(ctx context.Context, inputTheme *t
 		}
 		return fmt.Errorf("failed to create user-theme link: %w", err)
 	}
+	// Record the theme as created as its first revision
+	rev := inputTheme.NewRevision()
+	if err := r.PutThemeRevision(ctx, &rev); err != nil {
+		// Attempt to roll back the metadata and link so the theme does not exist without a history
+		log.Printf("WARN: Failed to create first revision for theme %s, attempting rollback: %v", inputTheme.ThemeID, err)
+		for _, key := range []map[string]types.AttributeValue{
+			{"PK": &types.AttributeValueMemberS{Value: meta.PK}, "SK": &types.AttributeValueMemberS{Value: meta.SK}},
+			{"PK": &types.AttributeValueMemberS{Value: link.PK}, "SK": &types.AttributeValueMemberS{Value: link.SK}},
+		} {
+			if _, rollbackErr := r.dbClient.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{TableName: aws.String(r.dbClient.TableName), Key: key}); rollbackErr != nil {
+				log.Printf("ERROR: Failed to rollback theme %s: %v", inputTheme.ThemeID, rollbackErr)
+			}
+		}
+		return fmt.Errorf("failed to create theme revision: %w", err)
+	}
 	return nil
 }
 
-// UpdateTheme updates an existing custom theme's metadata.
+// UpdateTheme updates an existing custom theme's metadata and records the new definition as revision
+// theme.Revision, in one transaction. The update is rejected with domain.ErrConflict unless the stored
+// theme is at the previous revision (or predates revisions) and the new revision is not stored yet.
 func (r *dynamoDBThemeRepository) UpdateTheme(ctx context.Context, theme *theme.Theme) error {
 	if theme.ThemeID == uuid.Nil || theme.OwnerUserID == nil || *theme.OwnerUserID == uuid.Nil {
 		return errors.New("theme ID and owner user ID are required for update")
 	}
+	if theme.Revision <= 1 {
+		return errors.New("theme revision must follow the stored revision")
+	}
 	// Ensure access and metadata key
 	pk := themePK(theme.ThemeID.String())
 	sk := themeMetadataSK()
 	now := time.Now()
+	theme.UpdatedAt = now
 	fieldsAV, err := attributevalue.Marshal(theme.Fields)
 	if err != nil {
 		return fmt.Errorf("failed to marshal fields for update: %w", err)
 	}
 	// Ensure SupportedFeatures is not nil before marshalling
-	if theme.SupportedFeatures == nil {
-		theme.SupportedFeatures = []string{}
-	}
+	theme.SupportedFeatures = supportedFeaturesOrEmpty(theme.SupportedFeatures)
 	featuresAV, err := attributevalue.Marshal(theme.SupportedFeatures)
 	if err != nil {
 		// This should ideally not happen if we initialize to empty slice
 		return fmt.Errorf("failed to marshal supported features for update: %w", err)
 	}
+	migrationsAV, err := attributevalue.Marshal(theme.Migrations)
+	if err != nil {
+		return fmt.Errorf("failed to marshal migrations for update: %w", err)
+	}
 
-	updateExpr := "SET ThemeName = :name, Fields = :fields, UpdatedAt = :updatedAt, SupportedFeatures = :features" // Add SupportedFeatures
-	exprAttrValues := map[string]types.AttributeValue{
-		":name":      &types.AttributeValueMemberS{Value: theme.ThemeName},
-		":fields":    fieldsAV,
-		":updatedAt": &types.AttributeValueMemberS{Value: now.Format(time.RFC3339Nano)},
-		":features":  featuresAV, // Add features to update
+	rev := theme.NewRevision()
+	rev.PK = pk
+	rev.SK = themeRevisionSK(rev.Revision)
+	revAV, err := attributevalue.MarshalMap(rev)
+	if err != nil {
+		return fmt.Errorf("failed to marshal theme revision: %w", err)
 	}
-	// Condition: Must exist, not be default, and owned by the user
-	conditionExpr := "attribute_exists(PK) AND attribute_exists(SK) AND IsDefault = :false AND OwnerUserID = :userId"
+
+	updateExpr := "SET ThemeName = :name, Fields = :fields, UpdatedAt = :updatedAt, SupportedFeatures = :features, SchemaVersion = :schemaVersion, Migrations = :migrations, Revision = :revision, UpdatedBy = :updatedBy"
+	exprAttrValues := map[string]types.AttributeValue{
+		":name":          &types.AttributeValueMemberS{Value: theme.ThemeName},
+		":fields":        fieldsAV,
+		":updatedAt":     &types.AttributeValueMemberS{Value: now.Format(time.RFC3339Nano)},
+		":features":      featuresAV, // Add features to update
+		":schemaVersion": &types.AttributeValueMemberN{Value: strconv.Itoa(theme.SchemaVersion)},
+		":migrations":    migrationsAV,
+		":revision":      &types.AttributeValueMemberN{Value: strconv.Itoa(theme.Revision)},
+		":updatedBy":     &types.AttributeValueMemberB{Value: rev.ChangedBy[:]},
+	}
+	var removes []string
+	if theme.RestoredFrom > 0 {
+		updateExpr += ", RestoredFrom = :restoredFrom"
+		exprAttrValues[":restoredFrom"] = &types.AttributeValueMemberN{Value: strconv.Itoa(theme.RestoredFrom)}
+	} else {
+		removes = append(removes, "RestoredFrom")
+	}
+	// Keep the theme in the scheduled themes index only while it schedules a feature
+	if theme.HasSchedules() {
+		updateExpr += ", GSI1PK = :gsi1pk, GSI1SK = :gsi1sk"
+		exprAttrValues[":gsi1pk"] = &types.AttributeValueMemberS{Value: scheduledThemesGSI1PK}
+		exprAttrValues[":gsi1sk"] = &types.AttributeValueMemberS{Value: scheduledThemeGSI1SK(theme.ThemeID.String())}
+	} else {
+		removes = append(removes, "GSI1PK", "GSI1SK")
+	}
+	if len(removes) > 0 {
+		updateExpr += " REMOVE " + strings.Join(removes, ", ")
+	}
+	// Condition: Must exist, not be default, owned by the user, and unchanged since it was read
+	conditionExpr := "attribute_exists(PK) AND attribute_exists(SK) AND IsDefault = :false AND OwnerUserID = :userId AND (attribute_not_exists(Revision) OR Revision = :previousRevision)"
 	condAttrValues := map[string]types.AttributeValue{
-		":false":  &types.AttributeValueMemberBOOL{Value: false},
-		":userId": &types.AttributeValueMemberS{Value: theme.OwnerUserID.String()},
+		":false":            &types.AttributeValueMemberBOOL{Value: false},
+		":userId":           &types.AttributeValueMemberB{Value: theme.OwnerUserID[:]}, // Stored as binary, like OwnerUserID
+		":previousRevision": &types.AttributeValueMemberN{Value: strconv.Itoa(theme.Revision - 1)},
 	}
 	// Merge expression attribute values, handling potential key collisions (though unlikely here)
 	mergedExprAttrValues := make(map[string]types.AttributeValue)
@@ -198,15 +345,32 @@ func (r *dynamoDBThemeRepository) UpdateTheme(ctx context.Context, theme *theme.
 		}
 	}
 
-	if _, err := r.dbClient.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
-		TableName:                 aws.String(r.dbClient.TableName),
-		Key:                       map[string]types.AttributeValue{"PK": &types.AttributeValueMemberS{Value: pk}, "SK": &types.AttributeValueMemberS{Value: sk}},
-		UpdateExpression:          aws.String(updateExpr),
-		ConditionExpression:       aws.String(conditionExpr),
-		ExpressionAttributeValues: mergedExprAttrValues,
+	// Update the metadata and write the revision together, so every stored state has its revision
+	if _, err := r.dbClient.Client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{Update: &types.Update{
+				TableName:                 aws.String(r.dbClient.TableName),
+				Key:                       map[string]types.AttributeValue{"PK": &types.AttributeValueMemberS{Value: pk}, "SK": &types.AttributeValueMemberS{Value: sk}},
+				UpdateExpression:          aws.String(updateExpr),
+				ConditionExpression:       aws.String(conditionExpr),
+				ExpressionAttributeValues: mergedExprAttrValues,
+			}},
+			{Put: &types.Put{
+				TableName:           aws.String(r.dbClient.TableName),
+				Item:                revAV,
+				ConditionExpression: aws.String("attribute_not_exists(SK)"), // Revisions are immutable
+			}},
+		},
 	}); err != nil {
-		var condCheckFailed *types.ConditionalCheckFailedException
-		if errors.As(err, &condCheckFailed) {
+		var txc *types.TransactionCanceledException
+		if errors.As(err, &txc) && len(txc.CancellationReasons) == 2 {
+			metaReason, revReason := txc.CancellationReasons[0], txc.CancellationReasons[1]
+			if revReason.Code != nil && *revReason.Code == "ConditionalCheckFailed" {
+				return domain.ErrConflict // Another update recorded this revision first
+			}
+			if metaReason.Code == nil || *metaReason.Code != "ConditionalCheckFailed" {
+				return fmt.Errorf("theme update transaction cancelled: %w", err)
+			}
 			// Check if the theme exists first to give a more specific error
 			// Use GetThemeByID which includes the ownership check logic
 			_, getErr := r.GetThemeByID(ctx, *theme.OwnerUserID, theme.ThemeID)
@@ -220,9 +384,8 @@ func (r *dynamoDBThemeRepository) UpdateTheme(ctx context.Context, theme *theme.
 				// Other error during GetThemeByID check
 				return fmt.Errorf("failed to update theme metadata (and failed to check existence/ownership): %w", err)
 			}
-			// If GetThemeByID succeeded without error, something unexpected happened with the condition check.
-			// This path might be less likely if GetThemeByID covers the checks.
-			return fmt.Errorf("failed to update theme metadata due to condition check failure, but ownership/existence check passed: %w", err)
+			// The theme exists and is owned by the user, so its revision changed since it was read
+			return domain.ErrConflict
 		}
 		// Other update error
 		return fmt.Errorf("failed to update theme metadata: %w", err)
@@ -249,13 +412,83 @@ func (r *dynamoDBThemeRepository) UpdateTheme(ctx context.Context, theme *theme.
 	return nil
 }
 
-// DeleteTheme deletes a custom theme (metadata and user link).
+// PutDefaultTheme writes a default theme's metadata and its revision theme.Revision in one transaction.
+// The first revision creates the theme; later ones replace it only if it is a default theme still at the
+// previous revision, so concurrent seeders cannot overwrite each other. Returns domain.ErrConflict otherwise.
+func (r *dynamoDBThemeRepository) PutDefaultTheme(ctx context.Context, inputTheme *theme.Theme) error {
+	if inputTheme.ThemeID == uuid.Nil || inputTheme.Revision < theme.InitialRevision {
+		return errors.New("theme ID and revision are required to put a default theme")
+	}
+	now := time.Now()
+	if inputTheme.CreatedAt.IsZero() {
+		inputTheme.CreatedAt = now
+	}
+	inputTheme.UpdatedAt = now
+	inputTheme.IsDefault = true
+	inputTheme.OwnerUserID = nil
+	inputTheme.UpdatedBy = nil
+	inputTheme.SupportedFeatures = supportedFeaturesOrEmpty(inputTheme.SupportedFeatures)
+
+	meta := *inputTheme
+	meta.PK = themePK(inputTheme.ThemeID.String())
+	meta.SK = themeMetadataSK()
+	meta.GSI1PK = defaultThemesGSI1PK
+	meta.GSI1SK = defaultThemeGSI1SK(inputTheme.ThemeID.String())
+	metaAV, err := attributevalue.MarshalMap(meta)
+	if err != nil {
+		return fmt.Errorf("failed to marshal default theme metadata: %w", err)
+	}
+	rev := inputTheme.NewRevision() // Changed by uuid.Nil: the system
+	rev.PK = meta.PK
+	rev.SK = themeRevisionSK(rev.Revision)
+	revAV, err := attributevalue.MarshalMap(rev)
+	if err != nil {
+		return fmt.Errorf("failed to marshal theme revision: %w", err)
+	}
+
+	metaPut := &types.Put{
+		TableName:           aws.String(r.dbClient.TableName),
+		Item:                metaAV,
+		ConditionExpression: aws.String("attribute_not_exists(PK)"),
+	}
+	if inputTheme.Revision > theme.InitialRevision {
+		metaPut.ConditionExpression = aws.String("IsDefault = :true AND Revision = :previousRevision")
+		metaPut.ExpressionAttributeValues = map[string]types.AttributeValue{
+			":true":             &types.AttributeValueMemberBOOL{Value: true},
+			":previousRevision": &types.AttributeValueMemberN{Value: strconv.Itoa(inputTheme.Revision - 1)},
+		}
+	}
+	if _, err := r.dbClient.Client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{Put: metaPut},
+			{Put: &types.Put{
+				TableName:           aws.String(r.dbClient.TableName),
+				Item:                revAV,
+				ConditionExpression: aws.String("attribute_not_exists(SK)"), // Revisions are immutable
+			}},
+		},
+	}); err != nil {
+		var txc *types.TransactionCanceledException
+		if errors.As(err, &txc) {
+			for _, reason := range txc.CancellationReasons {
+				if reason.Code != nil && *reason.Code == "ConditionalCheckFailed" {
+					return domain.ErrConflict
+				}
+			}
+		}
+		return fmt.Errorf("failed to put default theme: %w", err)
+	}
+	return nil
+}
+
+// DeleteTheme deletes a custom theme (metadata, the links of the owner and members, and revisions).
+// Only the owner can delete a theme.
 func (r *dynamoDBThemeRepository) DeleteTheme(ctx context.Context, userID uuid.UUID, themeID uuid.UUID) error {
 	if userID == uuid.Nil || themeID == uuid.Nil {
 		return errors.New("user ID and theme ID are required for delete")
 	}
 	// 1. Ensure theme exists, is owned by the user, and is not default
-	theme, err := r.GetThemeByID(ctx, userID, themeID)
+	th, err := r.GetThemeByID(ctx, userID, themeID)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) { // Use ErrNotFound
 			return domain.ErrNotFound
@@ -265,10 +498,12 @@ func (r *dynamoDBThemeRepository) DeleteTheme(ctx context.Context, userID uuid.U
 		}
 		return fmt.Errorf("failed to get theme for deletion check: %w", err) // Wrap original error
 	}
-	if theme.IsDefault {
+	if th.IsDefault {
 		return errors.New("cannot delete default theme") // Specific error for default theme
 	}
-	// Owner check is implicitly done by GetThemeByID
+	if th.Role != theme.RoleOwner {
+		return domain.ErrForbidden // Members the theme is shared with cannot delete it
+	}
 
 	// 2. Delete metadata item
 	metaPK := themePK(themeID.String())
@@ -285,47 +520,412 @@ func (r *dynamoDBThemeRepository) DeleteTheme(ctx context.Context, userID uuid.U
 		return fmt.Errorf("failed to delete theme metadata: %w", err)
 	}
 
-	// 3. Delete user link item
-	linkPK := userPK(userID.String())
-	linkSK := userThemeLinkSK(themeID.String()) // Use helper
-	linkKey := map[string]types.AttributeValue{
-		"PK": &types.AttributeValueMemberS{Value: linkPK},
-		"SK": &types.AttributeValueMemberS{Value: linkSK},
+	// 3. Delete the user link items of the owner and the members
+	if err := r.RemoveUserThemeLink(ctx, userID, themeID); err != nil {
+		// Log warning if link deletion fails, as metadata is already gone.
+		// ListThemes skips links to missing themes, but this indicates potential data inconsistency.
+		log.Printf("WARN: Failed to delete user-theme link for theme %s after metadata deletion: %v", themeID, err)
 	}
-	if _, err := r.dbClient.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+	members, err := r.ListThemeMembers(ctx, themeID)
+	if err != nil {
+		log.Printf("WARN: Failed to list members of theme %s after metadata deletion: %v", themeID, err)
+	}
+	for _, member := range members {
+		if member.UserID == userID {
+			continue
+		}
+		if err := r.RemoveUserThemeLink(ctx, member.UserID, themeID); err != nil {
+			log.Printf("WARN: Failed to delete user-theme link of member %s for theme %s after metadata deletion: %v", member.UserID, themeID, err)
+		}
+	}
+
+	// 4. Delete revision items
+	if err := r.deleteThemeRevisions(ctx, themeID); err != nil {
+		// As with the link, the theme is already gone; leftover revisions are unreachable
+		log.Printf("WARN: Failed to delete revisions of theme %s after metadata deletion: %v", themeID, err)
+	}
+
+	return nil
+}
+
+// ListThemeRevisions retrieves the revisions of a theme, newest first.
+// Access to the theme is not checked here.
+func (r *dynamoDBThemeRepository) ListThemeRevisions(ctx context.Context, themeID uuid.UUID) ([]theme.Revision, error) {
+	queryInput := &dynamodb.QueryInput{
+		TableName:              aws.String(r.dbClient.TableName),
+		KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :skPrefix)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":       &types.AttributeValueMemberS{Value: themePK(themeID.String())},
+			":skPrefix": &types.AttributeValueMemberS{Value: themeRevisionSKPrefix},
+		},
+		ScanIndexForward: aws.Bool(false),
+	}
+	paginator := dynamodb.NewQueryPaginator(r.dbClient.Client, queryInput)
+	revisions := []theme.Revision{}
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query theme revisions: %w", err)
+		}
+		var items []theme.Revision
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &items); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal theme revisions: %w", err)
+		}
+		revisions = append(revisions, items...)
+	}
+	return revisions, nil
+}
+
+// GetThemeRevision retrieves one revision of a theme. Access to the theme is not checked here.
+func (r *dynamoDBThemeRepository) GetThemeRevision(ctx context.Context, themeID uuid.UUID, revision int) (*theme.Revision, error) {
+	result, err := r.dbClient.Client.GetItem(ctx, &dynamodb.GetItemInput{
 		TableName: aws.String(r.dbClient.TableName),
-		Key:       linkKey,
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: themePK(themeID.String())},
+			"SK": &types.AttributeValueMemberS{Value: themeRevisionSK(revision)},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get theme revision: %w", err)
+	}
+	if result.Item == nil {
+		return nil, domain.ErrNotFound
+	}
+	var rev theme.Revision
+	if err := attributevalue.UnmarshalMap(result.Item, &rev); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal theme revision: %w", err)
+	}
+	return &rev, nil
+}
+
+// PutThemeRevision stores a revision unless one with the same number exists.
+func (r *dynamoDBThemeRepository) PutThemeRevision(ctx context.Context, revision *theme.Revision) error {
+	if revision.ThemeID == uuid.Nil || revision.Revision < theme.InitialRevision {
+		return errors.New("theme ID and revision number are required to store a revision")
+	}
+	revision.PK = themePK(revision.ThemeID.String())
+	revision.SK = themeRevisionSK(revision.Revision)
+	revision.SupportedFeatures = supportedFeaturesOrEmpty(revision.SupportedFeatures)
+	av, err := attributevalue.MarshalMap(revision)
+	if err != nil {
+		return fmt.Errorf("failed to marshal theme revision: %w", err)
+	}
+	if _, err := r.dbClient.Client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.dbClient.TableName),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(SK)"), // Revisions are immutable
 	}); err != nil {
-		// Log warning if link deletion fails, as metadata is already gone.
-		// This indicates potential data inconsistency.
-		log.Printf("WARN: Failed to delete user-theme link for theme %s (PK=%s, SK=%s) after metadata deletion: %v", themeID, linkPK, linkSK, err)
+		var condCheckFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &condCheckFailed) {
+			return domain.ErrAlreadyExists
+		}
+		return fmt.Errorf("failed to store theme revision: %w", err)
 	}
+	return nil
+}
 
+// deleteThemeRevisions deletes all revision items of a theme in batches.
+func (r *dynamoDBThemeRepository) deleteThemeRevisions(ctx context.Context, themeID uuid.UUID) error {
+	queryInput := &dynamodb.QueryInput{
+		TableName:              aws.String(r.dbClient.TableName),
+		KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :skPrefix)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":       &types.AttributeValueMemberS{Value: themePK(themeID.String())},
+			":skPrefix": &types.AttributeValueMemberS{Value: themeRevisionSKPrefix},
+		},
+		ProjectionExpression: aws.String("PK, SK"),
+	}
+	paginator := dynamodb.NewQueryPaginator(r.dbClient.Client, queryInput)
+	var requests []types.WriteRequest
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to query theme revisions: %w", err)
+		}
+		for _, item := range page.Items {
+			requests = append(requests, types.WriteRequest{DeleteRequest: &types.DeleteRequest{Key: item}})
+		}
+	}
+	for start := 0; start < len(requests); start += batchWriteSize {
+		end := start + batchWriteSize
+		if end > len(requests) {
+			end = len(requests)
+		}
+		if err := batchWrite(ctx, r.dbClient, requests[start:end]); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-// AddUserThemeLink creates a link item allowing a user to access a theme.
-// TODO: Implement actual logic
+// AddUserThemeLink creates a link item allowing a user to access a theme with the link's role.
+// The link is also written to GSI1 under the theme (see ListThemeMembers).
+// Writing an existing link again overwrites it, so the call is idempotent.
 func (r *dynamoDBThemeRepository) AddUserThemeLink(ctx context.Context, link *theme.UserThemeLink) error {
-	log.Printf("WARN: AddUserThemeLink not implemented yet")
-	// Placeholder implementation
-	return errors.New("AddUserThemeLink not implemented")
+	if link.UserID == uuid.Nil || link.ThemeID == uuid.Nil {
+		return errors.New("user ID and theme ID are required for a user-theme link")
+	}
+	setUserThemeLinkKeys(link)
+	av, err := attributevalue.MarshalMap(link)
+	if err != nil {
+		return fmt.Errorf("failed to marshal user-theme link: %w", err)
+	}
+	if _, err := r.dbClient.Client.PutItem(ctx, &dynamodb.PutItemInput{TableName: aws.String(r.dbClient.TableName), Item: av}); err != nil {
+		return fmt.Errorf("failed to put user-theme link: %w", err)
+	}
+	return nil
 }
 
 // RemoveUserThemeLink removes the link item, revoking user access to a theme.
-// TODO: Implement actual logic
+// Removing a link that does not exist is not an error.
 func (r *dynamoDBThemeRepository) RemoveUserThemeLink(ctx context.Context, userID, themeID uuid.UUID) error {
-	log.Printf("WARN: RemoveUserThemeLink not implemented yet")
-	// Placeholder implementation
-	return errors.New("RemoveUserThemeLink not implemented")
+	if _, err := r.dbClient.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.dbClient.TableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: userPK(userID.String())},
+			"SK": &types.AttributeValueMemberS{Value: userThemeLinkSK(themeID.String())},
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to delete user-theme link: %w", err)
+	}
+	return nil
 }
 
 // ListUserThemes retrieves the UserThemeLink items for a user.
-// TODO: Implement actual logic
+// Query PK=USER#<user_id>, SK begins_with THEME#, in theme ID order.
 func (r *dynamoDBThemeRepository) ListUserThemes(ctx context.Context, userID uuid.UUID) ([]theme.UserThemeLink, error) {
-	log.Printf("WARN: ListUserThemes not implemented yet")
-	// Placeholder implementation
-	return nil, errors.New("ListUserThemes not implemented")
+	queryInput := &dynamodb.QueryInput{
+		TableName:              aws.String(r.dbClient.TableName),
+		KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :skPrefix)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":       &types.AttributeValueMemberS{Value: userPK(userID.String())},
+			":skPrefix": &types.AttributeValueMemberS{Value: userThemeLinkSKPrefix},
+		},
+	}
+	paginator := dynamodb.NewQueryPaginator(r.dbClient.Client, queryInput)
+	links := []theme.UserThemeLink{}
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query user-theme links: %w", err)
+		}
+		var items []theme.UserThemeLink
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &items); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal user-theme links: %w", err)
+		}
+		links = append(links, items...)
+	}
+	return links, nil
+}
+
+// setUserThemeLinkKeys fills in the table and GSI1 keys of a link item.
+func setUserThemeLinkKeys(link *theme.UserThemeLink) {
+	link.PK = userPK(link.UserID.String())
+	link.SK = userThemeLinkSK(link.ThemeID.String())
+	link.GSI1PK = themeMemberGSI1PK(link.ThemeID.String())
+	link.GSI1SK = themeMemberGSI1SK(link.UserID.String())
+}
+
+// getUserThemeLink retrieves the user's link item to a theme, or nil if there is none.
+func (r *dynamoDBThemeRepository) getUserThemeLink(ctx context.Context, userID, themeID uuid.UUID) (*theme.UserThemeLink, error) {
+	result, err := r.dbClient.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.dbClient.TableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: userPK(userID.String())},
+			"SK": &types.AttributeValueMemberS{Value: userThemeLinkSK(themeID.String())},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user-theme link: %w", err)
+	}
+	if result.Item == nil {
+		return nil, nil
+	}
+	var link theme.UserThemeLink
+	if err := attributevalue.UnmarshalMap(result.Item, &link); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal user-theme link: %w", err)
+	}
+	return &link, nil
+}
+
+// ListThemeMembers retrieves the link items of a theme by querying GSI1 (PK=THEME#<theme_id>),
+// in user ID order. Owner links written before sharing existed are not on GSI1 and are not returned.
+func (r *dynamoDBThemeRepository) ListThemeMembers(ctx context.Context, themeID uuid.UUID) ([]theme.UserThemeLink, error) {
+	queryInput := &dynamodb.QueryInput{
+		TableName:              aws.String(r.dbClient.TableName),
+		IndexName:              aws.String("GSI1"),
+		KeyConditionExpression: aws.String("GSI1PK = :pk"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: themeMemberGSI1PK(themeID.String())},
+		},
+	}
+	paginator := dynamodb.NewQueryPaginator(r.dbClient.Client, queryInput)
+	members := []theme.UserThemeLink{}
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query theme members: %w", err)
+		}
+		var items []theme.UserThemeLink
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &items); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal theme members: %w", err)
+		}
+		members = append(members, items...)
+	}
+	return members, nil
+}
+
+// PutThemeInvitation stores an invitation in the invitee's partition (USER#<user_id> / INVITE#<theme_id>).
+// A pending invitation to the same theme is replaced, so inviting a member again changes their role on accept.
+func (r *dynamoDBThemeRepository) PutThemeInvitation(ctx context.Context, invitation *theme.Invitation) error {
+	if invitation.UserID == uuid.Nil || invitation.ThemeID == uuid.Nil {
+		return errors.New("user ID and theme ID are required for an invitation")
+	}
+	invitation.PK = userPK(invitation.UserID.String())
+	invitation.SK = invitationSK(invitation.ThemeID.String())
+	if invitation.CreatedAt.IsZero() {
+		invitation.CreatedAt = time.Now()
+	}
+	av, err := attributevalue.MarshalMap(invitation)
+	if err != nil {
+		return fmt.Errorf("failed to marshal invitation: %w", err)
+	}
+	if _, err := r.dbClient.Client.PutItem(ctx, &dynamodb.PutItemInput{TableName: aws.String(r.dbClient.TableName), Item: av}); err != nil {
+		return fmt.Errorf("failed to put invitation: %w", err)
+	}
+	return nil
+}
+
+// ListThemeInvitations retrieves the pending invitations of a user.
+// Query PK=USER#<user_id>, SK begins_with INVITE#.
+func (r *dynamoDBThemeRepository) ListThemeInvitations(ctx context.Context, userID uuid.UUID) ([]theme.Invitation, error) {
+	queryInput := &dynamodb.QueryInput{
+		TableName:              aws.String(r.dbClient.TableName),
+		KeyConditionExpression: aws.String("PK = :pk AND begins_with(SK, :skPrefix)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":       &types.AttributeValueMemberS{Value: userPK(userID.String())},
+			":skPrefix": &types.AttributeValueMemberS{Value: invitationSKPrefix},
+		},
+	}
+	paginator := dynamodb.NewQueryPaginator(r.dbClient.Client, queryInput)
+	invitations := []theme.Invitation{}
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query invitations: %w", err)
+		}
+		var items []theme.Invitation
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &items); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal invitations: %w", err)
+		}
+		invitations = append(invitations, items...)
+	}
+	return invitations, nil
+}
+
+// AcceptThemeInvitation deletes the user's invitation to the theme and writes the link granting its role,
+// in one transaction conditioned on the invitation still existing.
+func (r *dynamoDBThemeRepository) AcceptThemeInvitation(ctx context.Context, userID, themeID uuid.UUID) (*theme.UserThemeLink, error) {
+	invitationKey := map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: userPK(userID.String())},
+		"SK": &types.AttributeValueMemberS{Value: invitationSK(themeID.String())},
+	}
+	result, err := r.dbClient.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.dbClient.TableName),
+		Key:       invitationKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invitation: %w", err)
+	}
+	if result.Item == nil {
+		return nil, domain.ErrNotFound
+	}
+	var invitation theme.Invitation
+	if err := attributevalue.UnmarshalMap(result.Item, &invitation); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal invitation: %w", err)
+	}
+
+	link := theme.UserThemeLink{UserID: userID, ThemeID: themeID, Role: invitation.Role}
+	setUserThemeLinkKeys(&link)
+	linkAV, err := attributevalue.MarshalMap(link)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal user-theme link: %w", err)
+	}
+	_, err = r.dbClient.Client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{Delete: &types.Delete{
+				TableName:           aws.String(r.dbClient.TableName),
+				Key:                 invitationKey,
+				ConditionExpression: aws.String("attribute_exists(SK)"), // Accepted or declined concurrently otherwise
+			}},
+			{Put: &types.Put{
+				TableName: aws.String(r.dbClient.TableName),
+				Item:      linkAV,
+			}},
+		},
+	})
+	if err != nil {
+		var txc *types.TransactionCanceledException
+		if errors.As(err, &txc) {
+			for _, reason := range txc.CancellationReasons {
+				if reason.Code != nil && *reason.Code == "ConditionalCheckFailed" {
+					return nil, domain.ErrNotFound
+				}
+			}
+		}
+		return nil, fmt.Errorf("failed to accept invitation: %w", err)
+	}
+	return &link, nil
+}
+
+// DeleteThemeInvitation removes an invitation. Removing one that does not exist is not an error.
+func (r *dynamoDBThemeRepository) DeleteThemeInvitation(ctx context.Context, userID, themeID uuid.UUID) error {
+	if _, err := r.dbClient.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.dbClient.TableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: userPK(userID.String())},
+			"SK": &types.AttributeValueMemberS{Value: invitationSK(themeID.String())},
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to delete invitation: %w", err)
+	}
+	return nil
+}
+
+// supportedFeaturesOrEmpty returns an empty slice for nil so the attribute is stored as an empty list.
+func supportedFeaturesOrEmpty(features []theme.SupportedFeature) []theme.SupportedFeature {
+	if features == nil {
+		return []theme.SupportedFeature{}
+	}
+	return features
+}
+
+// unmarshalTheme decodes a theme metadata item.
+// Items written before per-feature configuration existed store SupportedFeatures as a list of
+// feature names; those entries are read as SupportedFeature values with an empty config.
+func unmarshalTheme(item map[string]types.AttributeValue, out *theme.Theme) error {
+	if list, ok := item["SupportedFeatures"].(*types.AttributeValueMemberL); ok {
+		upgraded := make([]types.AttributeValue, len(list.Value))
+		legacy := false
+		for i, v := range list.Value {
+			if name, isString := v.(*types.AttributeValueMemberS); isString {
+				upgraded[i] = &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{"Name": name}}
+				legacy = true
+				continue
+			}
+			upgraded[i] = v
+		}
+		if legacy {
+			// Copy the item so the caller's map is left untouched
+			copied := make(map[string]types.AttributeValue, len(item))
+			for k, v := range item {
+				copied[k] = v
+			}
+			copied["SupportedFeatures"] = &types.AttributeValueMemberL{Value: upgraded}
+			item = copied
+		}
+	}
+	return attributevalue.UnmarshalMap(item, out)
 }
 
 // Helper functions for PK/SK generation are defined in repository.go
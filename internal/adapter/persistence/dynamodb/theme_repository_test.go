@@ -1,12 +1,15 @@
 package dynamodbrepo
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
@@ -118,7 +121,9 @@ func TestDynamoDBThemeRepository_GetThemeByID_Forbidden(t *testing.T) {
 	}
 	item, _ := attributevalue.MarshalMap(forbiddenTheme)
 
-	mockDB.On("GetItem", ctx, mock.AnythingOfType("*dynamodb.GetItemInput")).Return(&dynamodb.GetItemOutput{Item: item}, nil)
+	mockDB.On("GetItem", ctx, getItemKeyMatcher(themePK(testThemeID.String()), themeMetadataSK())).Return(&dynamodb.GetItemOutput{Item: item}, nil).Once()
+	// The theme is not shared with the user
+	mockDB.On("GetItem", ctx, getItemKeyMatcher(userPK(testUserID.String()), userThemeLinkSK(testThemeID.String()))).Return(&dynamodb.GetItemOutput{}, nil).Once()
 
 	theme, err := repo.GetThemeByID(ctx, testUserID, testThemeID)
 
@@ -128,6 +133,37 @@ func TestDynamoDBThemeRepository_GetThemeByID_Forbidden(t *testing.T) {
 	mockDB.AssertExpectations(t)
 }
 
+func TestDynamoDBThemeRepository_GetThemeByID_Shared(t *testing.T) {
+	repo, mockDB := setupThemeRepoTest()
+	ctx := context.Background()
+	memberID := uuid.New()
+	ownerID := uuid.New()
+	testThemeID := uuid.New()
+
+	item, _ := attributevalue.MarshalMap(&theme.Theme{ThemeID: testThemeID, ThemeName: "Team Calendar", OwnerUserID: &ownerID})
+	linkItem, _ := attributevalue.MarshalMap(theme.UserThemeLink{UserID: memberID, ThemeID: testThemeID, Role: theme.RoleEditor})
+	mockDB.On("GetItem", ctx, getItemKeyMatcher(themePK(testThemeID.String()), themeMetadataSK())).Return(&dynamodb.GetItemOutput{Item: item}, nil).Once()
+	mockDB.On("GetItem", ctx, getItemKeyMatcher(userPK(memberID.String()), userThemeLinkSK(testThemeID.String()))).Return(&dynamodb.GetItemOutput{Item: linkItem}, nil).Once()
+
+	th, err := repo.GetThemeByID(ctx, memberID, testThemeID)
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, th) {
+		assert.Equal(t, theme.RoleEditor, th.Role)
+		assert.Equal(t, ownerID, *th.OwnerUserID)
+	}
+	mockDB.AssertExpectations(t)
+}
+
+// getItemKeyMatcher matches GetItem calls for the item with the given keys.
+func getItemKeyMatcher(pk, sk string) interface{} {
+	return mock.MatchedBy(func(input *dynamodb.GetItemInput) bool {
+		pkAttr, pkOk := input.Key["PK"].(*types.AttributeValueMemberS)
+		skAttr, skOk := input.Key["SK"].(*types.AttributeValueMemberS)
+		return pkOk && pkAttr.Value == pk && skOk && skAttr.Value == sk
+	})
+}
+
 func TestDynamoDBThemeRepository_GetThemeByID_NotFound(t *testing.T) {
 	repo, mockDB := setupThemeRepoTest()
 	ctx := context.Background()
@@ -144,43 +180,86 @@ func TestDynamoDBThemeRepository_GetThemeByID_NotFound(t *testing.T) {
 	mockDB.AssertExpectations(t)
 }
 
+func TestDynamoDBThemeRepository_GetThemeByID_LegacySupportedFeatures(t *testing.T) {
+	repo, mockDB := setupThemeRepoTest()
+	ctx := context.Background()
+	testUserID := uuid.New()
+	testThemeID := uuid.New()
+
+	legacyTheme := &theme.Theme{
+		PK:        "THEME#" + testThemeID.String(),
+		SK:        "METADATA",
+		ThemeID:   testThemeID,
+		ThemeName: "Legacy Theme",
+		IsDefault: true,
+	}
+	item, _ := attributevalue.MarshalMap(legacyTheme)
+	// Items written before per-feature config stored plain feature names
+	item["SupportedFeatures"] = &types.AttributeValueMemberL{Value: []types.AttributeValue{
+		&types.AttributeValueMemberS{Value: "monthly_summary"},
+	}}
+
+	mockDB.On("GetItem", ctx, mock.AnythingOfType("*dynamodb.GetItemInput")).Return(&dynamodb.GetItemOutput{Item: item}, nil)
+
+	got, err := repo.GetThemeByID(ctx, testUserID, testThemeID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []theme.SupportedFeature{{Name: "monthly_summary"}}, got.SupportedFeatures)
+	mockDB.AssertExpectations(t)
+}
+
 func TestDynamoDBThemeRepository_ListThemes_Success(t *testing.T) {
 	repo, mockDB := setupThemeRepoTest()
 	ctx := context.Background()
 	testUserID := uuid.New()
-	otherUserID := uuid.New()
 
 	defaultTheme := theme.Theme{ThemeID: uuid.New(), ThemeName: "Default", IsDefault: true}
 	userTheme := theme.Theme{ThemeID: uuid.New(), ThemeName: "My Theme", IsDefault: false, OwnerUserID: &testUserID}
-	otherTheme := theme.Theme{ThemeID: uuid.New(), ThemeName: "Other User Theme", IsDefault: false, OwnerUserID: &otherUserID}
+	otherUserID := uuid.New()
+	sharedTheme := theme.Theme{ThemeID: uuid.New(), ThemeName: "Shared Theme", IsDefault: false, OwnerUserID: &otherUserID}
 
 	itemDefault, _ := attributevalue.MarshalMap(defaultTheme)
 	itemUser, _ := attributevalue.MarshalMap(userTheme)
-	itemOther, _ := attributevalue.MarshalMap(otherTheme)
-
-	// Mock Scan to return all three themes
-	mockDB.On("Scan", ctx, mock.MatchedBy(func(input *dynamodb.ScanInput) bool {
-		return *input.TableName == repo.dbClient.TableName &&
-			*input.FilterExpression == "SK = :md"
-	})).Return(&dynamodb.ScanOutput{Items: []map[string]types.AttributeValue{itemDefault, itemUser, itemOther}, Count: 3}, nil)
+	itemShared, _ := attributevalue.MarshalMap(sharedTheme)
+
+	// A link whose theme was deleted is skipped
+	missingThemeID := uuid.New()
+	linkUser, _ := attributevalue.MarshalMap(theme.UserThemeLink{PK: "USER#" + testUserID.String(), SK: "THEME#" + userTheme.ThemeID.String(), UserID: testUserID, ThemeID: userTheme.ThemeID})
+	linkMissing, _ := attributevalue.MarshalMap(theme.UserThemeLink{PK: "USER#" + testUserID.String(), SK: "THEME#" + missingThemeID.String(), UserID: testUserID, ThemeID: missingThemeID})
+	linkShared, _ := attributevalue.MarshalMap(theme.UserThemeLink{PK: "USER#" + testUserID.String(), SK: "THEME#" + sharedTheme.ThemeID.String(), UserID: testUserID, ThemeID: sharedTheme.ThemeID, Role: theme.RoleViewer})
+
+	// Default themes come from GSI1, custom themes through the user's link items
+	mockDB.On("Query", ctx, mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+		pk, ok := input.ExpressionAttributeValues[":pk"].(*types.AttributeValueMemberS)
+		return input.IndexName != nil && *input.IndexName == "GSI1" && ok && pk.Value == "DEFAULT#THEME"
+	})).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{itemDefault}, Count: 1}, nil).Once()
+	mockDB.On("Query", ctx, mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+		pk, ok := input.ExpressionAttributeValues[":pk"].(*types.AttributeValueMemberS)
+		return input.IndexName == nil && ok && pk.Value == "USER#"+testUserID.String() &&
+			*input.KeyConditionExpression == "PK = :pk AND begins_with(SK, :skPrefix)"
+	})).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{linkUser, linkMissing, linkShared}, Count: 3}, nil).Once()
+	mockDB.On("BatchGetItem", ctx, mock.MatchedBy(func(input *dynamodb.BatchGetItemInput) bool {
+		keys := input.RequestItems[repo.dbClient.TableName].Keys
+		if len(keys) != 3 {
+			return false
+		}
+		pk, _ := keys[0]["PK"].(*types.AttributeValueMemberS)
+		sk, _ := keys[0]["SK"].(*types.AttributeValueMemberS)
+		return pk != nil && pk.Value == "THEME#"+userTheme.ThemeID.String() && sk != nil && sk.Value == "METADATA"
+	})).Return(&dynamodb.BatchGetItemOutput{Responses: map[string][]map[string]types.AttributeValue{repo.dbClient.TableName: {itemShared, itemUser}}}, nil).Once()
 
 	themes, err := repo.ListThemes(ctx, testUserID)
 
 	assert.NoError(t, err)
-	assert.Len(t, themes, 2) // Should include default and user's theme, exclude other user's theme
+	assert.Len(t, themes, 3) // Should include default, user's and shared theme
 
-	foundDefault := false
-	foundUser := false
+	roles := map[uuid.UUID]theme.Role{}
 	for _, th := range themes {
-		if th.ThemeID == defaultTheme.ThemeID {
-			foundDefault = true
-		}
-		if th.ThemeID == userTheme.ThemeID {
-			foundUser = true
-		}
+		roles[th.ThemeID] = th.Role
 	}
-	assert.True(t, foundDefault, "Default theme not found in list")
-	assert.True(t, foundUser, "User's theme not found in list")
+	assert.Equal(t, theme.RoleContributor, roles[defaultTheme.ThemeID], "Default theme not found in list")
+	assert.Equal(t, theme.RoleOwner, roles[userTheme.ThemeID], "User's theme not found in list")
+	assert.Equal(t, theme.RoleViewer, roles[sharedTheme.ThemeID], "Shared theme not found in list")
 
 	mockDB.AssertExpectations(t)
 }
@@ -193,7 +272,7 @@ func TestDynamoDBThemeRepository_CreateTheme_Success(t *testing.T) {
 		ThemeName:         "New Custom Theme",
 		Fields:            []theme.ThemeField{{Name: "field1", Type: theme.FieldTypeText}},
 		OwnerUserID:       &testUserID,
-		SupportedFeatures: []string{"summary"}, // Add features
+		SupportedFeatures: []theme.SupportedFeature{{Name: "summary"}}, // Add features
 	}
 
 	// Expect PutItem for metadata
@@ -223,12 +302,26 @@ func TestDynamoDBThemeRepository_CreateTheme_Success(t *testing.T) {
 			link.ThemeID == testTheme.ThemeID
 	})).Return(&dynamodb.PutItemOutput{}, nil).Once() // Expect once for link
 
+	// Expect PutItem for the first revision
+	mockDB.On("PutItem", ctx, mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+		var rev theme.Revision
+		err := attributevalue.UnmarshalMap(input.Item, &rev)
+		assert.NoError(t, err)
+		return *input.TableName == repo.dbClient.TableName &&
+			strings.HasPrefix(rev.PK, "THEME#") &&
+			rev.SK == "REV#0000000001" &&
+			rev.Revision == 1 &&
+			rev.ChangedBy == testUserID &&
+			*input.ConditionExpression == "attribute_not_exists(SK)"
+	})).Return(&dynamodb.PutItemOutput{}, nil).Once() // Expect once for revision
+
 	err := repo.CreateTheme(ctx, testTheme)
 
 	assert.NoError(t, err)
 	assert.NotEqual(t, uuid.Nil, testTheme.ThemeID) // Ensure ThemeID was generated
+	assert.Equal(t, 1, testTheme.Revision)
 	assert.False(t, testTheme.IsDefault)
-	assert.Equal(t, []string{"summary"}, testTheme.SupportedFeatures) // Check features remain
+	assert.Equal(t, []theme.SupportedFeature{{Name: "summary"}}, testTheme.SupportedFeatures) // Check features remain
 	mockDB.AssertExpectations(t)
 }
 
@@ -238,24 +331,32 @@ func TestDynamoDBThemeRepository_UpdateTheme_Success(t *testing.T) {
 	testUserID := uuid.New()
 	testThemeID := uuid.New()
 	themeToUpdate := &theme.Theme{
-		ThemeID:           testThemeID,
-		ThemeName:         "Updated Name",
-		Fields:            []theme.ThemeField{{Name: "new_field", Type: theme.FieldTypeBoolean}},
-		OwnerUserID:       &testUserID,
-		SupportedFeatures: []string{"feature1", "feature2"}, // Add supported features
-		IsDefault:         false,                            // Ensure it's not default for the update condition
+		ThemeID:     testThemeID,
+		ThemeName:   "Updated Name",
+		Fields:      []theme.ThemeField{{Name: "new_field", Type: theme.FieldTypeBoolean}},
+		OwnerUserID: &testUserID,
+		SupportedFeatures: []theme.SupportedFeature{ // Add supported features
+			{Name: "feature1"},
+			{Name: "feature2", Config: theme.FeatureConfig{Fields: map[string]string{"target": "new_field"}}},
+		},
+		IsDefault: false, // Ensure it's not default for the update condition
+		Revision:  3,
 		// CreatedAt should not be changed by UpdateTheme
 	}
 
 	// Mock the GetThemeByID check that happens inside UpdateTheme on conditional failure (not expected here, but good practice)
 	// We don't mock GetThemeByID directly here because the success path doesn't call it.
 
-	// Mock UpdateItem for metadata
-	mockDB.On("UpdateItem", ctx, mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
+	// Mock TransactWriteItems for the metadata update and the revision
+	mockDB.On("TransactWriteItems", ctx, mock.MatchedBy(func(tx *dynamodb.TransactWriteItemsInput) bool {
 		expectedPK := "THEME#" + testThemeID.String()
 		expectedSK := "METADATA"
 
-		if input == nil || input.TableName == nil || input.UpdateExpression == nil || input.ConditionExpression == nil {
+		if tx == nil || len(tx.TransactItems) != 2 || tx.TransactItems[0].Update == nil || tx.TransactItems[1].Put == nil {
+			return false
+		}
+		input := tx.TransactItems[0].Update
+		if input.TableName == nil || input.UpdateExpression == nil || input.ConditionExpression == nil {
 			return false
 		}
 
@@ -268,19 +369,19 @@ func TestDynamoDBThemeRepository_UpdateTheme_Success(t *testing.T) {
 		}
 
 		// Check UpdateExpression includes SupportedFeatures
-		if *input.UpdateExpression != "SET ThemeName = :name, Fields = :fields, UpdatedAt = :updatedAt, SupportedFeatures = :features" {
-			t.Logf("UpdateExpression mismatch: expected %q, got %q", "SET ThemeName = :name, Fields = :fields, UpdatedAt = :updatedAt, SupportedFeatures = :features", *input.UpdateExpression)
+		if *input.UpdateExpression != "SET ThemeName = :name, Fields = :fields, UpdatedAt = :updatedAt, SupportedFeatures = :features, SchemaVersion = :schemaVersion, Migrations = :migrations, Revision = :revision, UpdatedBy = :updatedBy REMOVE RestoredFrom, GSI1PK, GSI1SK" {
+			t.Logf("UpdateExpression mismatch: expected %q, got %q", "SET ThemeName = :name, Fields = :fields, UpdatedAt = :updatedAt, SupportedFeatures = :features, SchemaVersion = :schemaVersion, Migrations = :migrations, Revision = :revision, UpdatedBy = :updatedBy REMOVE RestoredFrom, GSI1PK, GSI1SK", *input.UpdateExpression)
 			return false
 		}
 
 		// Check ConditionExpression
-		if *input.ConditionExpression != "attribute_exists(PK) AND attribute_exists(SK) AND IsDefault = :false AND OwnerUserID = :userId" {
-			t.Logf("ConditionExpression mismatch: expected %q, got %q", "attribute_exists(PK) AND attribute_exists(SK) AND IsDefault = :false AND OwnerUserID = :userId", *input.ConditionExpression)
+		if *input.ConditionExpression != "attribute_exists(PK) AND attribute_exists(SK) AND IsDefault = :false AND OwnerUserID = :userId AND (attribute_not_exists(Revision) OR Revision = :previousRevision)" {
+			t.Logf("ConditionExpression mismatch: expected %q, got %q", "attribute_exists(PK) AND attribute_exists(SK) AND IsDefault = :false AND OwnerUserID = :userId AND (attribute_not_exists(Revision) OR Revision = :previousRevision)", *input.ConditionExpression)
 			return false
 		}
 
 		// Check ExpressionAttributeValues contains all expected keys
-		expectedKeys := []string{":name", ":fields", ":updatedAt", ":features", ":false", ":userId"}
+		expectedKeys := []string{":name", ":fields", ":updatedAt", ":features", ":schemaVersion", ":migrations", ":revision", ":updatedBy", ":false", ":userId", ":previousRevision"}
 		if len(input.ExpressionAttributeValues) != len(expectedKeys) {
 			t.Logf("ExpressionAttributeValues length mismatch: expected %d, got %d", len(expectedKeys), len(input.ExpressionAttributeValues))
 			return false
@@ -292,8 +393,8 @@ func TestDynamoDBThemeRepository_UpdateTheme_Success(t *testing.T) {
 			}
 		}
 		// Optionally, check specific values like :userId
-		userIdAttr, userIdOk := input.ExpressionAttributeValues[":userId"].(*types.AttributeValueMemberS)
-		if !userIdOk || userIdAttr.Value != testUserID.String() {
+		userIdAttr, userIdOk := input.ExpressionAttributeValues[":userId"].(*types.AttributeValueMemberB)
+		if !userIdOk || !bytes.Equal(userIdAttr.Value, testUserID[:]) {
 			t.Logf("ExpressionAttributeValues[:userId] mismatch or wrong type")
 			return false
 		}
@@ -303,15 +404,27 @@ func TestDynamoDBThemeRepository_UpdateTheme_Success(t *testing.T) {
 			t.Logf("ExpressionAttributeValues[:features] wrong type")
 			return false
 		}
-		var actualFeatures []string
+		var actualFeatures []theme.SupportedFeature
 		err := attributevalue.Unmarshal(featuresAttr, &actualFeatures)
 		if err != nil || !assert.ObjectsAreEqual(themeToUpdate.SupportedFeatures, actualFeatures) {
 			t.Logf("ExpressionAttributeValues[:features] mismatch: expected %v, got %v (err: %v)", themeToUpdate.SupportedFeatures, actualFeatures, err)
 			return false
 		}
+		previousAttr, previousOk := input.ExpressionAttributeValues[":previousRevision"].(*types.AttributeValueMemberN)
+		if !previousOk || previousAttr.Value != "2" {
+			t.Logf("ExpressionAttributeValues[:previousRevision] mismatch or wrong type")
+			return false
+		}
 
-		return true
-	})).Return(&dynamodb.UpdateItemOutput{}, nil).Once() // Expect metadata update once
+		// Check the revision snapshot
+		var rev theme.Revision
+		if err := attributevalue.UnmarshalMap(tx.TransactItems[1].Put.Item, &rev); err != nil {
+			return false
+		}
+		return rev.PK == expectedPK && rev.SK == "REV#0000000003" && rev.Revision == 3 &&
+			rev.ThemeName == themeToUpdate.ThemeName && rev.ChangedBy == testUserID &&
+			*tx.TransactItems[1].Put.ConditionExpression == "attribute_not_exists(SK)"
+	})).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once() // Expect the transaction once
 
 	// Mock UpdateItem for user link
 	mockDB.On("UpdateItem", ctx, mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
@@ -350,7 +463,7 @@ func TestDynamoDBThemeRepository_UpdateTheme_Success(t *testing.T) {
 
 	assert.NoError(t, err)
 	mockDB.AssertExpectations(t)
-	mockDB.AssertNumberOfCalls(t, "UpdateItem", 2) // Ensure both updates were called
+	mockDB.AssertNumberOfCalls(t, "UpdateItem", 1) // Only the link is updated outside the transaction
 }
 
 func TestDynamoDBThemeRepository_DeleteTheme_Success(t *testing.T) {
@@ -395,6 +508,38 @@ func TestDynamoDBThemeRepository_DeleteTheme_Success(t *testing.T) {
 			assert.ObjectsAreEqual(expectedLinkKey, input.Key)
 	})).Return(&dynamodb.DeleteItemOutput{}, nil).Once()
 
+	// Expect the members to be listed from GSI1 and their links deleted
+	memberID := uuid.New()
+	ownerLinkItem, _ := attributevalue.MarshalMap(theme.UserThemeLink{UserID: testUserID, ThemeID: testThemeID, Role: theme.RoleOwner})
+	memberLinkItem, _ := attributevalue.MarshalMap(theme.UserThemeLink{UserID: memberID, ThemeID: testThemeID, Role: theme.RoleViewer})
+	mockDB.On("Query", ctx, mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+		pkAttr, pkOk := input.ExpressionAttributeValues[":pk"].(*types.AttributeValueMemberS)
+		return input.IndexName != nil && *input.IndexName == "GSI1" && pkOk && pkAttr.Value == getPK
+	})).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{ownerLinkItem, memberLinkItem}}, nil).Once()
+	expectedMemberLinkKey := map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: userPK(memberID.String())},
+		"SK": &types.AttributeValueMemberS{Value: userThemeLinkSK(testThemeID.String())},
+	}
+	mockDB.On("DeleteItem", ctx, mock.MatchedBy(func(input *dynamodb.DeleteItemInput) bool {
+		return assert.ObjectsAreEqual(expectedMemberLinkKey, input.Key)
+	})).Return(&dynamodb.DeleteItemOutput{}, nil).Once()
+
+	// Expect the revisions to be queried and deleted
+	revKey := map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: themePK(testThemeID.String())},
+		"SK": &types.AttributeValueMemberS{Value: themeRevisionSK(1)},
+	}
+	mockDB.On("Query", ctx, mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+		pkAttr, pkOk := input.ExpressionAttributeValues[":pk"].(*types.AttributeValueMemberS)
+		prefixAttr, prefixOk := input.ExpressionAttributeValues[":skPrefix"].(*types.AttributeValueMemberS)
+		return pkOk && pkAttr.Value == getPK && prefixOk && prefixAttr.Value == "REV#"
+	})).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{revKey}}, nil).Once()
+	mockDB.On("BatchWriteItem", ctx, mock.MatchedBy(func(input *dynamodb.BatchWriteItemInput) bool {
+		requests := input.RequestItems[repo.dbClient.TableName]
+		return len(requests) == 1 && requests[0].DeleteRequest != nil &&
+			assert.ObjectsAreEqual(revKey, requests[0].DeleteRequest.Key)
+	})).Return(&dynamodb.BatchWriteItemOutput{}, nil).Once()
+
 	err := repo.DeleteTheme(ctx, testUserID, testThemeID)
 
 	assert.NoError(t, err)
@@ -456,6 +601,9 @@ func TestDynamoDBThemeRepository_DeleteTheme_Forbidden(t *testing.T) {
 		skAttr, skOk := input.Key["SK"].(*types.AttributeValueMemberS)
 		return pkOk && pkAttr.Value == getPK && skOk && skAttr.Value == getSK
 	})).Return(&dynamodb.GetItemOutput{Item: item}, nil).Once() // GetItem succeeds, but logic inside GetThemeByID returns forbidden
+	// The theme is shared with the user, but only the owner can delete it
+	linkItem, _ := attributevalue.MarshalMap(theme.UserThemeLink{UserID: testUserID, ThemeID: testThemeID, Role: theme.RoleEditor})
+	mockDB.On("GetItem", ctx, getItemKeyMatcher(userPK(testUserID.String()), userThemeLinkSK(testThemeID.String()))).Return(&dynamodb.GetItemOutput{Item: linkItem}, nil).Once()
 
 	err := repo.DeleteTheme(ctx, testUserID, testThemeID)
 
@@ -566,3 +714,256 @@ func TestDynamoDBThemeRepository_CreateTheme_DBError_Link(t *testing.T) {
 	mockDB.AssertNumberOfCalls(t, "PutItem", 2)    // Both PutItem calls were attempted
 	mockDB.AssertNumberOfCalls(t, "DeleteItem", 1) // Rollback DeleteItem was called
 }
+
+func TestDynamoDBThemeRepository_UpdateTheme_Conflict(t *testing.T) {
+	repo, mockDB := setupThemeRepoTest()
+	ctx := context.Background()
+	testUserID := uuid.New()
+	themeToUpdate := &theme.Theme{
+		ThemeID:     uuid.New(),
+		ThemeName:   "Updated Name",
+		Fields:      []theme.ThemeField{{Name: "field1", Type: theme.FieldTypeText}},
+		OwnerUserID: &testUserID,
+		Revision:    2,
+	}
+
+	// Another update recorded revision 2 first
+	canceled := &types.TransactionCanceledException{CancellationReasons: []types.CancellationReason{
+		{Code: aws.String("ConditionalCheckFailed")},
+		{Code: aws.String("ConditionalCheckFailed")},
+	}}
+	mockDB.On("TransactWriteItems", ctx, mock.Anything).Return(nil, canceled).Once()
+
+	err := repo.UpdateTheme(ctx, themeToUpdate)
+
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	mockDB.AssertExpectations(t)
+	mockDB.AssertNotCalled(t, "UpdateItem", mock.Anything, mock.Anything)
+}
+
+func TestDynamoDBThemeRepository_ListThemeRevisions(t *testing.T) {
+	repo, mockDB := setupThemeRepoTest()
+	ctx := context.Background()
+	testThemeID := uuid.New()
+	newer, _ := attributevalue.MarshalMap(theme.Revision{ThemeID: testThemeID, Revision: 2, ThemeName: "Renamed"})
+	older, _ := attributevalue.MarshalMap(theme.Revision{ThemeID: testThemeID, Revision: 1, ThemeName: "Original"})
+
+	mockDB.On("Query", ctx, mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+		pkAttr, pkOk := input.ExpressionAttributeValues[":pk"].(*types.AttributeValueMemberS)
+		return pkOk && pkAttr.Value == themePK(testThemeID.String()) &&
+			*input.KeyConditionExpression == "PK = :pk AND begins_with(SK, :skPrefix)" &&
+			input.ScanIndexForward != nil && !*input.ScanIndexForward // Newest first
+	})).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{newer, older}}, nil).Once()
+
+	revisions, err := repo.ListThemeRevisions(ctx, testThemeID)
+
+	assert.NoError(t, err)
+	if assert.Len(t, revisions, 2) {
+		assert.Equal(t, 2, revisions[0].Revision)
+		assert.Equal(t, "Original", revisions[1].ThemeName)
+	}
+	mockDB.AssertExpectations(t)
+}
+
+func TestDynamoDBThemeRepository_PutDefaultTheme(t *testing.T) {
+	repo, mockDB := setupThemeRepoTest()
+	ctx := context.Background()
+	def := &theme.Theme{ThemeID: uuid.New(), ThemeName: "Default", Revision: 2, SchemaVersion: 1,
+		Fields: []theme.ThemeField{{Name: "title", Label: "Title", Type: theme.FieldTypeText}}}
+
+	mockDB.On("TransactWriteItems", ctx, mock.MatchedBy(func(input *dynamodb.TransactWriteItemsInput) bool {
+		if len(input.TransactItems) != 2 {
+			return false
+		}
+		var meta theme.Theme
+		metaPut, revPut := input.TransactItems[0].Put, input.TransactItems[1].Put
+		if err := attributevalue.UnmarshalMap(metaPut.Item, &meta); err != nil {
+			return false
+		}
+		previous, _ := metaPut.ExpressionAttributeValues[":previousRevision"].(*types.AttributeValueMemberN)
+		revSK, _ := revPut.Item["SK"].(*types.AttributeValueMemberS)
+		return meta.IsDefault && meta.OwnerUserID == nil &&
+			meta.GSI1PK == "DEFAULT#THEME" && meta.GSI1SK == "THEME#"+def.ThemeID.String() &&
+			*metaPut.ConditionExpression == "IsDefault = :true AND Revision = :previousRevision" &&
+			previous != nil && previous.Value == "1" &&
+			revSK != nil && revSK.Value == "REV#0000000002"
+	})).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once()
+
+	assert.NoError(t, repo.PutDefaultTheme(ctx, def))
+
+	// A seeder that lost the race gets a conflict
+	mockDB.On("TransactWriteItems", ctx, mock.Anything).Return(nil, &types.TransactionCanceledException{
+		CancellationReasons: []types.CancellationReason{{Code: aws.String("ConditionalCheckFailed")}, {Code: aws.String("None")}},
+	}).Once()
+
+	assert.ErrorIs(t, repo.PutDefaultTheme(ctx, def), domain.ErrConflict)
+	mockDB.AssertExpectations(t)
+}
+
+func TestDynamoDBThemeRepository_AcceptThemeInvitation(t *testing.T) {
+	repo, mockDB := setupThemeRepoTest()
+	ctx := context.Background()
+	inviteeID := uuid.New()
+	testThemeID := uuid.New()
+
+	invitation, _ := attributevalue.MarshalMap(theme.Invitation{UserID: inviteeID, ThemeID: testThemeID, Role: theme.RoleContributor})
+	mockDB.On("GetItem", ctx, getItemKeyMatcher(userPK(inviteeID.String()), "INVITE#"+testThemeID.String())).Return(&dynamodb.GetItemOutput{Item: invitation}, nil).Once()
+	mockDB.On("TransactWriteItems", ctx, mock.MatchedBy(func(input *dynamodb.TransactWriteItemsInput) bool {
+		if len(input.TransactItems) != 2 || input.TransactItems[0].Delete == nil || input.TransactItems[1].Put == nil {
+			return false
+		}
+		var link theme.UserThemeLink
+		if err := attributevalue.UnmarshalMap(input.TransactItems[1].Put.Item, &link); err != nil {
+			return false
+		}
+		return *input.TransactItems[0].Delete.ConditionExpression == "attribute_exists(SK)" &&
+			link.PK == "USER#"+inviteeID.String() && link.SK == "THEME#"+testThemeID.String() &&
+			link.GSI1PK == "THEME#"+testThemeID.String() && link.GSI1SK == "MEMBER#"+inviteeID.String() &&
+			link.Role == theme.RoleContributor
+	})).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once()
+
+	link, err := repo.AcceptThemeInvitation(ctx, inviteeID, testThemeID)
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, link) {
+		assert.Equal(t, theme.RoleContributor, link.Role)
+	}
+
+	// Without a pending invitation there is nothing to accept
+	mockDB.On("GetItem", ctx, mock.Anything).Return(&dynamodb.GetItemOutput{}, nil).Once()
+
+	_, err = repo.AcceptThemeInvitation(ctx, inviteeID, testThemeID)
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	mockDB.AssertExpectations(t)
+}
+
+func TestDynamoDBThemeRepository_ScheduledThemeIndex(t *testing.T) {
+	repo, mockDB := setupThemeRepoTest()
+	ctx := context.Background()
+	ownerID := uuid.New()
+	scheduled := &theme.Theme{
+		ThemeName:   "Scheduled",
+		OwnerUserID: &ownerID,
+		SupportedFeatures: []theme.SupportedFeature{
+			{Name: "monthly_summary", Schedule: &theme.FeatureSchedule{Cron: "@monthly"}},
+		},
+	}
+
+	// CreateTheme puts the theme into the scheduled themes' index
+	var stored map[string]types.AttributeValue
+	mockDB.On("PutItem", ctx, mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+		sk, _ := input.Item["SK"].(*types.AttributeValueMemberS)
+		return sk != nil && sk.Value == "METADATA"
+	})).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*dynamodb.PutItemInput).Item
+	}).Return(&dynamodb.PutItemOutput{}, nil).Once()
+	mockDB.On("PutItem", ctx, mock.Anything).Return(&dynamodb.PutItemOutput{}, nil).Twice()
+
+	assert.NoError(t, repo.CreateTheme(ctx, scheduled))
+	var meta theme.Theme
+	assert.NoError(t, attributevalue.UnmarshalMap(stored, &meta))
+	assert.Equal(t, "SCHEDULED#THEME", meta.GSI1PK)
+	assert.Equal(t, "THEME#"+scheduled.ThemeID.String(), meta.GSI1SK)
+
+	// ListScheduledThemes queries that index instead of scanning the table
+	mockDB.On("Query", ctx, mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+		pk, _ := input.ExpressionAttributeValues[":pk"].(*types.AttributeValueMemberS)
+		return input.IndexName != nil && *input.IndexName == "GSI1" && pk != nil && pk.Value == "SCHEDULED#THEME"
+	})).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{stored}}, nil).Once()
+
+	themes, err := repo.ListScheduledThemes(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, themes, 1)
+	assert.Equal(t, scheduled.ThemeID, themes[0].ThemeID)
+
+	// UpdateTheme keeps the theme in the index while it has schedules
+	mockDB.On("TransactWriteItems", ctx, mock.MatchedBy(func(tx *dynamodb.TransactWriteItemsInput) bool {
+		update := tx.TransactItems[0].Update
+		gsi1pk, _ := update.ExpressionAttributeValues[":gsi1pk"].(*types.AttributeValueMemberS)
+		return strings.Contains(*update.UpdateExpression, "GSI1PK = :gsi1pk, GSI1SK = :gsi1sk") &&
+			strings.HasSuffix(*update.UpdateExpression, " REMOVE RestoredFrom") &&
+			gsi1pk != nil && gsi1pk.Value == "SCHEDULED#THEME"
+	})).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once()
+	mockDB.On("UpdateItem", ctx, mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+
+	scheduled.Revision++
+	assert.NoError(t, repo.UpdateTheme(ctx, scheduled))
+	mockDB.AssertExpectations(t)
+}
+
+// applyUpdate applies the SET and REMOVE clauses of an update expression made of plain
+// "Name = :value" assignments to a stored item, as DynamoDB would.
+func applyUpdate(t *testing.T, item map[string]types.AttributeValue, expr string, values map[string]types.AttributeValue) map[string]types.AttributeValue {
+	updated := make(map[string]types.AttributeValue, len(item))
+	for k, v := range item {
+		updated[k] = v
+	}
+	set, remove, _ := strings.Cut(strings.TrimPrefix(expr, "SET "), " REMOVE ")
+	for _, assignment := range strings.Split(set, ", ") {
+		name, placeholder, ok := strings.Cut(assignment, " = ")
+		if !assert.True(t, ok, "unexpected assignment %q", assignment) {
+			continue
+		}
+		value, ok := values[placeholder]
+		assert.True(t, ok, "missing value for %s", placeholder)
+		updated[name] = value
+	}
+	if remove != "" {
+		for _, name := range strings.Split(remove, ", ") {
+			delete(updated, name)
+		}
+	}
+	return updated
+}
+
+// assertEqualityConditions checks that every "Name = :value" comparison of a condition expression
+// holds on a stored item, as DynamoDB would compare them (type and value).
+func assertEqualityConditions(t *testing.T, item map[string]types.AttributeValue, cond string, values map[string]types.AttributeValue) {
+	for _, clause := range regexp.MustCompile(`(\w+) = (:\w+)`).FindAllStringSubmatch(cond, -1) {
+		assert.Equal(t, values[clause[2]], item[clause[1]], "condition %s = %s does not hold", clause[1], clause[2])
+	}
+}
+
+func TestDynamoDBThemeRepository_UpdateTheme_StoredItemRoundTrip(t *testing.T) {
+	repo, mockDB := setupThemeRepoTest()
+	ctx := context.Background()
+	ownerID, editorID := uuid.New(), uuid.New()
+	stored := theme.Theme{
+		PK:          "THEME#x",
+		SK:          "METADATA",
+		ThemeID:     uuid.New(),
+		ThemeName:   "Diary",
+		Fields:      []theme.ThemeField{{Name: "note", Label: "Note", Type: theme.FieldTypeText}},
+		OwnerUserID: &ownerID,
+		Revision:    1,
+	}
+	stored.PK = themePK(stored.ThemeID.String())
+	item, err := attributevalue.MarshalMap(stored)
+	assert.NoError(t, err)
+
+	var updated map[string]types.AttributeValue
+	mockDB.On("TransactWriteItems", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		update := args.Get(1).(*dynamodb.TransactWriteItemsInput).TransactItems[0].Update
+		assertEqualityConditions(t, item, *update.ConditionExpression, update.ExpressionAttributeValues)
+		updated = applyUpdate(t, item, *update.UpdateExpression, update.ExpressionAttributeValues)
+	}).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once()
+	mockDB.On("UpdateItem", ctx, mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+
+	th := stored
+	th.ThemeName = "Journal"
+	th.Revision = 2
+	th.UpdatedBy = &editorID
+	assert.NoError(t, repo.UpdateTheme(ctx, &th))
+
+	// The updated item still reads back as a theme, with the attributes typed as CreateTheme stores them
+	var read theme.Theme
+	assert.NoError(t, unmarshalTheme(updated, &read))
+	assert.Equal(t, "Journal", read.ThemeName)
+	assert.Equal(t, 2, read.Revision)
+	if assert.NotNil(t, read.UpdatedBy) {
+		assert.Equal(t, editorID, *read.UpdatedBy)
+	}
+	assert.Equal(t, ownerID, *read.OwnerUserID)
+	mockDB.AssertExpectations(t)
+}
@@ -22,12 +22,58 @@ type Entry struct {
 	Data      map[string]interface{} `dynamodbav:"Data"`      // Custom fields data
 	CreatedAt time.Time              `dynamodbav:"CreatedAt"`
 	UpdatedAt time.Time              `dynamodbav:"UpdatedAt"`
+	// IDs of the entries the reference fields point at (see IndexReferences), for backlink queries
+	ReferencedEntryIDs []string `dynamodbav:"ReferencedEntryIDs,omitempty"`
+	// Geohash of each geo field value by field name (see IndexGeohashes), for proximity queries
+	Geohashes map[string]string `dynamodbav:"Geohashes,omitempty"`
+	// Schema version of the theme the data was written with (see Migrate)
+	SchemaVersion int `dynamodbav:"SchemaVersion,omitempty"`
 	// GSI1 Keys for querying by date range
 	GSI1PK string `dynamodbav:"GSI1PK"` // Same as PK: USER#<user_id>
 	GSI1SK string `dynamodbav:"GSI1SK"` // ENTRY_DATE#<entry_date>#<theme_id>#<entry_id> (Updated based on design doc GSI-1)
 }
 
-// ValidateDataAgainstTheme checks if the entry's data matches the theme's field definitions.
+// ApplyDefaults sets the default value of each field with one that the entry's data omits.
+// Fields present with a null value are left as they are.
+func (e *Entry) ApplyDefaults(fields []theme.ThemeField) {
+	for _, field := range fields {
+		if field.Default == nil {
+			continue
+		}
+		if _, exists := e.Data[field.Name]; exists {
+			continue
+		}
+		if e.Data == nil {
+			e.Data = make(map[string]interface{})
+		}
+		e.Data[field.Name] = field.Default
+	}
+}
+
+// Migrate brings the entry's data up to the theme's current schema version.
+// It reports whether the entry was written with an older version.
+func (e *Entry) Migrate(th *theme.Theme) bool {
+	if e.SchemaVersion >= th.SchemaVersion {
+		return false
+	}
+	e.Data = th.MigrateData(e.Data, e.SchemaVersion)
+	e.SchemaVersion = th.SchemaVersion
+	return true
+}
+
+// RejectComputedValues fails if the entry's data holds a value for a computed field,
+// which only the server writes.
+func (e *Entry) RejectComputedValues(fields []theme.ThemeField) error {
+	for _, field := range fields {
+		if _, exists := e.Data[field.Name]; exists && field.IsComputed() {
+			return fmt.Errorf("field '%s' is computed and cannot be written", field.Name)
+		}
+	}
+	return nil
+}
+
+// ValidateDataAgainstTheme checks if the entry's data matches the theme's field definitions,
+// including their allowed options, bounds, lengths and patterns.
 func (e *Entry) ValidateDataAgainstTheme(fields []theme.ThemeField) error {
 	definedFields := make(map[string]theme.ThemeField)
 	for _, f := range fields {
@@ -50,6 +96,11 @@ func (e *Entry) ValidateDataAgainstTheme(fields []theme.ThemeField) error {
 					return fmt.Errorf("required field '%s' cannot be empty", field.Name)
 				}
 			}
+			if field.Type.IsList() {
+				if items, ok := theme.ListItems(val); !ok || len(items) == 0 {
+					return fmt.Errorf("required field '%s' cannot be empty", field.Name)
+				}
+			}
 		}
 	}
 
@@ -65,55 +116,43 @@ func (e *Entry) ValidateDataAgainstTheme(fields []theme.ThemeField) error {
 			continue
 		}
 
-		// Type validation logic
-		switch fieldDef.Type {
-		case theme.FieldTypeText, theme.FieldTypeTextarea, theme.FieldTypeSelect:
-			if _, ok := value.(string); !ok {
-				return fmt.Errorf("field '%s' expects a string, got %T", key, value)
-			}
-		case theme.FieldTypeNumber:
-			// Allow int or float64 from JSON unmarshalling
-			switch value.(type) {
-			case float64, int, int32, int64:
-				// OK
-			default:
-				return fmt.Errorf("field '%s' expects a number, got %T", key, value)
-			}
-		case theme.FieldTypeBoolean:
-			if _, ok := value.(bool); !ok {
-				return fmt.Errorf("field '%s' expects a boolean, got %T", key, value)
-			}
-		case theme.FieldTypeDate:
-			valStr, ok := value.(string)
-			if !ok {
-				return fmt.Errorf("field '%s' expects a date string (YYYY-MM-DD), got %T", key, value)
-			}
-			if _, err := time.Parse("2006-01-02", valStr); err != nil {
-				return fmt.Errorf("field '%s' has invalid date format: %v. Expected YYYY-MM-DD", key, err)
-			}
-		case theme.FieldTypeDateTime:
-			valStr, ok := value.(string)
-			if !ok {
-				return fmt.Errorf("field '%s' expects a datetime string (RFC3339), got %T", key, value)
-			}
-			if _, err := time.Parse(time.RFC3339, valStr); err != nil {
-				return fmt.Errorf("field '%s' has invalid datetime format: %v. Expected RFC3339", key, err)
-			}
-		default:
-			return fmt.Errorf("internal error: unknown field type '%s' for field '%s'", fieldDef.Type, key)
+		// Type and constraint validation
+		if err := fieldDef.ValidateValue(value); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+// Filter narrows the entries listed by ListEntriesByDateRange: a ContainsFilter or a GeoFilter.
+type Filter interface {
+	isFilter()
+}
+
+// ContainsFilter matches entries whose list field holds an item.
+type ContainsFilter struct {
+	Field string      // Name of a list field of the theme
+	Value interface{} // Item to look for, of the field's item type (string, float64 or bool)
+}
+
+func (ContainsFilter) isFilter() {}
+func (GeoFilter) isFilter()      {}
+
 // EntryRepository defines the interface for entry data persistence.
 type Repository interface {
 	// Define methods for entry CRUD operations, e.g.:
 	GetEntryByID(ctx context.Context, userID, entryID uuid.UUID) (*Entry, error)
-	ListEntriesByDateRange(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time, themeID uuid.UUID) ([]Entry, error)
+	// ListEntriesByDateRange lists a theme's entries in the range that match all filters.
+	ListEntriesByDateRange(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time, themeID uuid.UUID, filters ...Filter) ([]Entry, error)
 	CreateEntry(ctx context.Context, entry *Entry) error
 	UpdateEntry(ctx context.Context, entry *Entry) error
 	DeleteEntry(ctx context.Context, userID, entryID uuid.UUID, entryDate string) error
+	// ListBacklinks lists the user's entries whose reference fields point at the entry.
+	ListBacklinks(ctx context.Context, userID, entryID uuid.UUID) ([]Entry, error)
+	// ListEntriesBelowSchemaVersion lists a theme's entries written with a schema version older than version.
+	ListEntriesBelowSchemaVersion(ctx context.Context, userID, themeID uuid.UUID, version int) ([]Entry, error)
+	// PutMigratedEntries writes migrated entries, skipping those deleted or already migrated since they were read.
+	PutMigratedEntries(ctx context.Context, entries []Entry) (int, error)
 	GetEntriesForSummary(ctx context.Context, userID uuid.UUID, themeID uuid.UUID, yearMonth string) ([]Entry, error)
 }
@@ -0,0 +1,170 @@
+package entry
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+// --- Location index and proximity filters of geo fields ---
+
+// GeohashPrecision is the length of the geohashes indexed for geo values (cells of about 5 m).
+const GeohashPrecision = 9
+
+// maxGeohashCells bounds the geohash prefixes a filter is narrowed by in queries. Areas needing
+// more cells even at one character are not narrowed by the index and only checked exactly.
+const maxGeohashCells = 16
+
+// earthRadiusMeters is the mean radius of the Earth used for distances.
+const earthRadiusMeters = 6371008.8
+
+const geohashBase32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// Geohash encodes a position as a geohash of the given length.
+func Geohash(lat, lng float64, precision int) string {
+	latLo, latHi, lngLo, lngHi := -90.0, 90.0, -180.0, 180.0
+	hash := make([]byte, 0, precision)
+	bits, ch, even := 0, 0, true
+	for len(hash) < precision {
+		// Bits alternate between longitude and latitude, starting with longitude
+		if even {
+			mid := (lngLo + lngHi) / 2
+			if lng >= mid {
+				ch, lngLo = ch<<1|1, mid
+			} else {
+				ch, lngHi = ch<<1, mid
+			}
+		} else {
+			mid := (latLo + latHi) / 2
+			if lat >= mid {
+				ch, latLo = ch<<1|1, mid
+			} else {
+				ch, latHi = ch<<1, mid
+			}
+		}
+		even = !even
+		if bits++; bits == 5 {
+			hash = append(hash, geohashBase32[ch])
+			bits, ch = 0, 0
+		}
+	}
+	return string(hash)
+}
+
+// IndexGeohashes records the geohash of each geo field value of the entry, keyed by field name,
+// so proximity queries can narrow entries by geohash prefix.
+func (e *Entry) IndexGeohashes(fields []theme.ThemeField) {
+	e.Geohashes = nil
+	for _, f := range fields {
+		if f.Type != theme.FieldTypeGeo {
+			continue
+		}
+		p, err := theme.ParseGeo(e.Data[f.Name])
+		if err != nil {
+			continue
+		}
+		if e.Geohashes == nil {
+			e.Geohashes = make(map[string]string)
+		}
+		e.Geohashes[f.Name] = Geohash(p.Lat, p.Lng, GeohashPrecision)
+	}
+}
+
+// GeoFilter matches entries whose geo field lies within a bounding box, or within a radius of a
+// center. Boxes do not wrap around the antimeridian: West cannot be greater than East.
+type GeoFilter struct {
+	Field                    string  // Name of a geo field of the theme
+	South, West, North, East float64 // Bounding box in degrees (for radius filters, the box around the circle)
+
+	Center       *theme.GeoPoint // Center of a radius filter (nil for bounding boxes)
+	RadiusMeters float64
+}
+
+// NewBoxFilter returns a filter matching positions within a bounding box.
+func NewBoxFilter(field string, south, west, north, east float64) (GeoFilter, error) {
+	if err := theme.ValidateLatLng(south, west); err != nil {
+		return GeoFilter{}, err
+	}
+	if err := theme.ValidateLatLng(north, east); err != nil {
+		return GeoFilter{}, err
+	}
+	if south > north {
+		return GeoFilter{}, fmt.Errorf("south %v cannot be greater than north %v", south, north)
+	}
+	if west > east {
+		return GeoFilter{}, fmt.Errorf("west %v cannot be greater than east %v; boxes cannot cross the antimeridian", west, east)
+	}
+	return GeoFilter{Field: field, South: south, West: west, North: north, East: east}, nil
+}
+
+// NewRadiusFilter returns a filter matching positions within radiusMeters of a center.
+func NewRadiusFilter(field string, lat, lng, radiusMeters float64) (GeoFilter, error) {
+	if err := theme.ValidateLatLng(lat, lng); err != nil {
+		return GeoFilter{}, err
+	}
+	if !(radiusMeters > 0) || math.IsInf(radiusMeters, 0) {
+		return GeoFilter{}, fmt.Errorf("radius must be a positive number of meters, got %v", radiusMeters)
+	}
+	f := GeoFilter{Field: field, Center: &theme.GeoPoint{Lat: lat, Lng: lng}, RadiusMeters: radiusMeters}
+
+	// The box around the circle; near the poles it spans every longitude
+	dLat := radiusMeters / earthRadiusMeters * 180 / math.Pi
+	f.South, f.North = math.Max(-90, lat-dLat), math.Min(90, lat+dLat)
+	f.West, f.East = -180, 180
+	if f.South > -90 && f.North < 90 {
+		if dLng := dLat / math.Cos(lat*math.Pi/180); dLng < 180 {
+			f.West, f.East = math.Max(-180, lng-dLng), math.Min(180, lng+dLng)
+		}
+	}
+	return f, nil
+}
+
+// Matches reports whether a geo value lies within the filter's area.
+func (f GeoFilter) Matches(value interface{}) bool {
+	p, err := theme.ParseGeo(value)
+	if err != nil {
+		return false
+	}
+	if f.Center != nil {
+		return DistanceMeters(*f.Center, p) <= f.RadiusMeters
+	}
+	return p.Lat >= f.South && p.Lat <= f.North && p.Lng >= f.West && p.Lng <= f.East
+}
+
+// GeohashPrefixes returns the geohash cells covering the filter's bounding box, as precise as
+// possible with at most maxGeohashCells cells. Indexed geohashes starting with none of them are
+// outside the box. It returns nil if even one-character cells are too many.
+func (f GeoFilter) GeohashPrefixes() []string {
+	var prefixes []string
+	for precision := 1; precision <= GeohashPrecision; precision++ {
+		latBits, lngBits := 5*precision/2, (5*precision+1)/2
+		latCells, lngCells := 1<<latBits, 1<<lngBits
+		cellHeight, cellWidth := 180/float64(latCells), 360/float64(lngCells)
+		rowLo, rowHi := geohashCell(f.South+90, cellHeight, latCells), geohashCell(f.North+90, cellHeight, latCells)
+		colLo, colHi := geohashCell(f.West+180, cellWidth, lngCells), geohashCell(f.East+180, cellWidth, lngCells)
+		if (rowHi-rowLo+1)*(colHi-colLo+1) > maxGeohashCells {
+			break
+		}
+		prefixes = prefixes[:0]
+		for row := rowLo; row <= rowHi; row++ {
+			for col := colLo; col <= colHi; col++ {
+				prefixes = append(prefixes, Geohash(-90+(float64(row)+0.5)*cellHeight, -180+(float64(col)+0.5)*cellWidth, precision))
+			}
+		}
+	}
+	return prefixes
+}
+
+// geohashCell returns the index of the cell of the given size holding offset, among n cells.
+func geohashCell(offset, size float64, n int) int {
+	return int(math.Min(math.Floor(offset/size), float64(n-1)))
+}
+
+// DistanceMeters returns the great-circle distance between two positions.
+func DistanceMeters(a, b theme.GeoPoint) float64 {
+	toRad := math.Pi / 180
+	dLat, dLng := (b.Lat-a.Lat)*toRad, (b.Lng-a.Lng)*toRad
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(a.Lat*toRad)*math.Cos(b.Lat*toRad)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(h)))
+}
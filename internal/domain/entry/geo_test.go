@@ -0,0 +1,78 @@
+package entry
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+func TestGeohash(t *testing.T) {
+	assert.Equal(t, "u4pruydqqvj", Geohash(57.64911, 10.40744, 11))
+	assert.Equal(t, "xn76urx6", Geohash(35.6812, 139.7671, 8))
+}
+
+func TestEntry_IndexGeohashes(t *testing.T) {
+	fields := []theme.ThemeField{
+		{Name: "place", Type: theme.FieldTypeGeo},
+		{Name: "home", Type: theme.FieldTypeGeo},
+		{Name: "memo", Type: theme.FieldTypeText},
+	}
+	e := Entry{Data: map[string]interface{}{
+		"place": map[string]interface{}{"lat": 35.6812, "lng": 139.7671},
+		"home":  nil,
+		"memo":  "x",
+	}}
+
+	e.IndexGeohashes(fields)
+
+	assert.Equal(t, map[string]string{"place": Geohash(35.6812, 139.7671, GeohashPrecision)}, e.Geohashes)
+}
+
+func TestGeoFilter(t *testing.T) {
+	tokyo := map[string]interface{}{"lat": 35.6812, "lng": 139.7671}
+	shinjuku := map[string]interface{}{"lat": 35.6896, "lng": 139.7006}
+
+	// Radius filters measure great-circle distance (Tokyo Station to Shinjuku is about 6 km)
+	near, err := NewRadiusFilter("place", 35.6812, 139.7671, 1000)
+	assert.NoError(t, err)
+	assert.True(t, near.Matches(tokyo))
+	assert.False(t, near.Matches(shinjuku))
+	assert.False(t, near.Matches(nil))
+
+	box, err := NewBoxFilter("place", 35.6, 139.6, 35.8, 139.72)
+	assert.NoError(t, err)
+	assert.False(t, box.Matches(tokyo))
+	assert.True(t, box.Matches(shinjuku))
+
+	// Indexed geohashes of matching positions start with one of the covering prefixes
+	for _, f := range []GeoFilter{near, box} {
+		prefixes := f.GeohashPrefixes()
+		assert.NotEmpty(t, prefixes)
+		assert.LessOrEqual(t, len(prefixes), maxGeohashCells)
+		for _, p := range []map[string]interface{}{tokyo, shinjuku} {
+			if !f.Matches(p) {
+				continue
+			}
+			hash := Geohash(p["lat"].(float64), p["lng"].(float64), GeohashPrecision)
+			covered := false
+			for _, prefix := range prefixes {
+				covered = covered || strings.HasPrefix(hash, prefix)
+			}
+			assert.True(t, covered, "%s is not covered by %v", hash, prefixes)
+		}
+	}
+
+	// The whole world needs more cells than a query is narrowed by
+	world, _ := NewBoxFilter("place", -90, -180, 90, 180)
+	assert.Nil(t, world.GeohashPrefixes())
+
+	_, err = NewBoxFilter("place", 36, 139, 35, 140)
+	assert.EqualError(t, err, "south 36 cannot be greater than north 35")
+	_, err = NewBoxFilter("place", 35, 179, 36, -179)
+	assert.EqualError(t, err, "west 179 cannot be greater than east -179; boxes cannot cross the antimeridian")
+	_, err = NewRadiusFilter("place", 35, 139, 0)
+	assert.EqualError(t, err, "radius must be a positive number of meters, got 0")
+}
@@ -0,0 +1,92 @@
+package entry
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+// Reference is a link from a reference field of an entry to another entry.
+type Reference struct {
+	Field   string    // Name of the reference field
+	EntryID uuid.UUID // Referenced entry
+}
+
+// Backlink is an entry that references another one, with the fields holding the references.
+type Backlink struct {
+	Entry  Entry
+	Fields []string
+}
+
+// References returns the links held by the entry's reference fields, in field order.
+// Empty and invalid values are skipped.
+func (e *Entry) References(fields []theme.ThemeField) []Reference {
+	var refs []Reference
+	for _, field := range fields {
+		if field.Type != theme.FieldTypeReference || e.Data[field.Name] == nil {
+			continue
+		}
+		if id, err := theme.ParseReference(e.Data[field.Name]); err == nil {
+			refs = append(refs, Reference{Field: field.Name, EntryID: id})
+		}
+	}
+	return refs
+}
+
+// ReferencesTo returns the fields of the entry that reference the target entry.
+func (e *Entry) ReferencesTo(fields []theme.ThemeField, target uuid.UUID) []theme.ThemeField {
+	byName := make(map[string]theme.ThemeField, len(fields))
+	for _, f := range fields {
+		byName[f.Name] = f
+	}
+	var referencing []theme.ThemeField
+	for _, ref := range e.References(fields) {
+		if ref.EntryID == target {
+			referencing = append(referencing, byName[ref.Field])
+		}
+	}
+	return referencing
+}
+
+// IndexReferences records the IDs of the referenced entries in ReferencedEntryIDs.
+// It must be called whenever Data changes so backlink queries stay accurate.
+func (e *Entry) IndexReferences(fields []theme.ThemeField) {
+	seen := make(map[string]bool)
+	e.ReferencedEntryIDs = nil
+	for _, ref := range e.References(fields) {
+		id := ref.EntryID.String()
+		if !seen[id] {
+			seen[id] = true
+			e.ReferencedEntryIDs = append(e.ReferencedEntryIDs, id)
+		}
+	}
+	sort.Strings(e.ReferencedEntryIDs)
+}
+
+// ReferenceThemeID returns the theme a reference field points into: its target theme, if any,
+// else the entry's own theme.
+func (e *Entry) ReferenceThemeID(field theme.ThemeField) uuid.UUID {
+	if field.TargetThemeID != nil {
+		return *field.TargetThemeID
+	}
+	return e.ThemeID
+}
+
+// ValidateReferenceTarget checks that the entry a reference field points at may be referenced:
+// it must belong to the same user or, written by another member of a shared theme, to the theme the
+// field points into (see ReferenceThemeID); differ from the entry itself and belong to the field's
+// target theme, if any.
+func (e *Entry) ValidateReferenceTarget(field theme.ThemeField, target *Entry) error {
+	if target.UserID != e.UserID && target.ThemeID != e.ReferenceThemeID(field) {
+		return fmt.Errorf("field '%s' references entry %s, which was not found", field.Name, target.EntryID)
+	}
+	if target.EntryID == e.EntryID {
+		return fmt.Errorf("field '%s' cannot reference the entry itself", field.Name)
+	}
+	if field.TargetThemeID != nil && target.ThemeID != *field.TargetThemeID {
+		return fmt.Errorf("field '%s' must reference an entry of theme %s, got an entry of theme %s", field.Name, *field.TargetThemeID, target.ThemeID)
+	}
+	return nil
+}
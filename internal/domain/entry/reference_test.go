@@ -0,0 +1,62 @@
+package entry
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntry_References(t *testing.T) {
+	bookID, projectID := uuid.New(), uuid.New()
+	fields := []theme.ThemeField{
+		{Name: "book", Type: theme.FieldTypeReference},
+		{Name: "project", Type: theme.FieldTypeReference, OnDelete: theme.OnDeleteCascade},
+		{Name: "previous", Type: theme.FieldTypeReference},
+		{Name: "note", Type: theme.FieldTypeText},
+	}
+	e := Entry{Data: map[string]interface{}{
+		"book":     bookID.String(),
+		"project":  projectID.String(),
+		"previous": bookID.String(),
+		"note":     projectID.String(), // Not a reference field
+	}}
+
+	assert.Equal(t, []Reference{{"book", bookID}, {"project", projectID}, {"previous", bookID}}, e.References(fields))
+
+	referencing := e.ReferencesTo(fields, bookID)
+	assert.Len(t, referencing, 2)
+	assert.Equal(t, "book", referencing[0].Name)
+	assert.Equal(t, "previous", referencing[1].Name)
+	assert.Equal(t, theme.OnDeleteCascade, e.ReferencesTo(fields, projectID)[0].OnDeleteOrDefault())
+
+	e.IndexReferences(fields)
+	assert.ElementsMatch(t, []string{bookID.String(), projectID.String()}, e.ReferencedEntryIDs)
+
+	e.Data["book"], e.Data["project"], e.Data["previous"] = nil, nil, nil
+	e.IndexReferences(fields)
+	assert.Empty(t, e.ReferencedEntryIDs)
+}
+
+func TestEntry_ValidateReferenceTarget(t *testing.T) {
+	userID, bookThemeID := uuid.New(), uuid.New()
+	field := theme.ThemeField{Name: "book", Type: theme.FieldTypeReference, TargetThemeID: &bookThemeID}
+	e := Entry{EntryID: uuid.New(), UserID: userID}
+
+	book := &Entry{EntryID: uuid.New(), UserID: userID, ThemeID: bookThemeID}
+	assert.NoError(t, e.ValidateReferenceTarget(field, book))
+
+	other := &Entry{EntryID: uuid.New(), UserID: userID, ThemeID: uuid.New()}
+	assert.EqualError(t, e.ValidateReferenceTarget(field, other),
+		"field 'book' must reference an entry of theme "+bookThemeID.String()+", got an entry of theme "+other.ThemeID.String())
+
+	assert.EqualError(t, e.ValidateReferenceTarget(field, &e), "field 'book' cannot reference the entry itself")
+
+	// Entries of other members of a shared theme can be referenced within the theme only
+	shared := &Entry{EntryID: uuid.New(), UserID: uuid.New(), ThemeID: bookThemeID}
+	assert.NoError(t, e.ValidateReferenceTarget(field, shared))
+	foreign := &Entry{EntryID: uuid.New(), UserID: uuid.New(), ThemeID: uuid.New()}
+	assert.EqualError(t, e.ValidateReferenceTarget(theme.ThemeField{Name: "book", Type: theme.FieldTypeReference}, foreign),
+		"field 'book' references entry "+foreign.EntryID.String()+", which was not found")
+}
@@ -18,4 +18,6 @@ var (
 	ErrCannotUpdateDefaultTheme = errors.New("cannot update default theme")
 	// ErrAlreadyExists indicates an attempt to create an item that already exists.
 	ErrAlreadyExists = errors.New("item already exists")
+	// ErrConflict indicates that an item was changed concurrently since it was read.
+	ErrConflict = errors.New("item was modified concurrently")
 )
@@ -0,0 +1,128 @@
+package feature
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/soranjiro/axicalendar/internal/domain/entry"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+// --- Reading amounts from number-like fields ---
+
+// CurrencyOption is the option choosing the currency money fields are totalled in.
+const CurrencyOption = "currency"
+
+// DurationUnit is the unit durations are totalled in.
+const DurationUnit = "minutes"
+
+// AmountTypes are the field types executors can total: numbers, ratings, durations (in minutes)
+// and money (its amount, within a single currency).
+var AmountTypes = []theme.FieldType{theme.FieldTypeNumber, theme.FieldTypeRating, theme.FieldTypeDuration, theme.FieldTypeMoney}
+
+// CurrencyOptionSpec describes CurrencyOption for executors that total an amount field.
+func CurrencyOptionSpec() Option {
+	return Option{
+		Name:        CurrencyOption,
+		Type:        OptionTypeString,
+		Description: "ISO 4217 currency money amounts are totalled in; entries in other currencies are left out. Only needed when a money field holds several currencies.",
+	}
+}
+
+// Amounts reads the values of a number-like field of a theme.
+// Money is only ever totalled within one currency: amounts in other currencies are skipped.
+type Amounts struct {
+	Field    string
+	Type     theme.FieldType
+	Currency string // Currency of a money field ("" for other types, or when no entry has a value)
+}
+
+// NewAmounts resolves how the named field's values are read.
+// The field must be one of AmountTypes. For money fields the currency is the configured
+// CurrencyOption or else the single currency used by the entries; entries in several currencies
+// without a configured one are an ErrInvalidConfig, since their amounts cannot be added up.
+func NewAmounts(th theme.Theme, name string, config theme.FeatureConfig, entries []entry.Entry) (Amounts, error) {
+	var field *theme.ThemeField
+	for i := range th.Fields {
+		if th.Fields[i].Name == name {
+			field = &th.Fields[i]
+			break
+		}
+	}
+	if field == nil {
+		return Amounts{}, fmt.Errorf("%w: theme '%s' has no field named '%s'", ErrInvalidConfig, th.ThemeName, name)
+	}
+	a := Amounts{Field: name, Type: field.Type}
+	switch field.Type {
+	case theme.FieldTypeNumber, theme.FieldTypeRating, theme.FieldTypeDuration:
+		return a, nil
+	case theme.FieldTypeMoney:
+		// The currency is resolved below
+	default:
+		return Amounts{}, fmt.Errorf("%w: field '%s' has type '%s', expected one of %v", ErrInvalidConfig, name, field.Type, AmountTypes)
+	}
+
+	if currency := config.StringOption(CurrencyOption, ""); currency != "" {
+		if !theme.IsValidCurrency(currency) {
+			return Amounts{}, fmt.Errorf("%w: option '%s' must be an ISO 4217 currency code, got '%s'", ErrInvalidConfig, CurrencyOption, currency)
+		}
+		a.Currency = currency
+		return a, nil
+	}
+	currencies := make(map[string]bool)
+	for _, en := range entries {
+		if m, err := theme.ParseMoney(en.Data[name]); err == nil {
+			currencies[m.Currency] = true
+		}
+	}
+	if len(currencies) > 1 {
+		codes := make([]string, 0, len(currencies))
+		for c := range currencies {
+			codes = append(codes, c)
+		}
+		sort.Strings(codes)
+		return Amounts{}, fmt.Errorf("%w: field '%s' holds amounts in several currencies (%s); set the '%s' option to choose one", ErrInvalidConfig, name, strings.Join(codes, ", "), CurrencyOption)
+	}
+	for c := range currencies {
+		a.Currency = c
+	}
+	return a, nil
+}
+
+// Value returns the amount held by an entry's data.
+// ok is false if the field is empty, invalid or holds money in another currency.
+func (a Amounts) Value(data map[string]interface{}) (float64, bool) {
+	v := data[a.Field]
+	switch a.Type {
+	case theme.FieldTypeDuration:
+		s, ok := StringValue(v)
+		if !ok {
+			return 0, false
+		}
+		d, err := theme.ParseDuration(s)
+		if err != nil {
+			return 0, false
+		}
+		return d.Minutes(), true
+	case theme.FieldTypeMoney:
+		m, err := theme.ParseMoney(v)
+		if err != nil || m.Currency != a.Currency {
+			return 0, false
+		}
+		return m.Amount, true
+	default:
+		return NumberValue(v)
+	}
+}
+
+// Unit returns the unit of the amounts: the currency of money, DurationUnit for durations, or "".
+func (a Amounts) Unit() string {
+	switch a.Type {
+	case theme.FieldTypeMoney:
+		return a.Currency
+	case theme.FieldTypeDuration:
+		return DurationUnit
+	}
+	return ""
+}
@@ -0,0 +1,58 @@
+package feature
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/soranjiro/axicalendar/internal/domain/entry"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+func TestNewAmounts(t *testing.T) {
+	th := theme.Theme{ThemeName: "Expenses", Fields: []theme.ThemeField{
+		{Name: "price", Label: "Price", Type: theme.FieldTypeMoney},
+		{Name: "duration", Label: "Duration", Type: theme.FieldTypeDuration},
+		{Name: "note", Label: "Note", Type: theme.FieldTypeText},
+	}}
+	money := func(amount float64, currency string) map[string]interface{} {
+		return map[string]interface{}{"price": map[string]interface{}{"amount": amount, "currency": currency}}
+	}
+	jpy := []entry.Entry{{Data: money(1200, "JPY")}, {Data: money(800, "JPY")}, {Data: map[string]interface{}{}}}
+	mixed := append([]entry.Entry{{Data: money(15, "USD")}}, jpy...)
+
+	// A single currency is picked up from the entries
+	a, err := NewAmounts(th, "price", theme.FeatureConfig{}, jpy)
+	assert.NoError(t, err)
+	assert.Equal(t, "JPY", a.Unit())
+	v, ok := a.Value(jpy[0].Data)
+	assert.True(t, ok)
+	assert.Equal(t, 1200.0, v)
+
+	// Several currencies are only totalled in the configured one
+	_, err = NewAmounts(th, "price", theme.FeatureConfig{}, mixed)
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	assert.Contains(t, err.Error(), "several currencies (JPY, USD)")
+	a, err = NewAmounts(th, "price", theme.FeatureConfig{Options: map[string]interface{}{CurrencyOption: "USD"}}, mixed)
+	assert.NoError(t, err)
+	_, ok = a.Value(jpy[0].Data)
+	assert.False(t, ok)
+	v, ok = a.Value(mixed[0].Data)
+	assert.True(t, ok)
+	assert.Equal(t, 15.0, v)
+	_, err = NewAmounts(th, "price", theme.FeatureConfig{Options: map[string]interface{}{CurrencyOption: "usd"}}, mixed)
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+
+	// Durations are read in minutes
+	a, err = NewAmounts(th, "duration", theme.FeatureConfig{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, DurationUnit, a.Unit())
+	v, ok = a.Value(map[string]interface{}{"duration": "PT1H15M"})
+	assert.True(t, ok)
+	assert.Equal(t, 75.0, v)
+
+	_, err = NewAmounts(th, "note", theme.FeatureConfig{}, nil)
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	_, err = NewAmounts(th, "missing", theme.FeatureConfig{}, nil)
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+}
@@ -0,0 +1,209 @@
+package feature
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+// CacheKey identifies a cached feature result.
+type CacheKey struct {
+	UserID      uuid.UUID
+	ThemeID     uuid.UUID
+	FeatureName string
+	ConfigHash  string // See HashConfig
+	Window      Window
+}
+
+// String returns a stable string form of the key.
+func (k CacheKey) String() string {
+	return k.UserID.String() + "#" + k.ThemeID.String() + "#" + k.FeatureName + "#" +
+		k.Window.Start.Format("2006-01-02") + "#" + k.Window.End.Format("2006-01-02") + "#" + k.ConfigHash
+}
+
+// HashConfig returns a short, stable hash of a theme's feature config.
+// version (normally theme.UpdatedAt) is mixed in so cached results are not reused
+// after the theme definition changes.
+func HashConfig(config theme.FeatureConfig, version time.Time) string {
+	// encoding/json sorts map keys, so equal configs always produce the same bytes
+	b, err := json.Marshal(config)
+	if err != nil {
+		b = []byte{}
+	}
+	h := sha256.New()
+	h.Write(b)
+	h.Write([]byte(version.UTC().Format(time.RFC3339Nano)))
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// ResultCache stores feature results so executions over unchanged entries can be skipped.
+type ResultCache interface {
+	// Get returns the cached result for key. ok is false on a miss.
+	Get(ctx context.Context, key CacheKey) (result AnalysisResult, ok bool, err error)
+	// Set stores the result for key.
+	Set(ctx context.Context, key CacheKey, result AnalysisResult) error
+	// Invalidate removes the cached results of a user's theme whose window contains date (YYYY-MM-DD).
+	Invalidate(ctx context.Context, userID, themeID uuid.UUID, date string) error
+	// InvalidateTheme removes all cached results of a user's theme.
+	InvalidateTheme(ctx context.Context, userID, themeID uuid.UUID) error
+}
+
+// --- In-memory implementation ---
+
+// cachedResult is a result held by InMemoryResultCache.
+type cachedResult struct {
+	window    Window
+	result    AnalysisResult
+	expiresAt time.Time // Zero means no expiry
+}
+
+// InMemoryResultCache provides a simple in-memory implementation of ResultCache.
+// Results are grouped per user and theme so invalidation only scans that theme's results.
+type InMemoryResultCache struct {
+	ttl     time.Duration
+	results map[string]map[string]cachedResult // user#theme -> CacheKey.String() -> result
+	mu      sync.RWMutex
+}
+
+// NewInMemoryResultCache creates a new InMemoryResultCache.
+// Results expire after ttl; a ttl of zero keeps them until invalidated.
+func NewInMemoryResultCache(ttl time.Duration) *InMemoryResultCache {
+	return &InMemoryResultCache{
+		ttl:     ttl,
+		results: make(map[string]map[string]cachedResult),
+	}
+}
+
+// themeCacheKey groups the results of one user's theme.
+func themeCacheKey(userID, themeID uuid.UUID) string {
+	return userID.String() + "#" + themeID.String()
+}
+
+// Get returns the cached result for key, if present and not expired.
+func (c *InMemoryResultCache) Get(ctx context.Context, key CacheKey) (AnalysisResult, bool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	cached, exists := c.results[themeCacheKey(key.UserID, key.ThemeID)][key.String()]
+	if !exists || (!cached.expiresAt.IsZero() && time.Now().After(cached.expiresAt)) {
+		return nil, false, nil
+	}
+	return cached.result, true, nil
+}
+
+// Set stores the result for key.
+func (c *InMemoryResultCache) Set(ctx context.Context, key CacheKey, result AnalysisResult) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	group := themeCacheKey(key.UserID, key.ThemeID)
+	if c.results[group] == nil {
+		c.results[group] = make(map[string]cachedResult)
+	}
+	cached := cachedResult{window: key.Window, result: result}
+	if c.ttl > 0 {
+		cached.expiresAt = time.Now().Add(c.ttl)
+	}
+	c.results[group][key.String()] = cached
+	return nil
+}
+
+// Invalidate removes the cached results of the theme whose window contains date.
+func (c *InMemoryResultCache) Invalidate(ctx context.Context, userID, themeID uuid.UUID, date string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	group := themeCacheKey(userID, themeID)
+	for k, cached := range c.results[group] {
+		if cached.window.Contains(date) {
+			delete(c.results[group], k)
+		}
+	}
+	if len(c.results[group]) == 0 {
+		delete(c.results, group)
+	}
+	return nil
+}
+
+// InvalidateTheme removes all cached results of the theme.
+func (c *InMemoryResultCache) InvalidateTheme(ctx context.Context, userID, themeID uuid.UUID) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.results, themeCacheKey(userID, themeID))
+	return nil
+}
+
+// --- Metrics ---
+
+// CacheStats holds hit and miss counts of a ResultCache.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// HitRatio returns the fraction of lookups that were hits (0 if there were none).
+func (s CacheStats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// MeteredResultCache wraps a ResultCache and counts hits and misses.
+// Lookups that fail with an error are counted as misses.
+type MeteredResultCache struct {
+	cache  ResultCache
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// NewMeteredResultCache creates a MeteredResultCache around cache.
+func NewMeteredResultCache(cache ResultCache) *MeteredResultCache {
+	return &MeteredResultCache{cache: cache}
+}
+
+// Get returns the cached result for key and records a hit or a miss.
+func (m *MeteredResultCache) Get(ctx context.Context, key CacheKey) (AnalysisResult, bool, error) {
+	result, ok, err := m.cache.Get(ctx, key)
+	if ok && err == nil {
+		m.hits.Add(1)
+	} else {
+		m.misses.Add(1)
+	}
+	return result, ok, err
+}
+
+// Set stores the result for key.
+func (m *MeteredResultCache) Set(ctx context.Context, key CacheKey, result AnalysisResult) error {
+	return m.cache.Set(ctx, key, result)
+}
+
+// Invalidate removes the cached results of the theme whose window contains date.
+func (m *MeteredResultCache) Invalidate(ctx context.Context, userID, themeID uuid.UUID, date string) error {
+	return m.cache.Invalidate(ctx, userID, themeID, date)
+}
+
+// InvalidateTheme removes all cached results of the theme.
+func (m *MeteredResultCache) InvalidateTheme(ctx context.Context, userID, themeID uuid.UUID) error {
+	return m.cache.InvalidateTheme(ctx, userID, themeID)
+}
+
+// Stats returns the hit and miss counts recorded so far.
+func (m *MeteredResultCache) Stats() CacheStats {
+	return CacheStats{Hits: m.hits.Load(), Misses: m.misses.Load()}
+}
+
+// Compile-time checks to ensure the caches implement ResultCache.
+var (
+	_ ResultCache = (*InMemoryResultCache)(nil)
+	_ ResultCache = (*MeteredResultCache)(nil)
+)
@@ -0,0 +1,69 @@
+package feature
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+func TestInMemoryResultCache_InvalidateByWindow(t *testing.T) {
+	cache := NewMeteredResultCache(NewInMemoryResultCache(0))
+	ctx := context.Background()
+	userID, themeID := uuid.New(), uuid.New()
+	may := CacheKey{UserID: userID, ThemeID: themeID, FeatureName: "monthly_summary", Window: MonthWindow(time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC))}
+	june := may
+	june.Window = MonthWindow(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+
+	_, ok, err := cache.Get(ctx, may)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, cache.Set(ctx, may, AnalysisResult{"total_entries": 1}))
+	assert.NoError(t, cache.Set(ctx, june, AnalysisResult{"total_entries": 2}))
+	result, ok, _ := cache.Get(ctx, may)
+	assert.True(t, ok)
+	assert.Equal(t, 1, result["total_entries"])
+
+	// Writing an entry on 2025-05-20 only drops the May result
+	assert.NoError(t, cache.Invalidate(ctx, userID, themeID, "2025-05-20"))
+	_, ok, _ = cache.Get(ctx, may)
+	assert.False(t, ok)
+	_, ok, _ = cache.Get(ctx, june)
+	assert.True(t, ok)
+
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 2}, cache.Stats())
+}
+
+func TestInMemoryResultCache_InvalidateTheme(t *testing.T) {
+	cache := NewInMemoryResultCache(0)
+	ctx := context.Background()
+	userID, themeID := uuid.New(), uuid.New()
+	may := CacheKey{UserID: userID, ThemeID: themeID, FeatureName: "monthly_summary", Window: MonthWindow(time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC))}
+	june := may
+	june.Window = MonthWindow(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+	other := may
+	other.ThemeID = uuid.New()
+	for _, key := range []CacheKey{may, june, other} {
+		assert.NoError(t, cache.Set(ctx, key, AnalysisResult{"total_entries": 1}))
+	}
+
+	// Every window of the theme is dropped, other themes are kept
+	assert.NoError(t, cache.InvalidateTheme(ctx, userID, themeID))
+	_, ok, _ := cache.Get(ctx, may)
+	assert.False(t, ok)
+	_, ok, _ = cache.Get(ctx, june)
+	assert.False(t, ok)
+	_, ok, _ = cache.Get(ctx, other)
+	assert.True(t, ok)
+}
+
+func TestHashConfig_ChangesWithVersion(t *testing.T) {
+	v1 := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, HashConfig(theme.FeatureConfig{}, v1), HashConfig(theme.FeatureConfig{}, v1))
+	assert.NotEqual(t, HashConfig(theme.FeatureConfig{}, v1), HashConfig(theme.FeatureConfig{}, v1.Add(time.Second)))
+}
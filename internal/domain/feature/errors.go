@@ -0,0 +1,15 @@
+package feature
+
+import "errors"
+
+// Standard feature errors
+var (
+	// ErrInvalidConfig indicates that a feature cannot run because its configuration
+	// does not match the theme (e.g., a referenced field is missing or has the wrong type).
+	ErrInvalidConfig = errors.New("invalid feature configuration")
+	// ErrUnknownFeature indicates that no executor is registered for a feature name.
+	ErrUnknownFeature = errors.New("unknown feature")
+	// ErrResourceLimit indicates that an execution was stopped because it needed more
+	// CPU time or memory than allowed (e.g., an expensive user-defined formula).
+	ErrResourceLimit = errors.New("feature exceeded its resource limits")
+)
@@ -2,18 +2,150 @@ package feature
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/soranjiro/axicalendar/internal/domain/entry"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
 )
 
 // AnalysisResult represents the generic result of a feature execution.
 // The actual structure will vary depending on the feature.
 type AnalysisResult map[string]interface{}
 
+// Window is the inclusive date range a feature is executed over.
+// Start and End are dates (time of day is ignored).
+type Window struct {
+	Start time.Time
+	End   time.Time
+}
+
+// NewWindow creates a Window and checks that End is not before Start.
+func NewWindow(start, end time.Time) (Window, error) {
+	if start.IsZero() || end.IsZero() {
+		return Window{}, errors.New("start and end dates are required")
+	}
+	if end.Before(start) {
+		return Window{}, errors.New("end date cannot be before start date")
+	}
+	return Window{Start: start, End: end}, nil
+}
+
+// MonthWindow returns the Window covering the calendar month that contains t.
+func MonthWindow(t time.Time) Window {
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, -1)
+	return Window{Start: start, End: end}
+}
+
+// YearMonth returns the window's start month in YYYY-MM format.
+func (w Window) YearMonth() string {
+	return w.Start.Format("2006-01")
+}
+
+// IsSingleMonth reports whether the window starts and ends in the same calendar month.
+func (w Window) IsSingleMonth() bool {
+	return w.Start.Year() == w.End.Year() && w.Start.Month() == w.End.Month()
+}
+
+// Contains reports whether the date (YYYY-MM-DD) lies within the window.
+func (w Window) Contains(date string) bool {
+	return date >= w.Start.Format("2006-01-02") && date <= w.End.Format("2006-01-02")
+}
+
+// Days returns every date in the window in YYYY-MM-DD format, in order.
+func (w Window) Days() []string {
+	var days []string
+	start := time.Date(w.Start.Year(), w.Start.Month(), w.Start.Day(), 0, 0, 0, 0, time.UTC)
+	end := time.Date(w.End.Year(), w.End.Month(), w.End.Day(), 0, 0, 0, 0, time.UTC)
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		days = append(days, d.Format("2006-01-02"))
+	}
+	return days
+}
+
+// Input carries the data a FeatureExecutor works on.
+type Input struct {
+	Theme   theme.Theme   // Theme the entries belong to (field definitions are used to interpret Data)
+	Window  Window        // Date range the entries were loaded for, so executors can account for days without entries
+	Entries []entry.Entry // Entries of the theme within Window
+}
+
 // FeatureExecutor defines the interface for executing a theme-specific feature.
 type FeatureExecutor interface {
-	// Execute performs the feature logic on the provided entries.
+	// Execute performs the feature logic on the provided input.
+	// config is the theme's configuration for the feature (see theme.SupportedFeature).
 	// ctx can be used for cancellation or passing request-scoped values.
-	// entries are the relevant entries for the feature (e.g., for a specific theme and user).
-	Execute(ctx context.Context, entries []entry.Entry) (AnalysisResult, error)
+	Execute(ctx context.Context, input Input, config theme.FeatureConfig) (AnalysisResult, error)
+	// Describe returns the feature's metadata: what it does, the field roles it needs and its options.
+	Describe() Feature
+}
+
+// MonthScoped is implemented by executors that summarise exactly one calendar month.
+// Entries for these executors are loaded with EntryRepository.GetEntriesForSummary
+// and the execution window is widened to the whole month.
+type MonthScoped interface {
+	FeatureExecutor
+	MonthScoped()
+}
+
+// ThemeInput carries the data of one theme in a multi-theme execution.
+type ThemeInput struct {
+	Theme   theme.Theme         // Theme the entries belong to
+	Config  theme.FeatureConfig // The theme's configuration for the feature
+	Entries []entry.Entry       // Entries of the theme within the window
+}
+
+// MultiThemeInput carries the data a MultiTheme executor works on.
+type MultiThemeInput struct {
+	Window Window       // Date range the entries were loaded for
+	Themes []ThemeInput // One per requested theme, in request order
+}
+
+// MultiTheme is implemented by executors that correlate entries of several themes.
+// Each theme must enable the feature; its config is passed alongside its entries.
+type MultiTheme interface {
+	FeatureExecutor
+	ExecuteMulti(ctx context.Context, input MultiThemeInput) (AnalysisResult, error)
+}
+
+// DateDependent is implemented by executors whose result depends on the current date,
+// e.g. a streak that is still running today. Their cached results are only reused
+// on the day they were computed.
+type DateDependent interface {
+	FeatureExecutor
+	DateDependent()
+}
+
+// ConfigValidator is implemented by executors whose config needs checks beyond field roles
+// and option types, e.g. compiling formulas. ValidateSupportedFeatures calls it when a theme is saved.
+type ConfigValidator interface {
+	FeatureExecutor
+	// ValidateConfig returns an error wrapping ErrInvalidConfig if config cannot run on th.
+	ValidateConfig(th theme.Theme, config theme.FeatureConfig) error
+}
+
+// AlertStatus is the state reported by an Alerting executor.
+type AlertStatus string
+
+const (
+	AlertStatusOK       AlertStatus = "ok"       // Within the target
+	AlertStatusWarning  AlertStatus = "warning"  // Close to or projected to miss the target
+	AlertStatusExceeded AlertStatus = "exceeded" // Past the target
+)
+
+// Alert summarises an Alerting executor's result for an alert badge.
+type Alert struct {
+	Feature string      // Name of the feature that raised the alert
+	Status  AlertStatus // Overall status
+	Message string      // Short human-readable explanation
+}
+
+// Alerting is implemented by executors whose result drives an alert badge, e.g. a budget.
+// Besides running on demand, they are run over the month of every entry written to the theme
+// and the resulting alerts are returned with the entry.
+type Alerting interface {
+	MonthScoped
+	// Alert summarises a result returned by Execute.
+	Alert(result AnalysisResult) Alert
 }
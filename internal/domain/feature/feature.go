@@ -1,18 +1,49 @@
 package feature
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
 
 // Feature represents the metadata of a feature available in the system.
 type Feature struct {
-	ID          uuid.UUID `dynamodbav:"ID"`          // Unique identifier for the feature definition (optional, might not be stored directly)
-	Name        string    `dynamodbav:"Name"`        // Internal name (e.g., "monthly_summary") used for identification
-	DisplayName string    `dynamodbav:"DisplayName"` // User-facing name (e.g., "Monthly Summary")
-	Description string    `dynamodbav:"Description"` // Brief description of what the feature does
-	// Add other relevant metadata if needed, e.g., required theme fields, configuration options
+	ID          uuid.UUID   `dynamodbav:"ID"`          // Unique identifier for the feature definition (optional, might not be stored directly)
+	Name        string      `dynamodbav:"Name"`        // Internal name (e.g., "monthly_summary") used for identification
+	DisplayName string      `dynamodbav:"DisplayName"` // User-facing name (e.g., "Monthly Summary")
+	Description string      `dynamodbav:"Description"` // Brief description of what the feature does
+	Fields      []FieldRole `dynamodbav:"Fields"`      // Theme fields the feature works on, mapped via theme.FeatureConfig.Fields
+	Options     []Option    `dynamodbav:"Options"`     // Settings accepted in theme.FeatureConfig.Options
+	MultiTheme  bool        `dynamodbav:"MultiTheme"`  // Whether the feature can run over several themes (see MultiTheme)
 }
 
-// --- Potentially add a FeatureRepository interface if features need to be managed dynamically ---
-// type Repository interface {
-// 	GetFeatureByName(ctx context.Context, name string) (*Feature, error)
-// 	ListFeatures(ctx context.Context) ([]Feature, error)
-// }
+// FieldRole describes a theme field a feature works on.
+// Themes map the role to one of their fields in theme.FeatureConfig.Fields.
+type FieldRole struct {
+	Role         string            `dynamodbav:"Role"`         // Key used in theme.FeatureConfig.Fields (e.g., "category")
+	Description  string            `dynamodbav:"Description"`  // What the field is used for
+	Types        []theme.FieldType `dynamodbav:"Types"`        // Field types the feature accepts for this role
+	Required     bool              `dynamodbav:"Required"`     // Whether the feature cannot run without the field
+	DefaultField string            `dynamodbav:"DefaultField"` // Field name used when the role is not mapped ("" if none)
+}
+
+// OptionType is the value type of a feature option.
+type OptionType string
+
+const (
+	OptionTypeString  OptionType = "string"
+	OptionTypeNumber  OptionType = "number"
+	OptionTypeBoolean OptionType = "boolean"
+	// OptionTypeNumberMap is an object mapping names (e.g. categories) to numbers.
+	OptionTypeNumberMap OptionType = "number_map"
+	// OptionTypeStringMap is an object mapping names to strings (e.g. formulas by name).
+	OptionTypeStringMap OptionType = "string_map"
+)
+
+// Option describes a setting accepted in theme.FeatureConfig.Options.
+type Option struct {
+	Name        string      `dynamodbav:"Name"`        // Key used in theme.FeatureConfig.Options
+	Type        OptionType  `dynamodbav:"Type"`        // Value type of the option
+	Description string      `dynamodbav:"Description"` // What the option controls
+	Default     interface{} `dynamodbav:"Default"`     // Value used when the option is not set (nil if none)
+	Enum        []string    `dynamodbav:"Enum"`        // Allowed values for string options (empty means any)
+}
@@ -3,6 +3,7 @@ package feature
 import (
 	"fmt"
 	"regexp" // Import regexp
+	"sort"
 	"sync"
 )
 
@@ -10,6 +11,8 @@ import (
 type ExecutorRegistry interface {
 	RegisterExecutor(name string, executor FeatureExecutor) error
 	GetExecutor(name string) (FeatureExecutor, error)
+	// ListFeatures returns the metadata of every registered executor, sorted by name.
+	ListFeatures() []Feature
 }
 
 // InMemoryExecutorRegistry provides a simple in-memory implementation of ExecutorRegistry.
@@ -54,6 +57,25 @@ func (r *InMemoryExecutorRegistry) GetExecutor(name string) (FeatureExecutor, er
 	return executor, nil
 }
 
+// ListFeatures returns the metadata of every registered executor, sorted by name.
+// The Name of each Feature is the name the executor was registered under.
+func (r *InMemoryExecutorRegistry) ListFeatures() []Feature {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	features := make([]Feature, 0, len(r.executors))
+	for name, executor := range r.executors {
+		f := executor.Describe()
+		f.Name = name
+		_, f.MultiTheme = executor.(MultiTheme)
+		features = append(features, f)
+	}
+	sort.Slice(features, func(i, j int) bool {
+		return features[i].Name < features[j].Name
+	})
+	return features
+}
+
 // Compile-time check to ensure InMemoryExecutorRegistry implements ExecutorRegistry.
 var _ ExecutorRegistry = (*InMemoryExecutorRegistry)(nil)
 
@@ -0,0 +1,179 @@
+package feature
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+// Schedule is a parsed cron expression, evaluated in UTC at minute resolution.
+// Expressions have five fields: minute, hour, day of month, month and day of week (0 = Sunday).
+// Each field accepts *, numbers, ranges (1-5), lists (1,15) and steps (*/15, 1-10/2).
+// The shortcuts @hourly, @daily, @weekly (Sunday 00:00) and @monthly (1st 00:00) are also accepted.
+// As in cron, when both day fields are restricted a day matches if either of them matches.
+type Schedule struct {
+	minute, hour, dom, month, dow bitset
+	domAny, dowAny                bool
+}
+
+// bitset holds the allowed values of a cron field.
+type bitset uint64
+
+func (b bitset) has(v int) bool { return b&(1<<uint(v)) != 0 }
+
+// cronShortcuts maps the accepted @ shortcuts to their expressions.
+var cronShortcuts = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// ParseSchedule parses a cron expression (see Schedule).
+func ParseSchedule(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if shortcut, ok := cronShortcuts[expr]; ok {
+		expr = shortcut
+	}
+	parts := strings.Fields(expr)
+	if len(parts) != 5 {
+		return Schedule{}, fmt.Errorf("cron expression '%s' must have 5 fields, got %d", expr, len(parts))
+	}
+
+	var (
+		s   Schedule
+		err error
+	)
+	if s.minute, err = parseCronField(parts[0], 0, 59); err != nil {
+		return Schedule{}, fmt.Errorf("minute: %w", err)
+	}
+	if s.hour, err = parseCronField(parts[1], 0, 23); err != nil {
+		return Schedule{}, fmt.Errorf("hour: %w", err)
+	}
+	if s.dom, err = parseCronField(parts[2], 1, 31); err != nil {
+		return Schedule{}, fmt.Errorf("day of month: %w", err)
+	}
+	if s.month, err = parseCronField(parts[3], 1, 12); err != nil {
+		return Schedule{}, fmt.Errorf("month: %w", err)
+	}
+	if s.dow, err = parseCronField(parts[4], 0, 7); err != nil {
+		return Schedule{}, fmt.Errorf("day of week: %w", err)
+	}
+	// 7 is an alias for Sunday
+	if s.dow.has(7) {
+		s.dow |= 1
+	}
+	s.domAny = parts[2] == "*"
+	s.dowAny = parts[4] == "*"
+	return s, nil
+}
+
+// parseCronField parses one comma-separated cron field with values in [min, max].
+func parseCronField(field string, min, max int) (bitset, error) {
+	var set bitset
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step in '%s'", part)
+			}
+			step = n
+			part = part[:i]
+		}
+
+		lo, hi := min, max
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(bounds[0])
+			hi, err2 = strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil || lo > hi {
+				return 0, fmt.Errorf("invalid range '%s'", part)
+			}
+		default:
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value '%s'", part)
+			}
+			lo, hi = n, n
+			if step > 1 {
+				hi = max // "5/15" means every 15 starting at 5
+			}
+		}
+		if lo < min || hi > max {
+			return 0, fmt.Errorf("'%s' is out of range %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+// Matches reports whether the schedule fires at t's minute.
+func (s Schedule) Matches(t time.Time) bool {
+	t = t.UTC()
+	return s.minute.has(t.Minute()) && s.hour.has(t.Hour()) && s.month.has(int(t.Month())) && s.dayMatches(t)
+}
+
+// dayMatches applies cron's day-of-month / day-of-week rule to t's date.
+func (s Schedule) dayMatches(t time.Time) bool {
+	dom := s.dom.has(t.Day())
+	dow := s.dow.has(int(t.Weekday()))
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dow
+	case s.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// scheduleSearchLimit bounds Next for expressions that never fire (e.g. February 30th).
+const scheduleSearchLimit = 5 // years
+
+// Next returns the first time after after (at minute resolution, UTC) the schedule fires.
+// It returns the zero time if the schedule does not fire within the next five years.
+func (s Schedule) Next(after time.Time) time.Time {
+	t := after.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(scheduleSearchLimit, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case !s.month.has(int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		case !s.hour.has(t.Hour()):
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case !s.minute.has(t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// ScheduledWindow returns the window a scheduled run at t covers: the complete day, week
+// (7 days) or calendar month (see theme.FeatureSchedule.Period) that ended before t's date.
+func ScheduledWindow(period string, t time.Time) Window {
+	today := time.Date(t.UTC().Year(), t.UTC().Month(), t.UTC().Day(), 0, 0, 0, 0, time.UTC)
+	yesterday := today.AddDate(0, 0, -1)
+	switch period {
+	case theme.SchedulePeriodDay:
+		return Window{Start: yesterday, End: yesterday}
+	case theme.SchedulePeriodWeek:
+		return Window{Start: today.AddDate(0, 0, -7), End: yesterday}
+	default:
+		return MonthWindow(yesterday)
+	}
+}
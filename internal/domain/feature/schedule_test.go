@@ -0,0 +1,59 @@
+package feature
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+func TestParseSchedule_Next(t *testing.T) {
+	at := func(s string) time.Time {
+		tm, _ := time.Parse("2006-01-02 15:04", s)
+		return tm
+	}
+
+	tests := []struct {
+		name  string
+		expr  string
+		after time.Time
+		want  time.Time
+	}{
+		{"monthly shortcut", "@monthly", at("2025-05-14 09:30"), at("2025-06-01 00:00")},
+		{"every 15 minutes", "*/15 * * * *", at("2025-05-14 09:31"), at("2025-05-14 09:45")},
+		{"strictly after", "0 0 * * *", at("2025-05-14 00:00"), at("2025-05-15 00:00")},
+		{"weekdays at 8", "0 8 * * 1-5", at("2025-05-16 09:00"), at("2025-05-19 08:00")}, // Friday -> Monday
+		{"day of month or week", "0 0 13 * 5", at("2025-06-01 00:00"), at("2025-06-06 00:00")},
+		{"sunday as 7", "30 6 * * 7", at("2025-05-14 00:00"), at("2025-05-18 06:30")},
+		{"never fires", "0 0 30 2 *", at("2025-01-01 00:00"), time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParseSchedule(tt.expr)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, s.Next(tt.after))
+		})
+	}
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "@yearly"} {
+		_, err := ParseSchedule(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestScheduledWindow(t *testing.T) {
+	run := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, MonthWindow(time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)), ScheduledWindow(theme.SchedulePeriodMonth, run))
+	week := ScheduledWindow(theme.SchedulePeriodWeek, run)
+	assert.Equal(t, "2025-05-25", week.Start.Format("2006-01-02"))
+	assert.Equal(t, "2025-05-31", week.End.Format("2006-01-02"))
+	day := ScheduledWindow(theme.SchedulePeriodDay, run)
+	assert.Equal(t, day.Start, day.End)
+	assert.Equal(t, "2025-05-31", day.End.Format("2006-01-02"))
+}
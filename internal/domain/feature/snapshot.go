@@ -0,0 +1,32 @@
+package feature
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Snapshot is a stored result of a scheduled feature run.
+// Snapshots are not invalidated by entry writes, so they keep the history of a feature's results.
+type Snapshot struct {
+	UserID      uuid.UUID      // Owner of the theme; snapshots are stored in the user's partition
+	ThemeID     uuid.UUID      // Theme the feature ran on
+	FeatureName string         // Name of the feature
+	Window      Window         // Date range the feature ran over
+	Result      AnalysisResult // Result of the run
+	CreatedAt   time.Time      // When the run happened
+}
+
+// SnapshotRepository stores the results of scheduled feature runs.
+type SnapshotRepository interface {
+	// SaveSnapshot stores a snapshot. A snapshot of the same feature and window replaces the previous one.
+	SaveSnapshot(ctx context.Context, snapshot *Snapshot) error
+	// ListSnapshots returns the snapshots of a theme's feature, newest window first.
+	ListSnapshots(ctx context.Context, userID, themeID uuid.UUID, featureName string) ([]Snapshot, error)
+	// LastScheduledRun returns the time of the latest scheduled run of a theme's feature the scheduler
+	// has processed, or the zero time if there has been none.
+	LastScheduledRun(ctx context.Context, userID, themeID uuid.UUID, featureName string) (time.Time, error)
+	// SetLastScheduledRun records the time of the latest processed scheduled run of a theme's feature.
+	SetLastScheduledRun(ctx context.Context, userID, themeID uuid.UUID, featureName string, at time.Time) error
+}
@@ -0,0 +1,99 @@
+package feature
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// TableKey is the AnalysisResult key executors store their tabular result under.
+const TableKey = "table"
+
+// ColumnType is the value type of a table column.
+type ColumnType string
+
+const (
+	ColumnTypeString  ColumnType = "string"
+	ColumnTypeNumber  ColumnType = "number"
+	ColumnTypeBoolean ColumnType = "boolean"
+	ColumnTypeDate    ColumnType = "date" // YYYY-MM-DD strings (or the first day of a week or month)
+)
+
+// Column describes a column of a Table.
+type Column struct {
+	Name string     `json:"name"`
+	Type ColumnType `json:"type"`
+}
+
+// Chart suggests how a Table is best plotted. Fields name columns of the table.
+type Chart struct {
+	Mark  string `json:"mark"`            // Vega-Lite mark, e.g. "bar" or "line"
+	X     string `json:"x"`               // Column on the x axis
+	Y     string `json:"y"`               // Column on the y axis
+	Color string `json:"color,omitempty"` // Column series are split by ("" for a single series)
+}
+
+// Table is the typed, tabular form of a feature result, used for CSV and chart exports.
+// Each row holds one value per column, in column order; values are nil, string, float64, int or bool.
+type Table struct {
+	Columns []Column        `json:"columns"`
+	Rows    [][]interface{} `json:"rows"`
+	Chart   *Chart          `json:"chart,omitempty"` // Suggested chart (nil to let clients choose)
+}
+
+// NewTableFromRecords builds a table with the given columns from records keyed by column name.
+// Missing values become nil.
+func NewTableFromRecords(columns []Column, records []map[string]interface{}) *Table {
+	rows := make([][]interface{}, 0, len(records))
+	for _, record := range records {
+		row := make([]interface{}, len(columns))
+		for i, col := range columns {
+			row[i] = record[col.Name]
+		}
+		rows = append(rows, row)
+	}
+	return &Table{Columns: columns, Rows: rows}
+}
+
+// WithChart sets the table's suggested chart and returns the table.
+func (t *Table) WithChart(chart Chart) *Table {
+	t.Chart = &chart
+	return t
+}
+
+// Records returns the rows as maps keyed by column name.
+func (t *Table) Records() []map[string]interface{} {
+	records := make([]map[string]interface{}, 0, len(t.Rows))
+	for _, row := range t.Rows {
+		record := make(map[string]interface{}, len(t.Columns))
+		for i, col := range t.Columns {
+			if i < len(row) {
+				record[col.Name] = row[i]
+			}
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// Table returns the tabular form of the result, if the executor provided one.
+// Results read back from a cache or a snapshot hold the table as decoded JSON; it is converted back.
+func (r AnalysisResult) Table() (*Table, bool, error) {
+	switch v := r[TableKey].(type) {
+	case nil:
+		return nil, false, nil
+	case *Table:
+		return v, true, nil
+	case Table:
+		return &v, true, nil
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to encode table: %w", err)
+		}
+		var t Table
+		if err := json.Unmarshal(b, &t); err != nil {
+			return nil, false, fmt.Errorf("failed to decode table: %w", err)
+		}
+		return &t, true, nil
+	}
+}
@@ -0,0 +1,39 @@
+package feature
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalysisResult_Table(t *testing.T) {
+	table := NewTableFromRecords([]Column{
+		{Name: "category", Type: ColumnTypeString},
+		{Name: "total", Type: ColumnTypeNumber},
+	}, []map[string]interface{}{
+		{"category": "food", "total": 1200.0, "ignored": true},
+		{"category": "rent"},
+	}).WithChart(Chart{Mark: "bar", X: "category", Y: "total"})
+	result := AnalysisResult{"categories": 2, TableKey: table}
+
+	got, ok, err := result.Table()
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, [][]interface{}{{"food", 1200.0}, {"rent", nil}}, got.Rows)
+	assert.Equal(t, []map[string]interface{}{{"category": "food", "total": 1200.0}, {"category": "rent", "total": nil}}, got.Records())
+
+	// Results read back from the cache hold the table as decoded JSON
+	b, err := json.Marshal(result)
+	assert.NoError(t, err)
+	var decoded AnalysisResult
+	assert.NoError(t, json.Unmarshal(b, &decoded))
+	got, ok, err = decoded.Table()
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, table, got)
+
+	_, ok, err = AnalysisResult{"total": 1}.Table()
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
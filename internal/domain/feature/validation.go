@@ -0,0 +1,157 @@
+package feature
+
+import (
+	"fmt"
+
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+// ValidateSupportedFeatures checks a theme's supported features against the registered executors.
+// Every feature must be registered, each field role it declares must resolve to a theme field of an
+// accepted type, and config options must be known to the feature and have the declared type.
+// Roles not mapped in the config fall back to the role's DefaultField.
+// Schedules must have a valid cron expression, and month-scoped features can only be scheduled per month.
+// Executors implementing ConfigValidator check the rest of their config last.
+func ValidateSupportedFeatures(registry ExecutorRegistry, th theme.Theme) error {
+	fieldTypes := make(map[string]theme.FieldType, len(th.Fields))
+	for _, f := range th.Fields {
+		fieldTypes[f.Name] = f.Type
+	}
+
+	for _, sf := range th.SupportedFeatures {
+		executor, err := registry.GetExecutor(sf.Name)
+		if err != nil {
+			return fmt.Errorf("%w: '%s'", ErrUnknownFeature, sf.Name)
+		}
+		meta := executor.Describe()
+
+		// 1. Check field roles resolve to theme fields of an accepted type
+		roles := make(map[string]bool, len(meta.Fields))
+		for _, role := range meta.Fields {
+			roles[role.Role] = true
+			fieldName := sf.Config.FieldOr(role.Role, role.DefaultField)
+			fieldType, exists := fieldTypes[fieldName]
+			if !exists {
+				if role.Required {
+					return fmt.Errorf("%w: feature '%s' requires a field for role '%s' (map it in config.fields)", ErrInvalidConfig, sf.Name, role.Role)
+				}
+				continue
+			}
+			if !acceptsType(role.Types, fieldType) {
+				return fmt.Errorf("%w: feature '%s' role '%s': field '%s' has type '%s', expected one of %v", ErrInvalidConfig, sf.Name, role.Role, fieldName, fieldType, role.Types)
+			}
+		}
+		for role := range sf.Config.Fields {
+			if !roles[role] {
+				return fmt.Errorf("%w: feature '%s' has no field role '%s'", ErrInvalidConfig, sf.Name, role)
+			}
+		}
+
+		// 2. Check the schedule, if any
+		if sf.Schedule != nil {
+			if _, err := ParseSchedule(sf.Schedule.Cron); err != nil {
+				return fmt.Errorf("%w: feature '%s' schedule: %v", ErrInvalidConfig, sf.Name, err)
+			}
+			if _, monthScoped := executor.(MonthScoped); monthScoped && sf.Schedule.PeriodOrDefault() != theme.SchedulePeriodMonth {
+				return fmt.Errorf("%w: feature '%s' summarises whole months and can only be scheduled with period '%s'", ErrInvalidConfig, sf.Name, theme.SchedulePeriodMonth)
+			}
+		}
+
+		// 3. Check options are known and have the declared type
+		options := make(map[string]Option, len(meta.Options))
+		for _, o := range meta.Options {
+			options[o.Name] = o
+		}
+		for key, value := range sf.Config.Options {
+			option, known := options[key]
+			if !known {
+				return fmt.Errorf("%w: feature '%s' has no option '%s'", ErrInvalidConfig, sf.Name, key)
+			}
+			if err := checkOptionValue(option, value); err != nil {
+				return fmt.Errorf("%w: feature '%s' option '%s': %v", ErrInvalidConfig, sf.Name, key, err)
+			}
+		}
+
+		// 4. Let the executor check the rest of its config
+		if validator, ok := executor.(ConfigValidator); ok {
+			if err := validator.ValidateConfig(th, sf.Config); err != nil {
+				return fmt.Errorf("feature '%s': %w", sf.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// CheckField verifies that the theme defines the named field with one of the allowed types.
+// Executors use it on the fields they resolve at execution time; errors wrap ErrInvalidConfig.
+func CheckField(th theme.Theme, name string, allowed ...theme.FieldType) error {
+	for _, field := range th.Fields {
+		if field.Name != name {
+			continue
+		}
+		if acceptsType(allowed, field.Type) {
+			return nil
+		}
+		return fmt.Errorf("%w: field '%s' has type '%s', expected one of %v", ErrInvalidConfig, name, field.Type, allowed)
+	}
+	return fmt.Errorf("%w: theme has no field named '%s'", ErrInvalidConfig, name)
+}
+
+// acceptsType reports whether t is one of the accepted field types.
+func acceptsType(accepted []theme.FieldType, t theme.FieldType) bool {
+	for _, a := range accepted {
+		if a == t {
+			return true
+		}
+	}
+	return false
+}
+
+// checkOptionValue verifies that value matches the option's type and allowed values.
+func checkOptionValue(option Option, value interface{}) error {
+	switch option.Type {
+	case OptionTypeString:
+		s, ok := StringValue(value)
+		if !ok {
+			return fmt.Errorf("expected a string, got %T", value)
+		}
+		if len(option.Enum) == 0 {
+			return nil
+		}
+		for _, allowed := range option.Enum {
+			if s == allowed {
+				return nil
+			}
+		}
+		return fmt.Errorf("value '%s' is not one of %v", s, option.Enum)
+	case OptionTypeNumber:
+		if _, ok := NumberValue(value); !ok {
+			return fmt.Errorf("expected a number, got %T", value)
+		}
+	case OptionTypeBoolean:
+		if _, ok := BoolValue(value); !ok {
+			return fmt.Errorf("expected a boolean, got %T", value)
+		}
+	case OptionTypeNumberMap:
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("expected an object of numbers, got %T", value)
+		}
+		for key, v := range m {
+			if _, ok := NumberValue(v); !ok {
+				return fmt.Errorf("key '%s': expected a number, got %T", key, v)
+			}
+		}
+	case OptionTypeStringMap:
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("expected an object of strings, got %T", value)
+		}
+		for key, v := range m {
+			if _, ok := StringValue(v); !ok {
+				return fmt.Errorf("key '%s': expected a string, got %T", key, v)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package feature
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+// stubExecutor is a FeatureExecutor that only provides metadata.
+type stubExecutor struct {
+	meta Feature
+}
+
+func (s *stubExecutor) Execute(ctx context.Context, input Input, config theme.FeatureConfig) (AnalysisResult, error) {
+	return AnalysisResult{}, nil
+}
+
+func (s *stubExecutor) Describe() Feature {
+	return s.meta
+}
+
+func TestValidateSupportedFeatures(t *testing.T) {
+	registry := NewInMemoryExecutorRegistry()
+	err := registry.RegisterExecutor("totals", &stubExecutor{meta: Feature{
+		Fields: []FieldRole{
+			{Role: "amount", Types: []theme.FieldType{theme.FieldTypeNumber}, Required: true, DefaultField: "amount"},
+			{Role: "label", Types: []theme.FieldType{theme.FieldTypeText}},
+		},
+		Options: []Option{
+			{Name: "bucket", Type: OptionTypeString, Enum: []string{"daily", "weekly"}},
+			{Name: "limit", Type: OptionTypeNumber},
+			{Name: "limits", Type: OptionTypeNumberMap},
+			{Name: "labels", Type: OptionTypeStringMap},
+		},
+	}})
+	assert.NoError(t, err)
+
+	fields := []theme.ThemeField{
+		{Name: "amount", Label: "Amount", Type: theme.FieldTypeNumber},
+		{Name: "cost", Label: "Cost", Type: theme.FieldTypeNumber},
+		{Name: "memo", Label: "Memo", Type: theme.FieldTypeText},
+	}
+	withFeature := func(sf theme.SupportedFeature) theme.Theme {
+		return theme.Theme{Fields: fields, SupportedFeatures: []theme.SupportedFeature{sf}}
+	}
+
+	tests := []struct {
+		name    string
+		theme   theme.Theme
+		wantErr error
+	}{
+		{"default field", withFeature(theme.SupportedFeature{Name: "totals"}), nil},
+		{"mapped fields and options", withFeature(theme.SupportedFeature{Name: "totals", Config: theme.FeatureConfig{
+			Fields:  map[string]string{"amount": "cost", "label": "memo"},
+			Options: map[string]interface{}{"bucket": "weekly", "limit": 10.0, "limits": map[string]interface{}{"food": 100.0}},
+		}}), nil},
+		{"unknown feature", withFeature(theme.SupportedFeature{Name: "missing"}), ErrUnknownFeature},
+		{"wrong field type", withFeature(theme.SupportedFeature{Name: "totals", Config: theme.FeatureConfig{
+			Fields: map[string]string{"amount": "memo"},
+		}}), ErrInvalidConfig},
+		{"missing required field", theme.Theme{
+			Fields:            []theme.ThemeField{{Name: "memo", Label: "Memo", Type: theme.FieldTypeText}},
+			SupportedFeatures: []theme.SupportedFeature{{Name: "totals"}},
+		}, ErrInvalidConfig},
+		{"unknown role", withFeature(theme.SupportedFeature{Name: "totals", Config: theme.FeatureConfig{
+			Fields: map[string]string{"category": "memo"},
+		}}), ErrInvalidConfig},
+		{"option not in enum", withFeature(theme.SupportedFeature{Name: "totals", Config: theme.FeatureConfig{
+			Options: map[string]interface{}{"bucket": "hourly"},
+		}}), ErrInvalidConfig},
+		{"option wrong type", withFeature(theme.SupportedFeature{Name: "totals", Config: theme.FeatureConfig{
+			Options: map[string]interface{}{"limit": "ten"},
+		}}), ErrInvalidConfig},
+		{"number map with non-number", withFeature(theme.SupportedFeature{Name: "totals", Config: theme.FeatureConfig{
+			Options: map[string]interface{}{"limits": map[string]interface{}{"food": "lots"}},
+		}}), ErrInvalidConfig},
+		{"string map with non-string", withFeature(theme.SupportedFeature{Name: "totals", Config: theme.FeatureConfig{
+			Options: map[string]interface{}{"labels": map[string]interface{}{"food": 1.0}},
+		}}), ErrInvalidConfig},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSupportedFeatures(registry, tt.theme)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestCheckField(t *testing.T) {
+	th := theme.Theme{Fields: []theme.ThemeField{{Name: "category", Label: "Category", Type: theme.FieldTypeSelect}}}
+
+	assert.NoError(t, CheckField(th, "category", theme.FieldTypeSelect, theme.FieldTypeText))
+	assert.ErrorIs(t, CheckField(th, "category", theme.FieldTypeNumber), ErrInvalidConfig)
+	assert.ErrorIs(t, CheckField(th, "missing", theme.FieldTypeSelect), ErrInvalidConfig)
+}
@@ -0,0 +1,34 @@
+package feature
+
+// --- Helpers for reading entry data values inside executors ---
+
+// NumberValue converts a number field value to float64.
+// Values decoded from JSON are float64, while values built in Go code may be ints.
+func NumberValue(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+// BoolValue returns the value of a boolean field.
+func BoolValue(v interface{}) (bool, bool) {
+	b, ok := v.(bool)
+	return b, ok
+}
+
+// StringValue returns the value of a text-like field.
+func StringValue(v interface{}) (string, bool) {
+	s, ok := v.(string)
+	return s, ok
+}
@@ -0,0 +1,81 @@
+package formula
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+// --- Computed theme fields ---
+
+// computedTypes maps the types of computed fields to the type their formula must have.
+var computedTypes = map[theme.FieldType]Type{
+	theme.FieldTypeNumber:  TypeNumber,
+	theme.FieldTypeText:    TypeString,
+	theme.FieldTypeBoolean: TypeBool,
+}
+
+// CompileComputedFields compiles the formulas of a theme's computed fields, keyed by field name.
+// Formulas are per-entry expressions over the theme's other fields; computed fields cannot
+// reference each other. A formula's type must match its field's type. Errors name the field.
+func CompileComputedFields(fields []theme.ThemeField) (map[string]*Program, error) {
+	var inputs []theme.ThemeField
+	for _, f := range fields {
+		if !f.IsComputed() {
+			inputs = append(inputs, f)
+		}
+	}
+	schema := SchemaFromFields(inputs)
+
+	programs := make(map[string]*Program)
+	for _, f := range fields {
+		if !f.IsComputed() {
+			continue
+		}
+		p, err := CompileExpression(f.Formula, schema, DefaultLimits())
+		if err != nil {
+			return nil, fmt.Errorf("field '%s': invalid formula: %w", f.Name, err)
+		}
+		want, ok := computedTypes[f.Type]
+		if !ok {
+			return nil, fmt.Errorf("field '%s': formula is only allowed on %s, %s and %s fields", f.Name, theme.FieldTypeNumber, theme.FieldTypeText, theme.FieldTypeBoolean)
+		}
+		if !compatible(p.Type(), want) {
+			return nil, fmt.Errorf("field '%s': formula computes a %s, but the field is of type %s", f.Name, p.Type(), f.Type)
+		}
+		programs[f.Name] = p
+	}
+	return programs, nil
+}
+
+// ComputeFields sets the value of each computed field of the theme in data, evaluated from the
+// entry's other fields and its date. Fields whose formula evaluates to null (or to a number that
+// is not finite) are removed from data.
+func ComputeFields(ctx context.Context, fields []theme.ThemeField, data map[string]interface{}, date string) error {
+	programs, err := CompileComputedFields(fields)
+	if err != nil || len(programs) == 0 {
+		return err
+	}
+	row := RowFromData(fields, data, date)
+	for _, f := range fields {
+		p, ok := programs[f.Name]
+		if !ok {
+			continue
+		}
+		v, err := p.EvaluateRow(ctx, row)
+		if err != nil {
+			return fmt.Errorf("field '%s': %w", f.Name, err)
+		}
+		if n, ok := v.(float64); ok && (math.IsNaN(n) || math.IsInf(n, 0)) {
+			v = nil
+		}
+		if v == nil {
+			delete(data, f.Name)
+			continue
+		}
+		data[f.Name] = v
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package formula
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+var workFields = []theme.ThemeField{
+	{Name: "start", Label: "Start", Type: theme.FieldTypeTime},
+	{Name: "end", Label: "End", Type: theme.FieldTypeTime},
+	{Name: "price", Label: "Price", Type: theme.FieldTypeNumber},
+	{Name: "qty", Label: "Quantity", Type: theme.FieldTypeNumber},
+	{Name: "hours", Label: "Hours", Type: theme.FieldTypeNumber, Formula: "minutes_between(start, end) / 60"},
+	{Name: "total", Label: "Total", Type: theme.FieldTypeNumber, Formula: "price * qty"},
+	{Name: "summary", Label: "Summary", Type: theme.FieldTypeText, Formula: `entry_date + " " + coalesce(start, "?")`},
+}
+
+func TestEvaluateRow(t *testing.T) {
+	tests := []struct {
+		expr string
+		row  Row
+		want interface{}
+	}{
+		{`price * qty`, Row{"price": 2.5, "qty": 4.0}, 10.0},
+		{`price * qty`, Row{"price": 2.5}, nil},
+		{`minutes_between(start, end)`, Row{"start": "09:30", "end": "17:00"}, 450.0},
+		{`minutes_between(start, end)`, Row{"start": "22:00", "end": "06:00"}, 480.0}, // Past midnight
+		{`minutes_between(start, end)`, Row{"start": "2025-05-01T09:00:00Z", "end": "2025-05-01T10:30:00+01:00"}, 30.0},
+		{`minutes_between(entry_date, "2025-05-03")`, Row{"entry_date": "2025-05-01"}, 2880.0},
+		{`minutes_between(start, end)`, Row{"start": "09:30", "end": "2025-05-01"}, nil},
+		{`if(qty > 3, "bulk", "single")`, Row{"qty": 4.0}, "bulk"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			p, err := CompileExpression(tt.expr, SchemaFromFields(workFields), DefaultLimits())
+			assert.NoError(t, err)
+
+			v, err := p.EvaluateRow(context.Background(), tt.row)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, v)
+		})
+	}
+}
+
+func TestCompileComputedFields_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   theme.ThemeField
+		wantErr string
+	}{
+		{"aggregate", theme.ThemeField{Name: "f", Type: theme.FieldTypeNumber, Formula: "sum(price)"}, "field 'f': invalid formula: aggregate 'sum' at 0 cannot be used in a per-entry expression"},
+		{"group by", theme.ThemeField{Name: "f", Type: theme.FieldTypeNumber, Formula: "price by qty"}, "field 'f': invalid formula: 'by' cannot be used in a per-entry expression"},
+		{"other computed field", theme.ThemeField{Name: "f", Type: theme.FieldTypeNumber, Formula: "total * 2"}, "field 'f': invalid formula: unknown field 'total' at 0"},
+		{"wrong type", theme.ThemeField{Name: "f", Type: theme.FieldTypeNumber, Formula: "start"}, "field 'f': formula computes a string, but the field is of type number"},
+		{"bad arguments", theme.ThemeField{Name: "f", Type: theme.FieldTypeNumber, Formula: "minutes_between(price, end)"}, "field 'f': invalid formula: minutes_between at 0 expects times, datetimes or dates, got number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CompileComputedFields(append(workFields[:6:6], tt.field))
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestComputeFields(t *testing.T) {
+	data := map[string]interface{}{"start": "09:00", "end": "10:30", "price": 3.0, "total": 99.0}
+
+	err := ComputeFields(context.Background(), workFields, data, "2025-05-01")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1.5, data["hours"])
+	assert.NotContains(t, data, "total", "a null value removes the field")
+	assert.Equal(t, "2025-05-01 09:00", data["summary"])
+}
@@ -0,0 +1,498 @@
+package formula
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+// Row holds the values of one entry: its data plus DateField.
+type Row map[string]interface{}
+
+// Group is the value of a grouped formula for one value of the group-by field.
+type Group struct {
+	Key   string      // Value of the group-by field ("" for entries without one)
+	Value interface{} // Value of the formula over the group's entries
+}
+
+// Result is the outcome of evaluating a formula.
+// Value is set for ungrouped formulas and Groups, ordered by key, for grouped ones.
+// Values are float64, string, bool or nil.
+type Result struct {
+	Value  interface{}
+	Groups []Group
+}
+
+// Sizes charged against Limits.MaxMemory besides string contents.
+const (
+	groupCost = 64 // One group
+	rowCost   = 8  // One entry assigned to a group
+	dayCost   = 16 // One day tracked by count(days where ...)
+)
+
+// ctxCheckInterval is how many steps pass between checks of the context.
+const ctxCheckInterval = 1024
+
+// Evaluate runs the formula over rows. days are the dates (YYYY-MM-DD) of the window the rows
+// were loaded for. An error wrapping ErrLimitExceeded is returned if the evaluation needs more
+// steps, memory or time than the program's limits allow.
+func (p *Program) Evaluate(ctx context.Context, rows []Row, days []string) (Result, error) {
+	var result Result
+	err := p.run(ctx, days, func(e *evaluator) (err error) {
+		result, err = p.evaluate(e, rows)
+		return err
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	return result, nil
+}
+
+// EvaluateRow runs a per-entry expression compiled with CompileExpression on one row.
+// The value is a float64, string, bool or nil.
+func (p *Program) EvaluateRow(ctx context.Context, row Row) (interface{}, error) {
+	var v interface{}
+	err := p.run(ctx, nil, func(e *evaluator) (err error) {
+		v, err = e.eval(p.root, row)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// run calls f with an evaluator bounded by the program's limits, reporting a timeout as ErrLimitExceeded.
+func (p *Program) run(ctx context.Context, days []string, f func(e *evaluator) error) error {
+	parent := ctx
+	if p.limits.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.limits.Timeout)
+		defer cancel()
+	}
+	err := f(&evaluator{ctx: ctx, limits: p.limits, days: days})
+	if errors.Is(err, context.DeadlineExceeded) && parent.Err() == nil {
+		return fmt.Errorf("%w: evaluation took longer than %s", ErrLimitExceeded, p.limits.Timeout)
+	}
+	return err
+}
+
+func (p *Program) evaluate(e *evaluator, rows []Row) (Result, error) {
+	if p.groupBy == "" {
+		e.rows = rows
+		v, err := e.eval(p.root, nil)
+		return Result{Value: v}, err
+	}
+
+	// Split the rows by the group-by field and evaluate the formula per group
+	groups := make(map[string][]Row)
+	for _, row := range rows {
+		key := groupKey(row[p.groupBy])
+		if _, ok := groups[key]; !ok {
+			if err := e.alloc(groupCost + len(key)); err != nil {
+				return Result{}, err
+			}
+		}
+		if err := e.alloc(rowCost); err != nil {
+			return Result{}, err
+		}
+		groups[key] = append(groups[key], row)
+	}
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := Result{Groups: make([]Group, 0, len(keys))}
+	for _, key := range keys {
+		e.rows = groups[key]
+		v, err := e.eval(p.root, nil)
+		if err != nil {
+			return Result{}, err
+		}
+		result.Groups = append(result.Groups, Group{Key: key, Value: v})
+	}
+	return result, nil
+}
+
+// groupKey formats a group-by value as a group key.
+func groupKey(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case nil:
+		return ""
+	default:
+		if n, ok := number(v); ok {
+			return strconv.FormatFloat(n, 'f', -1, 64)
+		}
+		return fmt.Sprint(v)
+	}
+}
+
+// evaluator holds the state of one evaluation.
+type evaluator struct {
+	ctx    context.Context
+	limits Limits
+	steps  int
+	memory int
+	rows   []Row    // Rows aggregates run over (the current group's rows when grouped)
+	days   []string // Dates of the window
+}
+
+// step counts one evaluation step and periodically checks the context.
+func (e *evaluator) step() error {
+	e.steps++
+	if e.steps > e.limits.MaxSteps {
+		return fmt.Errorf("%w: evaluation needed more than %d steps", ErrLimitExceeded, e.limits.MaxSteps)
+	}
+	if e.steps%ctxCheckInterval == 0 {
+		return e.ctx.Err()
+	}
+	return nil
+}
+
+// alloc charges n bytes against the memory limit.
+func (e *evaluator) alloc(n int) error {
+	e.memory += n
+	if e.memory > e.limits.MaxMemory {
+		return fmt.Errorf("%w: evaluation needed more than %d bytes", ErrLimitExceeded, e.limits.MaxMemory)
+	}
+	return nil
+}
+
+// eval evaluates n. row is the current entry inside an aggregate and nil outside.
+func (e *evaluator) eval(n node, row Row) (interface{}, error) {
+	if err := e.step(); err != nil {
+		return nil, err
+	}
+	switch n := n.(type) {
+	case *literal:
+		return n.value, nil
+	case *fieldRef:
+		return fieldValue(row[n.name], n.typ), nil
+	case *unaryOp:
+		x, err := e.eval(n.x, row)
+		if err != nil {
+			return nil, err
+		}
+		if n.op == "not" {
+			return !truthy(x), nil
+		}
+		if v, ok := x.(float64); ok {
+			return -v, nil
+		}
+		return nil, nil
+	case *binaryOp:
+		return e.evalBinary(n, row)
+	case *call:
+		if aggregates[n.name] {
+			return e.evalAggregate(n)
+		}
+		return e.evalScalar(n, row)
+	}
+	return nil, fmt.Errorf("unsupported expression %T", n)
+}
+
+func (e *evaluator) evalBinary(n *binaryOp, row Row) (interface{}, error) {
+	l, err := e.eval(n.l, row)
+	if err != nil {
+		return nil, err
+	}
+	// and/or short-circuit
+	switch n.op {
+	case "and":
+		if !truthy(l) {
+			return false, nil
+		}
+	case "or":
+		if truthy(l) {
+			return true, nil
+		}
+	}
+	r, err := e.eval(n.r, row)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "and", "or":
+		return truthy(r), nil
+	case "==":
+		return l == r, nil
+	case "!=":
+		return l != r, nil
+	}
+	if l == nil || r == nil {
+		switch n.op {
+		case "<", "<=", ">", ">=":
+			return false, nil
+		}
+		return nil, nil
+	}
+	if ls, ok := l.(string); ok {
+		rs := r.(string)
+		switch n.op {
+		case "+":
+			if err := e.alloc(len(ls) + len(rs)); err != nil {
+				return nil, err
+			}
+			return ls + rs, nil
+		case "<":
+			return ls < rs, nil
+		case "<=":
+			return ls <= rs, nil
+		case ">":
+			return ls > rs, nil
+		default:
+			return ls >= rs, nil
+		}
+	}
+	lf, rf := l.(float64), r.(float64)
+	switch n.op {
+	case "+":
+		return lf + rf, nil
+	case "-":
+		return lf - rf, nil
+	case "*":
+		return lf * rf, nil
+	case "/":
+		if rf == 0 {
+			return nil, nil
+		}
+		return lf / rf, nil
+	case "%":
+		if rf == 0 {
+			return nil, nil
+		}
+		return math.Mod(lf, rf), nil
+	case "<":
+		return lf < rf, nil
+	case "<=":
+		return lf <= rf, nil
+	case ">":
+		return lf > rf, nil
+	default:
+		return lf >= rf, nil
+	}
+}
+
+func (e *evaluator) evalAggregate(n *call) (interface{}, error) {
+	if n.name == "count" && len(n.args) == 1 {
+		if ref, ok := n.args[0].(*fieldRef); ok && ref.name == "days" {
+			return e.countDays(n.where)
+		}
+	}
+	countRows := n.name == "count" && (len(n.args) == 0 || isCountTarget(n.args[0]))
+
+	var (
+		count    int
+		sum      float64
+		min, max float64
+	)
+	for _, row := range e.rows {
+		if n.where != nil {
+			ok, err := e.eval(n.where, row)
+			if err != nil {
+				return nil, err
+			}
+			if !truthy(ok) {
+				continue
+			}
+		}
+		if countRows {
+			count++
+			continue
+		}
+		v, err := e.eval(n.args[0], row)
+		if err != nil {
+			return nil, err
+		}
+		if v == nil {
+			continue
+		}
+		if f, ok := v.(float64); ok {
+			if count == 0 || f < min {
+				min = f
+			}
+			if count == 0 || f > max {
+				max = f
+			}
+			sum += f
+		}
+		count++
+	}
+
+	switch n.name {
+	case "count":
+		return float64(count), nil
+	case "sum":
+		return sum, nil
+	}
+	if count == 0 {
+		return nil, nil
+	}
+	switch n.name {
+	case "avg":
+		return sum / float64(count), nil
+	case "min":
+		return min, nil
+	default:
+		return max, nil
+	}
+}
+
+// countDays counts the days of the window, or with a condition the days with a matching entry.
+func (e *evaluator) countDays(where node) (interface{}, error) {
+	if where == nil {
+		return float64(len(e.days)), nil
+	}
+	inWindow := make(map[string]bool, len(e.days))
+	for _, day := range e.days {
+		inWindow[day] = true
+	}
+	matched := make(map[string]bool)
+	for _, row := range e.rows {
+		date, _ := row[DateField].(string)
+		if !inWindow[date] || matched[date] {
+			continue
+		}
+		ok, err := e.eval(where, row)
+		if err != nil {
+			return nil, err
+		}
+		if truthy(ok) {
+			if err := e.alloc(dayCost); err != nil {
+				return nil, err
+			}
+			matched[date] = true
+		}
+	}
+	return float64(len(matched)), nil
+}
+
+func (e *evaluator) evalScalar(n *call, row Row) (interface{}, error) {
+	if n.name == "if" {
+		cond, err := e.eval(n.args[0], row)
+		if err != nil {
+			return nil, err
+		}
+		if truthy(cond) {
+			return e.eval(n.args[1], row)
+		}
+		return e.eval(n.args[2], row)
+	}
+
+	args := make([]interface{}, len(n.args))
+	for i, arg := range n.args {
+		v, err := e.eval(arg, row)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	switch n.name {
+	case "coalesce":
+		if args[0] != nil {
+			return args[0], nil
+		}
+		return args[1], nil
+	case "abs":
+		if f, ok := args[0].(float64); ok {
+			return math.Abs(f), nil
+		}
+		return nil, nil
+	case "minutes_between":
+		return minutesBetween(args[0], args[1]), nil
+	default: // round
+		f, ok := args[0].(float64)
+		if !ok {
+			return nil, nil
+		}
+		digits := 0.0
+		if len(args) == 2 {
+			if d, ok := args[1].(float64); ok {
+				digits = math.Max(-15, math.Min(15, math.Trunc(d)))
+			}
+		}
+		scale := math.Pow(10, digits)
+		return math.Round(f*scale) / scale, nil
+	}
+}
+
+// fieldValue normalises an entry value of a field of type typ: numbers become float64,
+// and values that do not match the field's type (e.g. data written before a type change) become null.
+func fieldValue(v interface{}, typ Type) interface{} {
+	switch typ {
+	case TypeNumber:
+		if n, ok := number(v); ok {
+			return n
+		}
+	case TypeString:
+		if s, ok := v.(string); ok {
+			return s
+		}
+	case TypeBool:
+		if b, ok := v.(bool); ok {
+			return b
+		}
+	}
+	return nil
+}
+
+// number converts the numeric types entries decode to into float64.
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// truthy reports whether v is the boolean true; null and other values are false.
+func truthy(v interface{}) bool {
+	b, ok := v.(bool)
+	return ok && b
+}
+
+// Layouts minutes_between accepts, tried in order. Both values must use the same one.
+var timeLayouts = []string{theme.TimeOfDayLayout, time.RFC3339, "2006-01-02"}
+
+// minutesBetween returns the minutes from start to end, or nil if they are not both times,
+// datetimes or dates of the same kind. A time of day before start is on the next day.
+func minutesBetween(start, end interface{}) interface{} {
+	s, ok1 := start.(string)
+	t, ok2 := end.(string)
+	if !ok1 || !ok2 {
+		return nil
+	}
+	for _, layout := range timeLayouts {
+		from, err1 := time.Parse(layout, s)
+		to, err2 := time.Parse(layout, t)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		if layout == theme.TimeOfDayLayout && to.Before(from) {
+			to = to.Add(24 * time.Hour)
+		}
+		return to.Sub(from).Minutes()
+	}
+	return nil
+}
@@ -0,0 +1,437 @@
+// Package formula implements the expression language of user-defined features.
+//
+// A formula is an expression over aggregates of a theme's entries, optionally grouped by a field:
+//
+//	sum(amount where category == "food") / count(days)
+//	avg(minutes) by subject
+//
+// Aggregates are sum, avg, min, max and count. Their argument is evaluated once per entry and may
+// reference the theme's fields and entry_date; entries for which the optional where condition is
+// not true are skipped. count() and count(entries) count entries, count(days) counts the days of
+// the window (with a where condition, the days with at least one matching entry) and count(x)
+// counts entries where x is not null. Outside aggregates only literals, operators and the scalar
+// functions round(x[, digits]), abs(x), if(cond, a, b), coalesce(a, b) and minutes_between(start, end)
+// may be used. minutes_between takes two times (HH:MM), datetimes or dates; for times an end
+// before the start is taken to be on the next day.
+//
+// Computed theme fields use per-entry expressions, compiled with CompileExpression: they reference
+// the fields of a single entry directly and cannot use aggregates or group by a field:
+//
+//	price * qty
+//	minutes_between(start, end) / 60
+//
+// Operators are + - * / % (+ also joins strings), == != < <= > >=, and/or/not (&& || !).
+// Missing field values are null: arithmetic with null is null, aggregates skip nulls, and
+// comparisons other than == and != with null are false. Division by zero is null.
+//
+// Formulas are type-checked against the theme's fields when compiled and evaluated within Limits,
+// so a formula cannot exhaust the CPU or memory of the process running it.
+package formula
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+// ErrLimitExceeded indicates that a formula is too large or needed more steps, memory or time than allowed.
+var ErrLimitExceeded = errors.New("formula exceeded its resource limits")
+
+// DateField is the name formulas use for an entry's date (YYYY-MM-DD), unless the theme defines a field of that name.
+const DateField = "entry_date"
+
+// Type is the static type of an expression.
+type Type string
+
+const (
+	TypeNumber Type = "number"
+	TypeString Type = "string"
+	TypeBool   Type = "boolean"
+	TypeNull   Type = "null" // Type of the null literal; compatible with every other type
+)
+
+// CurrencySuffix is appended to a money field's name to reference its currency (e.g. price_currency),
+// unless the theme defines a field of that name.
+const CurrencySuffix = "_currency"
+
+// Schema maps the field names a formula can reference to their types.
+type Schema map[string]Type
+
+// SchemaFromFields builds the schema of a theme's fields.
+// Text-like, date, datetime, time and reference (entry ID) fields are strings; dates and times compare in calendar order.
+// Ratings are numbers, durations are numbers of minutes and money fields are their amount,
+// with the currency as a string field named with CurrencySuffix. List and geo fields cannot be referenced.
+func SchemaFromFields(fields []theme.ThemeField) Schema {
+	schema := Schema{DateField: TypeString}
+	for _, f := range fields {
+		if f.Type == theme.FieldTypeMoney {
+			schema[f.Name+CurrencySuffix] = TypeString
+		}
+	}
+	for _, f := range fields {
+		switch f.Type {
+		case theme.FieldTypeNumber, theme.FieldTypeRating, theme.FieldTypeDuration, theme.FieldTypeMoney:
+			schema[f.Name] = TypeNumber
+		case theme.FieldTypeBoolean:
+			schema[f.Name] = TypeBool
+		case theme.FieldTypeText, theme.FieldTypeTextarea, theme.FieldTypeSelect, theme.FieldTypeDate, theme.FieldTypeDateTime,
+			theme.FieldTypeTime, theme.FieldTypeURL, theme.FieldTypeEmail, theme.FieldTypeColor, theme.FieldTypeReference:
+			schema[f.Name] = TypeString
+		}
+	}
+	return schema
+}
+
+// RowFromData converts an entry's data and date to a row of the schema built by SchemaFromFields.
+// Fields of the theme take precedence over DateField and the currencies of money fields.
+func RowFromData(fields []theme.ThemeField, data map[string]interface{}, date string) Row {
+	defined := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		defined[f.Name] = true
+	}
+	row := make(Row, len(data)+1)
+	if !defined[DateField] {
+		row[DateField] = date
+	}
+	for k, v := range data {
+		row[k] = v
+	}
+	for _, f := range fields {
+		switch f.Type {
+		case theme.FieldTypeDuration:
+			row[f.Name] = nil
+			if s, ok := data[f.Name].(string); ok {
+				if d, err := theme.ParseDuration(s); err == nil {
+					row[f.Name] = d.Minutes()
+				}
+			}
+		case theme.FieldTypeMoney:
+			row[f.Name] = nil
+			m, err := theme.ParseMoney(data[f.Name])
+			if err == nil {
+				row[f.Name] = m.Amount
+			}
+			if currency := f.Name + CurrencySuffix; !defined[currency] {
+				row[currency] = nil
+				if err == nil {
+					row[currency] = m.Currency
+				}
+			}
+		}
+	}
+	return row
+}
+
+// Limits bounds the size of a formula and the resources one evaluation may use.
+type Limits struct {
+	MaxLength int           // Maximum length of the source in bytes
+	MaxDepth  int           // Maximum nesting depth of the expression
+	MaxSteps  int           // Maximum evaluation steps (about one per operator per entry)
+	MaxMemory int           // Maximum bytes held by strings and groups built during evaluation
+	Timeout   time.Duration // Maximum wall-clock time of an evaluation (0 means no timeout)
+}
+
+// DefaultLimits returns the limits used for user-defined features.
+func DefaultLimits() Limits {
+	return Limits{
+		MaxLength: 1000,
+		MaxDepth:  32,
+		MaxSteps:  1_000_000,
+		MaxMemory: 1 << 20,
+		Timeout:   500 * time.Millisecond,
+	}
+}
+
+// Program is a compiled, type-checked formula.
+type Program struct {
+	source  string
+	root    node
+	groupBy string
+	typ     Type
+	limits  Limits
+}
+
+// Compile parses src and type-checks it against schema.
+func Compile(src string, schema Schema, limits Limits) (*Program, error) {
+	if len(src) > limits.MaxLength {
+		return nil, fmt.Errorf("%w: formula is longer than %d characters", ErrLimitExceeded, limits.MaxLength)
+	}
+	root, groupBy, err := parse(src, limits.MaxDepth)
+	if err != nil {
+		return nil, err
+	}
+	if groupBy != "" {
+		if _, ok := schema[groupBy]; !ok {
+			return nil, fmt.Errorf("cannot group by unknown field '%s'", groupBy)
+		}
+	}
+	c := &checker{schema: schema}
+	typ, err := c.check(root, false)
+	if err != nil {
+		return nil, err
+	}
+	return &Program{source: src, root: root, groupBy: groupBy, typ: typ, limits: limits}, nil
+}
+
+// CompileExpression parses a per-entry expression and type-checks it against schema.
+// The program is evaluated with EvaluateRow.
+func CompileExpression(src string, schema Schema, limits Limits) (*Program, error) {
+	if len(src) > limits.MaxLength {
+		return nil, fmt.Errorf("%w: formula is longer than %d characters", ErrLimitExceeded, limits.MaxLength)
+	}
+	root, groupBy, err := parse(src, limits.MaxDepth)
+	if err != nil {
+		return nil, err
+	}
+	if groupBy != "" {
+		return nil, fmt.Errorf("'by' cannot be used in a per-entry expression")
+	}
+	c := &checker{schema: schema, entry: true}
+	typ, err := c.check(root, true)
+	if err != nil {
+		return nil, err
+	}
+	return &Program{source: src, root: root, typ: typ, limits: limits}, nil
+}
+
+// Source returns the formula the program was compiled from.
+func (p *Program) Source() string { return p.source }
+
+// Type returns the type of the formula's value.
+func (p *Program) Type() Type { return p.typ }
+
+// GroupBy returns the field the formula is grouped by, or "" if it is not grouped.
+func (p *Program) GroupBy() string { return p.groupBy }
+
+// aggregates are the functions evaluated over entries.
+var aggregates = map[string]bool{"sum": true, "avg": true, "min": true, "max": true, "count": true}
+
+// checker type-checks an expression against a schema.
+type checker struct {
+	schema Schema
+	entry  bool // Whether the expression is per-entry, where aggregates cannot be used
+}
+
+// check returns the type of n. perEntry is true inside an aggregate, where fields can be referenced.
+func (c *checker) check(n node, perEntry bool) (Type, error) {
+	switch n := n.(type) {
+	case *literal:
+		switch n.value.(type) {
+		case float64:
+			return TypeNumber, nil
+		case string:
+			return TypeString, nil
+		case bool:
+			return TypeBool, nil
+		default:
+			return TypeNull, nil
+		}
+	case *fieldRef:
+		typ, ok := c.schema[n.name]
+		if !ok {
+			return "", fmt.Errorf("unknown field '%s' at %d", n.name, n.pos)
+		}
+		if !perEntry {
+			return "", fmt.Errorf("field '%s' at %d must be used inside an aggregate such as sum(%s)", n.name, n.pos, n.name)
+		}
+		n.typ = typ
+		return typ, nil
+	case *unaryOp:
+		want := TypeNumber
+		if n.op == "not" {
+			want = TypeBool
+		}
+		typ, err := c.check(n.x, perEntry)
+		if err != nil {
+			return "", err
+		}
+		if !compatible(typ, want) {
+			return "", fmt.Errorf("'%s' expects a %s, got %s", n.op, want, typ)
+		}
+		return want, nil
+	case *binaryOp:
+		return c.checkBinary(n, perEntry)
+	case *call:
+		if aggregates[n.name] {
+			return c.checkAggregate(n, perEntry)
+		}
+		return c.checkScalar(n, perEntry)
+	}
+	return "", fmt.Errorf("unsupported expression %T", n)
+}
+
+func (c *checker) checkBinary(n *binaryOp, perEntry bool) (Type, error) {
+	l, err := c.check(n.l, perEntry)
+	if err != nil {
+		return "", err
+	}
+	r, err := c.check(n.r, perEntry)
+	if err != nil {
+		return "", err
+	}
+	mismatch := fmt.Errorf("'%s' at %d cannot combine %s and %s", n.op, n.pos, l, r)
+	switch n.op {
+	case "and", "or":
+		if !compatible(l, TypeBool) || !compatible(r, TypeBool) {
+			return "", mismatch
+		}
+		return TypeBool, nil
+	case "==", "!=":
+		if !compatible(l, r) {
+			return "", mismatch
+		}
+		return TypeBool, nil
+	case "<", "<=", ">", ">=":
+		t, ok := unify(l, r)
+		if !ok || t == TypeBool {
+			return "", mismatch
+		}
+		return TypeBool, nil
+	case "+":
+		t, ok := unify(l, r)
+		if !ok || t == TypeBool {
+			return "", mismatch
+		}
+		if t == TypeNull {
+			return TypeNumber, nil
+		}
+		return t, nil
+	default: // - * / %
+		if !compatible(l, TypeNumber) || !compatible(r, TypeNumber) {
+			return "", mismatch
+		}
+		return TypeNumber, nil
+	}
+}
+
+func (c *checker) checkAggregate(n *call, perEntry bool) (Type, error) {
+	if c.entry {
+		return "", fmt.Errorf("aggregate '%s' at %d cannot be used in a per-entry expression", n.name, n.pos)
+	}
+	if perEntry {
+		return "", fmt.Errorf("aggregate '%s' at %d cannot be nested inside another aggregate", n.name, n.pos)
+	}
+	if n.where != nil {
+		typ, err := c.check(n.where, true)
+		if err != nil {
+			return "", err
+		}
+		if !compatible(typ, TypeBool) {
+			return "", fmt.Errorf("where condition of '%s' at %d must be a boolean, got %s", n.name, n.pos, typ)
+		}
+	}
+	if n.name == "count" {
+		if len(n.args) > 1 {
+			return "", fmt.Errorf("count at %d takes at most one argument", n.pos)
+		}
+		if len(n.args) == 1 && !isCountTarget(n.args[0]) {
+			if _, err := c.check(n.args[0], true); err != nil {
+				return "", err
+			}
+		}
+		return TypeNumber, nil
+	}
+	if len(n.args) != 1 {
+		return "", fmt.Errorf("%s at %d takes exactly one argument", n.name, n.pos)
+	}
+	typ, err := c.check(n.args[0], true)
+	if err != nil {
+		return "", err
+	}
+	if !compatible(typ, TypeNumber) {
+		return "", fmt.Errorf("%s at %d expects a number, got %s", n.name, n.pos, typ)
+	}
+	return TypeNumber, nil
+}
+
+func (c *checker) checkScalar(n *call, perEntry bool) (Type, error) {
+	if !scalars[n.name] {
+		return "", fmt.Errorf("unknown function '%s' at %d", n.name, n.pos)
+	}
+	if n.where != nil {
+		return "", fmt.Errorf("'where' at %d can only be used in an aggregate", n.pos)
+	}
+	types := make([]Type, len(n.args))
+	for i, arg := range n.args {
+		typ, err := c.check(arg, perEntry)
+		if err != nil {
+			return "", err
+		}
+		types[i] = typ
+	}
+	switch n.name {
+	case "round", "abs":
+		if len(types) < 1 || len(types) > 2 || n.name == "abs" && len(types) != 1 {
+			return "", fmt.Errorf("wrong number of arguments to %s at %d", n.name, n.pos)
+		}
+		for _, t := range types {
+			if !compatible(t, TypeNumber) {
+				return "", fmt.Errorf("%s at %d expects numbers, got %s", n.name, n.pos, t)
+			}
+		}
+		return TypeNumber, nil
+	case "if":
+		if len(types) != 3 {
+			return "", fmt.Errorf("if at %d takes a condition and two values", n.pos)
+		}
+		if !compatible(types[0], TypeBool) {
+			return "", fmt.Errorf("condition of if at %d must be a boolean, got %s", n.pos, types[0])
+		}
+		t, ok := unify(types[1], types[2])
+		if !ok {
+			return "", fmt.Errorf("values of if at %d have different types %s and %s", n.pos, types[1], types[2])
+		}
+		return t, nil
+	case "coalesce":
+		if len(types) != 2 {
+			return "", fmt.Errorf("coalesce at %d takes two values", n.pos)
+		}
+		t, ok := unify(types[0], types[1])
+		if !ok {
+			return "", fmt.Errorf("values of coalesce at %d have different types %s and %s", n.pos, types[0], types[1])
+		}
+		return t, nil
+	case "minutes_between":
+		if len(types) != 2 {
+			return "", fmt.Errorf("minutes_between at %d takes a start and an end", n.pos)
+		}
+		for _, t := range types {
+			if !compatible(t, TypeString) {
+				return "", fmt.Errorf("minutes_between at %d expects times, datetimes or dates, got %s", n.pos, t)
+			}
+		}
+		return TypeNumber, nil
+	}
+	return "", fmt.Errorf("unknown function '%s' at %d", n.name, n.pos)
+}
+
+// scalars are the functions evaluated on single values.
+var scalars = map[string]bool{"round": true, "abs": true, "if": true, "coalesce": true, "minutes_between": true}
+
+// isCountTarget reports whether n is the days or entries argument of count.
+// These names take precedence over theme fields of the same name inside count.
+func isCountTarget(n node) bool {
+	ref, ok := n.(*fieldRef)
+	return ok && (ref.name == "days" || ref.name == "entries")
+}
+
+// compatible reports whether values of types a and b can be compared or combined.
+func compatible(a, b Type) bool {
+	_, ok := unify(a, b)
+	return ok
+}
+
+// unify returns the common type of a and b; null is compatible with every type.
+func unify(a, b Type) (Type, bool) {
+	switch {
+	case a == b:
+		return a, true
+	case a == TypeNull:
+		return b, true
+	case b == TypeNull:
+		return a, true
+	}
+	return "", false
+}
@@ -0,0 +1,142 @@
+package formula
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+var expenseSchema = SchemaFromFields([]theme.ThemeField{
+	{Name: "category", Label: "Category", Type: theme.FieldTypeSelect},
+	{Name: "amount", Label: "Amount", Type: theme.FieldTypeNumber},
+	{Name: "shared", Label: "Shared", Type: theme.FieldTypeBoolean},
+})
+
+var expenseRows = []Row{
+	{"entry_date": "2025-05-01", "category": "food", "amount": 1200.0, "shared": true},
+	{"entry_date": "2025-05-01", "category": "food", "amount": 800},
+	{"entry_date": "2025-05-02", "category": "rent", "amount": 50000.0},
+	{"entry_date": "2025-05-03", "category": "food", "amount": "broken"}, // Wrong type is treated as null
+	{"entry_date": "2025-05-03", "category": "hobby"},
+}
+
+var mayDays = []string{"2025-05-01", "2025-05-02", "2025-05-03", "2025-05-04"}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		formula string
+		want    interface{}
+	}{
+		{`sum(amount where category == "food") / count(days)`, 500.0},
+		{`count()`, 5.0},
+		{`count(entries where category == 'food')`, 3.0},
+		{`count(amount)`, 3.0},
+		{`count(days where category == "food")`, 2.0},
+		{`avg(amount)`, 52000.0 / 3},
+		{`min(amount) + max(amount)`, 50800.0},
+		{`max(amount where category == "none")`, nil},
+		{`round(avg(amount where shared or category == "rent"), -2)`, 25600.0},
+		{`sum(amount) / 0`, nil},
+		{`if(sum(amount) > 10000, "high", "low")`, "high"},
+		{`coalesce(max(amount where not shared and amount < 1000), -1)`, 800.0},
+		{`count(entries where entry_date >= "2025-05-02")`, 3.0},
+		{`-abs(2 - 5) * 2 % 4`, -2.0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.formula, func(t *testing.T) {
+			p, err := Compile(tt.formula, expenseSchema, DefaultLimits())
+			assert.NoError(t, err)
+
+			res, err := p.Evaluate(context.Background(), expenseRows, mayDays)
+
+			assert.NoError(t, err)
+			if f, ok := tt.want.(float64); ok {
+				assert.InDelta(t, f, res.Value, 1e-9)
+				return
+			}
+			assert.Equal(t, tt.want, res.Value)
+		})
+	}
+}
+
+func TestEvaluate_GroupBy(t *testing.T) {
+	p, err := Compile(`sum(amount) by category`, expenseSchema, DefaultLimits())
+	assert.NoError(t, err)
+	assert.Equal(t, "category", p.GroupBy())
+
+	res, err := p.Evaluate(context.Background(), expenseRows, mayDays)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Group{{Key: "food", Value: 2000.0}, {Key: "hobby", Value: 0.0}, {Key: "rent", Value: 50000.0}}, res.Groups)
+}
+
+func TestCompile_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		formula string
+		wantErr string
+	}{
+		{"unknown field", `sum(price)`, "unknown field 'price'"},
+		{"field outside aggregate", `amount + 1`, "must be used inside an aggregate"},
+		{"nested aggregate", `sum(sum(amount))`, "cannot be nested"},
+		{"sum of a string", `sum(category)`, "expects a number"},
+		{"non-boolean where", `sum(amount where amount)`, "must be a boolean"},
+		{"mismatched comparison", `count(entries where category == 1)`, "cannot combine string and number"},
+		{"unknown function", `median(amount)`, "unknown function 'median'"},
+		{"unknown group field", `count() by shop`, "unknown field 'shop'"},
+		{"syntax error", `sum(amount`, "expected ')'"},
+		{"trailing input", `count() count()`, "unexpected 'count'"},
+		{"unterminated string", `count(entries where category == "food)`, "unterminated string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile(tt.formula, expenseSchema, DefaultLimits())
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLimits(t *testing.T) {
+	limits := DefaultLimits()
+
+	// Size limits are enforced at compile time
+	_, err := Compile(strings.Repeat("1 + ", 300)+"1", expenseSchema, limits)
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+	_, err = Compile(strings.Repeat("(", 40)+"1"+strings.Repeat(")", 40), expenseSchema, limits)
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+
+	rows := make([]Row, 1000)
+	for i := range rows {
+		rows[i] = Row{"entry_date": "2025-05-01", "category": strings.Repeat("x", i), "amount": 1.0}
+	}
+
+	// Steps grow with the number of rows
+	limits.MaxSteps = 2000
+	p, err := Compile(`sum(amount * 2)`, expenseSchema, limits)
+	assert.NoError(t, err)
+	_, err = p.Evaluate(context.Background(), rows, mayDays)
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+
+	// Groups and built strings count against the memory limit
+	limits = DefaultLimits()
+	limits.MaxMemory = 64 * 1024
+	p, err = Compile(`count() by category`, expenseSchema, limits)
+	assert.NoError(t, err)
+	_, err = p.Evaluate(context.Background(), rows, mayDays)
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+
+	// A timeout stops slow evaluations
+	limits = DefaultLimits()
+	limits.Timeout = time.Nanosecond
+	p, err = Compile(`sum(amount * 2)`, expenseSchema, limits)
+	assert.NoError(t, err)
+	_, err = p.Evaluate(context.Background(), rows, mayDays)
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+}
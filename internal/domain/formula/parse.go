@@ -0,0 +1,423 @@
+package formula
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// --- Lexer ---
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokString
+	tokIdent
+	tokOp // Operators and punctuation
+)
+
+type token struct {
+	kind tokenKind
+	text string  // Identifier, operator or decoded string literal
+	num  float64 // Value of a number literal
+	pos  int     // Byte offset in the source, for error messages
+}
+
+// operators lists the operator tokens, longest first so "<=" wins over "<".
+var operators = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "+", "-", "*", "/", "%", "(", ")", ",", "!"}
+
+// lex splits src into tokens.
+func lex(src string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(src); {
+		c := rune(src[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c >= '0' && c <= '9' || c == '.' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9':
+			start := i
+			for i < len(src) && (src[i] >= '0' && src[i] <= '9' || src[i] == '.') {
+				i++
+			}
+			n, err := strconv.ParseFloat(src[start:i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number '%s' at %d", src[start:i], start)
+			}
+			tokens = append(tokens, token{kind: tokNumber, num: n, pos: start})
+		case c == '"' || c == '\'':
+			start := i
+			var sb strings.Builder
+			i++
+			for {
+				if i >= len(src) {
+					return nil, fmt.Errorf("unterminated string starting at %d", start)
+				}
+				if src[i] == byte(c) {
+					i++
+					break
+				}
+				if src[i] == '\\' && i+1 < len(src) {
+					i++
+					switch src[i] {
+					case 'n':
+						sb.WriteByte('\n')
+					case 't':
+						sb.WriteByte('\t')
+					default:
+						sb.WriteByte(src[i])
+					}
+					i++
+					continue
+				}
+				sb.WriteByte(src[i])
+				i++
+			}
+			tokens = append(tokens, token{kind: tokString, text: sb.String(), pos: start})
+		case c == '_' || c < unicode.MaxASCII && unicode.IsLetter(c):
+			start := i
+			for i < len(src) && (src[i] == '_' || src[i] < unicode.MaxASCII && (unicode.IsLetter(rune(src[i])) || unicode.IsDigit(rune(src[i])))) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: src[start:i], pos: start})
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(src[i:], op) {
+					tokens = append(tokens, token{kind: tokOp, text: op, pos: i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected character '%c' at %d", c, i)
+			}
+		}
+	}
+	return append(tokens, token{kind: tokEOF, pos: len(src)}), nil
+}
+
+// --- AST ---
+
+type node interface{}
+
+type (
+	literal  struct{ value interface{} } // float64, string, bool or nil
+	fieldRef struct {
+		name string
+		pos  int
+		typ  Type // Set by the type checker
+	}
+	unaryOp struct {
+		op string // "-" or "not"
+		x  node
+	}
+	binaryOp struct {
+		op   string // Arithmetic, comparison, "and" or "or"
+		l, r node
+		pos  int
+	}
+	call struct {
+		name  string
+		args  []node
+		where node // Row filter of an aggregate (nil if none)
+		pos   int
+	}
+)
+
+// keywords cannot be used as field names in formulas.
+var keywords = map[string]bool{"and": true, "or": true, "not": true, "where": true, "by": true, "true": true, "false": true, "null": true}
+
+// --- Parser ---
+//
+// formula := expr [ "by" IDENT ]
+// expr    := and { ("or" | "||") and }
+// and     := not { ("and" | "&&") not }
+// not     := ("not" | "!") not | cmp
+// cmp     := add [ ("==" | "!=" | "<" | "<=" | ">" | ">=") add ]
+// add     := mul { ("+" | "-") mul }
+// mul     := unary { ("*" | "/" | "%") unary }
+// unary   := "-" unary | primary
+// primary := NUMBER | STRING | "true" | "false" | "null" | IDENT
+//          | IDENT "(" [ expr { "," expr } ] [ "where" expr ] ")" | "(" expr ")"
+
+type parser struct {
+	tokens   []token
+	pos      int
+	depth    int
+	maxDepth int
+}
+
+// parse parses a formula and returns its expression and optional group-by field.
+func parse(src string, maxDepth int) (node, string, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, "", err
+	}
+	p := &parser{tokens: tokens, maxDepth: maxDepth}
+	root, err := p.expr()
+	if err != nil {
+		return nil, "", err
+	}
+	groupBy := ""
+	if p.isKeyword("by") {
+		p.next()
+		t := p.next()
+		if t.kind != tokIdent || keywords[t.text] {
+			return nil, "", fmt.Errorf("expected a field name after 'by' at %d", t.pos)
+		}
+		groupBy = t.text
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, "", fmt.Errorf("unexpected '%s' at %d", t.display(), t.pos)
+	}
+	return root, groupBy, nil
+}
+
+func (t token) display() string {
+	switch t.kind {
+	case tokNumber:
+		return strconv.FormatFloat(t.num, 'g', -1, 64)
+	case tokString:
+		return strconv.Quote(t.text)
+	case tokEOF:
+		return "end of formula"
+	default:
+		return t.text
+	}
+}
+
+func (p *parser) peek() token { return p.tokens[p.pos] }
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) isOp(ops ...string) bool {
+	t := p.peek()
+	if t.kind != tokOp {
+		return false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) isKeyword(kw string) bool {
+	t := p.peek()
+	return t.kind == tokIdent && t.text == kw
+}
+
+func (p *parser) expect(op string) error {
+	if !p.isOp(op) {
+		t := p.peek()
+		return fmt.Errorf("expected '%s' at %d, got '%s'", op, t.pos, t.display())
+	}
+	p.next()
+	return nil
+}
+
+// enter tracks nesting depth so deeply nested input cannot exhaust the stack.
+func (p *parser) enter() error {
+	p.depth++
+	if p.depth > p.maxDepth {
+		return fmt.Errorf("%w: formula is nested deeper than %d levels", ErrLimitExceeded, p.maxDepth)
+	}
+	return nil
+}
+
+func (p *parser) leave() { p.depth-- }
+
+func (p *parser) expr() (node, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+	l, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("or") || p.isOp("||") {
+		t := p.next()
+		r, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		l = &binaryOp{op: "or", l: l, r: r, pos: t.pos}
+	}
+	return l, nil
+}
+
+func (p *parser) and() (node, error) {
+	l, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("and") || p.isOp("&&") {
+		t := p.next()
+		r, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		l = &binaryOp{op: "and", l: l, r: r, pos: t.pos}
+	}
+	return l, nil
+}
+
+func (p *parser) not() (node, error) {
+	if p.isKeyword("not") || p.isOp("!") {
+		p.next()
+		if err := p.enter(); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+		x, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryOp{op: "not", x: x}, nil
+	}
+	return p.cmp()
+}
+
+func (p *parser) cmp() (node, error) {
+	l, err := p.add()
+	if err != nil {
+		return nil, err
+	}
+	if p.isOp("==", "!=", "<", "<=", ">", ">=") {
+		t := p.next()
+		r, err := p.add()
+		if err != nil {
+			return nil, err
+		}
+		return &binaryOp{op: t.text, l: l, r: r, pos: t.pos}, nil
+	}
+	return l, nil
+}
+
+func (p *parser) add() (node, error) {
+	l, err := p.mul()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("+", "-") {
+		t := p.next()
+		r, err := p.mul()
+		if err != nil {
+			return nil, err
+		}
+		l = &binaryOp{op: t.text, l: l, r: r, pos: t.pos}
+	}
+	return l, nil
+}
+
+func (p *parser) mul() (node, error) {
+	l, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("*", "/", "%") {
+		t := p.next()
+		r, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		l = &binaryOp{op: t.text, l: l, r: r, pos: t.pos}
+	}
+	return l, nil
+}
+
+func (p *parser) unary() (node, error) {
+	if p.isOp("-") {
+		p.next()
+		if err := p.enter(); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryOp{op: "-", x: x}, nil
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		return &literal{value: t.num}, nil
+	case tokString:
+		return &literal{value: t.text}, nil
+	case tokIdent:
+		switch t.text {
+		case "true":
+			return &literal{value: true}, nil
+		case "false":
+			return &literal{value: false}, nil
+		case "null":
+			return &literal{value: nil}, nil
+		}
+		if keywords[t.text] {
+			return nil, fmt.Errorf("unexpected '%s' at %d", t.text, t.pos)
+		}
+		if p.isOp("(") {
+			return p.call(t)
+		}
+		return &fieldRef{name: t.text, pos: t.pos}, nil
+	case tokOp:
+		if t.text == "(" {
+			x, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return x, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected '%s' at %d", t.display(), t.pos)
+}
+
+func (p *parser) call(name token) (node, error) {
+	p.next() // "("
+	c := &call{name: name.text, pos: name.pos}
+	if !p.isOp(")") && !p.isKeyword("where") {
+		for {
+			arg, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			c.args = append(c.args, arg)
+			if !p.isOp(",") {
+				break
+			}
+			p.next()
+		}
+	}
+	if p.isKeyword("where") {
+		p.next()
+		where, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		c.where = where
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -0,0 +1,34 @@
+package theme
+
+import "fmt"
+
+// ComputedTypes are the field types a computed field can have.
+var ComputedTypes = []FieldType{FieldTypeNumber, FieldTypeText, FieldTypeBoolean}
+
+// IsComputed reports whether the field's value is computed from its formula rather than written by clients.
+func (f ThemeField) IsComputed() bool {
+	return f.Formula != ""
+}
+
+// validateComputedSettings checks that a computed field's type and settings suit a value clients cannot write.
+// The formula itself is checked against the other fields by the formula package.
+func (f ThemeField) validateComputedSettings() error {
+	if !f.IsComputed() {
+		return nil
+	}
+	allowed := false
+	for _, t := range ComputedTypes {
+		allowed = allowed || f.Type == t
+	}
+	if !allowed {
+		return fmt.Errorf("formula is only allowed on %s, %s and %s fields", FieldTypeNumber, FieldTypeText, FieldTypeBoolean)
+	}
+	// A formula may evaluate to null, and clients cannot supply the value instead
+	if f.Required {
+		return fmt.Errorf("computed fields cannot be required")
+	}
+	if f.Default != nil {
+		return fmt.Errorf("computed fields cannot have a default")
+	}
+	return nil
+}
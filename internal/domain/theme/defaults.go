@@ -0,0 +1,59 @@
+package theme
+
+import (
+	"github.com/google/uuid"
+)
+
+// --- Default themes ---
+
+// DefaultTheme is a built-in theme definition offered to every user (see docs/rfp.md 2.2).
+// Default themes are seeded into the store at startup and updated there whenever their definition here changes.
+type DefaultTheme struct {
+	Definition Theme
+	// Migration holds the steps migrating entries written with the previously shipped definition
+	// to this one (see PlanSchemaChange). Reset it to nil once every deployment has been seeded.
+	Migration []MigrationStep
+}
+
+// Fixed IDs of the default themes. They must never change, since entries refer to themes by ID.
+var (
+	ScheduleThemeID = uuid.MustParse("7b0c2a4e-5d1f-4c3b-9a6e-0d8f2e1c4a01")
+	TodoThemeID     = uuid.MustParse("7b0c2a4e-5d1f-4c3b-9a6e-0d8f2e1c4a02")
+)
+
+// DefaultThemes returns the built-in default theme definitions.
+// A new slice is built on each call, so callers may modify the themes.
+func DefaultThemes() []DefaultTheme {
+	return []DefaultTheme{
+		{Definition: Theme{
+			ThemeID:   ScheduleThemeID,
+			ThemeName: "予定管理",
+			IsDefault: true,
+			Fields: []ThemeField{
+				{Name: "title", Label: "イベント名", Type: FieldTypeText, Required: true},
+				{Name: "start_datetime", Label: "開始日時", Type: FieldTypeDateTime, Required: true},
+				{Name: "end_datetime", Label: "終了日時", Type: FieldTypeDateTime},
+				{Name: "location", Label: "場所", Type: FieldTypeGeo},
+				{Name: "memo", Label: "メモ", Type: FieldTypeTextarea},
+			},
+			SupportedFeatures: []SupportedFeature{{Name: "monthly_summary"}},
+		}},
+		{Definition: Theme{
+			ThemeID:   TodoThemeID,
+			ThemeName: "ToDo 管理",
+			IsDefault: true,
+			Fields: []ThemeField{
+				{Name: "task_name", Label: "タスク名", Type: FieldTypeText, Required: true},
+				{Name: "due_datetime", Label: "期限日時", Type: FieldTypeDateTime},
+				{Name: "completed", Label: "完了", Type: FieldTypeBoolean},
+				{Name: "priority", Label: "優先度", Type: FieldTypeSelect, Default: "medium", Options: []FieldOption{
+					{Value: "high", Label: "高", Color: "#f44336"},
+					{Value: "medium", Label: "中", Color: "#ff9800"},
+					{Value: "low", Label: "低", Color: "#4caf50"},
+				}},
+				{Name: "memo", Label: "メモ", Type: FieldTypeTextarea},
+			},
+			SupportedFeatures: []SupportedFeature{{Name: "monthly_summary"}},
+		}},
+	}
+}
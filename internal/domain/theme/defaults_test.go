@@ -0,0 +1,26 @@
+package theme
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultThemes(t *testing.T) {
+	ids := map[string]bool{}
+	for _, d := range DefaultThemes() {
+		th := d.Definition
+		t.Run(th.ThemeName, func(t *testing.T) {
+			assert.NoError(t, th.Validate())
+			assert.True(t, th.IsDefault)
+			assert.Nil(t, th.OwnerUserID, "default themes have no owner")
+			assert.False(t, ids[th.ThemeID.String()], "theme IDs are unique")
+			ids[th.ThemeID.String()] = true
+		})
+	}
+
+	// Callers get their own copies
+	themes := DefaultThemes()
+	themes[0].Definition.Fields[0].Label = "changed"
+	assert.NotEqual(t, "changed", DefaultThemes()[0].Definition.Fields[0].Label)
+}
@@ -0,0 +1,392 @@
+package theme
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// FieldOption is an allowed value of a select field.
+// Corresponds to api.ThemeFieldOption.
+type FieldOption struct {
+	Value string `dynamodbav:"Value"`           // Value stored in entries
+	Label string `dynamodbav:"Label,omitempty"` // Display label ("" means the value is shown)
+	Color string `dynamodbav:"Color,omitempty"` // Display color as #RGB or #RRGGBB ("" for none)
+}
+
+// maxPatternLength bounds the size of a text field's regular expression.
+const maxPatternLength = 500
+
+var colorRegex = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// validateConstraints checks that the field's options, default and constraints suit its type.
+// Constraints of list fields apply to their items.
+func (f ThemeField) validateConstraints() error {
+	item := f.itemField()
+
+	// 1. Options only apply to select and multiselect fields
+	if len(f.Options) > 0 {
+		if item.Type != FieldTypeSelect {
+			return fmt.Errorf("options are only allowed on %s and %s fields", FieldTypeSelect, FieldTypeMultiSelect)
+		}
+		values := make(map[string]bool, len(f.Options))
+		for i, o := range f.Options {
+			if o.Value == "" {
+				return fmt.Errorf("option %d: value is required", i)
+			}
+			if values[o.Value] {
+				return fmt.Errorf("option value '%s' is duplicated", o.Value)
+			}
+			values[o.Value] = true
+			if o.Color != "" && !colorRegex.MatchString(o.Color) {
+				return fmt.Errorf("option '%s': color '%s' must be #RGB or #RRGGBB", o.Value, o.Color)
+			}
+		}
+	}
+
+	// 2. Min and max apply to ordered types and must be of the field's type
+	if f.Min != nil || f.Max != nil {
+		if !isOrdered(item.Type) {
+			return fmt.Errorf("min and max are only allowed on %s, %s, %s, %s, %s and %s fields and lists of them",
+				FieldTypeNumber, FieldTypeRating, FieldTypeDate, FieldTypeDateTime, FieldTypeTime, FieldTypeDuration)
+		}
+		if f.Min != nil {
+			if err := item.validateType(f.Min); err != nil {
+				return fmt.Errorf("min: %v", err)
+			}
+		}
+		if f.Max != nil {
+			if err := item.validateType(f.Max); err != nil {
+				return fmt.Errorf("max: %v", err)
+			}
+		}
+		if f.Min != nil && f.Max != nil && compareOrdered(item.Type, f.Min, f.Max) > 0 {
+			return fmt.Errorf("min cannot be greater than max")
+		}
+	}
+	if item.Type == FieldTypeRating {
+		if lo, hi := item.RatingBounds(); lo > hi {
+			return fmt.Errorf("min cannot be greater than max (ratings default to %d-%d)", DefaultRatingMin, DefaultRatingMax)
+		}
+	}
+
+	// 3. Lengths and patterns apply to text fields
+	if f.MinLength != nil || f.MaxLength != nil || f.Pattern != "" {
+		if item.Type != FieldTypeText && item.Type != FieldTypeTextarea {
+			return fmt.Errorf("min_length, max_length and pattern are only allowed on %s and %s fields and lists of text", FieldTypeText, FieldTypeTextarea)
+		}
+		if f.MinLength != nil && *f.MinLength < 0 || f.MaxLength != nil && *f.MaxLength < 0 {
+			return fmt.Errorf("min_length and max_length cannot be negative")
+		}
+		if f.MinLength != nil && f.MaxLength != nil && *f.MinLength > *f.MaxLength {
+			return fmt.Errorf("min_length cannot be greater than max_length")
+		}
+		if len(f.Pattern) > maxPatternLength {
+			return fmt.Errorf("pattern cannot be longer than %d characters", maxPatternLength)
+		}
+		if _, err := regexp.Compile(f.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern: %v", err)
+		}
+	}
+
+	// 4. Target themes and delete actions apply to reference fields
+	if err := f.validateReferenceSettings(); err != nil {
+		return err
+	}
+
+	// 5. Formulas apply to computed number, text and boolean fields
+	if err := f.validateComputedSettings(); err != nil {
+		return err
+	}
+
+	// 6. The default must itself be a valid value
+	if f.Default != nil {
+		if err := f.ValidateValue(f.Default); err != nil {
+			return fmt.Errorf("invalid default: %w", err)
+		}
+	}
+	return nil
+}
+
+// ValidateValue checks a non-nil entry value against the field's type and constraints.
+// Errors name the field.
+func (f ThemeField) ValidateValue(value interface{}) error {
+	if f.Type.IsList() {
+		return f.validateList(value)
+	}
+	if err := f.validateType(value); err != nil {
+		return fmt.Errorf("field '%s' %v", f.Name, err)
+	}
+
+	switch f.Type {
+	case FieldTypeSelect:
+		if len(f.Options) == 0 {
+			return nil
+		}
+		s := value.(string)
+		for _, o := range f.Options {
+			if o.Value == s {
+				return nil
+			}
+		}
+		return fmt.Errorf("field '%s' must be one of %v, got '%s'", f.Name, f.OptionValues(), s)
+	case FieldTypeRating:
+		n, _ := numberValue(value)
+		lo, hi := f.RatingBounds()
+		if n != math.Trunc(n) || n < lo || n > hi {
+			return fmt.Errorf("field '%s' expects a whole number from %g to %g, got %g", f.Name, lo, hi, n)
+		}
+	case FieldTypeNumber, FieldTypeDate, FieldTypeDateTime, FieldTypeTime, FieldTypeDuration:
+		if f.Min != nil && compareOrdered(f.Type, value, f.Min) < 0 {
+			return fmt.Errorf("field '%s' must be at least %v", f.Name, f.Min)
+		}
+		if f.Max != nil && compareOrdered(f.Type, value, f.Max) > 0 {
+			return fmt.Errorf("field '%s' must be at most %v", f.Name, f.Max)
+		}
+	case FieldTypeText, FieldTypeTextarea:
+		s := value.(string)
+		length := utf8.RuneCountInString(s)
+		if f.MinLength != nil && length < *f.MinLength {
+			return fmt.Errorf("field '%s' must be at least %d characters long", f.Name, *f.MinLength)
+		}
+		if f.MaxLength != nil && length > *f.MaxLength {
+			return fmt.Errorf("field '%s' must be at most %d characters long", f.Name, *f.MaxLength)
+		}
+		if f.Pattern != "" {
+			re, err := regexp.Compile(f.Pattern)
+			if err != nil {
+				return fmt.Errorf("field '%s' has an invalid pattern: %v", f.Name, err)
+			}
+			if !re.MatchString(s) {
+				return fmt.Errorf("field '%s' does not match the pattern '%s'", f.Name, f.Pattern)
+			}
+		}
+	}
+	return nil
+}
+
+// validateList checks the value of a list field and each of its items.
+// Multiselect and tags fields cannot hold the same item twice.
+func (f ThemeField) validateList(value interface{}) error {
+	items, ok := ListItems(value)
+	if !ok {
+		return fmt.Errorf("field '%s' expects a list, got %T", f.Name, value)
+	}
+	item := f.itemField()
+	unique := f.Type == FieldTypeMultiSelect || f.Type == FieldTypeTags
+	seen := make(map[interface{}]bool, len(items))
+	for i, v := range items {
+		if v == nil {
+			return fmt.Errorf("field '%s' cannot contain null (item %d)", f.Name, i)
+		}
+		if err := item.ValidateValue(v); err != nil {
+			return fmt.Errorf("%v (item %d)", err, i)
+		}
+		if f.Type == FieldTypeTags && v == "" {
+			return fmt.Errorf("field '%s' cannot contain empty tags (item %d)", f.Name, i)
+		}
+		if unique {
+			if seen[v] {
+				return fmt.Errorf("field '%s' contains '%v' more than once", f.Name, v)
+			}
+			seen[v] = true
+		}
+	}
+	return nil
+}
+
+// itemField returns the field describing each item of a list field, or the field itself if it is not a list.
+func (f ThemeField) itemField() ThemeField {
+	if item := f.Type.ItemType(); item != "" {
+		f.Type = item
+	}
+	return f
+}
+
+// ParseItem converts the text form of a list field's item (e.g. from a query string) to its value
+// and validates it. Numbers and booleans are parsed; other item types are kept as strings.
+func (f ThemeField) ParseItem(s string) (interface{}, error) {
+	if !f.Type.IsList() {
+		return nil, fmt.Errorf("field '%s' is not a list", f.Name)
+	}
+	item := f.itemField()
+	var value interface{} = s
+	switch item.Type {
+	case FieldTypeNumber:
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("field '%s' expects number items, got '%s'", f.Name, s)
+		}
+		value = n
+	case FieldTypeBoolean:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("field '%s' expects boolean items, got '%s'", f.Name, s)
+		}
+		value = b
+	}
+	if err := item.validateType(value); err != nil {
+		return nil, fmt.Errorf("field '%s' item %v", f.Name, err)
+	}
+	return value, nil
+}
+
+// ListItems returns the items of a list value as decoded from JSON or DynamoDB ([]interface{})
+// or built in Go ([]string). ok is false if the value is not a list.
+func ListItems(value interface{}) (items []interface{}, ok bool) {
+	switch v := value.(type) {
+	case []interface{}:
+		return v, true
+	case []string:
+		items = make([]interface{}, len(v))
+		for i, s := range v {
+			items[i] = s
+		}
+		return items, true
+	}
+	return nil, false
+}
+
+// OptionValues returns the values of the field's options, in order.
+func (f ThemeField) OptionValues() []string {
+	values := make([]string, len(f.Options))
+	for i, o := range f.Options {
+		values[i] = o.Value
+	}
+	return values
+}
+
+// validateType checks that a non-nil value has the representation of the field's type.
+// The error completes a sentence starting with the field name.
+func (f ThemeField) validateType(value interface{}) error {
+	switch f.Type {
+	case FieldTypeText, FieldTypeTextarea, FieldTypeSelect:
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("expects a string, got %T", value)
+		}
+	case FieldTypeNumber, FieldTypeRating:
+		// Allow int or float64 from JSON unmarshalling
+		if _, ok := numberValue(value); !ok {
+			return fmt.Errorf("expects a number, got %T", value)
+		}
+	case FieldTypeBoolean:
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("expects a boolean, got %T", value)
+		}
+	case FieldTypeDate:
+		valStr, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expects a date string (YYYY-MM-DD), got %T", value)
+		}
+		if _, err := time.Parse("2006-01-02", valStr); err != nil {
+			return fmt.Errorf("has invalid date format: %v. Expected YYYY-MM-DD", err)
+		}
+	case FieldTypeDateTime:
+		valStr, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expects a datetime string (RFC3339), got %T", value)
+		}
+		if _, err := time.Parse(time.RFC3339, valStr); err != nil {
+			return fmt.Errorf("has invalid datetime format: %v. Expected RFC3339", err)
+		}
+	case FieldTypeDuration, FieldTypeTime, FieldTypeURL, FieldTypeEmail, FieldTypeColor:
+		valStr, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expects a %s string, got %T", f.Type, value)
+		}
+		return validateFormattedString(f.Type, valStr)
+	case FieldTypeMoney:
+		_, err := ParseMoney(value)
+		return err
+	case FieldTypeGeo:
+		_, err := ParseGeo(value)
+		return err
+	case FieldTypeReference:
+		_, err := ParseReference(value)
+		return err
+	default:
+		return fmt.Errorf("has unknown type '%s'", f.Type)
+	}
+	return nil
+}
+
+// validateFormattedString checks the format of a duration, time, url, email or color value.
+func validateFormattedString(t FieldType, s string) error {
+	switch t {
+	case FieldTypeDuration:
+		if _, err := ParseDuration(s); err != nil {
+			return fmt.Errorf("has %v", err)
+		}
+	case FieldTypeTime:
+		return validateTimeOfDay(s)
+	case FieldTypeURL:
+		return validateURL(s)
+	case FieldTypeEmail:
+		return validateEmail(s)
+	case FieldTypeColor:
+		if !colorRegex.MatchString(s) {
+			return fmt.Errorf("has invalid color '%s'. Expected #RGB or #RRGGBB", s)
+		}
+	}
+	return nil
+}
+
+// isOrdered reports whether min and max apply to values of the type.
+func isOrdered(t FieldType) bool {
+	switch t {
+	case FieldTypeNumber, FieldTypeRating, FieldTypeDate, FieldTypeDateTime, FieldTypeTime, FieldTypeDuration:
+		return true
+	}
+	return false
+}
+
+// compareOrdered compares two values already validated for a field of an ordered type (see isOrdered).
+// It returns -1, 0 or 1.
+func compareOrdered(t FieldType, a, b interface{}) int {
+	switch t {
+	case FieldTypeNumber, FieldTypeRating:
+		x, _ := numberValue(a)
+		y, _ := numberValue(b)
+		return compare(x < y, x > y)
+	case FieldTypeDateTime:
+		x, _ := time.Parse(time.RFC3339, a.(string))
+		y, _ := time.Parse(time.RFC3339, b.(string))
+		return compare(x.Before(y), x.After(y))
+	case FieldTypeDuration:
+		x, _ := ParseDuration(a.(string))
+		y, _ := ParseDuration(b.(string))
+		return compare(x < y, x > y)
+	default:
+		// YYYY-MM-DD and HH:MM sort chronologically
+		x, y := a.(string), b.(string)
+		return compare(x < y, x > y)
+	}
+}
+
+func compare(less, greater bool) int {
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	}
+	return 0
+}
+
+// numberValue converts the numeric types JSON and DynamoDB decoding produce into float64.
+func numberValue(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
@@ -0,0 +1,164 @@
+package theme
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func intPtr(i int) *int { return &i }
+
+func TestThemeField_ValidateValue(t *testing.T) {
+	status := ThemeField{Name: "status", Label: "Status", Type: FieldTypeSelect, Options: []FieldOption{
+		{Value: "todo", Label: "To do", Color: "#ccc"},
+		{Value: "done", Label: "Done", Color: "#00ff00"},
+	}}
+	amount := ThemeField{Name: "amount", Label: "Amount", Type: FieldTypeNumber, Min: 0.0, Max: 1000.0}
+	due := ThemeField{Name: "due", Label: "Due", Type: FieldTypeDate, Min: "2024-01-01", Max: "2024-12-31"}
+	at := ThemeField{Name: "at", Label: "At", Type: FieldTypeDateTime, Min: "2024-01-01T09:00:00+09:00"}
+	labels := ThemeField{Name: "labels", Label: "Labels", Type: FieldTypeMultiSelect, Options: status.Options}
+	scores := ThemeField{Name: "scores", Label: "Scores", Type: ListOf(FieldTypeNumber), Min: 0.0, Max: 10.0}
+	code := ThemeField{Name: "code", Label: "Code", Type: FieldTypeText, MinLength: intPtr(2), MaxLength: intPtr(4), Pattern: `^[A-Z]+$`}
+
+	tests := []struct {
+		name    string
+		field   ThemeField
+		value   interface{}
+		wantErr string
+	}{
+		{"option", status, "done", ""},
+		{"unknown option", status, "doing", "field 'status' must be one of [todo done], got 'doing'"},
+		{"select without options", ThemeField{Name: "s", Type: FieldTypeSelect}, "anything", ""},
+		{"number in range", amount, 1000, ""},
+		{"number below min", amount, -0.5, "field 'amount' must be at least 0"},
+		{"number above max", amount, 1000.5, "field 'amount' must be at most 1000"},
+		{"wrong type", amount, "12", "field 'amount' expects a number, got string"},
+		{"date in range", due, "2024-06-30", ""},
+		{"date after max", due, "2025-01-01", "field 'due' must be at most 2024-12-31"},
+		{"datetime compared as instants", at, "2024-01-01T00:00:00Z", ""},
+		{"datetime before min", at, "2023-12-31T23:59:59Z", "field 'at' must be at least 2024-01-01T09:00:00+09:00"},
+		{"text", code, "ABC", ""},
+		{"text length counts characters", ThemeField{Name: "n", Type: FieldTypeText, MaxLength: intPtr(2)}, "日本", ""},
+		{"text too short", code, "A", "field 'code' must be at least 2 characters long"},
+		{"text too long", code, "ABCDE", "field 'code' must be at most 4 characters long"},
+		{"text not matching", code, "abc", "field 'code' does not match the pattern '^[A-Z]+$'"},
+		{"multiselect", labels, []interface{}{"todo", "done"}, ""},
+		{"multiselect as Go strings", labels, []string{"done"}, ""},
+		{"multiselect unknown option", labels, []interface{}{"todo", "doing"}, "field 'labels' must be one of [todo done], got 'doing' (item 1)"},
+		{"multiselect duplicate", labels, []interface{}{"todo", "todo"}, "field 'labels' contains 'todo' more than once"},
+		{"not a list", labels, "todo", "field 'labels' expects a list, got string"},
+		{"tags", ThemeField{Name: "tags", Type: FieldTypeTags}, []interface{}{"home", "urgent"}, ""},
+		{"empty tag", ThemeField{Name: "tags", Type: FieldTypeTags}, []interface{}{""}, "field 'tags' cannot contain empty tags (item 0)"},
+		{"list of numbers", scores, []interface{}{1.0, 10}, ""},
+		{"list item above max", scores, []interface{}{1.0, 11.0}, "field 'scores' must be at most 10 (item 1)"},
+		{"list item of wrong type", scores, []interface{}{"1"}, "field 'scores' expects a number, got string (item 0)"},
+		{"list item null", scores, []interface{}{nil}, "field 'scores' cannot contain null (item 0)"},
+		{"list allows duplicates", scores, []interface{}{1.0, 1.0}, ""},
+		{"duration", ThemeField{Name: "d", Type: FieldTypeDuration}, "PT1H30M", ""},
+		{"duration of days and fractional seconds", ThemeField{Name: "d", Type: FieldTypeDuration}, "P1DT0.5S", ""},
+		{"duration in months", ThemeField{Name: "d", Type: FieldTypeDuration}, "P1M", "field 'd' has invalid duration 'P1M': years and months are not supported, use weeks, days, hours, minutes and seconds"},
+		{"duration without components", ThemeField{Name: "d", Type: FieldTypeDuration}, "PT", "field 'd' has invalid duration 'PT'. Expected ISO 8601, e.g. PT1H30M"},
+		{"duration above max", ThemeField{Name: "d", Type: FieldTypeDuration, Max: "PT2H"}, "PT121M", "field 'd' must be at most PT2H"},
+		{"time", ThemeField{Name: "t", Type: FieldTypeTime, Min: "06:00"}, "23:59", ""},
+		{"time without leading zero", ThemeField{Name: "t", Type: FieldTypeTime}, "9:30", "field 't' has invalid time '9:30'. Expected HH:MM (00:00-23:59)"},
+		{"time out of range", ThemeField{Name: "t", Type: FieldTypeTime}, "24:00", "field 't' has invalid time '24:00'. Expected HH:MM (00:00-23:59)"},
+		{"time before min", ThemeField{Name: "t", Type: FieldTypeTime, Min: "06:00"}, "05:59", "field 't' must be at least 06:00"},
+		{"money", ThemeField{Name: "m", Type: FieldTypeMoney}, map[string]interface{}{"amount": 1200.0, "currency": "JPY"}, ""},
+		{"money with unknown currency", ThemeField{Name: "m", Type: FieldTypeMoney}, map[string]interface{}{"amount": 1.0, "currency": "XYZ"}, "field 'm' has unknown currency 'XYZ'. Expected an ISO 4217 code such as JPY or USD"},
+		{"money without amount", ThemeField{Name: "m", Type: FieldTypeMoney}, map[string]interface{}{"currency": "USD"}, "field 'm' expects a number 'amount', got <nil>"},
+		{"money with extra key", ThemeField{Name: "m", Type: FieldTypeMoney}, map[string]interface{}{"amount": 1.0, "currency": "USD", "note": "x"}, "field 'm' has unknown money key 'note'"},
+		{"money as number", ThemeField{Name: "m", Type: FieldTypeMoney}, 12.0, "field 'm' expects an object with 'amount' and 'currency', got float64"},
+		{"geo", ThemeField{Name: "g", Type: FieldTypeGeo}, map[string]interface{}{"lat": 35.68, "lng": 139.76, "label": "Tokyo Station"}, ""},
+		{"geo without label", ThemeField{Name: "g", Type: FieldTypeGeo}, map[string]interface{}{"lat": -33.86, "lng": 151.21}, ""},
+		{"geo out of range", ThemeField{Name: "g", Type: FieldTypeGeo}, map[string]interface{}{"lat": 91.0, "lng": 0.0}, "field 'g' has invalid latitude 91. Expected -90 to 90"},
+		{"geo with non-string label", ThemeField{Name: "g", Type: FieldTypeGeo}, map[string]interface{}{"lat": 0.0, "lng": 0.0, "label": 1.0}, "field 'g' expects a string 'label', got float64"},
+		{"geo as text", ThemeField{Name: "g", Type: FieldTypeGeo}, "Tokyo", "field 'g' expects an object with 'lat' and 'lng', got string"},
+		{"rating", ThemeField{Name: "r", Type: FieldTypeRating}, 5, ""},
+		{"rating above default max", ThemeField{Name: "r", Type: FieldTypeRating}, 6.0, "field 'r' expects a whole number from 1 to 5, got 6"},
+		{"rating not whole", ThemeField{Name: "r", Type: FieldTypeRating, Min: 0.0, Max: 10.0}, 7.5, "field 'r' expects a whole number from 0 to 10, got 7.5"},
+		{"url", ThemeField{Name: "u", Type: FieldTypeURL}, "https://example.com/a?b=c", ""},
+		{"relative url", ThemeField{Name: "u", Type: FieldTypeURL}, "/a/b", "field 'u' has invalid URL '/a/b'. Expected an absolute http or https URL"},
+		{"email", ThemeField{Name: "e", Type: FieldTypeEmail}, "taro@example.com", ""},
+		{"email with display name", ThemeField{Name: "e", Type: FieldTypeEmail}, "Taro <taro@example.com>", "field 'e' has invalid email address 'Taro <taro@example.com>'"},
+		{"color", ThemeField{Name: "c", Type: FieldTypeColor}, "#A0b1C2", ""},
+		{"named color", ThemeField{Name: "c", Type: FieldTypeColor}, "red", "field 'c' has invalid color 'red'. Expected #RGB or #RRGGBB"},
+		{"reference", ThemeField{Name: "book", Type: FieldTypeReference}, "0b6f3c1e-3f0a-4f7e-9a51-2c1d8e7b9a10", ""},
+		{"reference not a UUID", ThemeField{Name: "book", Type: FieldTypeReference}, "book-1", "field 'book' has invalid entry ID 'book-1'. Expected a UUID"},
+		{"reference as number", ThemeField{Name: "book", Type: FieldTypeReference}, 1.0, "field 'book' expects an entry ID string, got float64"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.field.ValidateValue(tt.value)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateThemeFields_Constraints(t *testing.T) {
+	bookThemeID := uuid.New()
+	tests := []struct {
+		name    string
+		field   ThemeField
+		wantErr string
+	}{
+		{"valid select", ThemeField{Name: "f", Label: "F", Type: FieldTypeSelect, Options: []FieldOption{{Value: "a"}, {Value: "b", Color: "#123abc"}}, Default: "a"}, ""},
+		{"options on text", ThemeField{Name: "f", Label: "F", Type: FieldTypeText, Options: []FieldOption{{Value: "a"}}}, "field 'f': options are only allowed on select and multiselect fields"},
+		{"empty option value", ThemeField{Name: "f", Label: "F", Type: FieldTypeSelect, Options: []FieldOption{{Label: "A"}}}, "field 'f': option 0: value is required"},
+		{"duplicate option", ThemeField{Name: "f", Label: "F", Type: FieldTypeSelect, Options: []FieldOption{{Value: "a"}, {Value: "a"}}}, "field 'f': option value 'a' is duplicated"},
+		{"invalid color", ThemeField{Name: "f", Label: "F", Type: FieldTypeSelect, Options: []FieldOption{{Value: "a", Color: "red"}}}, "field 'f': option 'a': color 'red' must be #RGB or #RRGGBB"},
+		{"default not an option", ThemeField{Name: "f", Label: "F", Type: FieldTypeSelect, Options: []FieldOption{{Value: "a"}}, Default: "b"}, "field 'f': invalid default: field 'f' must be one of [a], got 'b'"},
+		{"min on boolean", ThemeField{Name: "f", Label: "F", Type: FieldTypeBoolean, Min: 1.0}, "field 'f': min and max are only allowed on number, rating, date, datetime, time and duration fields and lists of them"},
+		{"min of wrong type", ThemeField{Name: "f", Label: "F", Type: FieldTypeDate, Min: 1.0}, "field 'f': min: expects a date string (YYYY-MM-DD), got float64"},
+		{"min above max", ThemeField{Name: "f", Label: "F", Type: FieldTypeNumber, Min: 10.0, Max: 1.0}, "field 'f': min cannot be greater than max"},
+		{"default out of range", ThemeField{Name: "f", Label: "F", Type: FieldTypeNumber, Max: 1.0, Default: 2.0}, "field 'f': invalid default: field 'f' must be at most 1"},
+		{"length on number", ThemeField{Name: "f", Label: "F", Type: FieldTypeNumber, MaxLength: intPtr(3)}, "field 'f': min_length, max_length and pattern are only allowed on text and textarea fields and lists of text"},
+		{"negative length", ThemeField{Name: "f", Label: "F", Type: FieldTypeText, MinLength: intPtr(-1)}, "field 'f': min_length and max_length cannot be negative"},
+		{"min length above max length", ThemeField{Name: "f", Label: "F", Type: FieldTypeText, MinLength: intPtr(3), MaxLength: intPtr(2)}, "field 'f': min_length cannot be greater than max_length"},
+		{"valid list", ThemeField{Name: "f", Label: "F", Type: "list<date>", Min: "2024-01-01", Default: []interface{}{"2024-05-01"}}, ""},
+		{"unknown list item type", ThemeField{Name: "f", Label: "F", Type: "list<select>"}, "field 'f': invalid type 'list<select>'"},
+		{"invalid list default", ThemeField{Name: "f", Label: "F", Type: FieldTypeTags, Default: "a"}, "field 'f': invalid default: field 'f' expects a list, got string"},
+		{"rating bounds", ThemeField{Name: "f", Label: "F", Type: FieldTypeRating, Max: 10.0}, ""},
+		{"rating min above default max", ThemeField{Name: "f", Label: "F", Type: FieldTypeRating, Min: 6.0}, "field 'f': min cannot be greater than max (ratings default to 1-5)"},
+		{"duration bounds", ThemeField{Name: "f", Label: "F", Type: FieldTypeDuration, Min: "PT1H", Max: "PT30M"}, "field 'f': min cannot be greater than max"},
+		{"reference settings", ThemeField{Name: "f", Label: "F", Type: FieldTypeReference, TargetThemeID: &bookThemeID, OnDelete: OnDeleteCascade}, ""},
+		{"on_delete on text", ThemeField{Name: "f", Label: "F", Type: FieldTypeText, OnDelete: OnDeleteBlock}, "field 'f': target_theme_id and on_delete are only allowed on reference fields"},
+		{"unknown on_delete", ThemeField{Name: "f", Label: "F", Type: FieldTypeReference, OnDelete: "restrict"}, "field 'f': invalid on_delete 'restrict'. Expected block, nullify or cascade"},
+		{"nullify required reference", ThemeField{Name: "f", Label: "F", Type: FieldTypeReference, Required: true, OnDelete: OnDeleteNullify}, "field 'f': on_delete 'nullify' cannot be used on a required field"},
+		{"computed number", ThemeField{Name: "f", Label: "F", Type: FieldTypeNumber, Formula: "price * qty"}, ""},
+		{"formula on date", ThemeField{Name: "f", Label: "F", Type: FieldTypeDate, Formula: "start"}, "field 'f': formula is only allowed on number, text and boolean fields"},
+		{"required computed field", ThemeField{Name: "f", Label: "F", Type: FieldTypeNumber, Required: true, Formula: "1"}, "field 'f': computed fields cannot be required"},
+		{"computed field with default", ThemeField{Name: "f", Label: "F", Type: FieldTypeNumber, Default: 1.0, Formula: "1"}, "field 'f': computed fields cannot have a default"},
+		{"invalid pattern", ThemeField{Name: "f", Label: "F", Type: FieldTypeTextarea, Pattern: "("}, "field 'f': invalid pattern: error parsing regexp: missing closing ): `(`"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateThemeFields([]ThemeField{tt.field})
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestThemeField_ParseItem(t *testing.T) {
+	v, err := ThemeField{Name: "scores", Type: ListOf(FieldTypeNumber)}.ParseItem("2.5")
+	assert.NoError(t, err)
+	assert.Equal(t, 2.5, v)
+
+	v, err = ThemeField{Name: "tags", Type: FieldTypeTags}.ParseItem("urgent")
+	assert.NoError(t, err)
+	assert.Equal(t, "urgent", v)
+
+	_, err = ThemeField{Name: "days", Type: ListOf(FieldTypeDate)}.ParseItem("May 1")
+	assert.EqualError(t, err, "field 'days' item has invalid date format: parsing time \"May 1\" as \"2006-01-02\": cannot parse \"May 1\" as \"2006\". Expected YYYY-MM-DD")
+
+	_, err = ThemeField{Name: "title", Type: FieldTypeText}.ParseItem("a")
+	assert.EqualError(t, err, "field 'title' is not a list")
+}
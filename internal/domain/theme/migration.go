@@ -0,0 +1,327 @@
+package theme
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// --- Schema versions and entry data migrations ---
+
+// InitialSchemaVersion is the schema version of newly created themes.
+// Themes and entries stored before versioning was introduced have version 0.
+const InitialSchemaVersion = 1
+
+// MigrationOp is the kind of change a migration step makes to stored entry data.
+type MigrationOp string
+
+const (
+	MigrationRename      MigrationOp = "rename"       // Move the value of Field to To
+	MigrationDrop        MigrationOp = "drop"         // Remove the value of Field
+	MigrationConvert     MigrationOp = "convert"      // Convert the value of Field to the field's new type; values that cannot be converted are removed
+	MigrationFillDefault MigrationOp = "fill_default" // Set Field to Value where it is missing or null
+)
+
+// MigrationStep is one change to the entry data written with a theme's previous fields.
+// Corresponds to api.ThemeMigrationStep.
+type MigrationStep struct {
+	Op    MigrationOp `dynamodbav:"Op"`
+	Field string      `dynamodbav:"Field"`           // Field name as it stands before the step
+	To    string      `dynamodbav:"To,omitempty"`    // rename: new field name
+	Type  FieldType   `dynamodbav:"Type,omitempty"`  // convert: type values are converted to (the field's type in the updated fields)
+	Value interface{} `dynamodbav:"Value,omitempty"` // fill_default: value to set
+}
+
+// Migration brings entry data written with the previous schema version up to Version.
+type Migration struct {
+	Version int             `dynamodbav:"Version"`
+	Steps   []MigrationStep `dynamodbav:"Steps"`
+}
+
+// Apply applies the migration's steps to entry data in place.
+func (m Migration) Apply(data map[string]interface{}) {
+	for _, s := range m.Steps {
+		v, ok := data[s.Field]
+		switch s.Op {
+		case MigrationRename:
+			if ok {
+				delete(data, s.Field)
+				data[s.To] = v
+			}
+		case MigrationDrop:
+			delete(data, s.Field)
+		case MigrationConvert:
+			if !ok {
+				continue
+			}
+			if converted, ok := ConvertValue(v, s.Type); ok && converted != nil {
+				data[s.Field] = converted
+			} else {
+				delete(data, s.Field)
+			}
+		case MigrationFillDefault:
+			if v == nil {
+				data[s.Field] = s.Value
+			}
+		}
+	}
+}
+
+// MigrateData returns entry data written with schema version from, brought up to the theme's
+// current version by applying every later migration in order. data itself is not modified.
+func (t *Theme) MigrateData(data map[string]interface{}, from int) map[string]interface{} {
+	migrated := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		migrated[k] = v
+	}
+	for _, m := range t.Migrations {
+		if m.Version > from {
+			m.Apply(migrated)
+		}
+	}
+	return migrated
+}
+
+// PlanSchemaChange sets the schema version and migration history of t, the updated form of previous.
+// If the names, types or formulas of the fields change, or steps are given, the version is bumped and
+// steps are recorded as the migration from previous. The steps must rename or drop every field that is
+// removed, convert every field whose type changes, and fill every new required field, so that entry
+// data written with the previous fields is valid against the updated ones.
+// Convert steps are completed with the field's new type.
+func (t *Theme) PlanSchemaChange(previous *Theme, steps []MigrationStep) error {
+	if len(steps) == 0 && sameSchema(previous.Fields, t.Fields) {
+		t.SchemaVersion = previous.SchemaVersion
+		t.Migrations = previous.Migrations
+		return nil
+	}
+
+	updated := make(map[string]ThemeField, len(t.Fields))
+	for _, f := range t.Fields {
+		updated[f.Name] = f
+	}
+	// current follows the field names and types of the data as each step changes them
+	current := make(map[string]FieldType, len(previous.Fields))
+	for _, f := range previous.Fields {
+		current[f.Name] = f.Type
+	}
+	filled := make(map[string]bool)
+
+	planned := make([]MigrationStep, len(steps))
+	for i, s := range steps {
+		if s.Field == "" {
+			return fmt.Errorf("migration step %d: field is required", i)
+		}
+		_, exists := current[s.Field]
+		next, defined := updated[s.Field]
+		switch s.Op {
+		case MigrationRename:
+			if !exists {
+				return fmt.Errorf("migration step %d: rename: field '%s' does not exist", i, s.Field)
+			}
+			if _, ok := updated[s.To]; !ok {
+				return fmt.Errorf("migration step %d: rename: target '%s' is not defined in the updated fields", i, s.To)
+			}
+			if _, ok := current[s.To]; ok {
+				return fmt.Errorf("migration step %d: rename: target '%s' already exists", i, s.To)
+			}
+			current[s.To] = current[s.Field]
+			delete(current, s.Field)
+		case MigrationDrop:
+			if !exists {
+				return fmt.Errorf("migration step %d: drop: field '%s' does not exist", i, s.Field)
+			}
+			if defined {
+				return fmt.Errorf("migration step %d: drop: field '%s' is still defined in the updated fields", i, s.Field)
+			}
+			delete(current, s.Field)
+		case MigrationConvert:
+			if !exists {
+				return fmt.Errorf("migration step %d: convert: field '%s' does not exist", i, s.Field)
+			}
+			if !defined {
+				return fmt.Errorf("migration step %d: convert: field '%s' is not defined in the updated fields", i, s.Field)
+			}
+			s.Type = next.Type
+			current[s.Field] = next.Type
+		case MigrationFillDefault:
+			if !defined {
+				return fmt.Errorf("migration step %d: fill_default: field '%s' is not defined in the updated fields", i, s.Field)
+			}
+			if next.IsComputed() {
+				return fmt.Errorf("migration step %d: fill_default: field '%s' is computed", i, s.Field)
+			}
+			if s.Value == nil {
+				return fmt.Errorf("migration step %d: fill_default: value is required", i)
+			}
+			if err := next.ValidateValue(s.Value); err != nil {
+				return fmt.Errorf("migration step %d: fill_default: %w", i, err)
+			}
+			current[s.Field] = next.Type
+			filled[s.Field] = true
+		default:
+			return fmt.Errorf("migration step %d: unknown operation '%s'", i, s.Op)
+		}
+		planned[i] = s
+	}
+
+	// The data must now fit the updated fields. Computed fields are recomputed, so their type may change freely.
+	for name, typ := range current {
+		f, ok := updated[name]
+		if !ok {
+			return fmt.Errorf("field '%s' is removed; add a rename or drop migration step", name)
+		}
+		if f.Type != typ && !f.IsComputed() {
+			return fmt.Errorf("field '%s' changes type from %s to %s; add a convert migration step", name, typ, f.Type)
+		}
+	}
+	for _, f := range t.Fields {
+		if _, ok := current[f.Name]; f.Required && !ok && !filled[f.Name] {
+			return fmt.Errorf("field '%s' is new and required; add a fill_default migration step", f.Name)
+		}
+	}
+
+	t.SchemaVersion = previous.SchemaVersion + 1
+	t.Migrations = append([]Migration(nil), previous.Migrations...)
+	if len(planned) > 0 {
+		t.Migrations = append(t.Migrations, Migration{Version: t.SchemaVersion, Steps: planned})
+	}
+	return nil
+}
+
+// sameSchema reports whether two field lists define the same names, types and formulas,
+// i.e. whether entry data written with one needs no migration for the other.
+func sameSchema(a, b []ThemeField) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	byName := make(map[string]ThemeField, len(a))
+	for _, f := range a {
+		byName[f.Name] = f
+	}
+	for _, f := range b {
+		old, ok := byName[f.Name]
+		if !ok || old.Type != f.Type || old.Formula != f.Formula {
+			return false
+		}
+	}
+	return true
+}
+
+// ConvertValue converts an entry value to the representation of another field type.
+// Values already valid for the type are kept. Otherwise text is parsed (numbers, booleans, dates),
+// numbers, booleans, money and geo values are formatted as text, dates and datetimes are converted
+// into each other, a single value becomes a one-item list and a one-item list its item.
+// ok is false if the value cannot be converted. Field constraints other than the type's own
+// (options, bounds, lengths) are not checked.
+func ConvertValue(v interface{}, to FieldType) (converted interface{}, ok bool) {
+	if v == nil {
+		return nil, true
+	}
+	target := ThemeField{Name: "value", Type: to}
+	if to.IsList() {
+		if target.ValidateValue(v) == nil {
+			return v, true
+		}
+		items, isList := ListItems(v)
+		if !isList {
+			items = []interface{}{v}
+		}
+		list := make([]interface{}, len(items))
+		for i, item := range items {
+			c, ok := ConvertValue(item, to.ItemType())
+			if !ok || c == nil {
+				return nil, false
+			}
+			list[i] = c
+		}
+		if target.ValidateValue(list) != nil { // e.g. duplicates in multiselect or tags
+			return nil, false
+		}
+		return list, true
+	}
+	if target.validateType(v) == nil {
+		return v, true
+	}
+	if items, isList := ListItems(v); isList {
+		if len(items) != 1 {
+			return nil, false
+		}
+		return ConvertValue(items[0], to)
+	}
+
+	switch to {
+	case FieldTypeNumber, FieldTypeRating:
+		switch x := v.(type) {
+		case string:
+			n, err := strconv.ParseFloat(strings.TrimSpace(x), 64)
+			if err == nil {
+				converted = n
+			}
+		case bool:
+			converted = 0.0
+			if x {
+				converted = 1.0
+			}
+		default:
+			if m, err := ParseMoney(v); err == nil {
+				converted = m.Amount
+			}
+		}
+	case FieldTypeBoolean:
+		switch x := v.(type) {
+		case string:
+			b, err := strconv.ParseBool(strings.TrimSpace(x))
+			if err == nil {
+				converted = b
+			}
+		default:
+			if n, ok := numberValue(v); ok {
+				converted = n != 0
+			}
+		}
+	case FieldTypeDate:
+		if s, ok := v.(string); ok {
+			if ts, err := time.Parse(time.RFC3339, s); err == nil {
+				converted = ts.Format("2006-01-02")
+			}
+		}
+	case FieldTypeDateTime:
+		if s, ok := v.(string); ok {
+			if d, err := time.Parse("2006-01-02", s); err == nil {
+				converted = d.Format(time.RFC3339)
+			}
+		}
+	case FieldTypeMoney, FieldTypeGeo:
+		// Structured values cannot be derived from other types
+	default:
+		converted = textOf(v)
+	}
+	if converted == nil || target.validateType(converted) != nil {
+		return nil, false
+	}
+	return converted, true
+}
+
+// textOf returns the text form of a scalar value, or nil if it has none.
+func textOf(v interface{}) interface{} {
+	switch x := v.(type) {
+	case string:
+		return x
+	case bool:
+		return strconv.FormatBool(x)
+	}
+	if n, ok := numberValue(v); ok {
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	}
+	if m, err := ParseMoney(v); err == nil {
+		return strconv.FormatFloat(m.Amount, 'f', -1, 64) + " " + m.Currency
+	}
+	if g, err := ParseGeo(v); err == nil {
+		if g.Label != "" {
+			return g.Label
+		}
+		return strconv.FormatFloat(g.Lat, 'f', -1, 64) + "," + strconv.FormatFloat(g.Lng, 'f', -1, 64)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package theme
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTheme_PlanSchemaChange(t *testing.T) {
+	previous := &Theme{SchemaVersion: 1, Fields: []ThemeField{
+		{Name: "note", Label: "Note", Type: FieldTypeText},
+		{Name: "amount", Label: "Amount", Type: FieldTypeText},
+		{Name: "legacy", Label: "Legacy", Type: FieldTypeBoolean},
+	}}
+	fields := []ThemeField{
+		{Name: "memo", Label: "Memo", Type: FieldTypeTextarea},
+		{Name: "amount", Label: "Amount", Type: FieldTypeNumber},
+		{Name: "status", Label: "Status", Type: FieldTypeSelect, Required: true, Options: []FieldOption{{Value: "open"}, {Value: "closed"}}},
+	}
+	steps := []MigrationStep{
+		{Op: MigrationRename, Field: "note", To: "memo"},
+		{Op: MigrationConvert, Field: "memo"},
+		{Op: MigrationConvert, Field: "amount"},
+		{Op: MigrationDrop, Field: "legacy"},
+		{Op: MigrationFillDefault, Field: "status", Value: "open"},
+	}
+
+	tests := []struct {
+		name    string
+		steps   []MigrationStep
+		wantErr string
+	}{
+		{"complete migration", steps, ""},
+		{"removed field", steps[:3], "field 'legacy' is removed; add a rename or drop migration step"},
+		{"retyped field", []MigrationStep{steps[0], steps[1], steps[3], steps[4]}, "field 'amount' changes type from text to number; add a convert migration step"},
+		{"new required field", steps[:4], "field 'status' is new and required; add a fill_default migration step"},
+		{"rename of unknown field", append([]MigrationStep{{Op: MigrationRename, Field: "missing", To: "memo"}}, steps...), "migration step 0: rename: field 'missing' does not exist"},
+		{"drop of kept field", []MigrationStep{{Op: MigrationDrop, Field: "amount"}}, "migration step 0: drop: field 'amount' is still defined in the updated fields"},
+		{"invalid default", []MigrationStep{{Op: MigrationFillDefault, Field: "status", Value: "pending"}}, "migration step 0: fill_default: field 'status' must be one of [open closed], got 'pending'"},
+		{"unknown operation", []MigrationStep{{Op: "merge", Field: "note"}}, "migration step 0: unknown operation 'merge'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated := &Theme{Fields: fields}
+			err := updated.PlanSchemaChange(previous, tt.steps)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, 2, updated.SchemaVersion)
+			if assert.Len(t, updated.Migrations, 1) {
+				assert.Equal(t, 2, updated.Migrations[0].Version)
+				assert.Equal(t, FieldTypeNumber, updated.Migrations[0].Steps[2].Type, "convert steps record the new type")
+			}
+		})
+	}
+
+	t.Run("label changes keep the version", func(t *testing.T) {
+		updated := &Theme{Fields: []ThemeField{
+			{Name: "note", Label: "Notes", Type: FieldTypeText},
+			{Name: "amount", Label: "Amount (JPY)", Type: FieldTypeText},
+			{Name: "legacy", Label: "Old", Type: FieldTypeBoolean},
+		}}
+		assert.NoError(t, updated.PlanSchemaChange(previous, nil))
+		assert.Equal(t, 1, updated.SchemaVersion)
+		assert.Empty(t, updated.Migrations)
+	})
+}
+
+func TestTheme_MigrateData(t *testing.T) {
+	th := &Theme{SchemaVersion: 3, Migrations: []Migration{
+		{Version: 2, Steps: []MigrationStep{
+			{Op: MigrationRename, Field: "note", To: "memo"},
+			{Op: MigrationConvert, Field: "amount", Type: FieldTypeNumber},
+			{Op: MigrationDrop, Field: "legacy"},
+		}},
+		{Version: 3, Steps: []MigrationStep{
+			{Op: MigrationFillDefault, Field: "status", Value: "open"},
+		}},
+	}}
+	data := map[string]interface{}{"note": "hello", "amount": "1200", "legacy": true}
+
+	migrated := th.MigrateData(data, 1)
+	assert.Equal(t, map[string]interface{}{"memo": "hello", "amount": 1200.0, "status": "open"}, migrated)
+	assert.Equal(t, "hello", data["note"], "the input is not modified")
+
+	// Only later migrations apply
+	migrated = th.MigrateData(map[string]interface{}{"memo": "hi", "status": nil}, 2)
+	assert.Equal(t, map[string]interface{}{"memo": "hi", "status": "open"}, migrated)
+
+	// Values that cannot be converted are removed
+	migrated = th.MigrateData(map[string]interface{}{"amount": "a lot"}, 1)
+	assert.Equal(t, map[string]interface{}{"status": "open"}, migrated)
+}
+
+func TestConvertValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  interface{}
+		to     FieldType
+		want   interface{}
+		wantOK bool
+	}{
+		{"valid value kept", "text", FieldTypeTextarea, "text", true},
+		{"text to number", " 42.5 ", FieldTypeNumber, 42.5, true},
+		{"text to number fails", "many", FieldTypeNumber, nil, false},
+		{"number to text", 3.0, FieldTypeText, "3", true},
+		{"boolean to number", true, FieldTypeNumber, 1.0, true},
+		{"number to boolean", 0.0, FieldTypeBoolean, false, true},
+		{"text to boolean", "true", FieldTypeBoolean, true, true},
+		{"money to number", map[string]interface{}{"amount": 1200.0, "currency": "JPY"}, FieldTypeNumber, 1200.0, true},
+		{"money to text", map[string]interface{}{"amount": 1200.0, "currency": "JPY"}, FieldTypeText, "1200 JPY", true},
+		{"geo to text", map[string]interface{}{"lat": 35.0, "lng": 139.0, "label": "Tokyo"}, FieldTypeText, "Tokyo", true},
+		{"datetime to date", "2025-05-03T10:00:00+09:00", FieldTypeDate, "2025-05-03", true},
+		{"date to datetime", "2025-05-03", FieldTypeDateTime, "2025-05-03T00:00:00Z", true},
+		{"text to tags", "urgent", FieldTypeTags, []interface{}{"urgent"}, true},
+		{"numbers to list of text", []interface{}{1.0, 2.0}, ListOf(FieldTypeText), []interface{}{"1", "2"}, true},
+		{"duplicate tags", []interface{}{1.0, "1"}, FieldTypeTags, nil, false},
+		{"one-item list to scalar", []interface{}{"7"}, FieldTypeNumber, 7.0, true},
+		{"list to scalar fails", []interface{}{"a", "b"}, FieldTypeText, nil, false},
+		{"text to money fails", "1200", FieldTypeMoney, nil, false},
+		{"text to invalid url fails", "not a url", FieldTypeURL, nil, false},
+		{"null", nil, FieldTypeNumber, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ConvertValue(tt.value, tt.to)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
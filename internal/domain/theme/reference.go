@@ -0,0 +1,61 @@
+package theme
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// DeleteAction is what happens to an entry when the entry one of its reference fields points at is deleted.
+type DeleteAction string
+
+const (
+	OnDeleteBlock   DeleteAction = "block"   // The referenced entry cannot be deleted while the reference exists
+	OnDeleteNullify DeleteAction = "nullify" // The reference field is set to null
+	OnDeleteCascade DeleteAction = "cascade" // The referencing entry is deleted too
+)
+
+// OnDeleteOrDefault returns the field's delete action, defaulting to OnDeleteBlock.
+func (f ThemeField) OnDeleteOrDefault() DeleteAction {
+	if f.OnDelete == "" {
+		return OnDeleteBlock
+	}
+	return f.OnDelete
+}
+
+// validateReferenceSettings checks the target theme and delete action of a field.
+func (f ThemeField) validateReferenceSettings() error {
+	if f.Type != FieldTypeReference {
+		if f.TargetThemeID != nil || f.OnDelete != "" {
+			return fmt.Errorf("target_theme_id and on_delete are only allowed on %s fields", FieldTypeReference)
+		}
+		return nil
+	}
+	switch f.OnDelete {
+	case "", OnDeleteBlock, OnDeleteCascade:
+	case OnDeleteNullify:
+		// Clearing a required reference would leave the entry invalid
+		if f.Required {
+			return fmt.Errorf("on_delete '%s' cannot be used on a required field", OnDeleteNullify)
+		}
+	default:
+		return fmt.Errorf("invalid on_delete '%s'. Expected %s, %s or %s", f.OnDelete, OnDeleteBlock, OnDeleteNullify, OnDeleteCascade)
+	}
+	if f.TargetThemeID != nil && *f.TargetThemeID == uuid.Nil {
+		return fmt.Errorf("target_theme_id cannot be the nil UUID")
+	}
+	return nil
+}
+
+// ParseReference reads the entry ID held by a reference field value.
+func ParseReference(v interface{}) (uuid.UUID, error) {
+	s, ok := v.(string)
+	if !ok {
+		return uuid.Nil, fmt.Errorf("expects an entry ID string, got %T", v)
+	}
+	id, err := uuid.Parse(s)
+	if err != nil || id == uuid.Nil {
+		return uuid.Nil, fmt.Errorf("has invalid entry ID '%s'. Expected a UUID", s)
+	}
+	return id, nil
+}
@@ -0,0 +1,175 @@
+package theme
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// --- Revision history ---
+
+// InitialRevision is the revision number of newly created themes.
+// Themes stored before revisions were introduced have revision 0 and no revision items.
+const InitialRevision = 1
+
+// Revision is an immutable snapshot of a theme's definition, written each time the theme is created,
+// updated or restored. Revisions are numbered from InitialRevision without gaps.
+// Corresponds to api.ThemeRevision.
+type Revision struct {
+	PK                string             `dynamodbav:"PK"` // Partition Key: THEME#<theme_id>
+	SK                string             `dynamodbav:"SK"` // Sort Key: REV#<revision> (zero-padded)
+	ThemeID           uuid.UUID          `dynamodbav:"ThemeID"`
+	Revision          int                `dynamodbav:"Revision"`
+	ThemeName         string             `dynamodbav:"ThemeName"`
+	Fields            []ThemeField       `dynamodbav:"Fields"`
+	SupportedFeatures []SupportedFeature `dynamodbav:"SupportedFeatures"`
+	SchemaVersion     int                `dynamodbav:"SchemaVersion"`
+	ChangedBy         uuid.UUID          `dynamodbav:"ChangedBy"`
+	ChangedAt         time.Time          `dynamodbav:"ChangedAt"`
+	RestoredFrom      int                `dynamodbav:"RestoredFrom,omitempty"` // Revision this one restored, 0 for regular changes
+}
+
+// NewRevision returns the snapshot of the theme's current definition as its revision t.Revision,
+// changed by t.UpdatedBy (the owner if unset) at t.UpdatedAt.
+func (t *Theme) NewRevision() Revision {
+	rev := Revision{
+		ThemeID:           t.ThemeID,
+		Revision:          t.Revision,
+		ThemeName:         t.ThemeName,
+		Fields:            t.Fields,
+		SupportedFeatures: t.SupportedFeatures,
+		SchemaVersion:     t.SchemaVersion,
+		ChangedAt:         t.UpdatedAt,
+		RestoredFrom:      t.RestoredFrom,
+	}
+	if t.UpdatedBy != nil {
+		rev.ChangedBy = *t.UpdatedBy
+	} else if t.OwnerUserID != nil {
+		rev.ChangedBy = *t.OwnerUserID
+	}
+	return rev
+}
+
+// Definition returns the theme definition recorded by the revision, to be saved over the theme
+// identified by ThemeID and owned by owner. Schema version, migrations and timestamps are left
+// for the update to set.
+func (r *Revision) Definition(owner uuid.UUID) Theme {
+	return Theme{
+		ThemeID:           r.ThemeID,
+		ThemeName:         r.ThemeName,
+		Fields:            r.Fields,
+		SupportedFeatures: r.SupportedFeatures,
+		OwnerUserID:       &owner,
+	}
+}
+
+// RevisionDiff describes how a theme's definition changed from one revision to another.
+// Corresponds to api.ThemeRevisionDiff.
+type RevisionDiff struct {
+	From, To        int
+	ThemeName       *NameChange   // nil if the name did not change
+	FieldsAdded     []ThemeField  // Fields only in To, in To's order
+	FieldsRemoved   []ThemeField  // Fields only in From, in From's order
+	FieldsChanged   []FieldChange // Fields in both whose definition differs, in To's order
+	FeaturesAdded   []string      // Supported features only in To
+	FeaturesRemoved []string      // Supported features only in From
+	FeaturesChanged []string      // Supported features in both whose config or schedule differs
+}
+
+// IsEmpty reports whether the two revisions record the same definition.
+func (d *RevisionDiff) IsEmpty() bool {
+	return d.ThemeName == nil && len(d.FieldsAdded) == 0 && len(d.FieldsRemoved) == 0 && len(d.FieldsChanged) == 0 &&
+		len(d.FeaturesAdded) == 0 && len(d.FeaturesRemoved) == 0 && len(d.FeaturesChanged) == 0
+}
+
+// NameChange is a changed theme name.
+type NameChange struct {
+	From, To string
+}
+
+// FieldChange is a field whose definition differs between two revisions.
+type FieldChange struct {
+	Name       string
+	Attributes []string // Names of the changed attributes, as in the API (e.g. "label", "min_length")
+	From, To   ThemeField
+}
+
+// fieldAttributes names the attributes of ThemeField compared by DiffRevisions.
+var fieldAttributes = []struct {
+	name  string
+	value func(f ThemeField) interface{}
+}{
+	{"label", func(f ThemeField) interface{} { return f.Label }},
+	{"type", func(f ThemeField) interface{} { return f.Type }},
+	{"required", func(f ThemeField) interface{} { return f.Required }},
+	{"options", func(f ThemeField) interface{} { return f.Options }},
+	{"default", func(f ThemeField) interface{} { return f.Default }},
+	{"min", func(f ThemeField) interface{} { return f.Min }},
+	{"max", func(f ThemeField) interface{} { return f.Max }},
+	{"min_length", func(f ThemeField) interface{} { return f.MinLength }},
+	{"max_length", func(f ThemeField) interface{} { return f.MaxLength }},
+	{"pattern", func(f ThemeField) interface{} { return f.Pattern }},
+	{"target_theme_id", func(f ThemeField) interface{} { return f.TargetThemeID }},
+	{"on_delete", func(f ThemeField) interface{} { return f.OnDelete }},
+	{"formula", func(f ThemeField) interface{} { return f.Formula }},
+}
+
+// DiffRevisions compares the definitions recorded by two revisions of a theme.
+// Fields are matched by name, so a renamed field shows as removed and added.
+func DiffRevisions(from, to *Revision) RevisionDiff {
+	diff := RevisionDiff{From: from.Revision, To: to.Revision}
+	if from.ThemeName != to.ThemeName {
+		diff.ThemeName = &NameChange{From: from.ThemeName, To: to.ThemeName}
+	}
+
+	before := make(map[string]ThemeField, len(from.Fields))
+	for _, f := range from.Fields {
+		before[f.Name] = f
+	}
+	after := make(map[string]bool, len(to.Fields))
+	for _, f := range to.Fields {
+		after[f.Name] = true
+		old, ok := before[f.Name]
+		if !ok {
+			diff.FieldsAdded = append(diff.FieldsAdded, f)
+			continue
+		}
+		var changed []string
+		for _, attr := range fieldAttributes {
+			if !reflect.DeepEqual(attr.value(old), attr.value(f)) {
+				changed = append(changed, attr.name)
+			}
+		}
+		if len(changed) > 0 {
+			diff.FieldsChanged = append(diff.FieldsChanged, FieldChange{Name: f.Name, Attributes: changed, From: old, To: f})
+		}
+	}
+	for _, f := range from.Fields {
+		if !after[f.Name] {
+			diff.FieldsRemoved = append(diff.FieldsRemoved, f)
+		}
+	}
+
+	features := make(map[string]SupportedFeature, len(from.SupportedFeatures))
+	for _, sf := range from.SupportedFeatures {
+		features[sf.Name] = sf
+	}
+	enabled := make(map[string]bool, len(to.SupportedFeatures))
+	for _, sf := range to.SupportedFeatures {
+		enabled[sf.Name] = true
+		old, ok := features[sf.Name]
+		switch {
+		case !ok:
+			diff.FeaturesAdded = append(diff.FeaturesAdded, sf.Name)
+		case !reflect.DeepEqual(old, sf):
+			diff.FeaturesChanged = append(diff.FeaturesChanged, sf.Name)
+		}
+	}
+	for _, sf := range from.SupportedFeatures {
+		if !enabled[sf.Name] {
+			diff.FeaturesRemoved = append(diff.FeaturesRemoved, sf.Name)
+		}
+	}
+	return diff
+}
@@ -0,0 +1,51 @@
+package theme
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffRevisions(t *testing.T) {
+	maxLength := 100
+	from := &Revision{Revision: 1, ThemeName: "Reading", Fields: []ThemeField{
+		{Name: "title", Label: "Title", Type: FieldTypeText},
+		{Name: "pages", Label: "Pages", Type: FieldTypeNumber},
+		{Name: "note", Label: "Note", Type: FieldTypeText},
+	}, SupportedFeatures: []SupportedFeature{
+		{Name: "summary"},
+		{Name: "trend", Config: FeatureConfig{Fields: map[string]string{"value": "pages"}}},
+	}}
+	to := &Revision{Revision: 3, ThemeName: "Reading", Fields: []ThemeField{
+		{Name: "title", Label: "Book title", Type: FieldTypeText, Required: true, MaxLength: &maxLength},
+		{Name: "pages", Label: "Pages", Type: FieldTypeNumber},
+		{Name: "memo", Label: "Note", Type: FieldTypeText},
+	}, SupportedFeatures: []SupportedFeature{
+		{Name: "trend", Config: FeatureConfig{Fields: map[string]string{"value": "pages"}, Options: map[string]interface{}{"bucket": "weekly"}}},
+		{Name: "streak"},
+	}}
+
+	diff := DiffRevisions(from, to)
+
+	assert.Equal(t, 1, diff.From)
+	assert.Equal(t, 3, diff.To)
+	assert.Nil(t, diff.ThemeName)
+	if assert.Len(t, diff.FieldsChanged, 1) {
+		assert.Equal(t, "title", diff.FieldsChanged[0].Name)
+		assert.Equal(t, []string{"label", "required", "max_length"}, diff.FieldsChanged[0].Attributes)
+	}
+	// Renamed fields are matched by name
+	assert.Equal(t, []ThemeField{to.Fields[2]}, diff.FieldsAdded)
+	assert.Equal(t, []ThemeField{from.Fields[2]}, diff.FieldsRemoved)
+	assert.Equal(t, []string{"streak"}, diff.FeaturesAdded)
+	assert.Equal(t, []string{"summary"}, diff.FeaturesRemoved)
+	assert.Equal(t, []string{"trend"}, diff.FeaturesChanged)
+
+	assert.False(t, diff.IsEmpty())
+
+	same := DiffRevisions(to, to)
+	assert.Empty(t, same.FieldsChanged)
+	assert.Empty(t, same.FieldsAdded)
+	assert.Empty(t, same.FeaturesChanged)
+	assert.True(t, same.IsEmpty())
+}
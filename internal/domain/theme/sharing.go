@@ -0,0 +1,65 @@
+package theme
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Role is a user's access level to a theme.
+type Role string
+
+const (
+	RoleOwner       Role = "owner"       // Created the theme; can also share and delete it
+	RoleEditor      Role = "editor"      // Can change the theme's fields and add entries
+	RoleContributor Role = "contributor" // Can add entries
+	RoleViewer      Role = "viewer"      // Can read the entries of the theme's members
+)
+
+// ParseShareRole parses a role a theme can be shared with. The owner role cannot be granted.
+func ParseShareRole(s string) (Role, error) {
+	switch r := Role(s); r {
+	case RoleEditor, RoleContributor, RoleViewer:
+		return r, nil
+	default:
+		return "", fmt.Errorf("invalid role '%s': must be one of viewer, contributor, editor", s)
+	}
+}
+
+// CanAddEntries reports whether the role allows writing entries of the theme.
+func (r Role) CanAddEntries() bool {
+	return r == RoleOwner || r == RoleEditor || r == RoleContributor
+}
+
+// CanEditTheme reports whether the role allows changing the theme's definition.
+func (r Role) CanEditTheme() bool {
+	return r == RoleOwner || r == RoleEditor
+}
+
+// Invitation is a pending offer to share a theme with a user.
+// Accepting it turns it into a UserThemeLink with the invited role.
+type Invitation struct {
+	PK        string    `dynamodbav:"PK"` // Partition Key: USER#<invitee_user_id>
+	SK        string    `dynamodbav:"SK"` // Sort Key: INVITE#<theme_id>
+	ThemeID   uuid.UUID `dynamodbav:"ThemeID"`
+	ThemeName string    `dynamodbav:"ThemeName"` // Name at the time of the invitation, for listing
+	UserID    uuid.UUID `dynamodbav:"UserID"`    // Invitee
+	Role      Role      `dynamodbav:"Role"`
+	InvitedBy uuid.UUID `dynamodbav:"InvitedBy"`
+	CreatedAt time.Time `dynamodbav:"CreatedAt"`
+}
+
+// OwnRole returns the role the user has on the theme without a share: contributor on default themes,
+// which every user can add entries to, and owner for the theme's owner. Otherwise it returns "", and
+// the role comes from the user's UserThemeLink.
+func (t *Theme) OwnRole(userID uuid.UUID) Role {
+	switch {
+	case t.IsDefault:
+		return RoleContributor
+	case t.OwnerUserID != nil && *t.OwnerUserID == userID:
+		return RoleOwner
+	default:
+		return ""
+	}
+}
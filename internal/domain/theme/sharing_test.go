@@ -0,0 +1,47 @@
+package theme
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseShareRole(t *testing.T) {
+	for _, s := range []string{"viewer", "contributor", "editor"} {
+		role, err := ParseShareRole(s)
+		assert.NoError(t, err)
+		assert.Equal(t, Role(s), role)
+	}
+	for _, s := range []string{"owner", "admin", ""} {
+		_, err := ParseShareRole(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestRolePermissions(t *testing.T) {
+	tests := []struct {
+		role                   Role
+		canAddEntries, canEdit bool
+	}{
+		{RoleOwner, true, true},
+		{RoleEditor, true, true},
+		{RoleContributor, true, false},
+		{RoleViewer, false, false},
+		{"", false, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.canAddEntries, tt.role.CanAddEntries(), tt.role)
+		assert.Equal(t, tt.canEdit, tt.role.CanEditTheme(), tt.role)
+	}
+}
+
+func TestThemeOwnRole(t *testing.T) {
+	owner, other := uuid.New(), uuid.New()
+	custom := Theme{OwnerUserID: &owner}
+	defaultTheme := Theme{IsDefault: true}
+
+	assert.Equal(t, RoleOwner, custom.OwnRole(owner))
+	assert.Equal(t, Role(""), custom.OwnRole(other))
+	assert.Equal(t, RoleContributor, defaultTheme.OwnRole(other))
+}
@@ -4,8 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -22,8 +22,86 @@ const (
 	FieldTypeBoolean  FieldType = "boolean"
 	FieldTypeTextarea FieldType = "textarea"
 	FieldTypeSelect   FieldType = "select"
+
+	// Structured scalar types, validated strictly (see ThemeField.ValidateValue).
+	FieldTypeDuration FieldType = "duration" // ISO 8601 duration string, e.g. "PT1H30M"
+	FieldTypeTime     FieldType = "time"     // Time of day as "HH:MM"
+	FieldTypeMoney    FieldType = "money"    // Object {"amount": number, "currency": ISO 4217 code}
+	FieldTypeRating   FieldType = "rating"   // Integer between min and max (1-5 by default)
+	FieldTypeURL      FieldType = "url"      // Absolute http or https URL
+	FieldTypeEmail    FieldType = "email"    // Email address without a display name
+	FieldTypeColor    FieldType = "color"    // "#RGB" or "#RRGGBB"
+	FieldTypeGeo      FieldType = "geo"      // Object {"lat": number, "lng": number} with an optional place "label"
+
+	// FieldTypeReference links to another entry of the same user. Values are entry IDs (UUID strings).
+	FieldTypeReference FieldType = "reference"
+
+	// List-valued types. Entry values are arrays; constraints apply to each item.
+	FieldTypeMultiSelect FieldType = "multiselect" // Items from the field's options, without duplicates
+	FieldTypeTags        FieldType = "tags"        // Free-form non-empty strings, without duplicates
+)
+
+// listTypePrefix and listTypeSuffix enclose the item type of a generic list type, e.g. "list<number>".
+const (
+	listTypePrefix = "list<"
+	listTypeSuffix = ">"
 )
 
+// listItemTypes are the item types a generic list type can hold.
+var listItemTypes = []FieldType{FieldTypeText, FieldTypeNumber, FieldTypeBoolean, FieldTypeDate, FieldTypeDateTime}
+
+// ListOf returns the generic list type holding items of the given type, e.g. "list<number>".
+func ListOf(item FieldType) FieldType {
+	return FieldType(listTypePrefix + string(item) + listTypeSuffix)
+}
+
+// ListFieldTypes returns the generic list types, one per supported item type.
+func ListFieldTypes() []FieldType {
+	types := make([]FieldType, len(listItemTypes))
+	for i, item := range listItemTypes {
+		types[i] = ListOf(item)
+	}
+	return types
+}
+
+// IsList reports whether values of the type are arrays.
+func (t FieldType) IsList() bool {
+	return t.ItemType() != ""
+}
+
+// ItemType returns the type of the items of a list type, or "" if t is not a list type.
+// Multiselect items are select values and tags are text.
+func (t FieldType) ItemType() FieldType {
+	switch t {
+	case FieldTypeMultiSelect:
+		return FieldTypeSelect
+	case FieldTypeTags:
+		return FieldTypeText
+	}
+	s := string(t)
+	if !strings.HasPrefix(s, listTypePrefix) || !strings.HasSuffix(s, listTypeSuffix) {
+		return ""
+	}
+	item := FieldType(strings.TrimSuffix(strings.TrimPrefix(s, listTypePrefix), listTypeSuffix))
+	for _, it := range listItemTypes {
+		if item == it {
+			return item
+		}
+	}
+	return ""
+}
+
+// IsValidFieldType reports whether t is a supported field type.
+func IsValidFieldType(t FieldType) bool {
+	switch t {
+	case FieldTypeText, FieldTypeDate, FieldTypeDateTime, FieldTypeNumber, FieldTypeBoolean, FieldTypeTextarea, FieldTypeSelect,
+		FieldTypeDuration, FieldTypeTime, FieldTypeMoney, FieldTypeRating, FieldTypeURL, FieldTypeEmail, FieldTypeColor,
+		FieldTypeGeo, FieldTypeReference:
+		return true
+	}
+	return t.IsList()
+}
+
 // ThemeField represents a single field definition within a theme.
 // Corresponds to api.ThemeField.
 type ThemeField struct {
@@ -31,30 +109,194 @@ type ThemeField struct {
 	Label    string    `dynamodbav:"Label"`    // Display label
 	Type     FieldType `dynamodbav:"Type"`     // Data type
 	Required bool      `dynamodbav:"Required"` // Whether the field is required
+
+	// Optional constraints, enforced on entry data. Each applies only to the field types noted.
+	Options   []FieldOption `dynamodbav:"Options,omitempty"`   // select: allowed values (none means any string)
+	Default   interface{}   `dynamodbav:"Default,omitempty"`   // Value set on new entries that omit the field
+	Min       interface{}   `dynamodbav:"Min,omitempty"`       // number, rating, date, datetime, time, duration: inclusive lower bound
+	Max       interface{}   `dynamodbav:"Max,omitempty"`       // number, rating, date, datetime, time, duration: inclusive upper bound
+	MinLength *int          `dynamodbav:"MinLength,omitempty"` // text, textarea: minimum length in characters
+	MaxLength *int          `dynamodbav:"MaxLength,omitempty"` // text, textarea: maximum length in characters
+	Pattern   string        `dynamodbav:"Pattern,omitempty"`   // text, textarea: regular expression the value must match (unanchored)
+
+	// Settings of reference fields.
+	TargetThemeID *uuid.UUID   `dynamodbav:"TargetThemeID,omitempty"` // Theme referenced entries must belong to (nil means any theme)
+	OnDelete      DeleteAction `dynamodbav:"OnDelete,omitempty"`      // What happens to the entry when the referenced entry is deleted ("" means OnDeleteBlock)
+
+	// Formula makes the field computed: its value is evaluated from the entry's other fields when the entry is saved.
+	Formula string `dynamodbav:"Formula,omitempty"` // number, text, boolean: per-entry expression (see package formula)
+}
+
+// FeatureConfig holds the per-theme settings of a supported feature.
+// Corresponds to api.FeatureConfig.
+type FeatureConfig struct {
+	Fields  map[string]string      `dynamodbav:"Fields,omitempty"`  // Feature-defined role (e.g. "category") -> theme field name
+	Options map[string]interface{} `dynamodbav:"Options,omitempty"` // Feature-specific settings (e.g. "bucket": "weekly")
+}
+
+// Field returns the theme field name mapped to the given role, or "" if none is configured.
+func (c FeatureConfig) Field(role string) string {
+	return c.Fields[role]
+}
+
+// FieldOr returns the theme field name mapped to the given role, or def if none is configured.
+func (c FeatureConfig) FieldOr(role, def string) string {
+	if name := c.Fields[role]; name != "" {
+		return name
+	}
+	return def
+}
+
+// StringOption returns a string option, or def if it is missing or not a string.
+func (c FeatureConfig) StringOption(key, def string) string {
+	if v, ok := c.Options[key].(string); ok {
+		return v
+	}
+	return def
+}
+
+// NumberOption returns a numeric option, or def if it is missing or not a number.
+// Options decoded from JSON or DynamoDB hold numbers as float64.
+func (c FeatureConfig) NumberOption(key string, def float64) float64 {
+	switch v := c.Options[key].(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	default:
+		return def
+	}
+}
+
+// IntOption returns an integer option, or def if it is missing or not a number.
+func (c FeatureConfig) IntOption(key string, def int) int {
+	return int(c.NumberOption(key, float64(def)))
+}
+
+// NumberMapOption returns an option holding an object of numbers (e.g. a limit per category).
+// Entries that are not numbers are skipped; nil is returned if the option is missing or not an object.
+func (c FeatureConfig) NumberMapOption(key string) map[string]float64 {
+	m, ok := c.Options[key].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	values := make(map[string]float64, len(m))
+	for k, v := range m {
+		switch n := v.(type) {
+		case float64:
+			values[k] = n
+		case int:
+			values[k] = float64(n)
+		case int64:
+			values[k] = float64(n)
+		}
+	}
+	return values
+}
+
+// StringMapOption returns an option holding an object of strings (e.g. formulas by name).
+// Entries that are not strings are skipped; nil is returned if the option is missing or not an object.
+func (c FeatureConfig) StringMapOption(key string) map[string]string {
+	m, ok := c.Options[key].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	values := make(map[string]string, len(m))
+	for k, v := range m {
+		if s, ok := v.(string); ok {
+			values[k] = s
+		}
+	}
+	return values
+}
+
+// Periods a FeatureSchedule run can cover.
+const (
+	SchedulePeriodDay   = "day"   // The day before the run
+	SchedulePeriodWeek  = "week"  // The 7 days before the run
+	SchedulePeriodMonth = "month" // The calendar month before the run's date
+)
+
+// FeatureSchedule configures periodic runs of a supported feature.
+// Each run's result is stored as a snapshot that is kept after entries change.
+// Corresponds to api.FeatureSchedule.
+type FeatureSchedule struct {
+	Cron   string `dynamodbav:"Cron"`             // Cron expression in UTC (e.g. "0 0 1 * *" or "@monthly")
+	Period string `dynamodbav:"Period,omitempty"` // Window each run covers; "" means SchedulePeriodMonth
+}
+
+// PeriodOrDefault returns the schedule's period, defaulting to SchedulePeriodMonth.
+func (s FeatureSchedule) PeriodOrDefault() string {
+	if s.Period == "" {
+		return SchedulePeriodMonth
+	}
+	return s.Period
+}
+
+// SupportedFeature is a feature enabled on a theme together with its configuration.
+// Corresponds to api.SupportedFeature.
+type SupportedFeature struct {
+	Name     string           `dynamodbav:"Name"`               // Feature identifier (e.g., "monthly_summary")
+	Config   FeatureConfig    `dynamodbav:"Config"`             // Per-theme settings passed to the feature executor
+	Schedule *FeatureSchedule `dynamodbav:"Schedule,omitempty"` // Optional periodic runs (nil means on demand only)
 }
 
 // Theme represents a calendar theme definition.
 // Corresponds to api.Theme but includes DynamoDB keys and uses domain types.
 type Theme struct {
-	PK                string       `dynamodbav:"PK"` // Partition Key: USER#<user_id> or DEFAULT#THEME
-	SK                string       `dynamodbav:"SK"` // Sort Key: THEME#<theme_id>
-	ThemeID           uuid.UUID    `dynamodbav:"ThemeID"`
-	ThemeName         string       `dynamodbav:"ThemeName"`
-	Fields            []ThemeField `dynamodbav:"Fields"`
-	IsDefault         bool         `dynamodbav:"IsDefault"`
-	OwnerUserID       *uuid.UUID   `dynamodbav:"OwnerUserID,omitempty"` // Pointer to allow null for default themes
-	SupportedFeatures []string     `dynamodbav:"SupportedFeatures"`
-	CreatedAt         time.Time    `dynamodbav:"CreatedAt"`
-	UpdatedAt         time.Time    `dynamodbav:"UpdatedAt"`
+	PK                string             `dynamodbav:"PK"`               // Partition Key: THEME#<theme_id>
+	SK                string             `dynamodbav:"SK"`               // Sort Key: METADATA
+	GSI1PK            string             `dynamodbav:"GSI1PK,omitempty"` // DEFAULT#THEME for default themes, SCHEDULED#THEME for custom themes with schedules, else unset
+	GSI1SK            string             `dynamodbav:"GSI1SK,omitempty"` // THEME#<theme_id> when GSI1PK is set
+	ThemeID           uuid.UUID          `dynamodbav:"ThemeID"`
+	ThemeName         string             `dynamodbav:"ThemeName"`
+	Fields            []ThemeField       `dynamodbav:"Fields"`
+	IsDefault         bool               `dynamodbav:"IsDefault"`
+	OwnerUserID       *uuid.UUID         `dynamodbav:"OwnerUserID,omitempty"` // Pointer to allow null for default themes
+	SupportedFeatures []SupportedFeature `dynamodbav:"SupportedFeatures"`
+	SchemaVersion     int                `dynamodbav:"SchemaVersion"`          // Bumped whenever the fields change shape (see PlanSchemaChange)
+	Migrations        []Migration        `dynamodbav:"Migrations,omitempty"`   // Steps migrating entry data to each version, oldest first
+	Revision          int                `dynamodbav:"Revision"`               // Number of the latest revision (see Revision), 0 if none was recorded
+	UpdatedBy         *uuid.UUID         `dynamodbav:"UpdatedBy,omitempty"`    // User who made the latest revision
+	RestoredFrom      int                `dynamodbav:"RestoredFrom,omitempty"` // Revision the latest revision restored, 0 for regular changes
+	CreatedAt         time.Time          `dynamodbav:"CreatedAt"`
+	UpdatedAt         time.Time          `dynamodbav:"UpdatedAt"`
+	Role              Role               `dynamodbav:"-"` // Requesting user's role, set when the theme is read for a user
+}
+
+// Feature returns the supported feature with the given name, if the theme enables it.
+func (t *Theme) Feature(name string) (SupportedFeature, bool) {
+	for _, f := range t.SupportedFeatures {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return SupportedFeature{}, false
+}
+
+// HasSchedules reports whether the theme runs any of its features on a schedule.
+func (t *Theme) HasSchedules() bool {
+	for _, f := range t.SupportedFeatures {
+		if f.Schedule != nil {
+			return true
+		}
+	}
+	return false
 }
 
 // UserThemeLink represents the association between a user and a theme they can use.
-// This is used for DynamoDB storage to quickly find themes accessible by a user.
+// This is used for DynamoDB storage to quickly find themes accessible by a user,
+// and through GSI1 the members of a theme.
 type UserThemeLink struct {
-	PK      string    `dynamodbav:"PK"`      // Partition Key: USER#<user_id>
-	SK      string    `dynamodbav:"SK"`      // Sort Key: LINK#THEME#<theme_id>
-	UserID  uuid.UUID `dynamodbav:"UserID"`  // For potential GSI queries if needed
-	ThemeID uuid.UUID `dynamodbav:"ThemeID"` // For potential GSI queries if needed
+	PK      string    `dynamodbav:"PK"`               // Partition Key: USER#<user_id>
+	SK      string    `dynamodbav:"SK"`               // Sort Key: THEME#<theme_id>
+	GSI1PK  string    `dynamodbav:"GSI1PK,omitempty"` // THEME#<theme_id>
+	GSI1SK  string    `dynamodbav:"GSI1SK,omitempty"` // MEMBER#<user_id>
+	UserID  uuid.UUID `dynamodbav:"UserID"`
+	ThemeID uuid.UUID `dynamodbav:"ThemeID"`
+	Role    Role      `dynamodbav:"Role,omitempty"` // Owner links written before sharing existed have none
 }
 
 // --- Validation Logic ---
@@ -69,7 +311,7 @@ func (t *Theme) Validate() error {
 	if err := ValidateThemeFields(t.Fields); err != nil {
 		return fmt.Errorf("invalid theme fields: %w", err)
 	}
-	if err := ValidateSupportedFeatures(t.SupportedFeatures); err != nil {
+	if err := ValidateSupportedFeatures(t.SupportedFeatures, t.Fields); err != nil {
 		return fmt.Errorf("invalid supported features: %w", err)
 	}
 	return nil
@@ -96,26 +338,13 @@ func ValidateThemeFields(fields []ThemeField) error {
 		}
 		names[field.Name] = true
 
-		isValidType := false
-		validTypes := []FieldType{
-			FieldTypeText,
-			FieldTypeDate,
-			FieldTypeDateTime,
-			FieldTypeNumber,
-			FieldTypeBoolean,
-			FieldTypeTextarea,
-			FieldTypeSelect,
-		}
-		for _, vt := range validTypes {
-			if field.Type == vt {
-				isValidType = true
-				break
-			}
-		}
-		if !isValidType {
+		if !IsValidFieldType(field.Type) {
 			return fmt.Errorf("field '%s': invalid type '%s'", field.Name, field.Type)
 		}
 		// Required is a boolean, no need for nil check like in API model
+		if err := field.validateConstraints(); err != nil {
+			return fmt.Errorf("field '%s': %w", field.Name, err)
+		}
 	}
 	return nil
 }
@@ -128,31 +357,50 @@ func IsValidFieldName(name string) bool {
 	return validFieldNameRegex.MatchString(name)
 }
 
-// ValidateSupportedFeatures performs basic validation on supported feature names.
-func ValidateSupportedFeatures(features []string) error {
+// ValidateSupportedFeatures performs basic validation on supported features and their configuration.
+// Field mappings in a feature's config must reference fields defined on the theme.
+// Whether a feature exists and accepts the mapped field types is checked against the
+// executor registry by feature.ValidateSupportedFeatures.
+func ValidateSupportedFeatures(features []SupportedFeature, fields []ThemeField) error {
 	// Allow empty or nil features list
 	if len(features) == 0 {
 		return nil
 	}
 
-	validFeatures := map[string]bool{
-		"monthly_summary":      true,
-		"category_aggregation": true,
-		// Add other known valid features here
+	definedFields := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		definedFields[f.Name] = true
 	}
 	names := make(map[string]bool)
 	for i, feature := range features {
-		if feature == "" {
+		if feature.Name == "" {
 			return fmt.Errorf("feature %d: name cannot be empty", i)
 		}
 
-		if !validFeatures[feature] {
-			log.Printf("WARN: Potentially unsupported feature '%s' included in theme definition.", feature)
+		if _, exists := names[feature.Name]; exists {
+			return fmt.Errorf("feature name '%s' is duplicated", feature.Name)
+		}
+		names[feature.Name] = true
+
+		for role, fieldName := range feature.Config.Fields {
+			if role == "" {
+				return fmt.Errorf("feature '%s': config field role cannot be empty", feature.Name)
+			}
+			if !definedFields[fieldName] {
+				return fmt.Errorf("feature '%s': config role '%s' references undefined field '%s'", feature.Name, role, fieldName)
+			}
 		}
-		if _, exists := names[feature]; exists {
-			return fmt.Errorf("feature name '%s' is duplicated", feature)
+
+		if feature.Schedule != nil {
+			if feature.Schedule.Cron == "" {
+				return fmt.Errorf("feature '%s': schedule cron expression is required", feature.Name)
+			}
+			switch feature.Schedule.Period {
+			case "", SchedulePeriodDay, SchedulePeriodWeek, SchedulePeriodMonth:
+			default:
+				return fmt.Errorf("feature '%s': invalid schedule period '%s'", feature.Name, feature.Schedule.Period)
+			}
 		}
-		names[feature] = true
 	}
 	return nil
 }
@@ -0,0 +1,200 @@
+package theme
+
+import (
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// --- Parsers for the values of structured field types ---
+
+// Money is the value of a money field: an amount in an ISO 4217 currency.
+// In entry data it is the object {"amount": <number>, "currency": "<code>"}.
+type Money struct {
+	Amount   float64
+	Currency string
+}
+
+// Keys of a money value in entry data.
+const (
+	MoneyAmountKey   = "amount"
+	MoneyCurrencyKey = "currency"
+)
+
+// ParseMoney reads a money value from entry data.
+func ParseMoney(v interface{}) (Money, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return Money{}, fmt.Errorf("expects an object with '%s' and '%s', got %T", MoneyAmountKey, MoneyCurrencyKey, v)
+	}
+	for k := range m {
+		if k != MoneyAmountKey && k != MoneyCurrencyKey {
+			return Money{}, fmt.Errorf("has unknown money key '%s'", k)
+		}
+	}
+	amount, ok := numberValue(m[MoneyAmountKey])
+	if !ok {
+		return Money{}, fmt.Errorf("expects a number '%s', got %T", MoneyAmountKey, m[MoneyAmountKey])
+	}
+	currency, ok := m[MoneyCurrencyKey].(string)
+	if !ok {
+		return Money{}, fmt.Errorf("expects a string '%s', got %T", MoneyCurrencyKey, m[MoneyCurrencyKey])
+	}
+	if !IsValidCurrency(currency) {
+		return Money{}, fmt.Errorf("has unknown currency '%s'. Expected an ISO 4217 code such as JPY or USD", currency)
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// GeoPoint is the value of a geo field: a WGS 84 position in degrees with an optional place name.
+// In entry data it is the object {"lat": <number>, "lng": <number>, "label": "<name>"}.
+type GeoPoint struct {
+	Lat   float64
+	Lng   float64
+	Label string
+}
+
+// Keys of a geo value in entry data.
+const (
+	GeoLatKey   = "lat"
+	GeoLngKey   = "lng"
+	GeoLabelKey = "label"
+)
+
+// ParseGeo reads a geo value from entry data.
+func ParseGeo(v interface{}) (GeoPoint, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return GeoPoint{}, fmt.Errorf("expects an object with '%s' and '%s', got %T", GeoLatKey, GeoLngKey, v)
+	}
+	for k := range m {
+		if k != GeoLatKey && k != GeoLngKey && k != GeoLabelKey {
+			return GeoPoint{}, fmt.Errorf("has unknown geo key '%s'", k)
+		}
+	}
+	lat, ok := numberValue(m[GeoLatKey])
+	if !ok {
+		return GeoPoint{}, fmt.Errorf("expects a number '%s', got %T", GeoLatKey, m[GeoLatKey])
+	}
+	lng, ok := numberValue(m[GeoLngKey])
+	if !ok {
+		return GeoPoint{}, fmt.Errorf("expects a number '%s', got %T", GeoLngKey, m[GeoLngKey])
+	}
+	if err := ValidateLatLng(lat, lng); err != nil {
+		return GeoPoint{}, fmt.Errorf("has %v", err)
+	}
+	p := GeoPoint{Lat: lat, Lng: lng}
+	if label, exists := m[GeoLabelKey]; exists && label != nil {
+		if p.Label, ok = label.(string); !ok {
+			return GeoPoint{}, fmt.Errorf("expects a string '%s', got %T", GeoLabelKey, label)
+		}
+	}
+	return p, nil
+}
+
+// ValidateLatLng checks that a latitude and longitude are within [-90, 90] and [-180, 180].
+func ValidateLatLng(lat, lng float64) error {
+	if math.IsNaN(lat) || lat < -90 || lat > 90 {
+		return fmt.Errorf("invalid latitude %v. Expected -90 to 90", lat)
+	}
+	if math.IsNaN(lng) || lng < -180 || lng > 180 {
+		return fmt.Errorf("invalid longitude %v. Expected -180 to 180", lng)
+	}
+	return nil
+}
+
+// IsValidCurrency reports whether code is an active ISO 4217 currency code.
+func IsValidCurrency(code string) bool {
+	return len(code) == 3 && strings.Contains(currencyCodes, " "+code+" ")
+}
+
+// currencyCodes lists the active ISO 4217 currency codes, space separated with a leading and trailing space.
+const currencyCodes = " AED AFN ALL AMD ANG AOA ARS AUD AWG AZN BAM BBD BDT BGN BHD BIF BMD BND BOB BRL BSD BTN BWP BYN BZD" +
+	" CAD CDF CHF CLP CNY COP CRC CUP CVE CZK DJF DKK DOP DZD EGP ERN ETB EUR FJD FKP GBP GEL GHS GIP GMD GNF GTQ GYD" +
+	" HKD HNL HTG HUF IDR ILS INR IQD IRR ISK JMD JOD JPY KES KGS KHR KMF KPW KRW KWD KYD KZT LAK LBP LKR LRD LSL LYD" +
+	" MAD MDL MGA MKD MMK MNT MOP MRU MUR MVR MWK MXN MYR MZN NAD NGN NIO NOK NPR NZD OMR PAB PEN PGK PHP PKR PLN PYG" +
+	" QAR RON RSD RUB RWF SAR SBD SCR SDG SEK SGD SHP SLE SOS SRD SSP STN SVC SYP SZL THB TJS TMT TND TOP TRY TTD TWD" +
+	" TZS UAH UGX USD UYU UZS VES VND VUV WST XAF XCD XCG XOF XPF YER ZAR ZMW ZWG "
+
+// isoDurationRegex matches the ISO 8601 durations of fixed length: weeks, or days and a time part.
+var isoDurationRegex = regexp.MustCompile(`^P(?:(\d+)W|(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:[.,]\d+)?)S)?)?)$`)
+
+// ParseDuration parses an ISO 8601 duration such as "PT1H30M", "P1DT12H" or "P2W".
+// Years and months are rejected because their length varies; a day is 24 hours.
+func ParseDuration(s string) (time.Duration, error) {
+	m := isoDurationRegex.FindStringSubmatch(s)
+	if m == nil || s == "P" || strings.HasSuffix(s, "T") {
+		if datePart, _, _ := strings.Cut(s, "T"); strings.HasPrefix(s, "P") && strings.ContainsAny(datePart, "YM") {
+			return 0, fmt.Errorf("invalid duration '%s': years and months are not supported, use weeks, days, hours, minutes and seconds", s)
+		}
+		return 0, fmt.Errorf("invalid duration '%s'. Expected ISO 8601, e.g. PT1H30M", s)
+	}
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	seconds := 0.0
+	for i, unit := range units {
+		if m[i+1] == "" {
+			continue
+		}
+		n, err := strconv.ParseFloat(strings.Replace(m[i+1], ",", ".", 1), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration '%s': %v", s, err)
+		}
+		seconds += n * unit.Seconds()
+	}
+	if seconds > math.MaxInt64/float64(time.Second) {
+		return 0, fmt.Errorf("invalid duration '%s': too long", s)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// TimeOfDayLayout is the layout of time field values (24-hour HH:MM).
+const TimeOfDayLayout = "15:04"
+
+// validateTimeOfDay checks a time field value.
+func validateTimeOfDay(s string) error {
+	if _, err := time.Parse(TimeOfDayLayout, s); err != nil || len(s) != len(TimeOfDayLayout) {
+		return fmt.Errorf("has invalid time '%s'. Expected HH:MM (00:00-23:59)", s)
+	}
+	return nil
+}
+
+// validateURL checks a url field value: an absolute http or https URL with a host.
+func validateURL(s string) error {
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("has invalid URL '%s'. Expected an absolute http or https URL", s)
+	}
+	return nil
+}
+
+// validateEmail checks an email field value: a bare address without a display name.
+func validateEmail(s string) error {
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s {
+		return fmt.Errorf("has invalid email address '%s'", s)
+	}
+	return nil
+}
+
+// Default bounds of rating fields without min or max.
+const (
+	DefaultRatingMin = 1
+	DefaultRatingMax = 5
+)
+
+// RatingBounds returns the inclusive bounds of a rating field.
+func (f ThemeField) RatingBounds() (lo, hi float64) {
+	lo, hi = DefaultRatingMin, DefaultRatingMax
+	if v, ok := numberValue(f.Min); ok {
+		lo = v
+	}
+	if v, ok := numberValue(f.Max); ok {
+		hi = v
+	}
+	return lo, hi
+}
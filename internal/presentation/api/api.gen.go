@@ -17,17 +17,78 @@ const (
 	CognitoAuthScopes = "CognitoAuth.Scopes"
 )
 
+// Defines values for FeatureAlertStatus.
+const (
+	Exceeded FeatureAlertStatus = "exceeded"
+	Ok       FeatureAlertStatus = "ok"
+	Warning  FeatureAlertStatus = "warning"
+)
+
+// Defines values for FeatureSchedulePeriod.
+const (
+	Day   FeatureSchedulePeriod = "day"
+	Month FeatureSchedulePeriod = "month"
+	Week  FeatureSchedulePeriod = "week"
+)
+
+// Defines values for ThemeFieldOnDelete.
+const (
+	Block   ThemeFieldOnDelete = "block"
+	Cascade ThemeFieldOnDelete = "cascade"
+	Nullify ThemeFieldOnDelete = "nullify"
+)
+
+// Defines values for ThemeMigrationStepOp.
+const (
+	Convert     ThemeMigrationStepOp = "convert"
+	Drop        ThemeMigrationStepOp = "drop"
+	FillDefault ThemeMigrationStepOp = "fill_default"
+	Rename      ThemeMigrationStepOp = "rename"
+)
+
 // Defines values for ThemeFieldType.
 const (
-	Boolean  ThemeFieldType = "boolean"
-	Date     ThemeFieldType = "date"
-	Datetime ThemeFieldType = "datetime"
-	Number   ThemeFieldType = "number"
-	Select   ThemeFieldType = "select"
-	Text     ThemeFieldType = "text"
-	Textarea ThemeFieldType = "textarea"
+	Boolean      ThemeFieldType = "boolean"
+	Color        ThemeFieldType = "color"
+	Date         ThemeFieldType = "date"
+	Datetime     ThemeFieldType = "datetime"
+	Duration     ThemeFieldType = "duration"
+	Email        ThemeFieldType = "email"
+	Geo          ThemeFieldType = "geo"
+	ListBoolean  ThemeFieldType = "list<boolean>"
+	ListDate     ThemeFieldType = "list<date>"
+	ListDatetime ThemeFieldType = "list<datetime>"
+	ListNumber   ThemeFieldType = "list<number>"
+	ListText     ThemeFieldType = "list<text>"
+	Money        ThemeFieldType = "money"
+	Multiselect  ThemeFieldType = "multiselect"
+	Number       ThemeFieldType = "number"
+	Rating       ThemeFieldType = "rating"
+	Reference    ThemeFieldType = "reference"
+	Select       ThemeFieldType = "select"
+	Tags         ThemeFieldType = "tags"
+	Text         ThemeFieldType = "text"
+	Textarea     ThemeFieldType = "textarea"
+	Time         ThemeFieldType = "time"
+	Url          ThemeFieldType = "url"
 )
 
+// Defines values for ThemeRole.
+const (
+	Contributor ThemeRole = "contributor"
+	Editor      ThemeRole = "editor"
+	Owner       ThemeRole = "owner"
+	Viewer      ThemeRole = "viewer"
+)
+
+// Backlink An entry referencing another one.
+type Backlink struct {
+	Entry Entry `json:"entry"`
+
+	// Fields Names of the reference fields pointing at the entry.
+	Fields []string `json:"fields"`
+}
+
 // ConfirmForgotPasswordRequest defines model for ConfirmForgotPasswordRequest.
 type ConfirmForgotPasswordRequest struct {
 	ConfirmationCode string              `json:"confirmation_code"`
@@ -54,13 +115,15 @@ type CreateThemeRequest struct {
 	Fields []ThemeField `json:"fields"`
 
 	// SupportedFeatures Optional list of features supported by this new theme.
-	SupportedFeatures *[]string `json:"supported_features,omitempty"`
-	ThemeName         string    `json:"theme_name"`
+	SupportedFeatures *[]SupportedFeature `json:"supported_features,omitempty"`
+	ThemeName         string              `json:"theme_name"`
 }
 
 // Entry defines model for Entry.
 type Entry struct {
-	CreatedAt *time.Time `json:"created_at,omitempty"`
+	// Alerts Status of the theme's alerting features (e.g., budget) for the entry's month. Only returned when an entry is created or updated.
+	Alerts    *[]FeatureAlert `json:"alerts,omitempty"`
+	CreatedAt *time.Time      `json:"created_at,omitempty"`
 
 	// Data Key-value pairs based on the theme's fields definition
 	Data map[string]interface{} `json:"data"`
@@ -68,9 +131,12 @@ type Entry struct {
 	// EntryDate The primary date for this entry on the calendar
 	EntryDate openapi_types.Date  `json:"entry_date"`
 	EntryId   *openapi_types.UUID `json:"entry_id,omitempty"`
-	ThemeId   openapi_types.UUID  `json:"theme_id"`
-	UpdatedAt *time.Time          `json:"updated_at,omitempty"`
-	UserId    *openapi_types.UUID `json:"user_id,omitempty"`
+
+	// SchemaVersion Schema version of the theme the entry's data was written with. Entries returned by the API are always migrated to the theme's current version.
+	SchemaVersion *int                `json:"schema_version,omitempty"`
+	ThemeId       openapi_types.UUID  `json:"theme_id"`
+	UpdatedAt     *time.Time          `json:"updated_at,omitempty"`
+	UserId        *openapi_types.UUID `json:"user_id,omitempty"`
 }
 
 // Error defines model for Error.
@@ -78,6 +144,72 @@ type Error struct {
 	Message string `json:"message"`
 }
 
+// FeatureAlert Alert badge raised by an alerting feature such as 'budget'.
+type FeatureAlert struct {
+	// Feature Name of the feature that raised the alert.
+	Feature string `json:"feature"`
+
+	// Message Short human-readable explanation.
+	Message string             `json:"message"`
+	Status  FeatureAlertStatus `json:"status"`
+}
+
+// FeatureAlertStatus defines model for FeatureAlert.Status.
+type FeatureAlertStatus string
+
+// FeatureConfig Per-theme configuration of a supported feature.
+type FeatureConfig struct {
+	// Fields Maps feature-defined roles (e.g., 'category', 'amount') to field names of the theme.
+	Fields *map[string]string `json:"fields,omitempty"`
+
+	// Options Feature-specific settings.
+	Options *map[string]interface{} `json:"options,omitempty"`
+}
+
+// FeatureDefinition Metadata of a feature that can be enabled on a theme.
+type FeatureDefinition struct {
+	Description string             `json:"description"`
+	DisplayName string             `json:"display_name"`
+	Fields      []FeatureFieldRole `json:"fields"`
+
+	// MultiTheme Whether the feature runs over several themes via /features/{feature_name}/results.
+	MultiTheme bool `json:"multi_theme"`
+
+	// Name Feature identifier used in supported_features (e.g., 'monthly_summary').
+	Name    string          `json:"name"`
+	Options []FeatureOption `json:"options"`
+}
+
+// FeatureFieldRole A theme field a feature works on. Themes map the role to one of their fields in the feature config.
+type FeatureFieldRole struct {
+	// DefaultField Field name used when the role is not mapped in the config.
+	DefaultField *string `json:"default_field,omitempty"`
+	Description  string  `json:"description"`
+
+	// Required Whether the feature cannot run without this field.
+	Required bool `json:"required"`
+
+	// Role Key used in the feature config's fields (e.g., 'category').
+	Role string `json:"role"`
+
+	// Types Accepted theme field types (values of ThemeField type).
+	Types []string `json:"types"`
+}
+
+// FeatureOption A setting accepted in a feature config's options.
+type FeatureOption struct {
+	// Default Value used when the option is not set.
+	Default     *interface{} `json:"default,omitempty"`
+	Description string       `json:"description"`
+
+	// Enum Allowed values for string options.
+	Enum *[]string `json:"enum,omitempty"`
+	Name string    `json:"name"`
+
+	// Type Value type of the option ('string', 'number', 'boolean', 'number_map', an object of numbers, or 'string_map', an object of strings).
+	Type string `json:"type"`
+}
+
 // ForgotPasswordRequest defines model for ForgotPasswordRequest.
 type ForgotPasswordRequest struct {
 	Email openapi_types.Email `json:"email"`
@@ -107,12 +239,55 @@ type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+// RestoreThemeRevisionRequest defines model for RestoreThemeRevisionRequest.
+type RestoreThemeRevisionRequest struct {
+	// Migration Steps migrating the data of existing entries to the restored fields, as for a theme update. Required when the restored fields remove, retype or add required fields relative to the current ones.
+	Migration *[]ThemeMigrationStep `json:"migration,omitempty"`
+}
+
 // SignupRequest defines model for SignupRequest.
 type SignupRequest struct {
 	Email    openapi_types.Email `json:"email"`
 	Password string              `json:"password"`
 }
 
+// FeatureSchedule Periodic runs of a supported feature. Each run's result is stored as a snapshot.
+type FeatureSchedule struct {
+	// Cron Five-field cron expression in UTC (e.g., '0 0 1 * *') or @hourly, @daily, @weekly, @monthly.
+	Cron string `json:"cron"`
+
+	// Period Window each run covers, ending the day before the run.
+	Period *FeatureSchedulePeriod `json:"period,omitempty"`
+}
+
+// FeatureSchedulePeriod Window each run covers, ending the day before the run.
+type FeatureSchedulePeriod string
+
+// FeatureSnapshot Stored result of a scheduled feature run.
+type FeatureSnapshot struct {
+	CreatedAt   time.Time          `json:"created_at"`
+	EndDate     openapi_types.Date `json:"end_date"`
+	FeatureName string             `json:"feature_name"`
+
+	// Result The result structure depends on the feature.
+	Result    map[string]interface{} `json:"result"`
+	StartDate openapi_types.Date     `json:"start_date"`
+	ThemeId   openapi_types.UUID     `json:"theme_id"`
+}
+
+// SupportedFeature A feature a theme supports. For compatibility with clients written before features were configurable,
+// requests may also give a plain feature name string, which is taken as {"name": <name>, "config": {}}.
+type SupportedFeature struct {
+	// Config Per-theme configuration of a supported feature.
+	Config *FeatureConfig `json:"config,omitempty"`
+
+	// Name Feature identifier (e.g., 'monthly_summary').
+	Name string `json:"name"`
+
+	// Schedule Periodic runs of a supported feature. Each run's result is stored as a snapshot.
+	Schedule *FeatureSchedule `json:"schedule,omitempty"`
+}
+
 // Theme defines model for Theme.
 type Theme struct {
 	CreatedAt   *time.Time          `json:"created_at,omitempty"`
@@ -120,29 +295,195 @@ type Theme struct {
 	IsDefault   *bool               `json:"is_default,omitempty"`
 	OwnerUserId *openapi_types.UUID `json:"owner_user_id,omitempty"`
 
-	// SupportedFeatures List of features supported by this theme (e.g., 'monthly_summary').
-	SupportedFeatures *[]string           `json:"supported_features,omitempty"`
+	// Revision Number of the theme's latest revision. Every create, update and restore records a new revision.
+	Revision *int `json:"revision,omitempty"`
+
+	// Role Access level of a user to a theme. The owner created the theme; editors can change its fields and add entries, contributors can add entries, and viewers can read the entries of all members.
+	Role *ThemeRole `json:"role,omitempty"`
+
+	// SchemaVersion Version of the theme's fields, bumped whenever fields are added, removed, renamed, retyped or their formulas change.
+	SchemaVersion *int `json:"schema_version,omitempty"`
+
+	// SupportedFeatures List of features supported by this theme, each with its configuration.
+	SupportedFeatures *[]SupportedFeature `json:"supported_features,omitempty"`
 	ThemeId           *openapi_types.UUID `json:"theme_id,omitempty"`
 	ThemeName         string              `json:"theme_name"`
 	UpdatedAt         *time.Time          `json:"updated_at,omitempty"`
+
+	// UpdatedBy User who made the latest revision.
+	UpdatedBy *openapi_types.UUID `json:"updated_by,omitempty"`
 }
 
 // ThemeField defines model for ThemeField.
 type ThemeField struct {
+	// Default Value set on new entries that omit the field. Must satisfy the field's type and constraints.
+	Default *interface{} `json:"default,omitempty"`
+
+	// Formula Makes the field computed: a per-entry expression over the theme's other fields (e.g. price * qty or minutes_between(start, end) / 60), evaluated whenever the entry is saved. Only number, text and boolean fields can be computed; clients cannot write their values.
+	Formula *string `json:"formula,omitempty"`
+
 	// Label Display label for the field
 	Label string `json:"label"`
 
+	// Max Inclusive upper bound of a number or rating (as a number), date (YYYY-MM-DD), datetime (RFC3339), time (HH:MM) or duration (ISO 8601) field.
+	Max *interface{} `json:"max,omitempty"`
+
+	// MaxLength Maximum length in characters of a text or textarea field.
+	MaxLength *int `json:"max_length,omitempty"`
+
+	// Min Inclusive lower bound of a number or rating (as a number), date (YYYY-MM-DD), datetime (RFC3339), time (HH:MM) or duration (ISO 8601) field.
+	Min *interface{} `json:"min,omitempty"`
+
+	// MinLength Minimum length in characters of a text or textarea field.
+	MinLength *int `json:"min_length,omitempty"`
+
 	// Name Internal field name (unique within theme, snake_case recommended)
-	Name     string `json:"name"`
-	Required *bool  `json:"required,omitempty"`
+	Name string `json:"name"`
+
+	// OnDelete What happens to the entry when the entry its reference field points at is deleted. nullify is not allowed on required fields.
+	OnDelete *ThemeFieldOnDelete `json:"on_delete,omitempty"`
 
-	// Type Data type of the field
+	// Options Allowed values of a select field. When empty, any string is accepted.
+	Options *[]ThemeFieldOption `json:"options,omitempty"`
+
+	// Pattern Regular expression (RE2 syntax) a text or textarea value must match. Unanchored; use ^ and $ to match the whole value.
+	Pattern  *string `json:"pattern,omitempty"`
+	Required *bool   `json:"required,omitempty"`
+
+	// TargetThemeId Theme the entries a reference field points at must belong to. Any theme when omitted.
+	TargetThemeId *openapi_types.UUID `json:"target_theme_id,omitempty"`
+
+	// Type Data type of the field. duration values are ISO 8601 strings without years or months (e.g. PT1H30M), time values are HH:MM,
+	// money values are objects {"amount": number, "currency": ISO 4217 code}, rating values are whole numbers between min and max (1-5 by default),
+	// url values are absolute http(s) URLs, email values are bare addresses and color values are #RGB or #RRGGBB.
+	// geo values are objects {"lat": number, "lng": number, "label": optional place name} in WGS 84 degrees.
+	// reference values are the IDs of other entries of the same user.
+	// multiselect, tags and list<type> values are arrays; constraints apply to each item.
 	Type ThemeFieldType `json:"type"`
 }
 
-// ThemeFieldType Data type of the field
+// ThemeFieldChange A field whose definition differs between two revisions.
+type ThemeFieldChange struct {
+	// Attributes Names of the changed attributes of the field (e.g. label, type, min_length).
+	Attributes []string   `json:"attributes"`
+	From       ThemeField `json:"from"`
+	Name       string     `json:"name"`
+	To         ThemeField `json:"to"`
+}
+
+// ThemeFieldOption An allowed value of a select field.
+type ThemeFieldOption struct {
+	// Color Display color as #RGB or #RRGGBB.
+	Color *string `json:"color,omitempty"`
+
+	// Label Display label. Defaults to the value.
+	Label *string `json:"label,omitempty"`
+
+	// Value Value stored in entry data.
+	Value string `json:"value"`
+}
+
+// ThemeFieldOnDelete What happens to the entry when the entry its reference field points at is deleted. nullify is not allowed on required fields.
+type ThemeFieldOnDelete string
+
+// ThemeFieldType Data type of the field. duration values are ISO 8601 strings without years or months (e.g. PT1H30M), time values are HH:MM,
+// money values are objects {"amount": number, "currency": ISO 4217 code}, rating values are whole numbers between min and max (1-5 by default),
+// url values are absolute http(s) URLs, email values are bare addresses and color values are #RGB or #RRGGBB.
+// reference values are the IDs of other entries of the same user.
+// multiselect, tags and list<type> values are arrays; constraints apply to each item.
 type ThemeFieldType string
 
+// ThemeInvitation A pending offer to share a theme with a user.
+type ThemeInvitation struct {
+	CreatedAt time.Time          `json:"created_at"`
+	InvitedBy openapi_types.UUID `json:"invited_by"`
+
+	// Role Access level of a user to a theme. The owner created the theme; editors can change its fields and add entries, contributors can add entries, and viewers can read the entries of all members.
+	Role      ThemeRole          `json:"role"`
+	ThemeId   openapi_types.UUID `json:"theme_id"`
+	ThemeName string             `json:"theme_name"`
+	UserId    openapi_types.UUID `json:"user_id"`
+}
+
+// ThemeInvitationRequest defines model for ThemeInvitationRequest.
+type ThemeInvitationRequest struct {
+	// Role Access level of a user to a theme. The owner created the theme; editors can change its fields and add entries, contributors can add entries, and viewers can read the entries of all members.
+	Role ThemeRole `json:"role"`
+
+	// UserId User to invite.
+	UserId openapi_types.UUID `json:"user_id"`
+}
+
+// ThemeMember A user with access to a shared theme.
+type ThemeMember struct {
+	// Role Access level of a user to a theme. The owner created the theme; editors can change its fields and add entries, contributors can add entries, and viewers can read the entries of all members.
+	Role   ThemeRole          `json:"role"`
+	UserId openapi_types.UUID `json:"user_id"`
+}
+
+// ThemeMigrationStep One change applied to the data of existing entries when a theme's fields are updated.
+type ThemeMigrationStep struct {
+	// Field Name of the field the step applies to, as it stands before the step.
+	Field string `json:"field"`
+
+	// Op rename moves the value to the field named by 'to'; drop removes it; convert converts it to the field's new type, removing values that cannot be converted; fill_default sets 'value' where the field is missing or null.
+	Op ThemeMigrationStepOp `json:"op"`
+
+	// To New name of a renamed field.
+	To *string `json:"to,omitempty"`
+
+	// Value Value set by a fill_default step. Must satisfy the field's type and constraints.
+	Value *interface{} `json:"value,omitempty"`
+}
+
+// ThemeMigrationStepOp rename moves the value to the field named by 'to'; drop removes it; convert converts it to the field's new type, removing values that cannot be converted; fill_default sets 'value' where the field is missing or null.
+type ThemeMigrationStepOp string
+
+// ThemeNameChange A changed theme name.
+type ThemeNameChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// ThemeRevision Immutable snapshot of a theme's definition, recorded when the theme was created, updated or restored.
+type ThemeRevision struct {
+	ChangedAt time.Time `json:"changed_at"`
+
+	// ChangedBy User who made the change.
+	ChangedBy openapi_types.UUID `json:"changed_by"`
+	Fields    []ThemeField       `json:"fields"`
+
+	// RestoredFrom Revision this revision restored. Omitted for regular changes.
+	RestoredFrom      *int               `json:"restored_from,omitempty"`
+	Revision          int                `json:"revision"`
+	SchemaVersion     int                `json:"schema_version"`
+	SupportedFeatures []SupportedFeature `json:"supported_features"`
+	ThemeName         string             `json:"theme_name"`
+}
+
+// ThemeRevisionDiff Changes to a theme's definition from one revision to another. Fields are matched by name, so a renamed field is listed as removed and added.
+type ThemeRevisionDiff struct {
+	// FeaturesAdded Supported features enabled in 'to' but not in 'from'.
+	FeaturesAdded []string `json:"features_added"`
+
+	// FeaturesChanged Supported features enabled in both whose config or schedule differs.
+	FeaturesChanged []string `json:"features_changed"`
+
+	// FeaturesRemoved Supported features enabled in 'from' but not in 'to'.
+	FeaturesRemoved []string           `json:"features_removed"`
+	FieldsAdded     []ThemeField       `json:"fields_added"`
+	FieldsChanged   []ThemeFieldChange `json:"fields_changed"`
+	FieldsRemoved   []ThemeField       `json:"fields_removed"`
+	From            int                `json:"from"`
+
+	// ThemeName A changed theme name.
+	ThemeName *ThemeNameChange `json:"theme_name,omitempty"`
+	To        int              `json:"to"`
+}
+
+// ThemeRole Access level of a user to a theme. The owner created the theme; editors can change its fields and add entries, contributors can add entries, and viewers can read the entries of all members.
+type ThemeRole string
+
 // UpdateEntryRequest defines model for UpdateEntryRequest.
 type UpdateEntryRequest struct {
 	// Data Keys should match field names defined in the theme.
@@ -154,9 +495,12 @@ type UpdateEntryRequest struct {
 type UpdateThemeRequest struct {
 	Fields []ThemeField `json:"fields"`
 
+	// Migration Steps migrating the data of existing entries to the updated fields, applied in order. Required when fields are removed (rename or drop), change type (convert) or are added as required (fill_default).
+	Migration *[]ThemeMigrationStep `json:"migration,omitempty"`
+
 	// SupportedFeatures Optional updated list of features supported by this theme.
-	SupportedFeatures *[]string `json:"supported_features,omitempty"`
-	ThemeName         string    `json:"theme_name"`
+	SupportedFeatures *[]SupportedFeature `json:"supported_features,omitempty"`
+	ThemeName         string              `json:"theme_name"`
 }
 
 // User defines model for User.
@@ -165,15 +509,39 @@ type User struct {
 	UserId *openapi_types.UUID  `json:"user_id,omitempty"`
 }
 
+// BboxQuery defines model for BboxQuery.
+type BboxQuery = string
+
+// ContainsQuery defines model for ContainsQuery.
+type ContainsQuery = []string
+
 // EndDateParam defines model for EndDateParam.
 type EndDateParam = openapi_types.Date
 
 // EntryIdParam defines model for EntryIdParam.
 type EntryIdParam = openapi_types.UUID
 
+// FeatureEndDateQuery defines model for FeatureEndDateQuery.
+type FeatureEndDateQuery = openapi_types.Date
+
 // FeatureNameParam defines model for FeatureNameParam.
 type FeatureNameParam = string
 
+// FeatureStartDateQuery defines model for FeatureStartDateQuery.
+type FeatureStartDateQuery = openapi_types.Date
+
+// NearQuery defines model for NearQuery.
+type NearQuery = string
+
+// RevisionFromQuery defines model for RevisionFromQuery.
+type RevisionFromQuery = int
+
+// RevisionParam defines model for RevisionParam.
+type RevisionParam = int
+
+// RevisionToQuery defines model for RevisionToQuery.
+type RevisionToQuery = int
+
 // StartDateParam defines model for StartDateParam.
 type StartDateParam = openapi_types.Date
 
@@ -183,9 +551,18 @@ type ThemeIdParam = openapi_types.UUID
 // ThemeIdQuery defines model for ThemeIdQuery.
 type ThemeIdQuery = openapi_types.UUID
 
+// ThemeIdsQuery defines model for ThemeIdsQuery.
+type ThemeIdsQuery = []openapi_types.UUID
+
+// UserIdParam defines model for UserIdParam.
+type UserIdParam = openapi_types.UUID
+
 // BadRequest defines model for BadRequest.
 type BadRequest = Error
 
+// Conflict defines model for Conflict.
+type Conflict = Error
+
 // Forbidden defines model for Forbidden.
 type Forbidden = Error
 
@@ -208,6 +585,45 @@ type GetEntriesParams struct {
 
 	// EndDate End date for the date range filter (inclusive)
 	EndDate EndDateParam `form:"end_date" json:"end_date"`
+
+	// Contains Only return entries whose list field (multiselect, tags or list<type>) holds an item, as <field>:<item> (e.g. tags:urgent). Repeat the parameter to require several items.
+	Contains *ContainsQuery `form:"contains,omitempty" json:"contains,omitempty"`
+
+	// Bbox Only return entries whose geo field lies within a bounding box, as <field>:<south>,<west>,<north>,<east> in degrees (e.g. place:35.6,139.6,35.8,139.9). Boxes cannot cross the antimeridian.
+	Bbox *BboxQuery `form:"bbox,omitempty" json:"bbox,omitempty"`
+
+	// Near Only return entries whose geo field lies within a radius of a point, as <field>:<lat>,<lng>,<radius in meters> (e.g. place:35.681,139.767,500).
+	Near *NearQuery `form:"near,omitempty" json:"near,omitempty"`
+}
+
+// GetFeaturesFeatureNameResultsParams defines parameters for GetFeaturesFeatureNameResults.
+type GetFeaturesFeatureNameResultsParams struct {
+	// ThemeIds IDs of the themes to run the feature over. Repeat the parameter for each theme.
+	ThemeIds ThemeIdsQuery `form:"theme_ids" json:"theme_ids"`
+
+	// StartDate Start of the date range the feature is executed over (inclusive). Defaults to the first day of the current month.
+	StartDate *FeatureStartDateQuery `form:"start_date,omitempty" json:"start_date,omitempty"`
+
+	// EndDate End of the date range the feature is executed over (inclusive). Defaults to the last day of the current month.
+	EndDate *FeatureEndDateQuery `form:"end_date,omitempty" json:"end_date,omitempty"`
+}
+
+// GetThemesThemeIdFeaturesFeatureNameParams defines parameters for GetThemesThemeIdFeaturesFeatureName.
+type GetThemesThemeIdFeaturesFeatureNameParams struct {
+	// StartDate Start of the date range the feature is executed over (inclusive). Defaults to the first day of the current month.
+	StartDate *FeatureStartDateQuery `form:"start_date,omitempty" json:"start_date,omitempty"`
+
+	// EndDate End of the date range the feature is executed over (inclusive). Defaults to the last day of the current month.
+	EndDate *FeatureEndDateQuery `form:"end_date,omitempty" json:"end_date,omitempty"`
+}
+
+// GetThemesThemeIdRevisionsDiffParams defines parameters for GetThemesThemeIdRevisionsDiff.
+type GetThemesThemeIdRevisionsDiffParams struct {
+	// From Revision the diff starts from.
+	From RevisionFromQuery `form:"from" json:"from"`
+
+	// To Revision the diff leads to.
+	To RevisionToQuery `form:"to" json:"to"`
 }
 
 // PostAuthConfirmForgotPasswordJSONRequestBody defines body for PostAuthConfirmForgotPassword for application/json ContentType.
@@ -240,6 +656,12 @@ type PostThemesJSONRequestBody = CreateThemeRequest
 // PutThemesThemeIdJSONRequestBody defines body for PutThemesThemeId for application/json ContentType.
 type PutThemesThemeIdJSONRequestBody = UpdateThemeRequest
 
+// PostThemesThemeIdInvitationsJSONRequestBody defines body for PostThemesThemeIdInvitations for application/json ContentType.
+type PostThemesThemeIdInvitationsJSONRequestBody = ThemeInvitationRequest
+
+// PostThemesThemeIdRevisionsRevisionRestoreJSONRequestBody defines body for PostThemesThemeIdRevisionsRevisionRestore for application/json ContentType.
+type PostThemesThemeIdRevisionsRevisionRestoreJSONRequestBody = RestoreThemeRevisionRequest
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
 	// Confirm forgot password and set new password
@@ -281,9 +703,27 @@ type ServerInterface interface {
 	// Update an entry
 	// (PUT /entries/{entry_id})
 	PutEntriesEntryId(ctx echo.Context, entryId EntryIdParam) error
+	// List entries referencing an entry
+	// (GET /entries/{entry_id}/backlinks)
+	GetEntriesEntryIdBacklinks(ctx echo.Context, entryId EntryIdParam) error
+	// List features that can be enabled on themes
+	// (GET /features)
+	GetFeatures(ctx echo.Context) error
+	// Execute a multi-theme feature over several themes (e.g., correlation)
+	// (GET /features/{feature_name}/results)
+	GetFeaturesFeatureNameResults(ctx echo.Context, featureName FeatureNameParam, params GetFeaturesFeatureNameResultsParams) error
 	// Health check endpoint
 	// (GET /health)
 	GetHealth(ctx echo.Context) error
+	// List the user's pending theme invitations
+	// (GET /invitations)
+	GetInvitations(ctx echo.Context) error
+	// Decline an invitation to a theme
+	// (DELETE /invitations/{theme_id})
+	DeleteInvitationsThemeId(ctx echo.Context, themeId ThemeIdParam) error
+	// Accept an invitation to a theme
+	// (POST /invitations/{theme_id}/accept)
+	PostInvitationsThemeIdAccept(ctx echo.Context, themeId ThemeIdParam) error
 	// List available themes
 	// (GET /themes)
 	GetThemes(ctx echo.Context) error
@@ -301,7 +741,28 @@ type ServerInterface interface {
 	PutThemesThemeId(ctx echo.Context, themeId ThemeIdParam) error
 	// Execute a specific feature for a theme (e.g., aggregation)
 	// (GET /themes/{theme_id}/features/{feature_name})
-	GetThemesThemeIdFeaturesFeatureName(ctx echo.Context, themeId ThemeIdParam, featureName FeatureNameParam) error
+	GetThemesThemeIdFeaturesFeatureName(ctx echo.Context, themeId ThemeIdParam, featureName FeatureNameParam, params GetThemesThemeIdFeaturesFeatureNameParams) error
+	// List stored results of a feature's scheduled runs
+	// (GET /themes/{theme_id}/features/{feature_name}/snapshots)
+	GetThemesThemeIdFeaturesFeatureNameSnapshots(ctx echo.Context, themeId ThemeIdParam, featureName FeatureNameParam) error
+	// Invite a user to a custom theme
+	// (POST /themes/{theme_id}/invitations)
+	PostThemesThemeIdInvitations(ctx echo.Context, themeId ThemeIdParam) error
+	// List the members of a shared theme
+	// (GET /themes/{theme_id}/members)
+	GetThemesThemeIdMembers(ctx echo.Context, themeId ThemeIdParam) error
+	// Remove a member from a shared theme
+	// (DELETE /themes/{theme_id}/members/{user_id})
+	DeleteThemesThemeIdMembersUserId(ctx echo.Context, themeId ThemeIdParam, userId UserIdParam) error
+	// List the revisions of a theme
+	// (GET /themes/{theme_id}/revisions)
+	GetThemesThemeIdRevisions(ctx echo.Context, themeId ThemeIdParam) error
+	// Compare two revisions of a theme
+	// (GET /themes/{theme_id}/revisions/diff)
+	GetThemesThemeIdRevisionsDiff(ctx echo.Context, themeId ThemeIdParam, params GetThemesThemeIdRevisionsDiffParams) error
+	// Restore a custom theme to one of its revisions
+	// (POST /themes/{theme_id}/revisions/{revision}/restore)
+	PostThemesThemeIdRevisionsRevisionRestore(ctx echo.Context, themeId ThemeIdParam, revision RevisionParam) error
 }
 
 // ServerInterfaceWrapper converts echo contexts to parameters.
@@ -414,6 +875,27 @@ func (w *ServerInterfaceWrapper) GetEntries(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter end_date: %s", err))
 	}
 
+	// ------------- Optional query parameter "contains" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "contains", ctx.QueryParams(), &params.Contains)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter contains: %s", err))
+	}
+
+	// ------------- Optional query parameter "bbox" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "bbox", ctx.QueryParams(), &params.Bbox)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter bbox: %s", err))
+	}
+
+	// ------------- Optional query parameter "near" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "near", ctx.QueryParams(), &params.Near)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter near: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
 	err = w.Handler.GetEntries(ctx, params)
 	return err
@@ -484,6 +966,76 @@ func (w *ServerInterfaceWrapper) PutEntriesEntryId(ctx echo.Context) error {
 	return err
 }
 
+// GetEntriesEntryIdBacklinks converts echo context to params.
+func (w *ServerInterfaceWrapper) GetEntriesEntryIdBacklinks(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "entry_id" -------------
+	var entryId EntryIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "entry_id", runtime.ParamLocationPath, ctx.Param("entry_id"), &entryId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter entry_id: %s", err))
+	}
+
+	ctx.Set(CognitoAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetEntriesEntryIdBacklinks(ctx, entryId)
+	return err
+}
+
+// GetFeatures converts echo context to params.
+func (w *ServerInterfaceWrapper) GetFeatures(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(CognitoAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetFeatures(ctx)
+	return err
+}
+
+// GetFeaturesFeatureNameResults converts echo context to params.
+func (w *ServerInterfaceWrapper) GetFeaturesFeatureNameResults(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "feature_name" -------------
+	var featureName FeatureNameParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "feature_name", runtime.ParamLocationPath, ctx.Param("feature_name"), &featureName)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter feature_name: %s", err))
+	}
+
+	ctx.Set(CognitoAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetFeaturesFeatureNameResultsParams
+	// ------------- Required query parameter "theme_ids" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "theme_ids", ctx.QueryParams(), &params.ThemeIds)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter theme_ids: %s", err))
+	}
+
+	// ------------- Optional query parameter "start_date" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "start_date", ctx.QueryParams(), &params.StartDate)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter start_date: %s", err))
+	}
+
+	// ------------- Optional query parameter "end_date" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "end_date", ctx.QueryParams(), &params.EndDate)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter end_date: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetFeaturesFeatureNameResults(ctx, featureName, params)
+	return err
+}
+
 // GetHealth converts echo context to params.
 func (w *ServerInterfaceWrapper) GetHealth(ctx echo.Context) error {
 	var err error
@@ -493,6 +1045,53 @@ func (w *ServerInterfaceWrapper) GetHealth(ctx echo.Context) error {
 	return err
 }
 
+// GetInvitations converts echo context to params.
+func (w *ServerInterfaceWrapper) GetInvitations(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(CognitoAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetInvitations(ctx)
+	return err
+}
+
+// DeleteInvitationsThemeId converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteInvitationsThemeId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "theme_id" -------------
+	var themeId ThemeIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "theme_id", runtime.ParamLocationPath, ctx.Param("theme_id"), &themeId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter theme_id: %s", err))
+	}
+
+	ctx.Set(CognitoAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteInvitationsThemeId(ctx, themeId)
+	return err
+}
+
+// PostInvitationsThemeIdAccept converts echo context to params.
+func (w *ServerInterfaceWrapper) PostInvitationsThemeIdAccept(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "theme_id" -------------
+	var themeId ThemeIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "theme_id", runtime.ParamLocationPath, ctx.Param("theme_id"), &themeId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter theme_id: %s", err))
+	}
+
+	ctx.Set(CognitoAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostInvitationsThemeIdAccept(ctx, themeId)
+	return err
+}
+
 // GetThemes converts echo context to params.
 func (w *ServerInterfaceWrapper) GetThemes(ctx echo.Context) error {
 	var err error
@@ -590,8 +1189,190 @@ func (w *ServerInterfaceWrapper) GetThemesThemeIdFeaturesFeatureName(ctx echo.Co
 
 	ctx.Set(CognitoAuthScopes, []string{})
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetThemesThemeIdFeaturesFeatureNameParams
+	// ------------- Optional query parameter "start_date" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "start_date", ctx.QueryParams(), &params.StartDate)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter start_date: %s", err))
+	}
+
+	// ------------- Optional query parameter "end_date" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "end_date", ctx.QueryParams(), &params.EndDate)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter end_date: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetThemesThemeIdFeaturesFeatureName(ctx, themeId, featureName, params)
+	return err
+}
+
+// GetThemesThemeIdFeaturesFeatureNameSnapshots converts echo context to params.
+func (w *ServerInterfaceWrapper) GetThemesThemeIdFeaturesFeatureNameSnapshots(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "theme_id" -------------
+	var themeId ThemeIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "theme_id", runtime.ParamLocationPath, ctx.Param("theme_id"), &themeId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter theme_id: %s", err))
+	}
+
+	// ------------- Path parameter "feature_name" -------------
+	var featureName FeatureNameParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "feature_name", runtime.ParamLocationPath, ctx.Param("feature_name"), &featureName)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter feature_name: %s", err))
+	}
+
+	ctx.Set(CognitoAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetThemesThemeIdFeaturesFeatureNameSnapshots(ctx, themeId, featureName)
+	return err
+}
+
+// PostThemesThemeIdInvitations converts echo context to params.
+func (w *ServerInterfaceWrapper) PostThemesThemeIdInvitations(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "theme_id" -------------
+	var themeId ThemeIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "theme_id", runtime.ParamLocationPath, ctx.Param("theme_id"), &themeId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter theme_id: %s", err))
+	}
+
+	ctx.Set(CognitoAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostThemesThemeIdInvitations(ctx, themeId)
+	return err
+}
+
+// GetThemesThemeIdMembers converts echo context to params.
+func (w *ServerInterfaceWrapper) GetThemesThemeIdMembers(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "theme_id" -------------
+	var themeId ThemeIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "theme_id", runtime.ParamLocationPath, ctx.Param("theme_id"), &themeId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter theme_id: %s", err))
+	}
+
+	ctx.Set(CognitoAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetThemesThemeIdMembers(ctx, themeId)
+	return err
+}
+
+// DeleteThemesThemeIdMembersUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteThemesThemeIdMembersUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "theme_id" -------------
+	var themeId ThemeIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "theme_id", runtime.ParamLocationPath, ctx.Param("theme_id"), &themeId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter theme_id: %s", err))
+	}
+
+	// ------------- Path parameter "user_id" -------------
+	var userId UserIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "user_id", runtime.ParamLocationPath, ctx.Param("user_id"), &userId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter user_id: %s", err))
+	}
+
+	ctx.Set(CognitoAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteThemesThemeIdMembersUserId(ctx, themeId, userId)
+	return err
+}
+
+// GetThemesThemeIdRevisions converts echo context to params.
+func (w *ServerInterfaceWrapper) GetThemesThemeIdRevisions(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "theme_id" -------------
+	var themeId ThemeIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "theme_id", runtime.ParamLocationPath, ctx.Param("theme_id"), &themeId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter theme_id: %s", err))
+	}
+
+	ctx.Set(CognitoAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetThemesThemeIdRevisions(ctx, themeId)
+	return err
+}
+
+// GetThemesThemeIdRevisionsDiff converts echo context to params.
+func (w *ServerInterfaceWrapper) GetThemesThemeIdRevisionsDiff(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "theme_id" -------------
+	var themeId ThemeIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "theme_id", runtime.ParamLocationPath, ctx.Param("theme_id"), &themeId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter theme_id: %s", err))
+	}
+
+	ctx.Set(CognitoAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetThemesThemeIdRevisionsDiffParams
+	// ------------- Required query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "from", ctx.QueryParams(), &params.From)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter from: %s", err))
+	}
+
+	// ------------- Required query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "to", ctx.QueryParams(), &params.To)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter to: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetThemesThemeIdRevisionsDiff(ctx, themeId, params)
+	return err
+}
+
+// PostThemesThemeIdRevisionsRevisionRestore converts echo context to params.
+func (w *ServerInterfaceWrapper) PostThemesThemeIdRevisionsRevisionRestore(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "theme_id" -------------
+	var themeId ThemeIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "theme_id", runtime.ParamLocationPath, ctx.Param("theme_id"), &themeId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter theme_id: %s", err))
+	}
+
+	// ------------- Path parameter "revision" -------------
+	var revision RevisionParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "revision", runtime.ParamLocationPath, ctx.Param("revision"), &revision)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter revision: %s", err))
+	}
+
+	ctx.Set(CognitoAuthScopes, []string{})
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetThemesThemeIdFeaturesFeatureName(ctx, themeId, featureName)
+	err = w.Handler.PostThemesThemeIdRevisionsRevisionRestore(ctx, themeId, revision)
 	return err
 }
 
@@ -636,12 +1417,25 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 	router.DELETE(baseURL+"/entries/:entry_id", wrapper.DeleteEntriesEntryId)
 	router.GET(baseURL+"/entries/:entry_id", wrapper.GetEntriesEntryId)
 	router.PUT(baseURL+"/entries/:entry_id", wrapper.PutEntriesEntryId)
+	router.GET(baseURL+"/entries/:entry_id/backlinks", wrapper.GetEntriesEntryIdBacklinks)
+	router.GET(baseURL+"/features", wrapper.GetFeatures)
+	router.GET(baseURL+"/features/:feature_name/results", wrapper.GetFeaturesFeatureNameResults)
 	router.GET(baseURL+"/health", wrapper.GetHealth)
+	router.GET(baseURL+"/invitations", wrapper.GetInvitations)
+	router.DELETE(baseURL+"/invitations/:theme_id", wrapper.DeleteInvitationsThemeId)
+	router.POST(baseURL+"/invitations/:theme_id/accept", wrapper.PostInvitationsThemeIdAccept)
 	router.GET(baseURL+"/themes", wrapper.GetThemes)
 	router.POST(baseURL+"/themes", wrapper.PostThemes)
 	router.DELETE(baseURL+"/themes/:theme_id", wrapper.DeleteThemesThemeId)
 	router.GET(baseURL+"/themes/:theme_id", wrapper.GetThemesThemeId)
 	router.PUT(baseURL+"/themes/:theme_id", wrapper.PutThemesThemeId)
 	router.GET(baseURL+"/themes/:theme_id/features/:feature_name", wrapper.GetThemesThemeIdFeaturesFeatureName)
+	router.GET(baseURL+"/themes/:theme_id/features/:feature_name/snapshots", wrapper.GetThemesThemeIdFeaturesFeatureNameSnapshots)
+	router.POST(baseURL+"/themes/:theme_id/invitations", wrapper.PostThemesThemeIdInvitations)
+	router.GET(baseURL+"/themes/:theme_id/members", wrapper.GetThemesThemeIdMembers)
+	router.DELETE(baseURL+"/themes/:theme_id/members/:user_id", wrapper.DeleteThemesThemeIdMembersUserId)
+	router.GET(baseURL+"/themes/:theme_id/revisions", wrapper.GetThemesThemeIdRevisions)
+	router.GET(baseURL+"/themes/:theme_id/revisions/diff", wrapper.GetThemesThemeIdRevisionsDiff)
+	router.POST(baseURL+"/themes/:theme_id/revisions/:revision/restore", wrapper.PostThemesThemeIdRevisionsRevisionRestore)
 
 }
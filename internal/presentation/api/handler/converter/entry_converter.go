@@ -1,7 +1,10 @@
 package converter
 
 import (
+	"fmt"
 	"log"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,6 +22,7 @@ func ToApiEntry(de entry.Entry) (api.Entry, error) {
 	themeID := de.ThemeID
 	createdAt := de.CreatedAt
 	updatedAt := de.UpdatedAt
+	schemaVersion := de.SchemaVersion
 
 	// Parse the YYYY-MM-DD date string into time.Time
 	entryDateTime, err := time.Parse("2006-01-02", de.EntryDate)
@@ -32,13 +36,14 @@ func ToApiEntry(de entry.Entry) (api.Entry, error) {
 	apiEntryDate := openapi_types.Date{Time: entryDateTime}
 
 	return api.Entry{
-		EntryId:   &entryID,
-		UserId:    &userID,
-		ThemeId:   themeID,
-		EntryDate: apiEntryDate,
-		Data:      de.Data,
-		CreatedAt: &createdAt,
-		UpdatedAt: &updatedAt,
+		EntryId:       &entryID,
+		UserId:        &userID,
+		ThemeId:       themeID,
+		EntryDate:     apiEntryDate,
+		Data:          de.Data,
+		SchemaVersion: &schemaVersion,
+		CreatedAt:     &createdAt,
+		UpdatedAt:     &updatedAt,
 	}, nil // Return nil error even if date parsing failed (logged)
 }
 
@@ -64,6 +69,20 @@ func ToApiEntries(des []entry.Entry) ([]api.Entry, error) {
 	return result, nil // Return nil error even if some entries failed
 }
 
+// ToApiBacklinks converts domain backlinks to API Backlinks
+func ToApiBacklinks(dbs []entry.Backlink) ([]api.Backlink, error) {
+	abs := make([]api.Backlink, 0, len(dbs))
+	for _, db := range dbs {
+		ae, err := ToApiEntry(db.Entry)
+		if err != nil {
+			log.Printf("WARN: Failed to convert backlink entry %s to API format: %v", db.Entry.EntryID, err)
+			continue
+		}
+		abs = append(abs, api.Backlink{Entry: ae, Fields: db.Fields})
+	}
+	return abs, nil
+}
+
 // FromApiCreateEntryRequest converts API CreateEntryRequest to domain Entry
 func FromApiCreateEntryRequest(req api.CreateEntryRequest, userID uuid.UUID) (entry.Entry, error) {
 	newEntry := entry.Entry{
@@ -91,3 +110,73 @@ func FromApiUpdateEntryRequest(req api.UpdateEntryRequest, entryID uuid.UUID, us
 	}
 	return updatedEntry, nil
 }
+
+// FromApiContainsQuery converts the contains query parameter (<field>:<item> values) to entry filters.
+// Items are kept as strings; the use case types them from the theme's fields.
+func FromApiContainsQuery(contains *api.ContainsQuery) ([]entry.Filter, error) {
+	if contains == nil {
+		return nil, nil
+	}
+	filters := make([]entry.Filter, 0, len(*contains))
+	for _, c := range *contains {
+		field, item, ok := strings.Cut(c, ":")
+		if !ok || field == "" {
+			return nil, fmt.Errorf("contains must be <field>:<item>, got '%s'", c)
+		}
+		filters = append(filters, entry.ContainsFilter{Field: field, Value: item})
+	}
+	return filters, nil
+}
+
+// FromApiBboxQuery converts the bbox query parameter (<field>:<south>,<west>,<north>,<east>) to a geo filter.
+func FromApiBboxQuery(bbox *api.BboxQuery) ([]entry.Filter, error) {
+	if bbox == nil {
+		return nil, nil
+	}
+	field, coords, err := parseGeoQuery(*bbox, 4)
+	if err != nil {
+		return nil, fmt.Errorf("bbox must be <field>:<south>,<west>,<north>,<east>: %w", err)
+	}
+	f, err := entry.NewBoxFilter(field, coords[0], coords[1], coords[2], coords[3])
+	if err != nil {
+		return nil, fmt.Errorf("bbox: %w", err)
+	}
+	return []entry.Filter{f}, nil
+}
+
+// FromApiNearQuery converts the near query parameter (<field>:<lat>,<lng>,<radius_m>) to a geo filter.
+func FromApiNearQuery(near *api.NearQuery) ([]entry.Filter, error) {
+	if near == nil {
+		return nil, nil
+	}
+	field, coords, err := parseGeoQuery(*near, 3)
+	if err != nil {
+		return nil, fmt.Errorf("near must be <field>:<lat>,<lng>,<radius_m>: %w", err)
+	}
+	f, err := entry.NewRadiusFilter(field, coords[0], coords[1], coords[2])
+	if err != nil {
+		return nil, fmt.Errorf("near: %w", err)
+	}
+	return []entry.Filter{f}, nil
+}
+
+// parseGeoQuery splits a <field>:<n1>,<n2>,... query value into the field and n numbers.
+func parseGeoQuery(q string, n int) (string, []float64, error) {
+	field, list, ok := strings.Cut(q, ":")
+	if !ok || field == "" {
+		return "", nil, fmt.Errorf("got '%s'", q)
+	}
+	parts := strings.Split(list, ",")
+	if len(parts) != n {
+		return "", nil, fmt.Errorf("expected %d numbers, got '%s'", n, list)
+	}
+	numbers := make([]float64, n)
+	for i, p := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid number '%s'", p)
+		}
+		numbers[i] = v
+	}
+	return field, numbers, nil
+}
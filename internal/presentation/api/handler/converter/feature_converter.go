@@ -0,0 +1,119 @@
+package converter
+
+import (
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+	"github.com/soranjiro/axicalendar/internal/presentation/api"
+)
+
+// --- Feature Converters ---
+
+// ToApiFeatureFieldRole converts domain FieldRole to api.FeatureFieldRole
+func ToApiFeatureFieldRole(df feature.FieldRole) api.FeatureFieldRole {
+	types := make([]string, len(df.Types))
+	for i, t := range df.Types {
+		types[i] = string(t)
+	}
+	var defaultField *string
+	if df.DefaultField != "" {
+		name := df.DefaultField
+		defaultField = &name
+	}
+	return api.FeatureFieldRole{
+		Role:         df.Role,
+		Description:  df.Description,
+		Types:        types,
+		Required:     df.Required,
+		DefaultField: defaultField,
+	}
+}
+
+// ToApiFeatureOption converts domain Option to api.FeatureOption
+func ToApiFeatureOption(do feature.Option) api.FeatureOption {
+	var defaultValue *interface{}
+	if do.Default != nil {
+		value := do.Default
+		defaultValue = &value
+	}
+	var enum *[]string
+	if len(do.Enum) > 0 {
+		values := make([]string, len(do.Enum))
+		copy(values, do.Enum)
+		enum = &values
+	}
+	return api.FeatureOption{
+		Name:        do.Name,
+		Type:        string(do.Type),
+		Description: do.Description,
+		Default:     defaultValue,
+		Enum:        enum,
+	}
+}
+
+// ToApiFeatureDefinition converts domain Feature to api.FeatureDefinition
+func ToApiFeatureDefinition(df feature.Feature) api.FeatureDefinition {
+	fields := make([]api.FeatureFieldRole, len(df.Fields))
+	for i, f := range df.Fields {
+		fields[i] = ToApiFeatureFieldRole(f)
+	}
+	options := make([]api.FeatureOption, len(df.Options))
+	for i, o := range df.Options {
+		options[i] = ToApiFeatureOption(o)
+	}
+	return api.FeatureDefinition{
+		Name:        df.Name,
+		DisplayName: df.DisplayName,
+		Description: df.Description,
+		Fields:      fields,
+		Options:     options,
+		MultiTheme:  df.MultiTheme,
+	}
+}
+
+// ToApiFeatureDefinitions converts a slice of domain Feature to api.FeatureDefinition
+func ToApiFeatureDefinitions(dfs []feature.Feature) []api.FeatureDefinition {
+	afs := make([]api.FeatureDefinition, len(dfs))
+	for i, df := range dfs {
+		afs[i] = ToApiFeatureDefinition(df)
+	}
+	return afs
+}
+
+// ToApiFeatureAlert converts domain Alert to api.FeatureAlert
+func ToApiFeatureAlert(da feature.Alert) api.FeatureAlert {
+	return api.FeatureAlert{
+		Feature: da.Feature,
+		Status:  api.FeatureAlertStatus(da.Status),
+		Message: da.Message,
+	}
+}
+
+// ToApiFeatureAlerts converts a slice of domain Alert to api.FeatureAlert
+func ToApiFeatureAlerts(das []feature.Alert) []api.FeatureAlert {
+	aas := make([]api.FeatureAlert, len(das))
+	for i, da := range das {
+		aas[i] = ToApiFeatureAlert(da)
+	}
+	return aas
+}
+
+// ToApiFeatureSnapshot converts domain Snapshot to api.FeatureSnapshot
+func ToApiFeatureSnapshot(ds feature.Snapshot) api.FeatureSnapshot {
+	return api.FeatureSnapshot{
+		ThemeId:     ds.ThemeID,
+		FeatureName: ds.FeatureName,
+		StartDate:   openapi_types.Date{Time: ds.Window.Start},
+		EndDate:     openapi_types.Date{Time: ds.Window.End},
+		CreatedAt:   ds.CreatedAt,
+		Result:      ds.Result,
+	}
+}
+
+// ToApiFeatureSnapshots converts a slice of domain Snapshot to api.FeatureSnapshot
+func ToApiFeatureSnapshots(dss []feature.Snapshot) []api.FeatureSnapshot {
+	ass := make([]api.FeatureSnapshot, len(dss))
+	for i, ds := range dss {
+		ass[i] = ToApiFeatureSnapshot(ds)
+	}
+	return ass
+}
@@ -0,0 +1,134 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+)
+
+// --- Table Converters ---
+
+// Media types a feature result can be exported as besides JSON.
+const (
+	MediaTypeCSV      = "text/csv"
+	MediaTypeVegaLite = "application/vnd.vegalite.v5+json"
+)
+
+// vegaLiteSchema is the $schema of the specifications returned by ToVegaLite.
+const vegaLiteSchema = "https://vega.github.io/schema/vega-lite/v5.json"
+
+// ToCSV encodes a feature table as CSV with a header row of column names.
+// Nulls are written as empty cells and numbers without exponents.
+func ToCSV(t *feature.Table) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	header := make([]string, len(t.Columns))
+	for i, col := range t.Columns {
+		header[i] = col.Name
+	}
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	record := make([]string, len(t.Columns))
+	for _, row := range t.Rows {
+		for i := range t.Columns {
+			record[i] = ""
+			if i < len(row) {
+				record[i] = formatCell(row[i])
+			}
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// formatCell formats a table value for CSV.
+func formatCell(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// ToVegaLite converts a feature table to a Vega-Lite v5 specification with the rows inlined as data.
+// The table's suggested chart is used if it has one; otherwise the first column is plotted
+// against the first numeric column after it, as a line over dates or as bars.
+func ToVegaLite(title string, t *feature.Table) map[string]interface{} {
+	types := make(map[string]feature.ColumnType, len(t.Columns))
+	for _, col := range t.Columns {
+		types[col.Name] = col.Type
+	}
+
+	chart := t.Chart
+	if chart == nil {
+		chart = defaultChart(t)
+	}
+	encoding := map[string]interface{}{}
+	if chart.X != "" {
+		encoding["x"] = vegaLiteField(chart.X, types[chart.X])
+	}
+	if chart.Y != "" {
+		encoding["y"] = vegaLiteField(chart.Y, types[chart.Y])
+	}
+	if chart.Color != "" {
+		encoding["color"] = vegaLiteField(chart.Color, types[chart.Color])
+	}
+
+	return map[string]interface{}{
+		"$schema":  vegaLiteSchema,
+		"title":    title,
+		"data":     map[string]interface{}{"values": t.Records()},
+		"mark":     chart.Mark,
+		"encoding": encoding,
+	}
+}
+
+// defaultChart picks a chart for a table without a suggested one.
+func defaultChart(t *feature.Table) *feature.Chart {
+	chart := &feature.Chart{Mark: "bar"}
+	if len(t.Columns) == 0 {
+		return chart
+	}
+	chart.X = t.Columns[0].Name
+	if t.Columns[0].Type == feature.ColumnTypeDate {
+		chart.Mark = "line"
+	}
+	for _, col := range t.Columns[1:] {
+		if col.Type == feature.ColumnTypeNumber {
+			chart.Y = col.Name
+			break
+		}
+	}
+	return chart
+}
+
+// vegaLiteField returns the Vega-Lite encoding of a column.
+func vegaLiteField(name string, t feature.ColumnType) map[string]interface{} {
+	field := map[string]interface{}{"field": name}
+	switch t {
+	case feature.ColumnTypeNumber:
+		field["type"] = "quantitative"
+	case feature.ColumnTypeDate:
+		field["type"] = "temporal"
+	default:
+		field["type"] = "nominal"
+	}
+	return field
+}
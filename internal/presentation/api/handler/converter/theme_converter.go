@@ -28,6 +28,38 @@ func FieldTypeFromApi(apiType api.ThemeFieldType) (theme.FieldType, error) {
 		return theme.FieldTypeTextarea, nil
 	case api.Select:
 		return theme.FieldTypeSelect, nil
+	case api.Duration:
+		return theme.FieldTypeDuration, nil
+	case api.Time:
+		return theme.FieldTypeTime, nil
+	case api.Money:
+		return theme.FieldTypeMoney, nil
+	case api.Rating:
+		return theme.FieldTypeRating, nil
+	case api.Url:
+		return theme.FieldTypeURL, nil
+	case api.Email:
+		return theme.FieldTypeEmail, nil
+	case api.Color:
+		return theme.FieldTypeColor, nil
+	case api.Geo:
+		return theme.FieldTypeGeo, nil
+	case api.Reference:
+		return theme.FieldTypeReference, nil
+	case api.Multiselect:
+		return theme.FieldTypeMultiSelect, nil
+	case api.Tags:
+		return theme.FieldTypeTags, nil
+	case api.ListText:
+		return theme.ListOf(theme.FieldTypeText), nil
+	case api.ListNumber:
+		return theme.ListOf(theme.FieldTypeNumber), nil
+	case api.ListBoolean:
+		return theme.ListOf(theme.FieldTypeBoolean), nil
+	case api.ListDate:
+		return theme.ListOf(theme.FieldTypeDate), nil
+	case api.ListDatetime:
+		return theme.ListOf(theme.FieldTypeDateTime), nil
 	default:
 		return "", fmt.Errorf("unknown API field type: %s", apiType)
 	}
@@ -50,6 +82,38 @@ func FieldTypeToApi(domainType theme.FieldType) (api.ThemeFieldType, error) {
 		return api.Textarea, nil
 	case theme.FieldTypeSelect:
 		return api.Select, nil
+	case theme.FieldTypeDuration:
+		return api.Duration, nil
+	case theme.FieldTypeTime:
+		return api.Time, nil
+	case theme.FieldTypeMoney:
+		return api.Money, nil
+	case theme.FieldTypeRating:
+		return api.Rating, nil
+	case theme.FieldTypeURL:
+		return api.Url, nil
+	case theme.FieldTypeEmail:
+		return api.Email, nil
+	case theme.FieldTypeColor:
+		return api.Color, nil
+	case theme.FieldTypeGeo:
+		return api.Geo, nil
+	case theme.FieldTypeReference:
+		return api.Reference, nil
+	case theme.FieldTypeMultiSelect:
+		return api.Multiselect, nil
+	case theme.FieldTypeTags:
+		return api.Tags, nil
+	case theme.ListOf(theme.FieldTypeText):
+		return api.ListText, nil
+	case theme.ListOf(theme.FieldTypeNumber):
+		return api.ListNumber, nil
+	case theme.ListOf(theme.FieldTypeBoolean):
+		return api.ListBoolean, nil
+	case theme.ListOf(theme.FieldTypeDate):
+		return api.ListDate, nil
+	case theme.ListOf(theme.FieldTypeDateTime):
+		return api.ListDatetime, nil
 	default:
 		return "", fmt.Errorf("unknown domain field type: %s", domainType)
 	}
@@ -65,12 +129,46 @@ func FromApiThemeField(af api.ThemeField) (theme.ThemeField, error) {
 	if af.Required != nil {
 		required = *af.Required
 	}
-	return theme.ThemeField{
-		Name:     af.Name,
-		Label:    af.Label,
-		Type:     domainType,
-		Required: required,
-	}, nil
+	df := theme.ThemeField{
+		Name:      af.Name,
+		Label:     af.Label,
+		Type:      domainType,
+		Required:  required,
+		MinLength: af.MinLength,
+		MaxLength: af.MaxLength,
+	}
+	if af.Options != nil {
+		df.Options = make([]theme.FieldOption, len(*af.Options))
+		for i, o := range *af.Options {
+			df.Options[i] = theme.FieldOption{Value: o.Value}
+			if o.Label != nil {
+				df.Options[i].Label = *o.Label
+			}
+			if o.Color != nil {
+				df.Options[i].Color = *o.Color
+			}
+		}
+	}
+	if af.Default != nil {
+		df.Default = *af.Default
+	}
+	if af.Min != nil {
+		df.Min = *af.Min
+	}
+	if af.Max != nil {
+		df.Max = *af.Max
+	}
+	if af.Pattern != nil {
+		df.Pattern = *af.Pattern
+	}
+	df.TargetThemeID = af.TargetThemeId
+	if af.OnDelete != nil {
+		df.OnDelete = theme.DeleteAction(*af.OnDelete)
+	}
+	if af.Formula != nil {
+		df.Formula = *af.Formula
+	}
+	return df, nil
 }
 
 // FromApiThemeFields converts a slice of api.ThemeField to domain ThemeField
@@ -93,12 +191,52 @@ func ToApiThemeField(df theme.ThemeField) (api.ThemeField, error) {
 		return api.ThemeField{}, err // Return api.ThemeField{} on error
 	}
 	required := df.Required // Copy bool value
-	return api.ThemeField{
-		Name:     df.Name,
-		Label:    df.Label,
-		Type:     apiType,
-		Required: &required, // Assign pointer to the copied value
-	}, nil
+	af := api.ThemeField{
+		Name:      df.Name,
+		Label:     df.Label,
+		Type:      apiType,
+		Required:  &required, // Assign pointer to the copied value
+		MinLength: df.MinLength,
+		MaxLength: df.MaxLength,
+	}
+	if len(df.Options) > 0 {
+		options := make([]api.ThemeFieldOption, len(df.Options))
+		for i, o := range df.Options {
+			options[i] = api.ThemeFieldOption{Value: o.Value}
+			if o.Label != "" {
+				label := o.Label
+				options[i].Label = &label
+			}
+			if o.Color != "" {
+				color := o.Color
+				options[i].Color = &color
+			}
+		}
+		af.Options = &options
+	}
+	if df.Default != nil {
+		af.Default = &df.Default
+	}
+	if df.Min != nil {
+		af.Min = &df.Min
+	}
+	if df.Max != nil {
+		af.Max = &df.Max
+	}
+	if df.Pattern != "" {
+		pattern := df.Pattern
+		af.Pattern = &pattern
+	}
+	af.TargetThemeId = df.TargetThemeID
+	if df.OnDelete != "" {
+		onDelete := api.ThemeFieldOnDelete(df.OnDelete)
+		af.OnDelete = &onDelete
+	}
+	if df.Formula != "" {
+		formula := df.Formula
+		af.Formula = &formula
+	}
+	return af, nil
 }
 
 // ToApiThemeFields converts a slice of domain ThemeField to api.ThemeField
@@ -114,6 +252,59 @@ func ToApiThemeFields(dfs []theme.ThemeField) ([]api.ThemeField, error) {
 	return afs, nil
 }
 
+// FromApiSupportedFeatures converts a slice of api.SupportedFeature to domain SupportedFeature
+func FromApiSupportedFeatures(afs []api.SupportedFeature) []theme.SupportedFeature {
+	dfs := make([]theme.SupportedFeature, len(afs))
+	for i, af := range afs {
+		df := theme.SupportedFeature{Name: af.Name}
+		if af.Config != nil {
+			if af.Config.Fields != nil {
+				df.Config.Fields = *af.Config.Fields
+			}
+			if af.Config.Options != nil {
+				df.Config.Options = *af.Config.Options
+			}
+		}
+		if af.Schedule != nil {
+			df.Schedule = &theme.FeatureSchedule{Cron: af.Schedule.Cron}
+			if af.Schedule.Period != nil {
+				df.Schedule.Period = string(*af.Schedule.Period)
+			}
+		}
+		dfs[i] = df
+	}
+	return dfs
+}
+
+// ToApiSupportedFeatures converts a slice of domain SupportedFeature to api.SupportedFeature
+func ToApiSupportedFeatures(dfs []theme.SupportedFeature) []api.SupportedFeature {
+	afs := make([]api.SupportedFeature, len(dfs))
+	for i, df := range dfs {
+		config := api.FeatureConfig{}
+		if df.Config.Fields != nil {
+			fields := make(map[string]string, len(df.Config.Fields))
+			for role, name := range df.Config.Fields {
+				fields[role] = name
+			}
+			config.Fields = &fields
+		}
+		if df.Config.Options != nil {
+			options := make(map[string]interface{}, len(df.Config.Options))
+			for key, value := range df.Config.Options {
+				options[key] = value
+			}
+			config.Options = &options
+		}
+		var schedule *api.FeatureSchedule
+		if df.Schedule != nil {
+			period := api.FeatureSchedulePeriod(df.Schedule.PeriodOrDefault())
+			schedule = &api.FeatureSchedule{Cron: df.Schedule.Cron, Period: &period}
+		}
+		afs[i] = api.SupportedFeature{Name: df.Name, Config: &config, Schedule: schedule}
+	}
+	return afs
+}
+
 // ToApiTheme converts internal Theme to API Theme
 func ToApiTheme(dt theme.Theme) (api.Theme, error) {
 	apiFields, err := ToApiThemeFields(dt.Fields)
@@ -125,16 +316,26 @@ func ToApiTheme(dt theme.Theme) (api.Theme, error) {
 	updatedAt := dt.UpdatedAt
 	themeID := dt.ThemeID
 	isDefault := dt.IsDefault
+	schemaVersion := dt.SchemaVersion
+	var revision *int
+	if dt.Revision > 0 {
+		r := dt.Revision
+		revision = &r
+	}
 	var ownerUserID *uuid.UUID
 	if dt.OwnerUserID != nil {
 		ownerUserID = dt.OwnerUserID // Copy pointer
 	}
+	var role *api.ThemeRole
+	if dt.Role != "" {
+		r := api.ThemeRole(dt.Role)
+		role = &r
+	}
 
-	var supportedFeatures *[]string
+	var supportedFeatures *[]api.SupportedFeature
 	if dt.SupportedFeatures != nil {
-		// Create a new slice and copy elements to avoid aliasing issues if dt.SupportedFeatures changes
-		tempFeatures := make([]string, len(dt.SupportedFeatures))
-		copy(tempFeatures, dt.SupportedFeatures)
+		// Copy features and their configs to avoid aliasing issues if dt.SupportedFeatures changes
+		tempFeatures := ToApiSupportedFeatures(dt.SupportedFeatures)
 		supportedFeatures = &tempFeatures
 	}
 
@@ -144,9 +345,13 @@ func ToApiTheme(dt theme.Theme) (api.Theme, error) {
 		Fields:            apiFields,
 		IsDefault:         &isDefault,
 		OwnerUserId:       ownerUserID,
+		Revision:          revision,
+		Role:              role,
+		SchemaVersion:     &schemaVersion,
 		SupportedFeatures: supportedFeatures,
 		CreatedAt:         &createdAt,
 		UpdatedAt:         &updatedAt,
+		UpdatedBy:         dt.UpdatedBy,
 	}, nil
 }
 
@@ -181,11 +386,11 @@ func FromApiCreateThemeRequest(req api.CreateThemeRequest, userID uuid.UUID) (th
 		return theme.Theme{}, fmt.Errorf("invalid theme fields in request: %w", err)
 	}
 
-	var supportedFeatures []string
+	var supportedFeatures []theme.SupportedFeature
 	if req.SupportedFeatures != nil {
-		supportedFeatures = *req.SupportedFeatures
+		supportedFeatures = FromApiSupportedFeatures(*req.SupportedFeatures)
 	} else {
-		supportedFeatures = []string{} // Default to empty slice
+		supportedFeatures = []theme.SupportedFeature{} // Default to empty slice
 	}
 
 	newTheme := theme.Theme{
@@ -202,15 +407,15 @@ func FromApiCreateThemeRequest(req api.CreateThemeRequest, userID uuid.UUID) (th
 
 // FromApiUpdateThemeRequest converts API UpdateThemeRequest to domain Theme
 // Requires existing theme to preserve fields not allowed to be updated.
-func FromApiUpdateThemeRequest(req api.UpdateThemeRequest, themeID uuid.UUID, userID uuid.UUID, existingTheme theme.Theme) (theme.Theme, error) {
+func FromApiUpdateThemeRequest(req api.UpdateThemeRequest, themeID uuid.UUID, existingTheme theme.Theme) (theme.Theme, error) {
 	domainFields, err := FromApiThemeFields(req.Fields)
 	if err != nil {
 		return theme.Theme{}, fmt.Errorf("invalid theme fields in request: %w", err)
 	}
 
-	var supportedFeatures []string
+	var supportedFeatures []theme.SupportedFeature
 	if req.SupportedFeatures != nil {
-		supportedFeatures = *req.SupportedFeatures
+		supportedFeatures = FromApiSupportedFeatures(*req.SupportedFeatures)
 	} else {
 		supportedFeatures = existingTheme.SupportedFeatures // Keep existing if not provided
 	}
@@ -219,11 +424,145 @@ func FromApiUpdateThemeRequest(req api.UpdateThemeRequest, themeID uuid.UUID, us
 		ThemeID:           themeID,
 		ThemeName:         req.ThemeName,
 		Fields:            domainFields,
-		IsDefault:         existingTheme.IsDefault, // Cannot change this flag via update
-		OwnerUserID:       &userID,                 // Should match existing owner
+		IsDefault:         existingTheme.IsDefault,   // Cannot change this flag via update
+		OwnerUserID:       existingTheme.OwnerUserID, // Editors update themes they do not own
 		SupportedFeatures: supportedFeatures,
 		CreatedAt:         existingTheme.CreatedAt, // Preserve original creation time
 		// UpdatedAt, PK, SK handled by repository
 	}
 	return updatedTheme, nil
 }
+
+// FromApiMigrationSteps converts the migration of an API UpdateThemeRequest to domain MigrationSteps.
+// The type of convert steps is filled in from the updated fields by the use case.
+func FromApiMigrationSteps(steps *[]api.ThemeMigrationStep) []theme.MigrationStep {
+	if steps == nil {
+		return nil
+	}
+	dss := make([]theme.MigrationStep, len(*steps))
+	for i, as := range *steps {
+		ds := theme.MigrationStep{Op: theme.MigrationOp(as.Op), Field: as.Field}
+		if as.To != nil {
+			ds.To = *as.To
+		}
+		if as.Value != nil {
+			ds.Value = *as.Value
+		}
+		dss[i] = ds
+	}
+	return dss
+}
+
+// ToApiThemeRevision converts a domain theme Revision to an API ThemeRevision
+func ToApiThemeRevision(dr theme.Revision) (api.ThemeRevision, error) {
+	apiFields, err := ToApiThemeFields(dr.Fields)
+	if err != nil {
+		return api.ThemeRevision{}, fmt.Errorf("error converting fields of revision %d of theme %s: %w", dr.Revision, dr.ThemeID, err)
+	}
+	var restoredFrom *int
+	if dr.RestoredFrom > 0 {
+		r := dr.RestoredFrom
+		restoredFrom = &r
+	}
+	return api.ThemeRevision{
+		Revision:          dr.Revision,
+		ThemeName:         dr.ThemeName,
+		Fields:            apiFields,
+		SupportedFeatures: ToApiSupportedFeatures(dr.SupportedFeatures),
+		SchemaVersion:     dr.SchemaVersion,
+		ChangedBy:         dr.ChangedBy,
+		ChangedAt:         dr.ChangedAt,
+		RestoredFrom:      restoredFrom,
+	}, nil
+}
+
+// ToApiThemeRevisions converts a slice of domain theme Revisions to API ThemeRevisions
+func ToApiThemeRevisions(drs []theme.Revision) ([]api.ThemeRevision, error) {
+	ars := make([]api.ThemeRevision, len(drs))
+	for i, dr := range drs {
+		ar, err := ToApiThemeRevision(dr)
+		if err != nil {
+			return nil, err
+		}
+		ars[i] = ar
+	}
+	return ars, nil
+}
+
+// ToApiThemeRevisionDiff converts a domain RevisionDiff to an API ThemeRevisionDiff.
+// Empty lists are returned as [] rather than null.
+func ToApiThemeRevisionDiff(dd theme.RevisionDiff) (api.ThemeRevisionDiff, error) {
+	added, err := ToApiThemeFields(dd.FieldsAdded)
+	if err != nil {
+		return api.ThemeRevisionDiff{}, err
+	}
+	removed, err := ToApiThemeFields(dd.FieldsRemoved)
+	if err != nil {
+		return api.ThemeRevisionDiff{}, err
+	}
+	changed := make([]api.ThemeFieldChange, len(dd.FieldsChanged))
+	for i, fc := range dd.FieldsChanged {
+		from, err := ToApiThemeField(fc.From)
+		if err != nil {
+			return api.ThemeRevisionDiff{}, err
+		}
+		to, err := ToApiThemeField(fc.To)
+		if err != nil {
+			return api.ThemeRevisionDiff{}, err
+		}
+		changed[i] = api.ThemeFieldChange{Name: fc.Name, Attributes: fc.Attributes, From: from, To: to}
+	}
+	var themeName *api.ThemeNameChange
+	if dd.ThemeName != nil {
+		themeName = &api.ThemeNameChange{From: dd.ThemeName.From, To: dd.ThemeName.To}
+	}
+	return api.ThemeRevisionDiff{
+		From:            dd.From,
+		To:              dd.To,
+		ThemeName:       themeName,
+		FieldsAdded:     added,
+		FieldsRemoved:   removed,
+		FieldsChanged:   changed,
+		FeaturesAdded:   stringsOrEmpty(dd.FeaturesAdded),
+		FeaturesRemoved: stringsOrEmpty(dd.FeaturesRemoved),
+		FeaturesChanged: stringsOrEmpty(dd.FeaturesChanged),
+	}, nil
+}
+
+// ToApiThemeInvitations converts domain Invitations to API ThemeInvitations.
+func ToApiThemeInvitations(dis []theme.Invitation) []api.ThemeInvitation {
+	ais := make([]api.ThemeInvitation, len(dis))
+	for i, di := range dis {
+		ais[i] = ToApiThemeInvitation(di)
+	}
+	return ais
+}
+
+// ToApiThemeInvitation converts a domain Invitation to an API ThemeInvitation.
+func ToApiThemeInvitation(di theme.Invitation) api.ThemeInvitation {
+	return api.ThemeInvitation{
+		ThemeId:   di.ThemeID,
+		ThemeName: di.ThemeName,
+		UserId:    di.UserID,
+		Role:      api.ThemeRole(di.Role),
+		InvitedBy: di.InvitedBy,
+		CreatedAt: di.CreatedAt,
+	}
+}
+
+// ToApiThemeMembers converts the UserThemeLinks of a theme's members to API ThemeMembers.
+func ToApiThemeMembers(links []theme.UserThemeLink) []api.ThemeMember {
+	members := make([]api.ThemeMember, len(links))
+	for i, link := range links {
+		members[i] = api.ThemeMember{UserId: link.UserID, Role: api.ThemeRole(link.Role)}
+	}
+	return members
+}
+
+// stringsOrEmpty returns an empty slice for nil so the list is encoded as [].
+func stringsOrEmpty(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,68 @@
+package converter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+	"github.com/soranjiro/axicalendar/internal/presentation/api"
+)
+
+func TestFromApiCreateThemeRequest_SupportedFeatures(t *testing.T) {
+	tests := []struct {
+		name     string
+		features string
+		want     []theme.SupportedFeature
+	}{
+		{
+			// Requests written before features were configurable list plain names
+			name:     "feature names",
+			features: `["summary"]`,
+			want:     []theme.SupportedFeature{{Name: "summary"}},
+		},
+		{
+			name:     "configured features",
+			features: `[{"name": "category_aggregation", "config": {"fields": {"category": "kind"}}}]`,
+			want: []theme.SupportedFeature{
+				{Name: "category_aggregation", Config: theme.FeatureConfig{Fields: map[string]string{"category": "kind"}}},
+			},
+		},
+		{
+			name:     "names and configured features mixed",
+			features: `["summary", {"name": "category_aggregation", "config": {}}]`,
+			want:     []theme.SupportedFeature{{Name: "summary"}, {Name: "category_aggregation"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `{"theme_name": "New Custom Theme", "fields": [{"name": "field1", "label": "Field 1", "type": "text"}], "supported_features": ` + tt.features + `}`
+			var req api.CreateThemeRequest
+			assert.NoError(t, json.Unmarshal([]byte(body), &req))
+
+			got, err := FromApiCreateThemeRequest(req, uuid.New())
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got.SupportedFeatures)
+		})
+	}
+}
+
+func TestFromApiUpdateThemeRequest_FeatureNames(t *testing.T) {
+	body := `{"theme_name": "Updated Name", "fields": [{"name": "new_field", "label": "New Field", "type": "boolean"}], "supported_features": ["feature1", "feature2"]}`
+	var req api.UpdateThemeRequest
+	assert.NoError(t, json.Unmarshal([]byte(body), &req))
+
+	got, err := FromApiUpdateThemeRequest(req, uuid.New(), theme.Theme{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []theme.SupportedFeature{{Name: "feature1"}, {Name: "feature2"}}, got.SupportedFeatures)
+}
+
+func TestSupportedFeature_UnmarshalJSON_Invalid(t *testing.T) {
+	var req api.CreateThemeRequest
+	assert.Error(t, json.Unmarshal([]byte(`{"supported_features": [42]}`), &req))
+}
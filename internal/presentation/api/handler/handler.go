@@ -2,12 +2,17 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/soranjiro/axicalendar/internal/domain/entry"
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
 	"github.com/soranjiro/axicalendar/internal/presentation/api"
 
 	"github.com/google/uuid"
@@ -183,9 +188,22 @@ func (h *ApiHandler) GetEntries(ctx echo.Context, params api.GetEntriesParams) e
 	themeID := params.ThemeId // This is already openapi_types.UUID which is compatible with uuid.UUID
 	startDate := params.StartDate.Time
 	endDate := params.EndDate.Time
+	filters, err := converter.FromApiContainsQuery(params.Contains)
+	if err != nil {
+		return newApiError(http.StatusBadRequest, "Invalid contains parameter", err)
+	}
+	bbox, err := converter.FromApiBboxQuery(params.Bbox)
+	if err != nil {
+		return newApiError(http.StatusBadRequest, "Invalid bbox parameter", err)
+	}
+	near, err := converter.FromApiNearQuery(params.Near)
+	if err != nil {
+		return newApiError(http.StatusBadRequest, "Invalid near parameter", err)
+	}
+	filters = append(append(filters, bbox...), near...)
 
 	// Call the use case method, which returns domain entries
-	domainEntries, err := h.useCase.GetEntries(ctx.Request().Context(), userID, themeID, startDate, endDate)
+	domainEntries, err := h.useCase.GetEntries(ctx.Request().Context(), userID, themeID, startDate, endDate, filters)
 	if err != nil {
 		var httpErr *echo.HTTPError
 		if errors.As(err, &httpErr) {
@@ -237,6 +255,7 @@ func (h *ApiHandler) PostEntries(ctx echo.Context) error {
 		log.Printf("Error converting created domain entry to API format: %v", err)
 		return newApiError(http.StatusInternalServerError, "Failed to format created entry response", err)
 	}
+	h.attachFeatureAlerts(ctx, &apiEntry, *createdDomainEntry)
 
 	return ctx.JSON(http.StatusCreated, apiEntry)
 }
@@ -286,6 +305,32 @@ func (h *ApiHandler) GetEntriesEntryId(ctx echo.Context, entryId openapi_types.U
 	return ctx.JSON(http.StatusOK, apiEntry)
 }
 
+// GetEntriesEntryIdBacklinks lists the entries whose reference fields point at an entry, with the referencing fields.
+func (h *ApiHandler) GetEntriesEntryIdBacklinks(ctx echo.Context, entryId openapi_types.UUID) error {
+	userID, err := GetUserIDFromContext(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	// Call the use case method, returns the referencing domain entries
+	backlinks, err := h.useCase.GetEntryBacklinks(ctx.Request().Context(), userID, entryId)
+	if err != nil {
+		var httpErr *echo.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr // Return the error directly from use case
+		}
+		return newApiError(http.StatusInternalServerError, "Failed to retrieve backlinks", err)
+	}
+
+	apiBacklinks, err := converter.ToApiBacklinks(backlinks)
+	if err != nil {
+		log.Printf("Error converting backlinks to API format: %v", err)
+		return newApiError(http.StatusInternalServerError, "Failed to format backlinks response", err)
+	}
+
+	return ctx.JSON(http.StatusOK, apiBacklinks)
+}
+
 func (h *ApiHandler) PutEntriesEntryId(ctx echo.Context, entryId openapi_types.UUID) error {
 	userID, err := GetUserIDFromContext(ctx.Request().Context())
 	if err != nil {
@@ -327,10 +372,25 @@ func (h *ApiHandler) PutEntriesEntryId(ctx echo.Context, entryId openapi_types.U
 		log.Printf("Error converting updated domain entry to API format: %v", err)
 		return newApiError(http.StatusInternalServerError, "Failed to format updated entry response", err)
 	}
+	h.attachFeatureAlerts(ctx, &apiEntry, *updatedDomainEntry)
 
 	return ctx.JSON(http.StatusOK, apiEntry)
 }
 
+// attachFeatureAlerts adds the alerts of the theme's alerting features to a written entry.
+// Alerts are best effort: failures are logged and the entry is returned without them.
+func (h *ApiHandler) attachFeatureAlerts(ctx echo.Context, apiEntry *api.Entry, de entry.Entry) {
+	alerts, err := h.useCase.GetFeatureAlerts(ctx.Request().Context(), de.UserID, de.ThemeID, de.EntryDate)
+	if err != nil {
+		log.Printf("WARN: Failed to compute feature alerts for entry %s: %v", de.EntryID, err)
+		return
+	}
+	if len(alerts) > 0 {
+		apiAlerts := converter.ToApiFeatureAlerts(alerts)
+		apiEntry.Alerts = &apiAlerts
+	}
+}
+
 // --- Theme Handlers ---
 
 func (h *ApiHandler) GetThemes(ctx echo.Context) error {
@@ -471,13 +531,14 @@ func (h *ApiHandler) PutThemesThemeId(ctx echo.Context, themeId openapi_types.UU
 	}
 
 	// 2. Convert API request to domain theme using the existing theme data
-	domainThemeUpdate, err := converter.FromApiUpdateThemeRequest(apiReq, themeId, userID, *existingDomainTheme)
+	domainThemeUpdate, err := converter.FromApiUpdateThemeRequest(apiReq, themeId, *existingDomainTheme)
 	if err != nil {
 		return newApiError(http.StatusBadRequest, "Invalid theme data format", err)
 	}
 
-	// 3. Call use case with the converted domain theme object
-	updatedDomainTheme, err := h.useCase.UpdateTheme(ctx.Request().Context(), userID, themeId, domainThemeUpdate) // Pass domain object
+	// 3. Call use case with the converted domain theme object and the migration of existing entries
+	migration := converter.FromApiMigrationSteps(apiReq.Migration)
+	updatedDomainTheme, err := h.useCase.UpdateTheme(ctx.Request().Context(), userID, themeId, domainThemeUpdate, migration)
 	if err != nil {
 		var httpErr *echo.HTTPError
 		if errors.As(err, &httpErr) {
@@ -496,15 +557,347 @@ func (h *ApiHandler) PutThemesThemeId(ctx echo.Context, themeId openapi_types.UU
 	return ctx.JSON(http.StatusOK, apiTheme)
 }
 
-// GetThemesThemeIdFeaturesFeatureName retrieves details about a specific feature supported by a theme.
-// Placeholder implementation.
-func (h *ApiHandler) GetThemesThemeIdFeaturesFeatureName(ctx echo.Context, themeId openapi_types.UUID, featureName string) error {
+// GetThemesThemeIdRevisions lists the revisions of a theme, newest first.
+func (h *ApiHandler) GetThemesThemeIdRevisions(ctx echo.Context, themeId openapi_types.UUID) error {
+	userID, err := GetUserIDFromContext(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	revisions, err := h.useCase.ListThemeRevisions(ctx.Request().Context(), userID, themeId)
+	if err != nil {
+		var httpErr *echo.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr // Return the error directly from use case
+		}
+		return newApiError(http.StatusInternalServerError, "Failed to retrieve theme revisions", err)
+	}
+
+	apiRevisions, err := converter.ToApiThemeRevisions(revisions)
+	if err != nil {
+		log.Printf("Error converting theme revisions to API format: %v", err)
+		return newApiError(http.StatusInternalServerError, "Failed to format theme revisions response", err)
+	}
+	return ctx.JSON(http.StatusOK, apiRevisions)
+}
+
+// GetThemesThemeIdRevisionsDiff compares two revisions of a theme.
+func (h *ApiHandler) GetThemesThemeIdRevisionsDiff(ctx echo.Context, themeId openapi_types.UUID, params api.GetThemesThemeIdRevisionsDiffParams) error {
+	userID, err := GetUserIDFromContext(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	diff, err := h.useCase.DiffThemeRevisions(ctx.Request().Context(), userID, themeId, params.From, params.To)
+	if err != nil {
+		var httpErr *echo.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr // Return the error directly from use case (e.g., 400, 404)
+		}
+		return newApiError(http.StatusInternalServerError, "Failed to compare theme revisions", err)
+	}
+
+	apiDiff, err := converter.ToApiThemeRevisionDiff(*diff)
+	if err != nil {
+		log.Printf("Error converting theme revision diff to API format: %v", err)
+		return newApiError(http.StatusInternalServerError, "Failed to format theme revision diff response", err)
+	}
+	return ctx.JSON(http.StatusOK, apiDiff)
+}
+
+// PostThemesThemeIdRevisionsRevisionRestore restores a custom theme to one of its revisions.
+func (h *ApiHandler) PostThemesThemeIdRevisionsRevisionRestore(ctx echo.Context, themeId openapi_types.UUID, revision int) error {
 	userID, err := GetUserIDFromContext(ctx.Request().Context())
 	if err != nil {
 		return err
 	}
 
-	log.Printf("GetThemesThemeIdFeaturesFeatureName called for ThemeID: %s, Feature: %s, UserID: %s (Not Implemented - Requires Feature Use Case)", themeId, featureName, userID)
+	// The body is optional; it only carries the migration of existing entries
+	var apiReq api.RestoreThemeRevisionRequest
+	if err := ctx.Bind(&apiReq); err != nil {
+		return newApiError(http.StatusBadRequest, "Invalid request body", err)
+	}
+
+	migration := converter.FromApiMigrationSteps(apiReq.Migration)
+	restoredTheme, err := h.useCase.RestoreThemeRevision(ctx.Request().Context(), userID, themeId, revision, migration)
+	if err != nil {
+		var httpErr *echo.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr // Return the error directly from use case (e.g., 400, 403, 404, 409)
+		}
+		return newApiError(http.StatusInternalServerError, "Failed to restore theme revision", err)
+	}
+
+	apiTheme, err := converter.ToApiTheme(*restoredTheme)
+	if err != nil {
+		log.Printf("Error converting restored domain theme to API format: %v", err)
+		return newApiError(http.StatusInternalServerError, "Failed to format restored theme response", err)
+	}
+	return ctx.JSON(http.StatusOK, apiTheme)
+}
+
+// --- Sharing Handlers ---
+
+// PostThemesThemeIdInvitations invites a user to a custom theme with a role.
+func (h *ApiHandler) PostThemesThemeIdInvitations(ctx echo.Context, themeId openapi_types.UUID) error {
+	userID, err := GetUserIDFromContext(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	var apiReq api.ThemeInvitationRequest
+	if err := ctx.Bind(&apiReq); err != nil {
+		return newApiError(http.StatusBadRequest, "Invalid request body", err)
+	}
+
+	invitation, err := h.useCase.InviteToTheme(ctx.Request().Context(), userID, themeId, apiReq.UserId, string(apiReq.Role))
+	if err != nil {
+		var httpErr *echo.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr // Return the error directly from use case (e.g., 400, 403, 404)
+		}
+		return newApiError(http.StatusInternalServerError, "Failed to create invitation", err)
+	}
+	return ctx.JSON(http.StatusCreated, converter.ToApiThemeInvitation(*invitation))
+}
+
+// GetThemesThemeIdMembers lists the members of a shared theme, owner first.
+func (h *ApiHandler) GetThemesThemeIdMembers(ctx echo.Context, themeId openapi_types.UUID) error {
+	userID, err := GetUserIDFromContext(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	members, err := h.useCase.ListThemeMembers(ctx.Request().Context(), userID, themeId)
+	if err != nil {
+		var httpErr *echo.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr // Return the error directly from use case (e.g., 400, 404)
+		}
+		return newApiError(http.StatusInternalServerError, "Failed to retrieve theme members", err)
+	}
+	return ctx.JSON(http.StatusOK, converter.ToApiThemeMembers(members))
+}
+
+// DeleteThemesThemeIdMembersUserId removes a member from a shared theme, or withdraws their invitation.
+func (h *ApiHandler) DeleteThemesThemeIdMembersUserId(ctx echo.Context, themeId openapi_types.UUID, userId openapi_types.UUID) error {
+	userID, err := GetUserIDFromContext(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	if err := h.useCase.RemoveThemeMember(ctx.Request().Context(), userID, themeId, userId); err != nil {
+		var httpErr *echo.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr // Return the error directly from use case (e.g., 400, 403, 404)
+		}
+		return newApiError(http.StatusInternalServerError, "Failed to remove theme member", err)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// GetInvitations lists the user's pending theme invitations.
+func (h *ApiHandler) GetInvitations(ctx echo.Context) error {
+	userID, err := GetUserIDFromContext(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	invitations, err := h.useCase.ListInvitations(ctx.Request().Context(), userID)
+	if err != nil {
+		var httpErr *echo.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr // Return the error directly from use case
+		}
+		return newApiError(http.StatusInternalServerError, "Failed to retrieve invitations", err)
+	}
+	return ctx.JSON(http.StatusOK, converter.ToApiThemeInvitations(invitations))
+}
+
+// PostInvitationsThemeIdAccept accepts an invitation and returns the shared theme.
+func (h *ApiHandler) PostInvitationsThemeIdAccept(ctx echo.Context, themeId openapi_types.UUID) error {
+	userID, err := GetUserIDFromContext(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	sharedTheme, err := h.useCase.AcceptInvitation(ctx.Request().Context(), userID, themeId)
+	if err != nil {
+		var httpErr *echo.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr // Return the error directly from use case (e.g., 404)
+		}
+		return newApiError(http.StatusInternalServerError, "Failed to accept invitation", err)
+	}
+
+	apiTheme, err := converter.ToApiTheme(*sharedTheme)
+	if err != nil {
+		log.Printf("Error converting shared domain theme to API format: %v", err)
+		return newApiError(http.StatusInternalServerError, "Failed to format theme response", err)
+	}
+	return ctx.JSON(http.StatusOK, apiTheme)
+}
+
+// DeleteInvitationsThemeId declines an invitation to a theme.
+func (h *ApiHandler) DeleteInvitationsThemeId(ctx echo.Context, themeId openapi_types.UUID) error {
+	userID, err := GetUserIDFromContext(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	if err := h.useCase.DeclineInvitation(ctx.Request().Context(), userID, themeId); err != nil {
+		var httpErr *echo.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		return newApiError(http.StatusInternalServerError, "Failed to decline invitation", err)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// --- Feature Handlers ---
+
+// GetFeatures lists the features that can be enabled on themes, with their field roles and options.
+func (h *ApiHandler) GetFeatures(ctx echo.Context) error {
+	if _, err := GetUserIDFromContext(ctx.Request().Context()); err != nil {
+		return err
+	}
+
+	// Call the use case method, returns domain features
+	features, err := h.useCase.ListFeatures(ctx.Request().Context())
+	if err != nil {
+		var httpErr *echo.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr // Return the error directly from use case
+		}
+		return newApiError(http.StatusInternalServerError, "Failed to retrieve features", err)
+	}
+
+	return ctx.JSON(http.StatusOK, converter.ToApiFeatureDefinitions(features))
+}
+
+// GetFeaturesFeatureNameResults executes a multi-theme feature over several themes and an optional date range.
+func (h *ApiHandler) GetFeaturesFeatureNameResults(ctx echo.Context, featureName string, params api.GetFeaturesFeatureNameResultsParams) error {
+	userID, err := GetUserIDFromContext(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	// Convert optional date params to domain types
+	var startDate, endDate *time.Time
+	if params.StartDate != nil {
+		startDate = &params.StartDate.Time
+	}
+	if params.EndDate != nil {
+		endDate = &params.EndDate.Time
+	}
+
+	// Call the use case method, returns the analysis result
+	result, err := h.useCase.ExecuteMultiThemeFeature(ctx.Request().Context(), userID, params.ThemeIds, featureName, startDate, endDate)
+	if err != nil {
+		var httpErr *echo.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr // Return the error directly from use case (e.g., 400, 403, 404)
+		}
+		return newApiError(http.StatusInternalServerError, fmt.Sprintf("Failed to execute feature '%s'", featureName), err)
+	}
+
+	return writeFeatureResult(ctx, featureName, result)
+}
+
+// GetThemesThemeIdFeaturesFeatureName executes a feature supported by a theme over an optional date range.
+func (h *ApiHandler) GetThemesThemeIdFeaturesFeatureName(ctx echo.Context, themeId openapi_types.UUID, featureName string, params api.GetThemesThemeIdFeaturesFeatureNameParams) error {
+	userID, err := GetUserIDFromContext(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	// Convert optional date params to domain types
+	var startDate, endDate *time.Time
+	if params.StartDate != nil {
+		startDate = &params.StartDate.Time
+	}
+	if params.EndDate != nil {
+		endDate = &params.EndDate.Time
+	}
+
+	// Call the use case method, returns the analysis result
+	result, err := h.useCase.ExecuteFeature(ctx.Request().Context(), userID, themeId, featureName, startDate, endDate)
+	if err != nil {
+		var httpErr *echo.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr // Return the error directly from use case (e.g., 400, 403, 404)
+		}
+		return newApiError(http.StatusInternalServerError, fmt.Sprintf("Failed to execute feature '%s'", featureName), err)
+	}
+
+	return writeFeatureResult(ctx, featureName, result)
+}
+
+// writeFeatureResult writes a feature result in the format asked for by the Accept header:
+// CSV or a Vega-Lite specification for features with a tabular result, and JSON otherwise.
+func writeFeatureResult(ctx echo.Context, featureName string, result feature.AnalysisResult) error {
+	format := negotiateFeatureFormat(ctx.Request().Header.Get(echo.HeaderAccept))
+	if format == echo.MIMEApplicationJSON {
+		return ctx.JSON(http.StatusOK, result)
+	}
+
+	table, ok, err := result.Table()
+	if err != nil {
+		return newApiError(http.StatusInternalServerError, fmt.Sprintf("Failed to export feature '%s'", featureName), err)
+	}
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotAcceptable, api.Error{Message: fmt.Sprintf("Feature '%s' has no tabular result to export as %s", featureName, format)})
+	}
+
+	if format == converter.MediaTypeCSV {
+		body, err := converter.ToCSV(table)
+		if err != nil {
+			return newApiError(http.StatusInternalServerError, fmt.Sprintf("Failed to export feature '%s'", featureName), err)
+		}
+		ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", featureName+".csv"))
+		return ctx.Blob(http.StatusOK, converter.MediaTypeCSV+"; charset=utf-8", body)
+	}
+	body, err := json.Marshal(converter.ToVegaLite(featureName, table))
+	if err != nil {
+		return newApiError(http.StatusInternalServerError, fmt.Sprintf("Failed to export feature '%s'", featureName), err)
+	}
+	return ctx.Blob(http.StatusOK, converter.MediaTypeVegaLite, body)
+}
+
+// negotiateFeatureFormat returns the first supported media type listed in an Accept header.
+// JSON is returned for anything else, including wildcards and a missing header.
+func negotiateFeatureFormat(accept string) string {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType := strings.ToLower(strings.TrimSpace(strings.SplitN(part, ";", 2)[0]))
+		switch mediaType {
+		case converter.MediaTypeCSV, converter.MediaTypeVegaLite:
+			return mediaType
+		case "application/vnd.vegalite+json":
+			return converter.MediaTypeVegaLite
+		case echo.MIMEApplicationJSON, "*/*":
+			return echo.MIMEApplicationJSON
+		}
+	}
+	return echo.MIMEApplicationJSON
+}
+
+// GetThemesThemeIdFeaturesFeatureNameSnapshots lists the stored results of a feature's scheduled runs.
+func (h *ApiHandler) GetThemesThemeIdFeaturesFeatureNameSnapshots(ctx echo.Context, themeId openapi_types.UUID, featureName string) error {
+	userID, err := GetUserIDFromContext(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	// Call the use case method, returns domain snapshots
+	snapshots, err := h.useCase.ListFeatureSnapshots(ctx.Request().Context(), userID, themeId, featureName)
+	if err != nil {
+		var httpErr *echo.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr // Return the error directly from use case
+		}
+		return newApiError(http.StatusInternalServerError, fmt.Sprintf("Failed to retrieve snapshots of feature '%s'", featureName), err)
+	}
 
-	return newApiError(http.StatusNotImplemented, fmt.Sprintf("Feature '%s' details not implemented for theme '%s'", featureName, themeId), nil)
+	return ctx.JSON(http.StatusOK, converter.ToApiFeatureSnapshots(snapshots))
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/google/uuid"
 	"github.com/soranjiro/axicalendar/internal/domain/entry"
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
 	"github.com/soranjiro/axicalendar/internal/domain/theme"
 	"github.com/soranjiro/axicalendar/internal/domain/user"
 	"time"
@@ -17,13 +18,15 @@ type UseCase interface {
 	// Accepts domain entry, returns domain entry
 	CreateEntry(ctx context.Context, newEntry entry.Entry) (*entry.Entry, error)
 	// Accepts IDs and date range, returns domain entries
-	GetEntries(ctx context.Context, userID uuid.UUID, themeID uuid.UUID, startDate time.Time, endDate time.Time) ([]entry.Entry, error)
+	GetEntries(ctx context.Context, userID uuid.UUID, themeID uuid.UUID, startDate time.Time, endDate time.Time, filters []entry.Filter) ([]entry.Entry, error)
 	// Accepts IDs, returns domain entry
 	GetEntryByID(ctx context.Context, userID uuid.UUID, entryID uuid.UUID) (*entry.Entry, error)
 	// Accepts IDs and domain entry, returns domain entry
 	UpdateEntry(ctx context.Context, userID uuid.UUID, entryID uuid.UUID, updatedEntry entry.Entry) (*entry.Entry, error)
 	// Accepts IDs
 	DeleteEntry(ctx context.Context, userID uuid.UUID, entryID uuid.UUID) error
+	// Accepts IDs, returns the domain entries referencing the entry with their referencing fields
+	GetEntryBacklinks(ctx context.Context, userID uuid.UUID, entryID uuid.UUID) ([]entry.Backlink, error)
 
 	// Themes
 	// Accepts domain theme, returns domain theme
@@ -33,7 +36,39 @@ type UseCase interface {
 	// Accepts IDs, returns domain theme
 	GetThemeByID(ctx context.Context, userID uuid.UUID, themeID uuid.UUID) (*theme.Theme, error)
 	// Accepts IDs and domain theme, returns domain theme
-	UpdateTheme(ctx context.Context, userID uuid.UUID, themeID uuid.UUID, updatedThemeData theme.Theme) (*theme.Theme, error)
+	UpdateTheme(ctx context.Context, userID uuid.UUID, themeID uuid.UUID, updatedThemeData theme.Theme, migration []theme.MigrationStep) (*theme.Theme, error)
 	// Accepts IDs
 	DeleteTheme(ctx context.Context, userID uuid.UUID, themeID uuid.UUID) error
+	// Accepts IDs, returns the theme's revisions, newest first
+	ListThemeRevisions(ctx context.Context, userID uuid.UUID, themeID uuid.UUID) ([]theme.Revision, error)
+	// Accepts IDs and two revision numbers, returns the changes between them
+	DiffThemeRevisions(ctx context.Context, userID uuid.UUID, themeID uuid.UUID, from, to int) (*theme.RevisionDiff, error)
+	// Accepts IDs, a revision number and the migration of existing entries, returns the restored domain theme
+	RestoreThemeRevision(ctx context.Context, userID uuid.UUID, themeID uuid.UUID, revision int, migration []theme.MigrationStep) (*theme.Theme, error)
+
+	// Sharing
+	// Accepts IDs, the invitee's ID and a role, returns the pending invitation
+	InviteToTheme(ctx context.Context, userID uuid.UUID, themeID uuid.UUID, inviteeID uuid.UUID, role string) (*theme.Invitation, error)
+	// Accepts ID, returns the user's pending invitations
+	ListInvitations(ctx context.Context, userID uuid.UUID) ([]theme.Invitation, error)
+	// Accepts IDs, returns the shared domain theme
+	AcceptInvitation(ctx context.Context, userID uuid.UUID, themeID uuid.UUID) (*theme.Theme, error)
+	// Accepts IDs
+	DeclineInvitation(ctx context.Context, userID uuid.UUID, themeID uuid.UUID) error
+	// Accepts IDs, returns the theme's members, owner first
+	ListThemeMembers(ctx context.Context, userID uuid.UUID, themeID uuid.UUID) ([]theme.UserThemeLink, error)
+	// Accepts IDs and the member's ID
+	RemoveThemeMember(ctx context.Context, userID uuid.UUID, themeID uuid.UUID, memberID uuid.UUID) error
+
+	// Features
+	// Returns the metadata of every feature that can be enabled on themes
+	ListFeatures(ctx context.Context) ([]feature.Feature, error)
+	// Accepts IDs, feature name and optional date range, returns the analysis result
+	ExecuteFeature(ctx context.Context, userID uuid.UUID, themeID uuid.UUID, featureName string, startDate, endDate *time.Time) (feature.AnalysisResult, error)
+	// Accepts IDs of several themes, feature name and optional date range, returns the analysis result
+	ExecuteMultiThemeFeature(ctx context.Context, userID uuid.UUID, themeIDs []uuid.UUID, featureName string, startDate, endDate *time.Time) (feature.AnalysisResult, error)
+	// Accepts IDs and feature name, returns the stored results of the feature's scheduled runs
+	ListFeatureSnapshots(ctx context.Context, userID uuid.UUID, themeID uuid.UUID, featureName string) ([]feature.Snapshot, error)
+	// Accepts IDs and the date of a written entry, returns the alerts of the theme's alerting features for that month
+	GetFeatureAlerts(ctx context.Context, userID uuid.UUID, themeID uuid.UUID, entryDate string) ([]feature.Alert, error)
 }
@@ -0,0 +1,15 @@
+package api
+
+import "encoding/json"
+
+// UnmarshalJSON decodes a supported feature. Besides the object form, it accepts the plain feature name
+// supported_features held before features were configurable, as {"name": <name>, "config": {}}.
+func (f *SupportedFeature) UnmarshalJSON(b []byte) error {
+	var name string
+	if err := json.Unmarshal(b, &name); err == nil {
+		*f = SupportedFeature{Name: name, Config: &FeatureConfig{}}
+		return nil
+	}
+	type object SupportedFeature // Drops this method, so the generated fields decode as usual
+	return json.Unmarshal(b, (*object)(f))
+}
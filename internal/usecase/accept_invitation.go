@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/soranjiro/axicalendar/internal/domain"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+	"github.com/soranjiro/axicalendar/internal/presentation/api"
+)
+
+// AcceptInvitation handles the logic for accepting an invitation to a theme.
+// The user becomes a member with the invited role. Returns the theme as the user now sees it.
+func (uc *UseCase) AcceptInvitation(ctx context.Context, userID uuid.UUID, themeID uuid.UUID) (*theme.Theme, error) {
+	if _, err := uc.themeRepo.AcceptThemeInvitation(ctx, userID, themeID); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, echo.NewHTTPError(http.StatusNotFound, api.Error{Message: "Invitation not found"})
+		}
+		log.Printf("Error accepting invitation of user %s to theme %s: %v", userID, themeID, err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to accept invitation"})
+	}
+
+	th, err := uc.themeRepo.GetThemeByID(ctx, userID, themeID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			// The theme was deleted after the invitation was sent; drop the link again
+			if err := uc.themeRepo.RemoveUserThemeLink(ctx, userID, themeID); err != nil {
+				log.Printf("WARN: Failed to remove link of user %s to deleted theme %s: %v", userID, themeID, err)
+			}
+			return nil, echo.NewHTTPError(http.StatusNotFound, api.Error{Message: "Theme no longer exists"})
+		}
+		log.Printf("Error retrieving theme %s after accepting invitation: %v", themeID, err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to retrieve theme"})
+	}
+	// The new member's entries now count towards the theme's features
+	uc.invalidateThemeFeatureResults(ctx, th)
+	return th, nil
+}
@@ -32,11 +32,24 @@ func (uc *UseCase) CreateEntry(ctx context.Context, newEntry entry.Entry) (*entr
 		log.Printf("Error validating theme %s for user %s: %v", themeID, userID, err)
 		return nil, echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to validate theme"})
 	}
+	if !th.Role.CanAddEntries() {
+		return nil, echo.NewHTTPError(http.StatusForbidden, api.Error{Message: "Viewers cannot add entries to the theme"})
+	}
 
-	// 2. Validate data against theme fields using domain method
+	// 2. Fill in field defaults and computed fields, then validate data against theme fields using domain methods
+	newEntry.ApplyDefaults(th.Fields)
+	if err := computeFields(ctx, &newEntry, th.Fields); err != nil {
+		return nil, err
+	}
 	if err := newEntry.ValidateDataAgainstTheme(th.Fields); err != nil {
 		return nil, echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: fmt.Sprintf("Entry data validation failed: %v", err)})
 	}
+	// Referenced entries must exist, belong to the user and match the fields' target themes
+	if err := uc.checkReferences(ctx, &newEntry, th.Fields); err != nil {
+		return nil, err
+	}
+	newEntry.IndexGeohashes(th.Fields)
+	newEntry.SchemaVersion = th.SchemaVersion
 
 	// 3. Domain entry object is already prepared (passed as argument)
 	// Ensure EntryID is set (should be done by converter or here)
@@ -54,6 +67,7 @@ func (uc *UseCase) CreateEntry(ctx context.Context, newEntry entry.Entry) (*entr
 		log.Printf("Error creating entry in repository for user %s: %v", userID, err)
 		return nil, echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to create entry"})
 	}
+	uc.invalidateFeatureResults(ctx, userID, th, newEntry.EntryDate)
 
 	// 5. Fetch the created entry to return the full object with timestamps
 	createdEntry, err := uc.entryRepo.GetEntryByID(ctx, userID, newEntry.EntryID)
@@ -9,6 +9,8 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+	"github.com/soranjiro/axicalendar/internal/domain/formula"
 	"github.com/soranjiro/axicalendar/internal/domain/theme"
 	"github.com/soranjiro/axicalendar/internal/presentation/api"
 )
@@ -20,6 +22,20 @@ func (uc *UseCase) CreateTheme(ctx context.Context, newTheme theme.Theme) (*them
 	if err := newTheme.Validate(); err != nil {
 		return nil, echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: fmt.Sprintf("Theme validation failed: %v", err)})
 	}
+	// Supported features must be registered and their field requirements met by the theme
+	if err := feature.ValidateSupportedFeatures(uc.featureRegistry, newTheme); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: fmt.Sprintf("Theme validation failed: %v", err)})
+	}
+	// Formulas of computed fields must type-check against the theme's other fields
+	if _, err := formula.CompileComputedFields(newTheme.Fields); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: fmt.Sprintf("Theme validation failed: %v", err)})
+	}
+	// Reference fields can only target themes the user can access
+	if newTheme.OwnerUserID != nil {
+		if err := uc.checkReferenceTargetThemes(ctx, *newTheme.OwnerUserID, newTheme.Fields); err != nil {
+			return nil, err
+		}
+	}
 
 	// 2. Ensure OwnerUserID is set (should be set by converter/handler)
 	if newTheme.OwnerUserID == nil || *newTheme.OwnerUserID == uuid.Nil {
@@ -33,8 +49,10 @@ func (uc *UseCase) CreateTheme(ctx context.Context, newTheme theme.Theme) (*them
 		newTheme.ThemeID = uuid.New()
 	}
 
-	// 4. Ensure IsDefault is false for user-created themes
+	// 4. Ensure IsDefault is false for user-created themes, and start the schema history
 	newTheme.IsDefault = false
+	newTheme.SchemaVersion = theme.InitialSchemaVersion
+	newTheme.Migrations = nil
 
 	// 5. Call repository to create theme
 	if err := uc.themeRepo.CreateTheme(ctx, &newTheme); err != nil {
@@ -0,0 +1,21 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/soranjiro/axicalendar/internal/presentation/api"
+)
+
+// DeclineInvitation handles the logic for declining an invitation to a theme.
+// Declining an invitation that does not exist is not an error.
+func (uc *UseCase) DeclineInvitation(ctx context.Context, userID uuid.UUID, themeID uuid.UUID) error {
+	if err := uc.themeRepo.DeleteThemeInvitation(ctx, userID, themeID); err != nil {
+		log.Printf("Error declining invitation of user %s to theme %s: %v", userID, themeID, err)
+		return echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to decline invitation"})
+	}
+	return nil // Success indicates no content (204)
+}
@@ -13,9 +13,12 @@ import (
 )
 
 // DeleteEntry handles the logic for deleting an entry.
+// Editors of a shared theme can also delete the entries other members wrote.
+// Entries referencing it are handled by their reference fields' on_delete action;
+// a blocking reference fails the deletion with 409 Conflict.
 func (uc *UseCase) DeleteEntry(ctx context.Context, userID uuid.UUID, entryID uuid.UUID) error {
 	// Need EntryDate to delete. Get the entry first.
-	e, err := uc.entryRepo.GetEntryByID(ctx, userID, entryID)
+	e, err := uc.findAccessibleEntry(ctx, userID, entryID)
 	if err != nil {
 		if errors.Is(err, domain.ErrEntryNotFound) {
 			return echo.NewHTTPError(http.StatusNotFound, api.Error{Message: "Entry not found"})
@@ -25,15 +28,25 @@ func (uc *UseCase) DeleteEntry(ctx context.Context, userID uuid.UUID, entryID uu
 		return echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to retrieve entry before delete"})
 	}
 
-	// Now delete using the retrieved date
-	err = uc.entryRepo.DeleteEntry(ctx, userID, entryID, e.EntryDate)
+	// Only members who can add entries to the theme can delete theirs; viewers and removed members cannot
+	th, err := uc.getAccessibleTheme(ctx, userID, e.ThemeID)
 	if err != nil {
-		if errors.Is(err, domain.ErrEntryNotFound) { // Should not happen if GetEntryByID succeeded, but check anyway
-			return echo.NewHTTPError(http.StatusNotFound, api.Error{Message: "Entry not found during delete attempt"})
-		}
-		// Log internal error if needed
-		log.Printf("Error deleting entry from repository: %v", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to delete entry"})
+		return err
+	}
+	if !th.Role.CanAddEntries() {
+		return echo.NewHTTPError(http.StatusForbidden, api.Error{Message: "Viewers cannot delete entries of the theme"})
+	}
+	if !canChangeEntry(userID, th.Role, e) {
+		return echo.NewHTTPError(http.StatusForbidden, api.Error{Message: "Only editors can delete entries of other members"})
+	}
+
+	// Follow the on_delete action of every reference to the entry: block, nullify or cascade
+	plan, err := uc.planEntryDeletion(ctx, *e)
+	if err != nil {
+		return err
+	}
+	if err := plan.apply(ctx, uc); err != nil {
+		return err
 	}
 
 	return nil // Success indicates no content (204)
@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+	"github.com/soranjiro/axicalendar/internal/presentation/api"
+)
+
+// DiffThemeRevisions handles the logic for comparing two revisions of a theme.
+// Either order is allowed; the diff describes the change from the revision from to the revision to.
+func (uc *UseCase) DiffThemeRevisions(ctx context.Context, userID uuid.UUID, themeID uuid.UUID, from, to int) (*theme.RevisionDiff, error) {
+	if from < theme.InitialRevision || to < theme.InitialRevision {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: "Revision numbers start at 1"})
+	}
+	if err := uc.checkThemeAccess(ctx, userID, themeID); err != nil {
+		return nil, err
+	}
+	fromRev, err := uc.getThemeRevision(ctx, themeID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRev, err := uc.getThemeRevision(ctx, themeID, to)
+	if err != nil {
+		return nil, err
+	}
+	diff := theme.DiffRevisions(fromRev, toRev)
+	return &diff, nil
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/soranjiro/axicalendar/internal/domain"
+	"github.com/soranjiro/axicalendar/internal/domain/entry"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+// findAccessibleEntry looks up an entry the user can open: one they wrote, or one another member wrote
+// in a theme shared with them. Entries are stored per writer, so the user's own partition is searched
+// first, then those of the other members of each theme the user is linked to.
+// Returns domain.ErrEntryNotFound if there is no such entry.
+func (uc *UseCase) findAccessibleEntry(ctx context.Context, userID uuid.UUID, entryID uuid.UUID) (*entry.Entry, error) {
+	e, err := uc.entryRepo.GetEntryByID(ctx, userID, entryID)
+	if !errors.Is(err, domain.ErrEntryNotFound) {
+		return e, err
+	}
+
+	links, err := uc.themeRepo.ListUserThemes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	sharedBy := make(map[uuid.UUID][]uuid.UUID) // Other member -> themes they share with the user
+	var writers []uuid.UUID
+	for _, link := range links {
+		th, err := uc.themeRepo.GetThemeByID(ctx, userID, link.ThemeID)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrThemeNotFound) || errors.Is(err, domain.ErrForbidden) {
+				continue // Pending invitation, or a theme deleted since
+			}
+			return nil, err
+		}
+		members, err := themeMembers(ctx, uc.themeRepo, th)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range members {
+			if m.UserID == userID {
+				continue
+			}
+			if _, seen := sharedBy[m.UserID]; !seen {
+				writers = append(writers, m.UserID)
+			}
+			sharedBy[m.UserID] = append(sharedBy[m.UserID], th.ThemeID)
+		}
+	}
+
+	for _, writer := range writers {
+		e, err := uc.entryRepo.GetEntryByID(ctx, writer, entryID)
+		if errors.Is(err, domain.ErrEntryNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		// The writer's entries of themes not shared with the user stay hidden
+		for _, themeID := range sharedBy[writer] {
+			if e.ThemeID == themeID {
+				return e, nil
+			}
+		}
+		return nil, domain.ErrEntryNotFound
+	}
+	return nil, domain.ErrEntryNotFound
+}
+
+// canChangeEntry reports whether a user with the role on its theme can update or delete the entry:
+// members who can add entries change their own, and editors (and the owner) those of every member.
+func canChangeEntry(userID uuid.UUID, role theme.Role, e *entry.Entry) bool {
+	if e.UserID == userID {
+		return role.CanAddEntries()
+	}
+	return role.CanEditTheme()
+}
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/soranjiro/axicalendar/internal/domain/entry"
+	"github.com/soranjiro/axicalendar/internal/domain/formula"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+	"github.com/soranjiro/axicalendar/internal/presentation/api"
+)
+
+// computeFields rejects client-written values of computed fields, then evaluates each computed
+// field from the entry's other fields so the value is stored with the entry.
+func computeFields(ctx context.Context, e *entry.Entry, fields []theme.ThemeField) error {
+	if err := e.RejectComputedValues(fields); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: fmt.Sprintf("Entry data validation failed: %v", err)})
+	}
+	if e.Data == nil {
+		e.Data = make(map[string]interface{})
+	}
+	if err := formula.ComputeFields(ctx, fields, e.Data, e.EntryDate); err != nil {
+		if errors.Is(err, formula.ErrLimitExceeded) {
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, api.Error{Message: fmt.Sprintf("Computed field was stopped: %v", err)})
+		}
+		log.Printf("Error computing fields of entry %s: %v", e.EntryID, err)
+		return echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to compute fields"})
+	}
+	return nil
+}
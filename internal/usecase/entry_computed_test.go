@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/soranjiro/axicalendar/internal/domain/entry"
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+func TestUpdateEntry_ComputedFields(t *testing.T) {
+	userID := uuid.New()
+	orders := &theme.Theme{ThemeID: uuid.New(), Fields: []theme.ThemeField{
+		{Name: "price", Type: theme.FieldTypeNumber},
+		{Name: "qty", Type: theme.FieldTypeNumber},
+		{Name: "total", Type: theme.FieldTypeNumber, Formula: "price * qty"},
+	}}
+	order := entry.Entry{EntryID: uuid.New(), UserID: userID, ThemeID: orders.ThemeID, EntryDate: "2025-05-01",
+		Data: map[string]interface{}{"price": 2.0, "qty": 1.0, "total": 2.0}}
+	entries := &memoryEntryRepo{entries: map[uuid.UUID]entry.Entry{order.EntryID: order}}
+	themes := &memoryThemeRepo{themes: map[uuid.UUID]*theme.Theme{orders.ThemeID: orders}}
+	uc := NewUseCase(themes, entries, nil, feature.NewInMemoryResultCache(time.Minute), nil)
+
+	// Computed values are evaluated and stored with the entry
+	updated, err := uc.UpdateEntry(context.Background(), userID, order.EntryID, entry.Entry{EntryDate: "2025-05-01",
+		Data: map[string]interface{}{"price": 2.5, "qty": 4.0}})
+	assert.NoError(t, err)
+	assert.Equal(t, 10.0, updated.Data["total"])
+	assert.Equal(t, 10.0, entries.entries[order.EntryID].Data["total"])
+
+	// Clients cannot write them
+	_, err = uc.UpdateEntry(context.Background(), userID, order.EntryID, entry.Entry{EntryDate: "2025-05-01",
+		Data: map[string]interface{}{"price": 2.5, "qty": 4.0, "total": 1.0}})
+	var httpErr *echo.HTTPError
+	assert.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+}
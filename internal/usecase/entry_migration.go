@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/soranjiro/axicalendar/internal/domain/entry"
+	"github.com/soranjiro/axicalendar/internal/domain/formula"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+// migrationBatchSize is the number of migrated entries written at a time.
+const migrationBatchSize = 100
+
+// migrateThemeEntries rewrites the theme's entries written with an older schema version (see migrateEntry).
+// Entries are written in batches; if the pass fails part way, the remaining entries keep their old version,
+// so they are still migrated on read and picked up by the next pass. Entries deleted or rewritten by their
+// user while the pass runs are skipped.
+func (uc *UseCase) migrateThemeEntries(ctx context.Context, userID uuid.UUID, th *theme.Theme) error {
+	if th.SchemaVersion == 0 {
+		return nil // Never versioned, so nothing can be stale
+	}
+	stale, err := uc.entryRepo.ListEntriesBelowSchemaVersion(ctx, userID, th.ThemeID, th.SchemaVersion)
+	if err != nil {
+		return err
+	}
+	migrated := 0
+	for start := 0; start < len(stale); start += migrationBatchSize {
+		end := start + migrationBatchSize
+		if end > len(stale) {
+			end = len(stale)
+		}
+		batch := stale[start:end]
+		dates := make([]string, len(batch))
+		for i := range batch {
+			migrateEntry(ctx, &batch[i], th)
+			dates[i] = batch[i].EntryDate
+		}
+		written, err := uc.entryRepo.PutMigratedEntries(ctx, batch)
+		if err != nil {
+			return fmt.Errorf("writing entries %d to %d of %d: %w", start+1, end, len(stale), err)
+		}
+		migrated += written
+		uc.invalidateFeatureResults(ctx, userID, th, dates...)
+	}
+	if migrated > 0 {
+		log.Printf("Migrated %d entries of theme %s to schema version %d", migrated, th.ThemeID, th.SchemaVersion)
+	}
+	return nil
+}
+
+// migrateMemberEntries migrates the entries each member of a custom theme wrote (see migrateThemeEntries).
+func (uc *UseCase) migrateMemberEntries(ctx context.Context, th *theme.Theme) error {
+	members, err := themeMembers(ctx, uc.themeRepo, th)
+	if err != nil {
+		return err
+	}
+	for _, m := range members {
+		if err := uc.migrateThemeEntries(ctx, m.UserID, th); err != nil {
+			return fmt.Errorf("entries of user %s: %w", m.UserID, err)
+		}
+	}
+	return nil
+}
+
+// migrateEntry brings an entry written with an older schema version up to the theme's,
+// recomputing its computed fields and rebuilding its reference and location indexes.
+// Entries already at the theme's version are left as they are.
+func migrateEntry(ctx context.Context, e *entry.Entry, th *theme.Theme) {
+	if !e.Migrate(th) {
+		return
+	}
+	if err := formula.ComputeFields(ctx, th.Fields, e.Data, e.EntryDate); err != nil {
+		log.Printf("WARN: Failed to recompute fields of migrated entry %s: %v", e.EntryID, err)
+	}
+	e.IndexReferences(th.Fields)
+	e.IndexGeohashes(th.Fields)
+}
+
+// migrateOnRead migrates entries the eager pass has not reached yet in memory,
+// so readers see data matching their theme's current fields.
+func (uc *UseCase) migrateOnRead(ctx context.Context, userID uuid.UUID, entries []entry.Entry) {
+	themes := uc.newThemeLookup(userID)
+	for i := range entries {
+		th, err := themes.get(ctx, entries[i].ThemeID)
+		if err != nil {
+			log.Printf("WARN: Failed to load theme %s to migrate entry %s on read: %v", entries[i].ThemeID, entries[i].EntryID, err)
+			continue
+		}
+		migrateEntry(ctx, &entries[i], th)
+	}
+}
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/soranjiro/axicalendar/internal/domain"
+	"github.com/soranjiro/axicalendar/internal/domain/entry"
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+func (r *memoryThemeRepo) UpdateTheme(ctx context.Context, th *theme.Theme) error {
+	if err := r.PutThemeRevision(ctx, &theme.Revision{ThemeID: th.ThemeID, Revision: th.Revision}); err != nil {
+		return domain.ErrConflict
+	}
+	r.revisions[len(r.revisions)-1] = th.NewRevision()
+	updated := *th
+	r.themes[th.ThemeID] = &updated
+	return nil
+}
+
+func (r *memoryEntryRepo) ListEntriesBelowSchemaVersion(ctx context.Context, userID, themeID uuid.UUID, version int) ([]entry.Entry, error) {
+	var stale []entry.Entry
+	for _, e := range r.entries {
+		if e.UserID == userID && e.ThemeID == themeID && e.SchemaVersion < version {
+			stale = append(stale, e)
+		}
+	}
+	return stale, nil
+}
+
+func (r *memoryEntryRepo) PutMigratedEntries(ctx context.Context, entries []entry.Entry) (int, error) {
+	written := 0
+	for _, e := range entries {
+		if stored, ok := r.entries[e.EntryID]; ok && stored.SchemaVersion < e.SchemaVersion {
+			r.entries[e.EntryID] = e
+			written++
+		}
+	}
+	return written, nil
+}
+
+// deletingEntryRepo deletes an entry right after the stale entries are listed,
+// as if its user deleted it while a migration pass was running.
+type deletingEntryRepo struct {
+	*memoryEntryRepo
+	deleteID uuid.UUID
+}
+
+func (r *deletingEntryRepo) ListEntriesBelowSchemaVersion(ctx context.Context, userID, themeID uuid.UUID, version int) ([]entry.Entry, error) {
+	stale, err := r.memoryEntryRepo.ListEntriesBelowSchemaVersion(ctx, userID, themeID, version)
+	delete(r.entries, r.deleteID)
+	return stale, err
+}
+
+func TestUpdateTheme_MigratesEntries(t *testing.T) {
+	userID := uuid.New()
+	th := &theme.Theme{ThemeID: uuid.New(), ThemeName: "Expenses", OwnerUserID: &userID, SchemaVersion: 1, Fields: []theme.ThemeField{
+		{Name: "note", Label: "Note", Type: theme.FieldTypeText},
+		{Name: "amount", Label: "Amount", Type: theme.FieldTypeText},
+	}}
+	stored := entry.Entry{EntryID: uuid.New(), UserID: userID, ThemeID: th.ThemeID, EntryDate: "2025-01-01", SchemaVersion: 1,
+		Data: map[string]interface{}{"note": "lunch", "amount": "1200"}}
+	entries := &memoryEntryRepo{entries: map[uuid.UUID]entry.Entry{stored.EntryID: stored}}
+	themes := &memoryThemeRepo{themes: map[uuid.UUID]*theme.Theme{th.ThemeID: th}}
+	uc := NewUseCase(themes, entries, nil, feature.NewInMemoryResultCache(time.Minute), nil)
+
+	updated := theme.Theme{ThemeID: th.ThemeID, ThemeName: "Expenses", OwnerUserID: &userID, Fields: []theme.ThemeField{
+		{Name: "memo", Label: "Memo", Type: theme.FieldTypeText},
+		{Name: "amount", Label: "Amount", Type: theme.FieldTypeNumber},
+		{Name: "tax", Label: "Tax", Type: theme.FieldTypeNumber, Formula: "amount * 0.1"},
+	}}
+
+	// Retyping a field without a convert step is rejected before anything is saved
+	_, err := uc.UpdateTheme(context.Background(), userID, th.ThemeID, updated, []theme.MigrationStep{{Op: theme.MigrationRename, Field: "note", To: "memo"}})
+	var httpErr *echo.HTTPError
+	assert.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+	assert.Equal(t, 1, themes.themes[th.ThemeID].SchemaVersion)
+
+	result, err := uc.UpdateTheme(context.Background(), userID, th.ThemeID, updated, []theme.MigrationStep{
+		{Op: theme.MigrationRename, Field: "note", To: "memo"},
+		{Op: theme.MigrationConvert, Field: "amount"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.SchemaVersion)
+
+	migrated := entries.entries[stored.EntryID]
+	assert.Equal(t, 2, migrated.SchemaVersion)
+	assert.Equal(t, map[string]interface{}{"memo": "lunch", "amount": 1200.0, "tax": 120.0}, migrated.Data)
+	assert.NoError(t, migrated.ValidateDataAgainstTheme(result.Fields))
+}
+
+func TestGetEntryByID_MigratesOnRead(t *testing.T) {
+	userID := uuid.New()
+	th := &theme.Theme{ThemeID: uuid.New(), SchemaVersion: 2,
+		Fields:     []theme.ThemeField{{Name: "memo", Label: "Memo", Type: theme.FieldTypeText}},
+		Migrations: []theme.Migration{{Version: 2, Steps: []theme.MigrationStep{{Op: theme.MigrationRename, Field: "note", To: "memo"}}}},
+	}
+	stored := entry.Entry{EntryID: uuid.New(), UserID: userID, ThemeID: th.ThemeID, EntryDate: "2025-01-01", SchemaVersion: 1,
+		Data: map[string]interface{}{"note": "lunch"}}
+	entries := &memoryEntryRepo{entries: map[uuid.UUID]entry.Entry{stored.EntryID: stored}}
+	themes := &memoryThemeRepo{themes: map[uuid.UUID]*theme.Theme{th.ThemeID: th}}
+	uc := NewUseCase(themes, entries, nil, feature.NewInMemoryResultCache(time.Minute), nil)
+
+	e, err := uc.GetEntryByID(context.Background(), userID, stored.EntryID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, e.SchemaVersion)
+	assert.Equal(t, map[string]interface{}{"memo": "lunch"}, e.Data)
+	assert.Equal(t, "lunch", entries.entries[stored.EntryID].Data["note"], "reads do not write the migrated entry")
+}
+
+func TestUpdateTheme_MigrationSkipsEntriesDeletedDuringThePass(t *testing.T) {
+	userID := uuid.New()
+	th := &theme.Theme{ThemeID: uuid.New(), ThemeName: "Notes", OwnerUserID: &userID, SchemaVersion: 1,
+		Fields: []theme.ThemeField{{Name: "note", Label: "Note", Type: theme.FieldTypeText}}}
+	kept := entry.Entry{EntryID: uuid.New(), UserID: userID, ThemeID: th.ThemeID, EntryDate: "2025-01-01", SchemaVersion: 1,
+		Data: map[string]interface{}{"note": "kept"}}
+	deleted := entry.Entry{EntryID: uuid.New(), UserID: userID, ThemeID: th.ThemeID, EntryDate: "2025-01-02", SchemaVersion: 1,
+		Data: map[string]interface{}{"note": "deleted"}}
+	entries := &deletingEntryRepo{
+		memoryEntryRepo: &memoryEntryRepo{entries: map[uuid.UUID]entry.Entry{kept.EntryID: kept, deleted.EntryID: deleted}},
+		deleteID:        deleted.EntryID,
+	}
+	themes := &memoryThemeRepo{themes: map[uuid.UUID]*theme.Theme{th.ThemeID: th}}
+	uc := NewUseCase(themes, entries, nil, feature.NewInMemoryResultCache(time.Minute), nil)
+
+	updated := theme.Theme{ThemeID: th.ThemeID, ThemeName: "Notes", OwnerUserID: &userID,
+		Fields: []theme.ThemeField{{Name: "memo", Label: "Memo", Type: theme.FieldTypeText}}}
+	_, err := uc.UpdateTheme(context.Background(), userID, th.ThemeID, updated, []theme.MigrationStep{{Op: theme.MigrationRename, Field: "note", To: "memo"}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"memo": "kept"}, entries.entries[kept.EntryID].Data)
+	_, recreated := entries.entries[deleted.EntryID]
+	assert.False(t, recreated, "the deleted entry is not written back")
+}
@@ -0,0 +1,269 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/soranjiro/axicalendar/internal/domain"
+	"github.com/soranjiro/axicalendar/internal/domain/entry"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+	"github.com/soranjiro/axicalendar/internal/presentation/api"
+)
+
+// checkReferences verifies that every entry the reference fields of e point at exists,
+// belongs to the same user or to the shared theme the field points into, and matches the field's
+// target theme, then indexes the references for backlink queries. e must already be valid against fields.
+func (uc *UseCase) checkReferences(ctx context.Context, e *entry.Entry, fields []theme.ThemeField) error {
+	byName := make(map[string]theme.ThemeField, len(fields))
+	for _, f := range fields {
+		byName[f.Name] = f
+	}
+	themes := uc.newThemeLookup(e.UserID)
+	for _, ref := range e.References(fields) {
+		target, err := uc.findReferenceTarget(ctx, themes, e, byName[ref.Field], ref.EntryID)
+		if err != nil {
+			if errors.Is(err, domain.ErrEntryNotFound) {
+				return echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: fmt.Sprintf("Entry data validation failed: field '%s' references entry %s, which was not found", ref.Field, ref.EntryID)})
+			}
+			log.Printf("Error fetching entry %s referenced by field '%s': %v", ref.EntryID, ref.Field, err)
+			return echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to validate referenced entries"})
+		}
+		if err := e.ValidateReferenceTarget(byName[ref.Field], target); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: fmt.Sprintf("Entry data validation failed: %v", err)})
+		}
+	}
+	e.IndexReferences(fields)
+	return nil
+}
+
+// findReferenceTarget looks up an entry a reference field of e points at among the entries of the user
+// and, when the theme the field points into is shared with them, of its other members.
+func (uc *UseCase) findReferenceTarget(ctx context.Context, themes *themeLookup, e *entry.Entry, field theme.ThemeField, entryID uuid.UUID) (*entry.Entry, error) {
+	writers, err := themes.writers(ctx, e.UserID, e.ReferenceThemeID(field))
+	if err != nil {
+		return nil, err
+	}
+	for _, writer := range writers {
+		target, err := uc.entryRepo.GetEntryByID(ctx, writer, entryID)
+		if errors.Is(err, domain.ErrEntryNotFound) {
+			continue
+		}
+		return target, err
+	}
+	return nil, domain.ErrEntryNotFound
+}
+
+// checkReferenceTargetThemes verifies that the target theme of each reference field is accessible to the user.
+func (uc *UseCase) checkReferenceTargetThemes(ctx context.Context, userID uuid.UUID, fields []theme.ThemeField) error {
+	for _, f := range fields {
+		if f.TargetThemeID == nil {
+			continue
+		}
+		if _, err := uc.themeRepo.GetThemeByID(ctx, userID, *f.TargetThemeID); err != nil {
+			if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrThemeNotFound) || errors.Is(err, domain.ErrForbidden) {
+				return echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: fmt.Sprintf("Theme validation failed: field '%s': target theme %s not found or access denied", f.Name, *f.TargetThemeID)})
+			}
+			log.Printf("Error fetching target theme %s of field '%s': %v", *f.TargetThemeID, f.Name, err)
+			return echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to validate target themes"})
+		}
+	}
+	return nil
+}
+
+// themeLookup loads themes on demand and remembers them for the rest of a request.
+type themeLookup struct {
+	uc     *UseCase
+	userID uuid.UUID
+	themes map[uuid.UUID]*theme.Theme
+}
+
+func (uc *UseCase) newThemeLookup(userID uuid.UUID) *themeLookup {
+	return &themeLookup{uc: uc, userID: userID, themes: make(map[uuid.UUID]*theme.Theme)}
+}
+
+func (l *themeLookup) get(ctx context.Context, themeID uuid.UUID) (*theme.Theme, error) {
+	return l.getAs(ctx, l.userID, themeID)
+}
+
+// getAs loads a theme as another user, e.g. the writer of an entry found in a shared theme.
+// Only the definition is remembered, so callers must not rely on the returned role.
+func (l *themeLookup) getAs(ctx context.Context, userID, themeID uuid.UUID) (*theme.Theme, error) {
+	if th, ok := l.themes[themeID]; ok {
+		return th, nil
+	}
+	th, err := l.uc.themeRepo.GetThemeByID(ctx, userID, themeID)
+	if err != nil {
+		return nil, err
+	}
+	l.themes[themeID] = th
+	return th, nil
+}
+
+// writers returns the users whose entries of the theme the user works with (see themeWriters).
+// For themes the user cannot access, that is only the user.
+func (l *themeLookup) writers(ctx context.Context, userID, themeID uuid.UUID) ([]uuid.UUID, error) {
+	th, err := l.getAs(ctx, userID, themeID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrThemeNotFound) || errors.Is(err, domain.ErrForbidden) {
+			return []uuid.UUID{userID}, nil
+		}
+		return nil, err
+	}
+	return themeWriters(ctx, l.uc.themeRepo, userID, th)
+}
+
+// backlinksOf lists the entries referencing the entry, each with its referencing fields.
+// In a shared theme, these may have been written by any of its members.
+func (uc *UseCase) backlinksOf(ctx context.Context, themes *themeLookup, target entry.Entry) ([]entry.Backlink, error) {
+	writers, err := themes.writers(ctx, target.UserID, target.ThemeID)
+	if err != nil {
+		return nil, err
+	}
+	var referencing []entry.Entry
+	for _, writer := range writers {
+		written, err := uc.entryRepo.ListBacklinks(ctx, writer, target.EntryID)
+		if err != nil {
+			return nil, err
+		}
+		referencing = append(referencing, written...)
+	}
+	backlinks := make([]entry.Backlink, 0, len(referencing))
+	for _, e := range referencing {
+		th, err := themes.getAs(ctx, e.UserID, e.ThemeID)
+		if errors.Is(err, domain.ErrForbidden) {
+			continue // The writer has left the theme, and their entries no longer count
+		}
+		if err != nil {
+			return nil, fmt.Errorf("theme %s of referencing entry %s: %w", e.ThemeID, e.EntryID, err)
+		}
+		b := entry.Backlink{Entry: e}
+		for _, f := range e.ReferencesTo(th.Fields, target.EntryID) {
+			b.Fields = append(b.Fields, f.Name)
+		}
+		// The index may be stale if the theme changed; only entries still referencing count
+		if len(b.Fields) > 0 {
+			backlinks = append(backlinks, b)
+		}
+	}
+	return backlinks, nil
+}
+
+// entryDeletion is the set of changes deleting an entry implies, following the
+// on_delete action of every reference field that points at a deleted entry.
+type entryDeletion struct {
+	themes  *themeLookup                   // Themes of the changed entries, loaded while planning
+	deletes []entry.Entry                  // The requested entry first, then cascaded deletes in discovery order
+	clears  map[uuid.UUID]*entryNullifying // Entries whose reference fields are set to null
+	order   []uuid.UUID                    // Keys of clears in discovery order
+}
+
+// entryNullifying is an entry with the reference fields to set to null.
+type entryNullifying struct {
+	entry  entry.Entry
+	fields []theme.ThemeField // All fields of the entry's theme, to re-index its references
+	clear  []string
+}
+
+// planEntryDeletion walks the backlinks of the entry and of every entry deleted by cascade,
+// including those other members of a shared theme wrote.
+// It fails with 409 Conflict if any reference blocks the deletion, before anything is changed.
+func (uc *UseCase) planEntryDeletion(ctx context.Context, root entry.Entry) (*entryDeletion, error) {
+	themes := uc.newThemeLookup(root.UserID)
+	plan := &entryDeletion{themes: themes, deletes: []entry.Entry{root}, clears: make(map[uuid.UUID]*entryNullifying)}
+	deleted := map[uuid.UUID]bool{root.EntryID: true}
+
+	for i := 0; i < len(plan.deletes); i++ {
+		target := plan.deletes[i]
+		backlinks, err := uc.backlinksOf(ctx, themes, target)
+		if err != nil {
+			log.Printf("Error listing backlinks of entry %s: %v", target.EntryID, err)
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to check entries referencing the entry"})
+		}
+		for _, b := range backlinks {
+			if deleted[b.Entry.EntryID] {
+				continue
+			}
+			th, _ := themes.getAs(ctx, b.Entry.UserID, b.Entry.ThemeID) // Loaded by backlinksOf
+			for _, f := range b.Entry.ReferencesTo(th.Fields, target.EntryID) {
+				switch f.OnDeleteOrDefault() {
+				case theme.OnDeleteBlock:
+					return nil, echo.NewHTTPError(http.StatusConflict, api.Error{Message: fmt.Sprintf("Entry %s is referenced by field '%s' of entry %s, which blocks its deletion", target.EntryID, f.Name, b.Entry.EntryID)})
+				case theme.OnDeleteNullify:
+					n, ok := plan.clears[b.Entry.EntryID]
+					if !ok {
+						n = &entryNullifying{entry: b.Entry, fields: th.Fields}
+						plan.clears[b.Entry.EntryID] = n
+						plan.order = append(plan.order, b.Entry.EntryID)
+					}
+					n.clear = append(n.clear, f.Name)
+				case theme.OnDeleteCascade:
+					if !deleted[b.Entry.EntryID] {
+						deleted[b.Entry.EntryID] = true
+						plan.deletes = append(plan.deletes, b.Entry)
+					}
+				}
+			}
+		}
+	}
+	// Entries deleted by cascade need no clearing
+	for id := range deleted {
+		delete(plan.clears, id)
+	}
+	return plan, nil
+}
+
+// apply clears the nullified references, then deletes the cascaded entries and finally the requested one,
+// so a failure part way leaves the requested entry in place to retry.
+func (plan *entryDeletion) apply(ctx context.Context, uc *UseCase) error {
+	for _, id := range plan.order {
+		n, ok := plan.clears[id]
+		if !ok {
+			continue
+		}
+		e := n.entry
+		data := make(map[string]interface{}, len(e.Data))
+		for k, v := range e.Data {
+			data[k] = v
+		}
+		for _, name := range n.clear {
+			data[name] = nil
+		}
+		e.Data = data
+		e.IndexReferences(n.fields)
+		if err := uc.entryRepo.UpdateEntry(ctx, &e); err != nil {
+			log.Printf("Error clearing references of entry %s: %v", e.EntryID, err)
+			return echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to clear references to the entry"})
+		}
+		plan.invalidateFeatureResults(ctx, uc, e)
+	}
+	for i := len(plan.deletes) - 1; i >= 0; i-- {
+		e := plan.deletes[i]
+		if err := uc.entryRepo.DeleteEntry(ctx, e.UserID, e.EntryID, e.EntryDate); err != nil {
+			if errors.Is(err, domain.ErrEntryNotFound) && i > 0 {
+				continue // Already gone
+			}
+			if errors.Is(err, domain.ErrEntryNotFound) { // Should not happen if GetEntryByID succeeded, but check anyway
+				return echo.NewHTTPError(http.StatusNotFound, api.Error{Message: "Entry not found during delete attempt"})
+			}
+			log.Printf("Error deleting entry %s from repository: %v", e.EntryID, err)
+			return echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to delete entry"})
+		}
+		plan.invalidateFeatureResults(ctx, uc, e)
+	}
+	return nil
+}
+
+// invalidateFeatureResults drops the cached feature results covering a changed entry (see UseCase.invalidateFeatureResults).
+func (plan *entryDeletion) invalidateFeatureResults(ctx context.Context, uc *UseCase, e entry.Entry) {
+	th, err := plan.themes.getAs(ctx, e.UserID, e.ThemeID)
+	if err != nil {
+		log.Printf("WARN: Failed to load theme %s to invalidate cached feature results: %v", e.ThemeID, err)
+		return
+	}
+	uc.invalidateFeatureResults(ctx, e.UserID, th, e.EntryDate)
+}
@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	dynamodbrepo "github.com/soranjiro/axicalendar/internal/adapter/persistence/dynamodb"
+	"github.com/soranjiro/axicalendar/internal/domain"
+	"github.com/soranjiro/axicalendar/internal/domain/entry"
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+// memoryThemeRepo serves themes by ID and keeps their revisions, members and invitations.
+type memoryThemeRepo struct {
+	dynamodbrepo.ThemeRepository
+	themes      map[uuid.UUID]*theme.Theme
+	revisions   []theme.Revision
+	links       []theme.UserThemeLink
+	invitations []theme.Invitation
+}
+
+// GetThemeByID returns a copy of the theme with the user's role on it.
+// Themes without an owner are treated as the user's own.
+func (r *memoryThemeRepo) GetThemeByID(ctx context.Context, userID, themeID uuid.UUID) (*theme.Theme, error) {
+	th, ok := r.themes[themeID]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	found := *th
+	found.Role = found.OwnRole(userID)
+	if found.Role == "" && found.OwnerUserID == nil {
+		found.Role = theme.RoleOwner
+	}
+	for _, link := range r.links {
+		if found.Role == "" && link.UserID == userID && link.ThemeID == themeID {
+			found.Role = link.Role
+		}
+	}
+	if found.Role == "" {
+		return nil, domain.ErrForbidden
+	}
+	return &found, nil
+}
+
+// memoryEntryRepo keeps the entries of one user in memory.
+type memoryEntryRepo struct {
+	dynamodbrepo.EntryRepository
+	entries map[uuid.UUID]entry.Entry
+}
+
+func (r *memoryEntryRepo) GetEntryByID(ctx context.Context, userID, entryID uuid.UUID) (*entry.Entry, error) {
+	if e, ok := r.entries[entryID]; ok && e.UserID == userID {
+		return &e, nil
+	}
+	return nil, domain.ErrEntryNotFound
+}
+
+func (r *memoryEntryRepo) ListBacklinks(ctx context.Context, userID, entryID uuid.UUID) ([]entry.Entry, error) {
+	var backlinks []entry.Entry
+	for _, e := range r.entries {
+		for _, id := range e.ReferencedEntryIDs {
+			if id == entryID.String() && e.UserID == userID {
+				backlinks = append(backlinks, e)
+			}
+		}
+	}
+	return backlinks, nil
+}
+
+func (r *memoryEntryRepo) UpdateEntry(ctx context.Context, e *entry.Entry) error {
+	r.entries[e.EntryID] = *e
+	return nil
+}
+
+func (r *memoryEntryRepo) DeleteEntry(ctx context.Context, userID, entryID uuid.UUID, entryDate string) error {
+	delete(r.entries, entryID)
+	return nil
+}
+
+// referenceFixture is a book, a reading log entry referencing it and a review referencing the log.
+type referenceFixture struct {
+	uc                *UseCase
+	entries           *memoryEntryRepo
+	userID            uuid.UUID
+	book, log, review entry.Entry
+}
+
+func newReferenceFixture(logAction, reviewAction theme.DeleteAction) *referenceFixture {
+	userID := uuid.New()
+	books := &theme.Theme{ThemeID: uuid.New(), Fields: []theme.ThemeField{{Name: "title", Type: theme.FieldTypeText}}}
+	logs := &theme.Theme{ThemeID: uuid.New(), Fields: []theme.ThemeField{
+		{Name: "book", Type: theme.FieldTypeReference, TargetThemeID: &books.ThemeID, OnDelete: logAction},
+	}}
+	reviews := &theme.Theme{ThemeID: uuid.New(), Fields: []theme.ThemeField{
+		{Name: "log", Type: theme.FieldTypeReference, OnDelete: reviewAction},
+	}}
+
+	f := &referenceFixture{userID: userID}
+	f.book = entry.Entry{EntryID: uuid.New(), UserID: userID, ThemeID: books.ThemeID, EntryDate: "2025-01-01",
+		Data: map[string]interface{}{"title": "Dune"}}
+	f.log = entry.Entry{EntryID: uuid.New(), UserID: userID, ThemeID: logs.ThemeID, EntryDate: "2025-01-02",
+		Data: map[string]interface{}{"book": f.book.EntryID.String()}}
+	f.log.IndexReferences(logs.Fields)
+	f.review = entry.Entry{EntryID: uuid.New(), UserID: userID, ThemeID: reviews.ThemeID, EntryDate: "2025-01-03",
+		Data: map[string]interface{}{"log": f.log.EntryID.String()}}
+	f.review.IndexReferences(reviews.Fields)
+
+	f.entries = &memoryEntryRepo{entries: map[uuid.UUID]entry.Entry{
+		f.book.EntryID: f.book, f.log.EntryID: f.log, f.review.EntryID: f.review,
+	}}
+	themes := &memoryThemeRepo{themes: map[uuid.UUID]*theme.Theme{
+		books.ThemeID: books, logs.ThemeID: logs, reviews.ThemeID: reviews,
+	}}
+	f.uc = NewUseCase(themes, f.entries, nil, feature.NewInMemoryResultCache(time.Minute), nil)
+	return f
+}
+
+func TestDeleteEntry_Block(t *testing.T) {
+	f := newReferenceFixture("", theme.OnDeleteCascade) // The log's reference blocks by default
+
+	err := f.uc.DeleteEntry(context.Background(), f.userID, f.book.EntryID)
+
+	var httpErr *echo.HTTPError
+	assert.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusConflict, httpErr.Code)
+	assert.Len(t, f.entries.entries, 3, "nothing is deleted when a reference blocks")
+}
+
+func TestDeleteEntry_Nullify(t *testing.T) {
+	f := newReferenceFixture(theme.OnDeleteNullify, "")
+
+	assert.NoError(t, f.uc.DeleteEntry(context.Background(), f.userID, f.book.EntryID))
+
+	assert.NotContains(t, f.entries.entries, f.book.EntryID)
+	log := f.entries.entries[f.log.EntryID]
+	assert.Nil(t, log.Data["book"])
+	assert.Empty(t, log.ReferencedEntryIDs)
+	assert.Contains(t, f.entries.entries, f.review.EntryID)
+}
+
+func TestDeleteEntry_CascadeFollowsChains(t *testing.T) {
+	f := newReferenceFixture(theme.OnDeleteCascade, theme.OnDeleteCascade)
+
+	assert.NoError(t, f.uc.DeleteEntry(context.Background(), f.userID, f.book.EntryID))
+	assert.Empty(t, f.entries.entries)
+
+	// A blocking reference further down the chain blocks the whole deletion
+	f = newReferenceFixture(theme.OnDeleteCascade, theme.OnDeleteBlock)
+	assert.Error(t, f.uc.DeleteEntry(context.Background(), f.userID, f.book.EntryID))
+	assert.Len(t, f.entries.entries, 3)
+}
+
+func TestGetEntryBacklinks(t *testing.T) {
+	f := newReferenceFixture("", "")
+
+	backlinks, err := f.uc.GetEntryBacklinks(context.Background(), f.userID, f.book.EntryID)
+
+	assert.NoError(t, err)
+	assert.Len(t, backlinks, 1)
+	assert.Equal(t, f.log.EntryID, backlinks[0].Entry.EntryID)
+	assert.Equal(t, []string{"book"}, backlinks[0].Fields)
+}
+
+func TestCheckReferences(t *testing.T) {
+	f := newReferenceFixture("", "")
+	logs, _ := f.uc.themeRepo.GetThemeByID(context.Background(), f.userID, f.log.ThemeID)
+
+	// Only entries of the target theme can be referenced
+	e := entry.Entry{EntryID: uuid.New(), UserID: f.userID, Data: map[string]interface{}{"book": f.review.EntryID.String()}}
+	err := f.uc.checkReferences(context.Background(), &e, logs.Fields)
+	var httpErr *echo.HTTPError
+	assert.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+
+	// Entries of other users are not found
+	e = entry.Entry{EntryID: uuid.New(), UserID: uuid.New(), Data: map[string]interface{}{"book": f.book.EntryID.String()}}
+	assert.Error(t, f.uc.checkReferences(context.Background(), &e, logs.Fields))
+
+	e = entry.Entry{EntryID: uuid.New(), UserID: f.userID, Data: map[string]interface{}{"book": f.book.EntryID.String()}}
+	assert.NoError(t, f.uc.checkReferences(context.Background(), &e, logs.Fields))
+	assert.Equal(t, []string{f.book.EntryID.String()}, e.ReferencedEntryIDs)
+}
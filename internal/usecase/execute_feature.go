@@ -0,0 +1,202 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	dynamodbrepo "github.com/soranjiro/axicalendar/internal/adapter/persistence/dynamodb"
+	"github.com/soranjiro/axicalendar/internal/domain"
+	"github.com/soranjiro/axicalendar/internal/domain/entry"
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+	"github.com/soranjiro/axicalendar/internal/presentation/api"
+)
+
+// ExecuteFeature handles the logic for running a theme feature over the user's entries,
+// or, for a shared theme, the entries of all its members.
+// startDate and endDate are optional; when both are nil the current month is used.
+// Returns the feature's analysis result.
+func (uc *UseCase) ExecuteFeature(ctx context.Context, userID uuid.UUID, themeID uuid.UUID, featureName string, startDate, endDate *time.Time) (feature.AnalysisResult, error) {
+	// 1. Resolve the date window
+	now := time.Now()
+	window, err := resolveFeatureWindow(startDate, endDate, now)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: fmt.Sprintf("Invalid date range: %v", err)})
+	}
+
+	// 2. Get theme (includes access check: default, owned or shared)
+	th, err := uc.themeRepo.GetThemeByID(ctx, userID, themeID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrForbidden) {
+			return nil, echo.NewHTTPError(http.StatusNotFound, api.Error{Message: "Theme not found or access denied"})
+		}
+		log.Printf("Error retrieving theme %s for feature %s: %v", themeID, featureName, err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to retrieve theme"})
+	}
+
+	// 3. Check the feature is supported by the theme and get its configuration
+	supported, ok := th.Feature(featureName)
+	if !ok {
+		return nil, echo.NewHTTPError(http.StatusForbidden, api.Error{Message: fmt.Sprintf("Feature '%s' is not supported by this theme", featureName)})
+	}
+
+	// 4. Resolve the executor (normally guaranteed by the SupportedFeatures check)
+	executor, err := uc.featureRegistry.GetExecutor(featureName)
+	if err != nil {
+		log.Printf("ERROR: Theme %s supports feature '%s' but no executor is registered: %v", themeID, featureName, err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Feature is not available"})
+	}
+
+	// 5. Month-scoped features are summarised per calendar month
+	if _, monthScoped := executor.(feature.MonthScoped); monthScoped {
+		if !window.IsSingleMonth() {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: fmt.Sprintf("Feature '%s' requires a date range within a single month", featureName)})
+		}
+		window = feature.MonthWindow(window.Start)
+	}
+
+	// 6. Run the feature, reusing a cached result if entries in the window are unchanged
+	result, err := uc.runFeature(ctx, userID, th, supported, executor, window, now)
+	if err != nil {
+		if errors.Is(err, feature.ErrInvalidConfig) {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: fmt.Sprintf("Feature '%s' cannot run on this theme: %v", featureName, err)})
+		}
+		if errors.Is(err, feature.ErrResourceLimit) {
+			return nil, echo.NewHTTPError(http.StatusUnprocessableEntity, api.Error{Message: fmt.Sprintf("Feature '%s' was stopped: %v", featureName, err)})
+		}
+		if errors.Is(err, errFeatureEntries) {
+			log.Printf("Error fetching entries for feature %s (theme %s, user %s): %v", featureName, themeID, userID, err)
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to retrieve entries"})
+		}
+		log.Printf("Error executing feature %s for theme %s: %v", featureName, themeID, err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to execute feature"})
+	}
+
+	return result, nil
+}
+
+// errFeatureEntries is returned by runFeature when the entries of the window cannot be loaded.
+var errFeatureEntries = errors.New("failed to retrieve entries")
+
+// runFeature executes a theme's feature over window, on the entries of every member of a shared theme.
+// The cached result is returned if entries in the window are unchanged since it was computed; members
+// share the results (see featureResultsUser). Cache failures are logged and the feature is executed as
+// if the result was not cached.
+// Month-scoped executors must be given a whole-month window.
+func (uc *UseCase) runFeature(ctx context.Context, userID uuid.UUID, th *theme.Theme, supported theme.SupportedFeature, executor feature.FeatureExecutor, window feature.Window, now time.Time) (feature.AnalysisResult, error) {
+	// 1. Return the cached result if there is one
+	cacheKey := feature.CacheKey{
+		UserID:      featureResultsUser(userID, th),
+		ThemeID:     th.ThemeID,
+		FeatureName: supported.Name,
+		ConfigHash:  feature.HashConfig(supported.Config, th.UpdatedAt),
+		Window:      window,
+	}
+	// Results that depend on today's date are only reused on the same day
+	if _, ok := executor.(feature.DateDependent); ok {
+		cacheKey.ConfigHash += "@" + now.UTC().Format("2006-01-02")
+	}
+	if cached, ok, err := uc.featureCache.Get(ctx, cacheKey); err != nil {
+		log.Printf("WARN: Failed to read cached result of feature %s for theme %s: %v", supported.Name, th.ThemeID, err)
+	} else if ok {
+		return cached, nil
+	}
+
+	// 2. Fetch the entries for the window
+	writers, err := themeWriters(ctx, uc.themeRepo, userID, th)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errFeatureEntries, err)
+	}
+	entries, err := loadFeatureEntries(ctx, uc.entryRepo, executor, writers, th, window)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Execute the feature
+	result, err := executor.Execute(ctx, feature.Input{Theme: *th, Window: window, Entries: entries}, supported.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Cache the result
+	if err := uc.featureCache.Set(ctx, cacheKey, result); err != nil {
+		log.Printf("WARN: Failed to cache result of feature %s for theme %s: %v", supported.Name, th.ThemeID, err)
+	}
+
+	return result, nil
+}
+
+// loadFeatureEntries fetches the entries of a theme within window that any of writers wrote (see themeWriters)
+// for executor, migrated to the theme's fields. Month-scoped features use the summary query.
+func loadFeatureEntries(ctx context.Context, entryRepo dynamodbrepo.EntryRepository, executor feature.FeatureExecutor, writers []uuid.UUID, th *theme.Theme, window feature.Window) ([]entry.Entry, error) {
+	themeID := th.ThemeID
+	var (
+		entries []entry.Entry
+		err     error
+	)
+	if _, monthScoped := executor.(feature.MonthScoped); monthScoped {
+		for _, writer := range writers {
+			var written []entry.Entry
+			written, err = entryRepo.GetEntriesForSummary(ctx, writer, themeID, window.YearMonth())
+			if err != nil {
+				break
+			}
+			entries = append(entries, written...)
+		}
+		if len(writers) > 1 {
+			sort.SliceStable(entries, func(i, j int) bool { return entries[i].EntryDate < entries[j].EntryDate })
+		}
+	} else {
+		entries, err = listWritersEntries(ctx, entryRepo, writers, window.Start, window.End, themeID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errFeatureEntries, err)
+	}
+	for i := range entries {
+		migrateEntry(ctx, &entries[i], th)
+	}
+	return entries, nil
+}
+
+// invalidateFeatureResults drops cached feature results of the theme whose window contains any of dates,
+// after userID changed entries of it. Results of a shared theme are dropped for all its members.
+// Failures are logged only; the entry write has already succeeded.
+func (uc *UseCase) invalidateFeatureResults(ctx context.Context, userID uuid.UUID, th *theme.Theme, dates ...string) {
+	themeID := th.ThemeID
+	for _, date := range dates {
+		if err := uc.featureCache.Invalidate(ctx, featureResultsUser(userID, th), themeID, date); err != nil {
+			log.Printf("WARN: Failed to invalidate cached feature results for theme %s on %s: %v", themeID, date, err)
+		}
+	}
+}
+
+// invalidateThemeFeatureResults drops all cached feature results of a shared theme after its members changed:
+// the results are computed over the entries of every member, so they no longer hold once someone joins or leaves.
+// Failures are logged only; the membership change has already succeeded.
+func (uc *UseCase) invalidateThemeFeatureResults(ctx context.Context, th *theme.Theme) {
+	if th.IsDefault || th.OwnerUserID == nil {
+		return
+	}
+	if err := uc.featureCache.InvalidateTheme(ctx, *th.OwnerUserID, th.ThemeID); err != nil {
+		log.Printf("WARN: Failed to invalidate cached feature results for theme %s: %v", th.ThemeID, err)
+	}
+}
+
+// resolveFeatureWindow builds the execution window from the optional query dates.
+// Both dates must be given together; when neither is given the month containing now is used.
+func resolveFeatureWindow(startDate, endDate *time.Time, now time.Time) (feature.Window, error) {
+	if startDate == nil && endDate == nil {
+		return feature.MonthWindow(now), nil
+	}
+	if startDate == nil || endDate == nil {
+		return feature.Window{}, errors.New("start_date and end_date must be provided together")
+	}
+	return feature.NewWindow(*startDate, *endDate)
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/soranjiro/axicalendar/internal/domain"
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+	"github.com/soranjiro/axicalendar/internal/presentation/api"
+)
+
+// maxFeatureThemes bounds the number of themes a multi-theme feature runs over in one request.
+const maxFeatureThemes = 10
+
+// ExecuteMultiThemeFeature handles the logic for running a multi-theme feature over several of the user's themes.
+// Every theme must support the feature; startDate and endDate behave as in ExecuteFeature.
+// Shared themes contribute the entries of all their members.
+// Returns the feature's analysis result.
+func (uc *UseCase) ExecuteMultiThemeFeature(ctx context.Context, userID uuid.UUID, themeIDs []uuid.UUID, featureName string, startDate, endDate *time.Time) (feature.AnalysisResult, error) {
+	// 1. Resolve the date window and check the theme list
+	window, err := resolveFeatureWindow(startDate, endDate, time.Now())
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: fmt.Sprintf("Invalid date range: %v", err)})
+	}
+	if len(themeIDs) < 2 || len(themeIDs) > maxFeatureThemes {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: fmt.Sprintf("Between 2 and %d themes are required", maxFeatureThemes)})
+	}
+	seen := make(map[uuid.UUID]bool, len(themeIDs))
+	for _, id := range themeIDs {
+		if seen[id] {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: fmt.Sprintf("Theme %s is listed more than once", id)})
+		}
+		seen[id] = true
+	}
+
+	// 2. Resolve the executor and check it supports multiple themes
+	executor, err := uc.featureRegistry.GetExecutor(featureName)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, api.Error{Message: fmt.Sprintf("Feature '%s' not found", featureName)})
+	}
+	multi, ok := executor.(feature.MultiTheme)
+	if !ok {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: fmt.Sprintf("Feature '%s' does not support multiple themes", featureName)})
+	}
+
+	// 3. Load each theme, its feature config and its entries for the window
+	input := feature.MultiThemeInput{Window: window, Themes: make([]feature.ThemeInput, 0, len(themeIDs))}
+	for _, themeID := range themeIDs {
+		th, err := uc.themeRepo.GetThemeByID(ctx, userID, themeID)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrForbidden) {
+				return nil, echo.NewHTTPError(http.StatusNotFound, api.Error{Message: fmt.Sprintf("Theme %s not found or access denied", themeID)})
+			}
+			log.Printf("Error retrieving theme %s for feature %s: %v", themeID, featureName, err)
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to retrieve theme"})
+		}
+		supported, ok := th.Feature(featureName)
+		if !ok {
+			return nil, echo.NewHTTPError(http.StatusForbidden, api.Error{Message: fmt.Sprintf("Feature '%s' is not supported by theme %s", featureName, themeID)})
+		}
+		writers, err := themeWriters(ctx, uc.themeRepo, userID, th)
+		if err != nil {
+			log.Printf("Error listing members of theme %s for feature %s: %v", themeID, featureName, err)
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to retrieve entries"})
+		}
+		entries, err := listWritersEntries(ctx, uc.entryRepo, writers, window.Start, window.End, themeID)
+		if err != nil {
+			log.Printf("Error fetching entries for feature %s (theme %s, user %s): %v", featureName, themeID, userID, err)
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to retrieve entries"})
+		}
+		for i := range entries {
+			migrateEntry(ctx, &entries[i], th)
+		}
+		input.Themes = append(input.Themes, feature.ThemeInput{Theme: *th, Config: supported.Config, Entries: entries})
+	}
+
+	// 4. Execute the feature
+	result, err := multi.ExecuteMulti(ctx, input)
+	if err != nil {
+		if errors.Is(err, feature.ErrInvalidConfig) {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: fmt.Sprintf("Feature '%s' cannot run on these themes: %v", featureName, err)})
+		}
+		log.Printf("Error executing feature %s for themes %v: %v", featureName, themeIDs, err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to execute feature"})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	dynamodbrepo "github.com/soranjiro/axicalendar/internal/adapter/persistence/dynamodb"
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+// FeatureScheduler runs the features themes schedule (see theme.FeatureSchedule) and stores each
+// result as a feature.Snapshot. It is driven by calling RunDue periodically (see cmd/scheduler).
+// The latest run processed for each theme's feature is stored with the snapshots, so a restarted
+// scheduler catches up on the runs it missed (the latest maxCatchUpRuns of them) without repeating any.
+// Results are always computed from the stored entries; the feature result cache is not used.
+type FeatureScheduler struct {
+	themeRepo       dynamodbrepo.ThemeRepository
+	entryRepo       dynamodbrepo.EntryRepository
+	featureRegistry feature.ExecutorRegistry
+	snapshotRepo    feature.SnapshotRepository
+	now             func() time.Time
+	lastTick        time.Time // When RunDue last ran; schedules that never ran start from it
+}
+
+// maxCatchUpRuns caps the runs of one theme's schedule per RunDue call. After a long outage only the
+// latest missed firings are run, so a frequent schedule cannot hold up the others.
+const maxCatchUpRuns = 24
+
+// NewFeatureScheduler creates a new FeatureScheduler.
+// now is the scheduler's clock (normally time.Now); schedules that never ran before are run from
+// the minute before it.
+func NewFeatureScheduler(themeRepo dynamodbrepo.ThemeRepository, entryRepo dynamodbrepo.EntryRepository, featureRegistry feature.ExecutorRegistry, snapshotRepo feature.SnapshotRepository, now func() time.Time) *FeatureScheduler {
+	return &FeatureScheduler{
+		themeRepo:       themeRepo,
+		entryRepo:       entryRepo,
+		featureRegistry: featureRegistry,
+		snapshotRepo:    snapshotRepo,
+		now:             now,
+		lastTick:        now().Add(-time.Minute),
+	}
+}
+
+// RunDue runs every scheduled feature that was due since its latest recorded run, up to now.
+// A schedule that fired several times in that span runs once per firing, each over its own window,
+// for at most the latest maxCatchUpRuns firings; older ones are skipped.
+// Failed runs are logged and skipped; an error is only returned if the themes cannot be listed.
+// Returns the number of snapshots stored.
+func (s *FeatureScheduler) RunDue(ctx context.Context) (int, error) {
+	now := s.now().UTC()
+	since := s.lastTick
+
+	// 1. Load the themes that schedule features
+	themes, err := s.themeRepo.ListScheduledThemes(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list scheduled themes: %w", err)
+	}
+	s.lastTick = now
+
+	// 2. Run every schedule that fired after its latest recorded run, up to now
+	stored := 0
+	for i := range themes {
+		th := &themes[i]
+		if th.OwnerUserID == nil {
+			continue // Snapshots live in the owner's partition
+		}
+		for _, sf := range th.SupportedFeatures {
+			if sf.Schedule == nil {
+				continue
+			}
+			schedule, err := feature.ParseSchedule(sf.Schedule.Cron)
+			if err != nil {
+				log.Printf("WARN: Theme %s has an invalid schedule for feature %s: %v", th.ThemeID, sf.Name, err)
+				continue
+			}
+			from, err := s.snapshotRepo.LastScheduledRun(ctx, *th.OwnerUserID, th.ThemeID, sf.Name)
+			if err != nil {
+				log.Printf("ERROR: Failed to load the last scheduled run of feature %s for theme %s: %v", sf.Name, th.ThemeID, err)
+				continue
+			}
+			if from.IsZero() {
+				from = since
+			}
+			runs, skipped := dueRuns(schedule, from, now)
+			if skipped > 0 {
+				log.Printf("WARN: Skipping %d missed runs of feature %s for theme %s, before %s", skipped, sf.Name, th.ThemeID, runs[0].Format(time.RFC3339))
+			}
+			for _, at := range runs {
+				if err := ctx.Err(); err != nil {
+					return stored, err
+				}
+				if err := s.run(ctx, th, sf, at); err != nil {
+					log.Printf("ERROR: Scheduled run of feature %s for theme %s at %s failed: %v", sf.Name, th.ThemeID, at.Format(time.RFC3339), err)
+				} else {
+					stored++
+				}
+				// Recorded after every firing, so a scheduler stopped part way resumes after the last one
+				if err := s.snapshotRepo.SetLastScheduledRun(ctx, *th.OwnerUserID, th.ThemeID, sf.Name, at); err != nil {
+					log.Printf("ERROR: Failed to record the scheduled run of feature %s for theme %s at %s: %v", sf.Name, th.ThemeID, at.Format(time.RFC3339), err)
+					break
+				}
+			}
+		}
+	}
+	return stored, nil
+}
+
+// dueRuns returns the firings of schedule after from, up to now, oldest first. Only the latest
+// maxCatchUpRuns are returned; skipped is the number of older firings left out.
+func dueRuns(schedule feature.Schedule, from, now time.Time) (runs []time.Time, skipped int) {
+	for at := schedule.Next(from); !at.IsZero() && !at.After(now); at = schedule.Next(at) {
+		if len(runs) == maxCatchUpRuns {
+			runs = append(runs[:0], runs[1:]...)
+			skipped++
+		}
+		runs = append(runs, at)
+	}
+	return runs, skipped
+}
+
+// run executes a theme's feature over the window of the run at time at and stores the snapshot.
+func (s *FeatureScheduler) run(ctx context.Context, th *theme.Theme, sf theme.SupportedFeature, at time.Time) error {
+	executor, err := s.featureRegistry.GetExecutor(sf.Name)
+	if err != nil {
+		return err
+	}
+	window := feature.ScheduledWindow(sf.Schedule.PeriodOrDefault(), at)
+	if _, monthScoped := executor.(feature.MonthScoped); monthScoped {
+		window = feature.MonthWindow(window.Start)
+	}
+
+	writers, err := themeWriters(ctx, s.themeRepo, *th.OwnerUserID, th)
+	if err != nil {
+		return fmt.Errorf("failed to list theme members: %w", err)
+	}
+	entries, err := loadFeatureEntries(ctx, s.entryRepo, executor, writers, th, window)
+	if err != nil {
+		return err
+	}
+	result, err := executor.Execute(ctx, feature.Input{Theme: *th, Window: window, Entries: entries}, sf.Config)
+	if err != nil {
+		return fmt.Errorf("failed to execute feature: %w", err)
+	}
+
+	return s.snapshotRepo.SaveSnapshot(ctx, &feature.Snapshot{
+		UserID:      *th.OwnerUserID,
+		ThemeID:     th.ThemeID,
+		FeatureName: sf.Name,
+		Window:      window,
+		Result:      result,
+		CreatedAt:   s.now().UTC(),
+	})
+}
@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	dynamodbrepo "github.com/soranjiro/axicalendar/internal/adapter/persistence/dynamodb"
+	"github.com/soranjiro/axicalendar/internal/domain/entry"
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+// scheduledThemeRepo returns a fixed list of scheduled themes.
+type scheduledThemeRepo struct {
+	dynamodbrepo.ThemeRepository
+	themes []theme.Theme
+}
+
+func (r *scheduledThemeRepo) ListScheduledThemes(ctx context.Context) ([]theme.Theme, error) {
+	return r.themes, nil
+}
+
+func (r *scheduledThemeRepo) ListThemeMembers(ctx context.Context, themeID uuid.UUID) ([]theme.UserThemeLink, error) {
+	return nil, nil
+}
+
+// rangeEntryRepo records the ranges entries are requested for.
+type rangeEntryRepo struct {
+	dynamodbrepo.EntryRepository
+	months []string
+}
+
+func (r *rangeEntryRepo) GetEntriesForSummary(ctx context.Context, userID, themeID uuid.UUID, yearMonth string) ([]entry.Entry, error) {
+	r.months = append(r.months, yearMonth)
+	return []entry.Entry{}, nil
+}
+
+// countingExecutor is a month-scoped executor that returns the number of entries.
+type countingExecutor struct{}
+
+func (e *countingExecutor) Execute(ctx context.Context, input feature.Input, config theme.FeatureConfig) (feature.AnalysisResult, error) {
+	return feature.AnalysisResult{"count": len(input.Entries)}, nil
+}
+func (e *countingExecutor) Describe() feature.Feature { return feature.Feature{} }
+func (e *countingExecutor) MonthScoped()              {}
+
+// memorySnapshots keeps saved snapshots in memory.
+type memorySnapshots struct {
+	saved   []feature.Snapshot
+	lastRun map[string]time.Time
+}
+
+func (m *memorySnapshots) SaveSnapshot(ctx context.Context, s *feature.Snapshot) error {
+	m.saved = append(m.saved, *s)
+	return nil
+}
+
+func (m *memorySnapshots) ListSnapshots(ctx context.Context, userID, themeID uuid.UUID, featureName string) ([]feature.Snapshot, error) {
+	return m.saved, nil
+}
+
+func (m *memorySnapshots) LastScheduledRun(ctx context.Context, userID, themeID uuid.UUID, featureName string) (time.Time, error) {
+	return m.lastRun[themeID.String()+"#"+featureName], nil
+}
+
+func (m *memorySnapshots) SetLastScheduledRun(ctx context.Context, userID, themeID uuid.UUID, featureName string, at time.Time) error {
+	if m.lastRun == nil {
+		m.lastRun = map[string]time.Time{}
+	}
+	m.lastRun[themeID.String()+"#"+featureName] = at
+	return nil
+}
+
+func TestFeatureScheduler_RunDue(t *testing.T) {
+	owner := uuid.New()
+	themes := &scheduledThemeRepo{themes: []theme.Theme{{
+		ThemeID:     uuid.New(),
+		OwnerUserID: &owner,
+		SupportedFeatures: []theme.SupportedFeature{
+			{Name: "counting", Schedule: &theme.FeatureSchedule{Cron: "@monthly"}},
+			{Name: "counting_on_demand"},
+		},
+	}}}
+	entries := &rangeEntryRepo{}
+	registry := feature.NewInMemoryExecutorRegistry()
+	assert.NoError(t, registry.RegisterExecutor("counting", &countingExecutor{}))
+	snapshots := &memorySnapshots{}
+
+	clock := time.Date(2025, 5, 31, 23, 58, 0, 0, time.UTC)
+	scheduler := NewFeatureScheduler(themes, entries, registry, snapshots, func() time.Time { return clock })
+
+	// Nothing is due before the start of the month
+	stored, err := scheduler.RunDue(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, stored)
+
+	// The run at 2025-06-01 00:00 summarises May
+	clock = time.Date(2025, 6, 1, 0, 1, 0, 0, time.UTC)
+	stored, err = scheduler.RunDue(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, stored)
+	assert.Equal(t, []string{"2025-05"}, entries.months)
+	assert.Equal(t, owner, snapshots.saved[0].UserID)
+	assert.Equal(t, "2025-05-31", snapshots.saved[0].Window.End.Format("2006-01-02"))
+
+	// The same firing is not run twice
+	clock = clock.Add(time.Minute)
+	stored, err = scheduler.RunDue(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, stored)
+}
+
+func TestFeatureScheduler_RunDue_AfterRestart(t *testing.T) {
+	owner := uuid.New()
+	themes := &scheduledThemeRepo{themes: []theme.Theme{{
+		ThemeID:     uuid.New(),
+		OwnerUserID: &owner,
+		SupportedFeatures: []theme.SupportedFeature{
+			{Name: "counting", Schedule: &theme.FeatureSchedule{Cron: "@daily", Period: "day"}},
+		},
+	}}}
+	registry := feature.NewInMemoryExecutorRegistry()
+	assert.NoError(t, registry.RegisterExecutor("counting", &countingExecutor{}))
+	snapshots := &memorySnapshots{}
+
+	clock := time.Date(2025, 5, 31, 23, 59, 0, 0, time.UTC)
+	scheduler := NewFeatureScheduler(themes, &rangeEntryRepo{}, registry, snapshots, func() time.Time { return clock })
+	clock = time.Date(2025, 6, 1, 0, 1, 0, 0, time.UTC)
+	stored, err := scheduler.RunDue(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, stored)
+
+	// A scheduler started two days later runs the missed firings, but not the one already run
+	clock = time.Date(2025, 6, 3, 0, 30, 0, 0, time.UTC)
+	stored, err = NewFeatureScheduler(themes, &rangeEntryRepo{}, registry, snapshots, func() time.Time { return clock }).RunDue(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, stored)
+	assert.Len(t, snapshots.saved, 3)
+	last, err := snapshots.LastScheduledRun(context.Background(), owner, themes.themes[0].ThemeID, "counting")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 6, 3, 0, 0, 0, 0, time.UTC), last)
+}
+
+func TestFeatureScheduler_RunDue_CatchUpCap(t *testing.T) {
+	owner := uuid.New()
+	themes := &scheduledThemeRepo{themes: []theme.Theme{{
+		ThemeID:     uuid.New(),
+		OwnerUserID: &owner,
+		SupportedFeatures: []theme.SupportedFeature{
+			{Name: "counting", Schedule: &theme.FeatureSchedule{Cron: "@monthly"}},
+		},
+	}}}
+	entries := &rangeEntryRepo{}
+	registry := feature.NewInMemoryExecutorRegistry()
+	assert.NoError(t, registry.RegisterExecutor("counting", &countingExecutor{}))
+	snapshots := &memorySnapshots{}
+	assert.NoError(t, snapshots.SetLastScheduledRun(context.Background(), owner, themes.themes[0].ThemeID, "counting",
+		time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	// Of the 29 monthly firings missed since, only the latest are run
+	clock := time.Date(2025, 6, 1, 0, 30, 0, 0, time.UTC)
+	stored, err := NewFeatureScheduler(themes, entries, registry, snapshots, func() time.Time { return clock }).RunDue(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, maxCatchUpRuns, stored)
+	if assert.Len(t, entries.months, maxCatchUpRuns) {
+		assert.Equal(t, "2023-06", entries.months[0])
+		assert.Equal(t, "2025-05", entries.months[maxCatchUpRuns-1])
+	}
+	last, err := snapshots.LastScheduledRun(context.Background(), owner, themes.themes[0].ThemeID, "counting")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), last)
+}
@@ -2,19 +2,27 @@ package usecase
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	dynamodbrepo "github.com/soranjiro/axicalendar/internal/adapter/persistence/dynamodb"
 	"github.com/soranjiro/axicalendar/internal/domain/entry"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
 	"github.com/soranjiro/axicalendar/internal/presentation/api" // api.Errorのため
 )
 
 // GetEntries handles the logic for getting entries.
+// Entries of a shared theme are those of all its members, ordered by date.
+// Filters restrict the entries to those whose list fields hold the given items, or whose geo fields
+// lie within an area. The values of contains filters are the items' text form and are converted to
+// the fields' item types here.
 // Returns domain entries.
-func (uc *UseCase) GetEntries(ctx context.Context, userID uuid.UUID, themeID uuid.UUID, startDate time.Time, endDate time.Time) ([]entry.Entry, error) {
+func (uc *UseCase) GetEntries(ctx context.Context, userID uuid.UUID, themeID uuid.UUID, startDate time.Time, endDate time.Time, filters []entry.Filter) ([]entry.Entry, error) {
 	// Basic date validation
 	if startDate.IsZero() || endDate.IsZero() {
 		return nil, echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: "start_date and end_date cannot be zero"})
@@ -23,16 +31,84 @@ func (uc *UseCase) GetEntries(ctx context.Context, userID uuid.UUID, themeID uui
 		return nil, echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: "end_date cannot be before start_date"})
 	}
 
-	// Call repository with time.Time dates and themeID as a slice
-	entries, err := uc.entryRepo.ListEntriesByDateRange(ctx, userID, startDate, endDate, themeID)
+	// Every role can read the theme's entries
+	th, err := uc.getAccessibleTheme(ctx, userID, themeID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Type the filters against the theme's list and geo fields
+	if len(filters) > 0 {
+		typed, err := typeFilters(th.Fields, filters)
+		if err != nil {
+			return nil, err
+		}
+		filters = typed
+	}
+
+	// Entries are stored per user, so a shared theme's entries are read from each member
+	writers, err := themeWriters(ctx, uc.themeRepo, userID, th)
+	if err != nil {
+		log.Printf("Error listing members of theme %s: %v", themeID, err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to retrieve entries"})
+	}
+	entries, err := listWritersEntries(ctx, uc.entryRepo, writers, startDate, endDate, themeID, filters...)
 	if err != nil {
-		// Log the internal error if needed
-		// log.Printf("Error fetching entries from repository: %v", err)
-		// Return a generic error to the handler
 		log.Printf("Error fetching entries from repository: %v", err)
 		return nil, echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to retrieve entries"})
 	}
 
-	// Return domain models directly
+	// Entries not yet reached by a theme migration are migrated in memory
+	uc.migrateOnRead(ctx, userID, entries)
+	return entries, nil
+}
+
+// listWritersEntries lists the entries of a theme within the date range that any of writers wrote
+// (see themeWriters), ordered by date.
+func listWritersEntries(ctx context.Context, entryRepo dynamodbrepo.EntryRepository, writers []uuid.UUID, startDate, endDate time.Time, themeID uuid.UUID, filters ...entry.Filter) ([]entry.Entry, error) {
+	entries := []entry.Entry{}
+	for _, writer := range writers {
+		written, err := entryRepo.ListEntriesByDateRange(ctx, writer, startDate, endDate, themeID, filters...)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, written...)
+	}
+	if len(writers) > 1 {
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].EntryDate < entries[j].EntryDate })
+	}
 	return entries, nil
 }
+
+// typeFilters checks that each contains filter names a list field of the theme and parses its item,
+// and that each geo filter names a geo field.
+func typeFilters(themeFields []theme.ThemeField, filters []entry.Filter) ([]entry.Filter, error) {
+	fields := make(map[string]theme.ThemeField, len(themeFields))
+	for _, f := range themeFields {
+		fields[f.Name] = f
+	}
+
+	typed := make([]entry.Filter, len(filters))
+	for i, filter := range filters {
+		switch f := filter.(type) {
+		case entry.ContainsFilter:
+			field, ok := fields[f.Field]
+			if !ok || !field.Type.IsList() {
+				return nil, echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: fmt.Sprintf("contains: field '%s' is not a list field of the theme", f.Field)})
+			}
+			value, err := field.ParseItem(fmt.Sprint(f.Value))
+			if err != nil {
+				return nil, echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: fmt.Sprintf("contains: %v", err)})
+			}
+			typed[i] = entry.ContainsFilter{Field: f.Field, Value: value}
+		case entry.GeoFilter:
+			if field, ok := fields[f.Field]; !ok || field.Type != theme.FieldTypeGeo {
+				return nil, echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: fmt.Sprintf("field '%s' is not a geo field of the theme", f.Field)})
+			}
+			typed[i] = f
+		default:
+			return nil, echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: fmt.Sprintf("unsupported filter %T", filter)})
+		}
+	}
+	return typed, nil
+}
@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/soranjiro/axicalendar/internal/domain"
+	"github.com/soranjiro/axicalendar/internal/domain/entry"
+	"github.com/soranjiro/axicalendar/internal/presentation/api"
+)
+
+// GetEntryBacklinks handles the logic for listing the entries whose reference fields point at an entry.
+// Returns the referencing domain entries with the names of the referencing fields.
+func (uc *UseCase) GetEntryBacklinks(ctx context.Context, userID uuid.UUID, entryID uuid.UUID) ([]entry.Backlink, error) {
+	// 1. The entry itself must be accessible to the user (see GetEntryByID)
+	e, err := uc.findAccessibleEntry(ctx, userID, entryID)
+	if err != nil {
+		if errors.Is(err, domain.ErrEntryNotFound) {
+			return nil, echo.NewHTTPError(http.StatusNotFound, api.Error{Message: "Entry not found"})
+		}
+		log.Printf("Error fetching entry %s before listing backlinks: %v", entryID, err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to retrieve entry"})
+	}
+
+	// 2. Find the referencing entries and their referencing fields
+	backlinks, err := uc.backlinksOf(ctx, uc.newThemeLookup(userID), *e)
+	if err != nil {
+		log.Printf("Error listing backlinks of entry %s for user %s: %v", entryID, userID, err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to retrieve backlinks"})
+	}
+	return backlinks, nil
+}
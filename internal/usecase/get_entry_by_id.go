@@ -14,11 +14,12 @@ import (
 )
 
 // GetEntryByID handles the logic for getting a single entry by its ID.
+// The entry may have been written by another member of a theme shared with the user.
 // Returns a domain entry.
 func (uc *UseCase) GetEntryByID(ctx context.Context, userID uuid.UUID, entryID uuid.UUID) (*entry.Entry, error) {
-	e, err := uc.entryRepo.GetEntryByID(ctx, userID, entryID)
+	e, err := uc.findAccessibleEntry(ctx, userID, entryID)
 	if err != nil {
-		if errors.Is(err, domain.ErrNotFound) { // Use domain error
+		if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrEntryNotFound) { // Use domain error
 			return nil, echo.NewHTTPError(http.StatusNotFound, api.Error{Message: "Entry not found"})
 		}
 		// Log internal error if needed
@@ -26,6 +27,8 @@ func (uc *UseCase) GetEntryByID(ctx context.Context, userID uuid.UUID, entryID u
 		return nil, echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to retrieve entry"})
 	}
 
-	// Return domain model directly
-	return e, nil
+	// Entries not yet reached by a theme migration are migrated in memory
+	migrated := []entry.Entry{*e}
+	uc.migrateOnRead(ctx, userID, migrated)
+	return &migrated[0], nil
 }
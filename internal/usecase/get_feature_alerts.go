@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+)
+
+// GetFeatureAlerts runs the theme's alerting features (see feature.Alerting) over the month of entryDate.
+// It is called after every entry write so clients can show an alert badge.
+// Features that fail are logged and left out; an error is only returned if the theme cannot be loaded.
+func (uc *UseCase) GetFeatureAlerts(ctx context.Context, userID uuid.UUID, themeID uuid.UUID, entryDate string) ([]feature.Alert, error) {
+	// 1. Resolve the month of the entry
+	date, err := time.Parse("2006-01-02", entryDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid entry date '%s': %w", entryDate, err)
+	}
+	window := feature.MonthWindow(date)
+
+	// 2. Get theme (includes access check)
+	th, err := uc.themeRepo.GetThemeByID(ctx, userID, themeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve theme %s: %w", themeID, err)
+	}
+
+	// 3. Run every alerting feature the theme enables
+	// The entry write invalidated the cached results of the month, so these are fresh.
+	now := time.Now()
+	alerts := []feature.Alert{}
+	for _, supported := range th.SupportedFeatures {
+		executor, err := uc.featureRegistry.GetExecutor(supported.Name)
+		if err != nil {
+			continue
+		}
+		alerting, ok := executor.(feature.Alerting)
+		if !ok {
+			continue
+		}
+		result, err := uc.runFeature(ctx, userID, th, supported, alerting, window, now)
+		if err != nil {
+			log.Printf("WARN: Failed to run alerting feature %s for theme %s: %v", supported.Name, themeID, err)
+			continue
+		}
+		alerts = append(alerts, alerting.Alert(result))
+	}
+
+	return alerts, nil
+}
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+	"github.com/soranjiro/axicalendar/internal/presentation/api"
+)
+
+// InviteToTheme handles the logic for inviting a user to a custom theme with a role.
+// Only the owner can invite. The invitee gets access once they accept (see AcceptInvitation);
+// inviting a member again offers them the new role.
+func (uc *UseCase) InviteToTheme(ctx context.Context, userID uuid.UUID, themeID uuid.UUID, inviteeID uuid.UUID, role string) (*theme.Invitation, error) {
+	shareRole, err := theme.ParseShareRole(role)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: err.Error()})
+	}
+	if inviteeID == uuid.Nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: "Invalid user ID"})
+	}
+	if inviteeID == userID {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: "Cannot invite yourself to a theme"})
+	}
+
+	th, err := uc.getAccessibleTheme(ctx, userID, themeID)
+	if err != nil {
+		return nil, err
+	}
+	if th.IsDefault {
+		return nil, echo.NewHTTPError(http.StatusForbidden, api.Error{Message: "Default themes cannot be shared"})
+	}
+	if th.Role != theme.RoleOwner {
+		return nil, echo.NewHTTPError(http.StatusForbidden, api.Error{Message: "Only the owner can share the theme"})
+	}
+
+	invitation := &theme.Invitation{
+		ThemeID:   themeID,
+		ThemeName: th.ThemeName,
+		UserID:    inviteeID,
+		Role:      shareRole,
+		InvitedBy: userID,
+	}
+	if err := uc.themeRepo.PutThemeInvitation(ctx, invitation); err != nil {
+		log.Printf("Error inviting user %s to theme %s: %v", inviteeID, themeID, err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to create invitation"})
+	}
+	return invitation, nil
+}
@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/soranjiro/axicalendar/internal/domain"
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+	"github.com/soranjiro/axicalendar/internal/presentation/api"
+)
+
+// ListFeatureSnapshots handles the logic for listing the stored results of a theme's scheduled feature runs.
+// Snapshots are returned even if the feature is no longer scheduled, newest window first.
+func (uc *UseCase) ListFeatureSnapshots(ctx context.Context, userID uuid.UUID, themeID uuid.UUID, featureName string) ([]feature.Snapshot, error) {
+	// 1. Get theme (includes access check: default, owned or shared)
+	th, err := uc.themeRepo.GetThemeByID(ctx, userID, themeID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrForbidden) {
+			return nil, echo.NewHTTPError(http.StatusNotFound, api.Error{Message: "Theme not found or access denied"})
+		}
+		log.Printf("Error retrieving theme %s for snapshots of feature %s: %v", themeID, featureName, err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to retrieve theme"})
+	}
+
+	// 2. Fetch the snapshots; those of a shared theme are kept under its owner
+	snapshots, err := uc.snapshotRepo.ListSnapshots(ctx, featureResultsUser(userID, th), themeID, featureName)
+	if err != nil {
+		log.Printf("Error listing snapshots of feature %s for theme %s: %v", featureName, themeID, err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to retrieve feature snapshots"})
+	}
+	return snapshots, nil
+}
@@ -0,0 +1,13 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+)
+
+// ListFeatures handles the logic for listing the features that can be enabled on themes.
+// Returns the metadata of every registered feature executor.
+func (uc *UseCase) ListFeatures(ctx context.Context) ([]feature.Feature, error) {
+	return uc.featureRegistry.ListFeatures(), nil
+}
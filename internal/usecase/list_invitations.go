@@ -0,0 +1,22 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+	"github.com/soranjiro/axicalendar/internal/presentation/api"
+)
+
+// ListInvitations handles the logic for listing the user's pending theme invitations.
+func (uc *UseCase) ListInvitations(ctx context.Context, userID uuid.UUID) ([]theme.Invitation, error) {
+	invitations, err := uc.themeRepo.ListThemeInvitations(ctx, userID)
+	if err != nil {
+		log.Printf("Error listing invitations of user %s: %v", userID, err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to retrieve invitations"})
+	}
+	return invitations, nil
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	dynamodbrepo "github.com/soranjiro/axicalendar/internal/adapter/persistence/dynamodb"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+	"github.com/soranjiro/axicalendar/internal/presentation/api"
+)
+
+// ListThemeMembers handles the logic for listing the users a custom theme is shared with, owner first.
+// Every member can see the others.
+func (uc *UseCase) ListThemeMembers(ctx context.Context, userID uuid.UUID, themeID uuid.UUID) ([]theme.UserThemeLink, error) {
+	th, err := uc.getAccessibleTheme(ctx, userID, themeID)
+	if err != nil {
+		return nil, err
+	}
+	if th.IsDefault {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: "Default themes are not shared"})
+	}
+	members, err := themeMembers(ctx, uc.themeRepo, th)
+	if err != nil {
+		log.Printf("Error listing members of theme %s: %v", themeID, err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to retrieve theme members"})
+	}
+	return members, nil
+}
+
+// themeMembers returns the links of the users of a custom theme, owner first, and nil for default themes.
+// The owner's link is built from the theme, as owner links written before sharing existed are not listed
+// by the repository.
+func themeMembers(ctx context.Context, themeRepo dynamodbrepo.ThemeRepository, th *theme.Theme) ([]theme.UserThemeLink, error) {
+	if th.IsDefault || th.OwnerUserID == nil {
+		return nil, nil
+	}
+	links, err := themeRepo.ListThemeMembers(ctx, th.ThemeID)
+	if err != nil {
+		return nil, err
+	}
+	members := []theme.UserThemeLink{{UserID: *th.OwnerUserID, ThemeID: th.ThemeID, Role: theme.RoleOwner}}
+	for _, link := range links {
+		if link.UserID == *th.OwnerUserID || link.Role == "" {
+			continue
+		}
+		members = append(members, link)
+	}
+	return members, nil
+}
+
+// themeWriters returns the users whose entries make up the theme for the user: the user, then the other
+// members of a shared theme. Entries are stored per user, so a shared theme's are read from each of them.
+func themeWriters(ctx context.Context, themeRepo dynamodbrepo.ThemeRepository, userID uuid.UUID, th *theme.Theme) ([]uuid.UUID, error) {
+	members, err := themeMembers(ctx, themeRepo, th)
+	if err != nil {
+		return nil, err
+	}
+	writers := []uuid.UUID{userID}
+	for _, m := range members {
+		if m.UserID != userID {
+			writers = append(writers, m.UserID)
+		}
+	}
+	return writers, nil
+}
+
+// featureResultsUser returns the user whose partition holds the feature results and snapshots of the theme
+// for userID. Every member of a custom theme sees the same entries, so they share the results kept under
+// its owner; entries of default themes, and so their results, are each user's own.
+func featureResultsUser(userID uuid.UUID, th *theme.Theme) uuid.UUID {
+	if th.IsDefault || th.OwnerUserID == nil {
+		return userID
+	}
+	return *th.OwnerUserID
+}
@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/soranjiro/axicalendar/internal/domain"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+	"github.com/soranjiro/axicalendar/internal/presentation/api"
+)
+
+// ListThemeRevisions handles the logic for listing the revisions of a theme, newest first.
+// Themes not updated since revisions were introduced, and default themes, have none.
+func (uc *UseCase) ListThemeRevisions(ctx context.Context, userID uuid.UUID, themeID uuid.UUID) ([]theme.Revision, error) {
+	// 1. Get theme (includes access check: default, owned or shared)
+	if err := uc.checkThemeAccess(ctx, userID, themeID); err != nil {
+		return nil, err
+	}
+
+	// 2. Fetch the revisions
+	revisions, err := uc.themeRepo.ListThemeRevisions(ctx, themeID)
+	if err != nil {
+		log.Printf("Error listing revisions of theme %s: %v", themeID, err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to retrieve theme revisions"})
+	}
+	return revisions, nil
+}
+
+// getThemeRevision fetches one revision of a theme, with a 404 error if it does not exist.
+// Access to the theme must be checked first (see checkThemeAccess).
+func (uc *UseCase) getThemeRevision(ctx context.Context, themeID uuid.UUID, revision int) (*theme.Revision, error) {
+	rev, err := uc.themeRepo.GetThemeRevision(ctx, themeID, revision)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, echo.NewHTTPError(http.StatusNotFound, api.Error{Message: fmt.Sprintf("Theme revision %d not found", revision)})
+		}
+		log.Printf("Error retrieving revision %d of theme %s: %v", revision, themeID, err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to retrieve theme revision"})
+	}
+	return rev, nil
+}
@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+	"github.com/soranjiro/axicalendar/internal/presentation/api"
+)
+
+// RemoveThemeMember handles the logic for revoking a user's access to a shared theme.
+// The owner can remove any member and withdraw pending invitations; members can only leave.
+// The entries the member wrote stay theirs, but are no longer shown to the other members.
+func (uc *UseCase) RemoveThemeMember(ctx context.Context, userID uuid.UUID, themeID uuid.UUID, memberID uuid.UUID) error {
+	th, err := uc.getAccessibleTheme(ctx, userID, themeID)
+	if err != nil {
+		return err
+	}
+	if th.IsDefault {
+		return echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: "Default themes are not shared"})
+	}
+	if th.OwnerUserID != nil && memberID == *th.OwnerUserID {
+		return echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: "The owner cannot be removed from the theme"})
+	}
+	if th.Role != theme.RoleOwner && memberID != userID {
+		return echo.NewHTTPError(http.StatusForbidden, api.Error{Message: "Only the owner can remove other members"})
+	}
+
+	if err := uc.themeRepo.RemoveUserThemeLink(ctx, memberID, themeID); err != nil {
+		log.Printf("Error removing member %s from theme %s: %v", memberID, themeID, err)
+		return echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to remove theme member"})
+	}
+	// The member's entries no longer count towards the theme's features
+	uc.invalidateThemeFeatureResults(ctx, th)
+	if th.Role == theme.RoleOwner {
+		if err := uc.themeRepo.DeleteThemeInvitation(ctx, memberID, themeID); err != nil {
+			log.Printf("Error withdrawing invitation of user %s to theme %s: %v", memberID, themeID, err)
+			return echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to withdraw invitation"})
+		}
+	}
+	return nil // Success indicates no content (204)
+}
@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+	"github.com/soranjiro/axicalendar/internal/presentation/api"
+)
+
+// RestoreThemeRevision handles the logic for rolling a theme back to one of its revisions.
+// The revision's definition is saved as a new revision through the same path as UpdateTheme, so it is
+// validated against the current feature registry and themes, and migration must bring the entries
+// written since then back to the restored fields.
+// Returns the updated domain theme object.
+func (uc *UseCase) RestoreThemeRevision(ctx context.Context, userID uuid.UUID, themeID uuid.UUID, revision int, migration []theme.MigrationStep) (*theme.Theme, error) {
+	if themeID == uuid.Nil || userID == uuid.Nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: "Invalid theme ID or user ID"})
+	}
+	if err := uc.checkThemeAccess(ctx, userID, themeID); err != nil {
+		return nil, err
+	}
+	rev, err := uc.getThemeRevision(ctx, themeID, revision)
+	if err != nil {
+		return nil, err
+	}
+	return uc.updateTheme(ctx, userID, themeID, rev.Definition(userID), migration, rev.Revision)
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/soranjiro/axicalendar/internal/domain"
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+	"github.com/soranjiro/axicalendar/internal/domain/formula"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+// SeedDefaultThemes stores the built-in default themes (see theme.DefaultThemes) and brings stored ones
+// up to date with their definitions. It is idempotent: themes whose stored definition matches are left
+// untouched, so it can run at every startup. A changed definition is saved as the theme's next revision
+// after the same checks as a custom theme update; entries written with the previous fields are migrated
+// when read. A definition whose change is not covered by its migration steps is not saved.
+// Returns the number of themes created or updated.
+func (uc *UseCase) SeedDefaultThemes(ctx context.Context) (int, error) {
+	seeded := 0
+	var errs []error
+	for _, d := range theme.DefaultThemes() {
+		changed, err := uc.seedDefaultTheme(ctx, d)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("default theme %s (%s): %w", d.Definition.ThemeName, d.Definition.ThemeID, err))
+			continue
+		}
+		if changed {
+			seeded++
+		}
+	}
+	return seeded, errors.Join(errs...)
+}
+
+// seedDefaultTheme creates or updates one default theme, reporting whether it was written.
+func (uc *UseCase) seedDefaultTheme(ctx context.Context, d theme.DefaultTheme) (bool, error) {
+	def := d.Definition
+	if err := def.Validate(); err != nil {
+		return false, fmt.Errorf("invalid definition: %w", err)
+	}
+	if err := feature.ValidateSupportedFeatures(uc.featureRegistry, def); err != nil {
+		return false, fmt.Errorf("invalid definition: %w", err)
+	}
+	if _, err := formula.CompileComputedFields(def.Fields); err != nil {
+		return false, fmt.Errorf("invalid definition: %w", err)
+	}
+
+	// Default themes are readable by everyone, so the user ID does not matter here
+	existing, err := uc.themeRepo.GetThemeByID(ctx, uuid.Nil, def.ThemeID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		def.SchemaVersion = theme.InitialSchemaVersion
+		def.Revision = theme.InitialRevision
+	case errors.Is(err, domain.ErrForbidden):
+		return false, errors.New("the theme ID is taken by a custom theme")
+	case err != nil:
+		return false, fmt.Errorf("failed to get stored theme: %w", err)
+	case !existing.IsDefault:
+		return false, errors.New("the theme ID is taken by a custom theme")
+	default:
+		stored, wanted := existing.NewRevision(), def.NewRevision()
+		if diff := theme.DiffRevisions(&stored, &wanted); diff.IsEmpty() {
+			return false, nil
+		}
+		if err := def.PlanSchemaChange(existing, d.Migration); err != nil {
+			return false, fmt.Errorf("migration validation failed: %w", err)
+		}
+		def.CreatedAt = existing.CreatedAt
+		def.Revision = existing.Revision + 1
+		if existing.Revision < theme.InitialRevision {
+			// Seeded before revisions existed: record the stored state first, as updateTheme does
+			baseline := existing.NewRevision()
+			baseline.Revision = theme.InitialRevision
+			if err := uc.themeRepo.PutThemeRevision(ctx, &baseline); err != nil && !errors.Is(err, domain.ErrAlreadyExists) {
+				return false, fmt.Errorf("failed to record initial revision: %w", err)
+			}
+			def.Revision = theme.InitialRevision + 1
+		}
+	}
+
+	if err := uc.themeRepo.PutDefaultTheme(ctx, &def); err != nil {
+		if errors.Is(err, domain.ErrConflict) {
+			// Another instance seeded the theme concurrently; it writes the same definition
+			log.Printf("Default theme %s was seeded concurrently, skipping", def.ThemeID)
+			return false, nil
+		}
+		return false, err
+	}
+	log.Printf("Seeded default theme %s (%s) as revision %d, schema version %d", def.ThemeName, def.ThemeID, def.Revision, def.SchemaVersion)
+	return true, nil
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/soranjiro/axicalendar/internal/adapter/features"
+	"github.com/soranjiro/axicalendar/internal/domain"
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+func (r *memoryThemeRepo) PutDefaultTheme(ctx context.Context, th *theme.Theme) error {
+	stored, ok := r.themes[th.ThemeID]
+	if (th.Revision == theme.InitialRevision) == ok || (ok && stored.Revision != th.Revision-1) {
+		return domain.ErrConflict
+	}
+	if err := r.PutThemeRevision(ctx, &theme.Revision{ThemeID: th.ThemeID, Revision: th.Revision}); err != nil {
+		return domain.ErrConflict
+	}
+	r.revisions[len(r.revisions)-1] = th.NewRevision()
+	saved := *th
+	r.themes[th.ThemeID] = &saved
+	return nil
+}
+
+func TestSeedDefaultThemes(t *testing.T) {
+	registry := feature.NewInMemoryExecutorRegistry()
+	assert.NoError(t, features.RegisterBuiltins(registry))
+	themes := &memoryThemeRepo{themes: map[uuid.UUID]*theme.Theme{}}
+	uc := NewUseCase(themes, nil, registry, feature.NewInMemoryResultCache(time.Minute), nil)
+	ctx := context.Background()
+
+	seeded, err := uc.SeedDefaultThemes(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, len(theme.DefaultThemes()), seeded)
+	todo := themes.themes[theme.TodoThemeID]
+	if assert.NotNil(t, todo) {
+		assert.True(t, todo.IsDefault)
+		assert.Equal(t, theme.InitialRevision, todo.Revision)
+		assert.Equal(t, theme.InitialSchemaVersion, todo.SchemaVersion)
+	}
+
+	// Seeding again changes nothing
+	seeded, err = uc.SeedDefaultThemes(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, seeded)
+	assert.Len(t, themes.revisions, len(theme.DefaultThemes()))
+
+	// A stored definition that differs only in labels is updated in place
+	outdated := *todo
+	outdated.Fields = append([]theme.ThemeField{}, todo.Fields...)
+	outdated.Fields[0].Label = "Task"
+	themes.themes[theme.TodoThemeID] = &outdated
+	seeded, err = uc.SeedDefaultThemes(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, seeded)
+	assert.Equal(t, 2, themes.themes[theme.TodoThemeID].Revision)
+	assert.Equal(t, theme.InitialSchemaVersion, themes.themes[theme.TodoThemeID].SchemaVersion)
+	assert.Equal(t, "タスク名", themes.themes[theme.TodoThemeID].Fields[0].Label)
+
+	// A field removed without a migration step is not dropped from stored themes
+	extended := *themes.themes[theme.TodoThemeID]
+	extended.Fields = append(append([]theme.ThemeField{}, extended.Fields...), theme.ThemeField{Name: "assignee", Label: "Assignee", Type: theme.FieldTypeText})
+	themes.themes[theme.TodoThemeID] = &extended
+	seeded, err = uc.SeedDefaultThemes(ctx)
+	assert.ErrorContains(t, err, "field 'assignee' is removed")
+	assert.Equal(t, 0, seeded)
+	assert.Same(t, &extended, themes.themes[theme.TodoThemeID])
+}
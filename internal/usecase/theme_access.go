@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/soranjiro/axicalendar/internal/domain"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+	"github.com/soranjiro/axicalendar/internal/presentation/api"
+)
+
+// checkThemeAccess returns a 404 error unless the theme exists and is accessible to the user:
+// default, owned by the user, or shared with them.
+func (uc *UseCase) checkThemeAccess(ctx context.Context, userID uuid.UUID, themeID uuid.UUID) error {
+	_, err := uc.getAccessibleTheme(ctx, userID, themeID)
+	return err
+}
+
+// getAccessibleTheme fetches a theme with the user's role on it, with a 404 error unless it is accessible
+// to the user (see checkThemeAccess).
+func (uc *UseCase) getAccessibleTheme(ctx context.Context, userID uuid.UUID, themeID uuid.UUID) (*theme.Theme, error) {
+	th, err := uc.themeRepo.GetThemeByID(ctx, userID, themeID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrForbidden) {
+			return nil, echo.NewHTTPError(http.StatusNotFound, api.Error{Message: "Theme not found or access denied"})
+		}
+		log.Printf("Error retrieving theme %s for user %s: %v", themeID, userID, err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to retrieve theme"})
+	}
+	return th, nil
+}
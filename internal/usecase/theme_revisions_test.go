@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/soranjiro/axicalendar/internal/domain"
+	"github.com/soranjiro/axicalendar/internal/domain/entry"
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+	"github.com/soranjiro/axicalendar/internal/presentation/api"
+)
+
+func (r *memoryThemeRepo) PutThemeRevision(ctx context.Context, rev *theme.Revision) error {
+	if _, err := r.GetThemeRevision(ctx, rev.ThemeID, rev.Revision); err == nil {
+		return domain.ErrAlreadyExists
+	}
+	r.revisions = append(r.revisions, *rev)
+	return nil
+}
+
+func (r *memoryThemeRepo) GetThemeRevision(ctx context.Context, themeID uuid.UUID, revision int) (*theme.Revision, error) {
+	for _, rev := range r.revisions {
+		if rev.ThemeID == themeID && rev.Revision == revision {
+			return &rev, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func TestRestoreThemeRevision(t *testing.T) {
+	userID := uuid.New()
+	// A theme stored before revisions existed
+	th := &theme.Theme{ThemeID: uuid.New(), ThemeName: "Expenses", OwnerUserID: &userID, SchemaVersion: 1, Fields: []theme.ThemeField{
+		{Name: "note", Label: "Note", Type: theme.FieldTypeText},
+	}}
+	themes := &memoryThemeRepo{themes: map[uuid.UUID]*theme.Theme{th.ThemeID: th}}
+	entries := &memoryEntryRepo{entries: map[uuid.UUID]entry.Entry{}}
+	uc := NewUseCase(themes, entries, nil, feature.NewInMemoryResultCache(time.Minute), nil)
+	ctx := context.Background()
+
+	updated := theme.Theme{ThemeID: th.ThemeID, ThemeName: "Spending", OwnerUserID: &userID, Fields: []theme.ThemeField{
+		{Name: "note", Label: "Memo", Type: theme.FieldTypeText},
+		{Name: "amount", Label: "Amount", Type: theme.FieldTypeNumber},
+	}}
+	result, err := uc.UpdateTheme(ctx, userID, th.ThemeID, updated, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Revision, "the stored state is recorded as revision 1 first")
+	assert.Equal(t, userID, *result.UpdatedBy)
+
+	diff, err := uc.DiffThemeRevisions(ctx, userID, th.ThemeID, 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, &theme.NameChange{From: "Expenses", To: "Spending"}, diff.ThemeName)
+	if assert.Len(t, diff.FieldsChanged, 1) {
+		assert.Equal(t, []string{"label"}, diff.FieldsChanged[0].Attributes)
+	}
+	assert.Len(t, diff.FieldsAdded, 1)
+
+	// Revision 1 lacks the amount field, so restoring it drops the field's data
+	_, err = uc.RestoreThemeRevision(ctx, userID, th.ThemeID, 1, nil)
+	var httpErr *echo.HTTPError
+	assert.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+
+	restored, err := uc.RestoreThemeRevision(ctx, userID, th.ThemeID, 1, []theme.MigrationStep{{Op: theme.MigrationDrop, Field: "amount"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, restored.Revision)
+	assert.Equal(t, 1, restored.RestoredFrom)
+	assert.Equal(t, "Expenses", restored.ThemeName)
+	assert.Equal(t, "Note", restored.Fields[0].Label)
+	assert.Equal(t, 3, restored.SchemaVersion, "restoring is a schema change like any other")
+
+	rev, err := themes.GetThemeRevision(ctx, th.ThemeID, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, rev.RestoredFrom)
+
+	_, err = uc.RestoreThemeRevision(ctx, userID, th.ThemeID, 9, nil)
+	assert.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusNotFound, httpErr.Code)
+}
+
+func TestRestoreThemeRevision_Validates(t *testing.T) {
+	userID := uuid.New()
+	th := &theme.Theme{ThemeID: uuid.New(), ThemeName: "Reading", OwnerUserID: &userID, SchemaVersion: 1, Revision: 1,
+		Fields: []theme.ThemeField{{Name: "title", Label: "Title", Type: theme.FieldTypeText}}}
+	// A revision that does not pass validation (revisions written by earlier versions may not)
+	themes := &memoryThemeRepo{themes: map[uuid.UUID]*theme.Theme{th.ThemeID: th}, revisions: []theme.Revision{
+		{ThemeID: th.ThemeID, Revision: 1, ThemeName: "", Fields: th.Fields},
+	}}
+	uc := NewUseCase(themes, &memoryEntryRepo{entries: map[uuid.UUID]entry.Entry{}}, nil, feature.NewInMemoryResultCache(time.Minute), nil)
+
+	_, err := uc.RestoreThemeRevision(context.Background(), userID, th.ThemeID, 1, nil)
+
+	var httpErr *echo.HTTPError
+	assert.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+	assert.Contains(t, httpErr.Message.(api.Error).Message, "theme name is required")
+	assert.Equal(t, 1, themes.themes[th.ThemeID].Revision, "nothing is saved")
+}
@@ -0,0 +1,452 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/soranjiro/axicalendar/internal/domain"
+	"github.com/soranjiro/axicalendar/internal/domain/entry"
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+	"github.com/soranjiro/axicalendar/internal/domain/theme"
+)
+
+func (r *memoryThemeRepo) ListThemeMembers(ctx context.Context, themeID uuid.UUID) ([]theme.UserThemeLink, error) {
+	var members []theme.UserThemeLink
+	for _, link := range r.links {
+		if link.ThemeID == themeID {
+			members = append(members, link)
+		}
+	}
+	return members, nil
+}
+
+// ListUserThemes returns the user's links, with the owner link CreateTheme writes for each theme they own.
+func (r *memoryThemeRepo) ListUserThemes(ctx context.Context, userID uuid.UUID) ([]theme.UserThemeLink, error) {
+	var links []theme.UserThemeLink
+	for _, th := range r.themes {
+		if th.OwnerUserID != nil && *th.OwnerUserID == userID {
+			links = append(links, theme.UserThemeLink{UserID: userID, ThemeID: th.ThemeID, Role: theme.RoleOwner})
+		}
+	}
+	for _, link := range r.links {
+		if link.UserID == userID {
+			links = append(links, link)
+		}
+	}
+	return links, nil
+}
+
+func (r *memoryThemeRepo) RemoveUserThemeLink(ctx context.Context, userID, themeID uuid.UUID) error {
+	for i, link := range r.links {
+		if link.UserID == userID && link.ThemeID == themeID {
+			r.links = append(r.links[:i], r.links[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (r *memoryThemeRepo) PutThemeInvitation(ctx context.Context, invitation *theme.Invitation) error {
+	_ = r.DeleteThemeInvitation(ctx, invitation.UserID, invitation.ThemeID)
+	r.invitations = append(r.invitations, *invitation)
+	return nil
+}
+
+func (r *memoryThemeRepo) ListThemeInvitations(ctx context.Context, userID uuid.UUID) ([]theme.Invitation, error) {
+	var invitations []theme.Invitation
+	for _, inv := range r.invitations {
+		if inv.UserID == userID {
+			invitations = append(invitations, inv)
+		}
+	}
+	return invitations, nil
+}
+
+func (r *memoryThemeRepo) AcceptThemeInvitation(ctx context.Context, userID, themeID uuid.UUID) (*theme.UserThemeLink, error) {
+	for _, inv := range r.invitations {
+		if inv.UserID == userID && inv.ThemeID == themeID {
+			_ = r.DeleteThemeInvitation(ctx, userID, themeID)
+			_ = r.RemoveUserThemeLink(ctx, userID, themeID)
+			link := theme.UserThemeLink{UserID: userID, ThemeID: themeID, Role: inv.Role}
+			r.links = append(r.links, link)
+			return &link, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *memoryThemeRepo) DeleteThemeInvitation(ctx context.Context, userID, themeID uuid.UUID) error {
+	for i, inv := range r.invitations {
+		if inv.UserID == userID && inv.ThemeID == themeID {
+			r.invitations = append(r.invitations[:i], r.invitations[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (r *memoryEntryRepo) CreateEntry(ctx context.Context, e *entry.Entry) error {
+	r.entries[e.EntryID] = *e
+	return nil
+}
+
+func (r *memoryEntryRepo) ListEntriesByDateRange(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time, themeID uuid.UUID, filters ...entry.Filter) ([]entry.Entry, error) {
+	var entries []entry.Entry
+	for _, e := range r.entries {
+		if e.UserID == userID && e.ThemeID == themeID &&
+			e.EntryDate >= startDate.Format("2006-01-02") && e.EntryDate <= endDate.Format("2006-01-02") {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+// sharingFixture is a custom theme of owner, with nothing shared yet.
+type sharingFixture struct {
+	uc      *UseCase
+	themes  *memoryThemeRepo
+	entries *memoryEntryRepo
+	owner   uuid.UUID
+	th      *theme.Theme
+}
+
+func newSharingFixture() *sharingFixture {
+	owner := uuid.New()
+	th := &theme.Theme{ThemeID: uuid.New(), ThemeName: "Team", OwnerUserID: &owner, SchemaVersion: 1, Revision: 1,
+		Fields: []theme.ThemeField{{Name: "title", Label: "Title", Type: theme.FieldTypeText}}}
+	themes := &memoryThemeRepo{themes: map[uuid.UUID]*theme.Theme{th.ThemeID: th}}
+	entries := &memoryEntryRepo{entries: map[uuid.UUID]entry.Entry{}}
+	return &sharingFixture{
+		uc:      NewUseCase(themes, entries, nil, feature.NewInMemoryResultCache(time.Minute), nil),
+		themes:  themes,
+		entries: entries,
+		owner:   owner,
+		th:      th,
+	}
+}
+
+// join invites the user with the role and accepts the invitation.
+func (f *sharingFixture) join(t *testing.T, userID uuid.UUID, role theme.Role) {
+	ctx := context.Background()
+	_, err := f.uc.InviteToTheme(ctx, f.owner, f.th.ThemeID, userID, string(role))
+	assert.NoError(t, err)
+	_, err = f.uc.AcceptInvitation(ctx, userID, f.th.ThemeID)
+	assert.NoError(t, err)
+}
+
+func assertHTTPStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	var httpErr *echo.HTTPError
+	if assert.ErrorAs(t, err, &httpErr) {
+		assert.Equal(t, status, httpErr.Code)
+	}
+}
+
+func TestInviteAndAcceptInvitation(t *testing.T) {
+	f := newSharingFixture()
+	ctx := context.Background()
+	viewer := uuid.New()
+
+	// Only share roles can be granted, and only by the owner to someone else
+	_, err := f.uc.InviteToTheme(ctx, f.owner, f.th.ThemeID, viewer, "owner")
+	assertHTTPStatus(t, err, http.StatusBadRequest)
+	_, err = f.uc.InviteToTheme(ctx, f.owner, f.th.ThemeID, f.owner, "viewer")
+	assertHTTPStatus(t, err, http.StatusBadRequest)
+	_, err = f.uc.InviteToTheme(ctx, uuid.New(), f.th.ThemeID, viewer, "viewer")
+	assertHTTPStatus(t, err, http.StatusNotFound)
+
+	invitation, err := f.uc.InviteToTheme(ctx, f.owner, f.th.ThemeID, viewer, "viewer")
+	assert.NoError(t, err)
+	assert.Equal(t, "Team", invitation.ThemeName)
+
+	// The theme stays hidden until the invitation is accepted
+	_, err = f.uc.GetThemeByID(ctx, viewer, f.th.ThemeID)
+	assertHTTPStatus(t, err, http.StatusNotFound)
+	invitations, err := f.uc.ListInvitations(ctx, viewer)
+	assert.NoError(t, err)
+	assert.Len(t, invitations, 1)
+
+	th, err := f.uc.AcceptInvitation(ctx, viewer, f.th.ThemeID)
+	assert.NoError(t, err)
+	assert.Equal(t, theme.RoleViewer, th.Role)
+	invitations, _ = f.uc.ListInvitations(ctx, viewer)
+	assert.Empty(t, invitations)
+	_, err = f.uc.AcceptInvitation(ctx, viewer, f.th.ThemeID)
+	assertHTTPStatus(t, err, http.StatusNotFound)
+
+	// Viewers can neither share the theme nor write to it
+	_, err = f.uc.InviteToTheme(ctx, viewer, f.th.ThemeID, uuid.New(), "viewer")
+	assertHTTPStatus(t, err, http.StatusForbidden)
+	_, err = f.uc.CreateEntry(ctx, entry.Entry{UserID: viewer, ThemeID: f.th.ThemeID, EntryDate: "2025-03-01",
+		Data: map[string]interface{}{"title": "Kickoff"}})
+	assertHTTPStatus(t, err, http.StatusForbidden)
+	_, err = f.uc.UpdateTheme(ctx, viewer, f.th.ThemeID, theme.Theme{ThemeID: f.th.ThemeID, ThemeName: "Renamed", Fields: f.th.Fields}, nil)
+	assertHTTPStatus(t, err, http.StatusForbidden)
+
+	members, err := f.uc.ListThemeMembers(ctx, viewer, f.th.ThemeID)
+	assert.NoError(t, err)
+	assert.Equal(t, []theme.UserThemeLink{
+		{UserID: f.owner, ThemeID: f.th.ThemeID, Role: theme.RoleOwner},
+		{UserID: viewer, ThemeID: f.th.ThemeID, Role: theme.RoleViewer},
+	}, members)
+}
+
+func TestUpdateTheme_ByEditor(t *testing.T) {
+	f := newSharingFixture()
+	ctx := context.Background()
+	editor := uuid.New()
+	f.join(t, editor, theme.RoleEditor)
+	stored := entry.Entry{EntryID: uuid.New(), UserID: f.owner, ThemeID: f.th.ThemeID, EntryDate: "2025-03-01", SchemaVersion: 1,
+		Data: map[string]interface{}{"title": "Kickoff"}}
+	f.entries.entries[stored.EntryID] = stored
+
+	updated := theme.Theme{ThemeID: f.th.ThemeID, ThemeName: "Team", Fields: []theme.ThemeField{
+		{Name: "name", Label: "Name", Type: theme.FieldTypeText},
+	}}
+	result, err := f.uc.UpdateTheme(ctx, editor, f.th.ThemeID, updated, []theme.MigrationStep{{Op: theme.MigrationRename, Field: "title", To: "name"}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, f.owner, *result.OwnerUserID, "the owner is kept")
+	assert.Equal(t, editor, *result.UpdatedBy)
+	// The owner's entries are migrated too
+	assert.Equal(t, "Kickoff", f.entries.entries[stored.EntryID].Data["name"])
+}
+
+func TestGetEntries_SharedTheme(t *testing.T) {
+	f := newSharingFixture()
+	ctx := context.Background()
+	contributor, outsider := uuid.New(), uuid.New()
+	f.join(t, contributor, theme.RoleContributor)
+
+	own := entry.Entry{EntryID: uuid.New(), UserID: f.owner, ThemeID: f.th.ThemeID, EntryDate: "2025-03-02", SchemaVersion: 1,
+		Data: map[string]interface{}{"title": "Review"}}
+	f.entries.entries[own.EntryID] = own
+	added, err := f.uc.CreateEntry(ctx, entry.Entry{UserID: contributor, ThemeID: f.th.ThemeID, EntryDate: "2025-03-01",
+		Data: map[string]interface{}{"title": "Kickoff"}})
+	assert.NoError(t, err)
+
+	start, end := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+	for _, userID := range []uuid.UUID{f.owner, contributor} {
+		entries, err := f.uc.GetEntries(ctx, userID, f.th.ThemeID, start, end, nil)
+		assert.NoError(t, err)
+		if assert.Len(t, entries, 2) {
+			assert.Equal(t, added.EntryID, entries[0].EntryID, "entries of all members, by date")
+			assert.Equal(t, own.EntryID, entries[1].EntryID)
+		}
+	}
+	_, err = f.uc.GetEntries(ctx, outsider, f.th.ThemeID, start, end, nil)
+	assertHTTPStatus(t, err, http.StatusNotFound)
+}
+
+func TestRemoveThemeMember(t *testing.T) {
+	f := newSharingFixture()
+	ctx := context.Background()
+	viewer, editor, invitee := uuid.New(), uuid.New(), uuid.New()
+	f.join(t, viewer, theme.RoleViewer)
+	f.join(t, editor, theme.RoleEditor)
+	_, err := f.uc.InviteToTheme(ctx, f.owner, f.th.ThemeID, invitee, "contributor")
+	assert.NoError(t, err)
+
+	// Members other than the owner can only leave
+	assertHTTPStatus(t, f.uc.RemoveThemeMember(ctx, editor, f.th.ThemeID, viewer), http.StatusForbidden)
+	assertHTTPStatus(t, f.uc.RemoveThemeMember(ctx, editor, f.th.ThemeID, f.owner), http.StatusBadRequest)
+	assert.NoError(t, f.uc.RemoveThemeMember(ctx, viewer, f.th.ThemeID, viewer))
+	_, err = f.uc.GetThemeByID(ctx, viewer, f.th.ThemeID)
+	assertHTTPStatus(t, err, http.StatusNotFound)
+
+	// The owner removes members and withdraws invitations
+	assert.NoError(t, f.uc.RemoveThemeMember(ctx, f.owner, f.th.ThemeID, editor))
+	assert.NoError(t, f.uc.RemoveThemeMember(ctx, f.owner, f.th.ThemeID, invitee))
+	assert.Empty(t, f.themes.links)
+	assert.Empty(t, f.themes.invitations)
+}
+
+func TestDeleteEntry_SharedTheme(t *testing.T) {
+	f := newSharingFixture()
+	ctx := context.Background()
+	downgraded, removed := uuid.New(), uuid.New()
+	written := map[uuid.UUID]uuid.UUID{}
+	for _, userID := range []uuid.UUID{downgraded, removed} {
+		f.join(t, userID, theme.RoleContributor)
+		e, err := f.uc.CreateEntry(ctx, entry.Entry{UserID: userID, ThemeID: f.th.ThemeID, EntryDate: "2025-03-01",
+			Data: map[string]interface{}{"title": "Kickoff"}})
+		assert.NoError(t, err)
+		written[userID] = e.EntryID
+	}
+
+	// A member lowered to viewer keeps their entries but can no longer delete them
+	f.join(t, downgraded, theme.RoleViewer)
+	assertHTTPStatus(t, f.uc.DeleteEntry(ctx, downgraded, written[downgraded]), http.StatusForbidden)
+	assert.Contains(t, f.entries.entries, written[downgraded])
+
+	// Neither can a removed member
+	assert.NoError(t, f.uc.RemoveThemeMember(ctx, f.owner, f.th.ThemeID, removed))
+	assertHTTPStatus(t, f.uc.DeleteEntry(ctx, removed, written[removed]), http.StatusNotFound)
+	assert.Contains(t, f.entries.entries, written[removed])
+}
+
+func TestEntryByID_SharedTheme(t *testing.T) {
+	f := newSharingFixture()
+	ctx := context.Background()
+	editor, contributor, viewer, outsider := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	f.join(t, editor, theme.RoleEditor)
+	f.join(t, contributor, theme.RoleContributor)
+	f.join(t, viewer, theme.RoleViewer)
+	written, err := f.uc.CreateEntry(ctx, entry.Entry{UserID: contributor, ThemeID: f.th.ThemeID, EntryDate: "2025-03-01",
+		Data: map[string]interface{}{"title": "Kickoff"}})
+	assert.NoError(t, err)
+	owned, err := f.uc.CreateEntry(ctx, entry.Entry{UserID: f.owner, ThemeID: f.th.ThemeID, EntryDate: "2025-03-02",
+		Data: map[string]interface{}{"title": "Review"}})
+	assert.NoError(t, err)
+	// Entries of themes not shared with the others stay hidden
+	private := entry.Entry{EntryID: uuid.New(), UserID: contributor, ThemeID: uuid.New(), EntryDate: "2025-03-01", SchemaVersion: 1,
+		Data: map[string]interface{}{"title": "Private"}}
+	f.entries.entries[private.EntryID] = private
+
+	// Every member opens the entries other members wrote
+	for _, userID := range []uuid.UUID{f.owner, editor, viewer} {
+		e, err := f.uc.GetEntryByID(ctx, userID, written.EntryID)
+		assert.NoError(t, err)
+		assert.Equal(t, contributor, e.UserID)
+		_, err = f.uc.GetEntryByID(ctx, userID, private.EntryID)
+		assertHTTPStatus(t, err, http.StatusNotFound)
+	}
+	_, err = f.uc.GetEntryByID(ctx, outsider, written.EntryID)
+	assertHTTPStatus(t, err, http.StatusNotFound)
+
+	// Editors change them, and they stay the writer's; contributors and viewers cannot
+	change := entry.Entry{EntryDate: "2025-03-01", Data: map[string]interface{}{"title": "Kickoff, moved"}}
+	_, err = f.uc.UpdateEntry(ctx, contributor, owned.EntryID, change)
+	assertHTTPStatus(t, err, http.StatusForbidden)
+	_, err = f.uc.UpdateEntry(ctx, viewer, written.EntryID, change)
+	assertHTTPStatus(t, err, http.StatusForbidden)
+	updated, err := f.uc.UpdateEntry(ctx, editor, written.EntryID, change)
+	assert.NoError(t, err)
+	assert.Equal(t, contributor, updated.UserID)
+	assert.Equal(t, "Kickoff, moved", f.entries.entries[written.EntryID].Data["title"])
+
+	assertHTTPStatus(t, f.uc.DeleteEntry(ctx, contributor, owned.EntryID), http.StatusForbidden)
+	assert.NoError(t, f.uc.DeleteEntry(ctx, editor, written.EntryID))
+	assert.NoError(t, f.uc.DeleteEntry(ctx, f.owner, owned.EntryID))
+	assert.Equal(t, map[uuid.UUID]entry.Entry{private.EntryID: private}, f.entries.entries)
+}
+
+func TestEntryReferences_SharedTheme(t *testing.T) {
+	for _, action := range []theme.DeleteAction{theme.OnDeleteBlock, theme.OnDeleteCascade} {
+		f := newSharingFixture()
+		f.th.Fields = append(f.th.Fields, theme.ThemeField{Name: "follows", Label: "Follows", Type: theme.FieldTypeReference, OnDelete: action})
+		ctx := context.Background()
+		contributor := uuid.New()
+		f.join(t, contributor, theme.RoleContributor)
+
+		// Members can reference entries other members wrote in the theme
+		kickoff, err := f.uc.CreateEntry(ctx, entry.Entry{UserID: f.owner, ThemeID: f.th.ThemeID, EntryDate: "2025-03-01",
+			Data: map[string]interface{}{"title": "Kickoff"}})
+		assert.NoError(t, err)
+		review, err := f.uc.CreateEntry(ctx, entry.Entry{UserID: contributor, ThemeID: f.th.ThemeID, EntryDate: "2025-03-02",
+			Data: map[string]interface{}{"title": "Review", "follows": kickoff.EntryID.String()}})
+		assert.NoError(t, err)
+
+		backlinks, err := f.uc.GetEntryBacklinks(ctx, f.owner, kickoff.EntryID)
+		assert.NoError(t, err)
+		if assert.Len(t, backlinks, 1) {
+			assert.Equal(t, review.EntryID, backlinks[0].Entry.EntryID)
+		}
+
+		// The on_delete action of the other member's reference applies
+		err = f.uc.DeleteEntry(ctx, f.owner, kickoff.EntryID)
+		if action == theme.OnDeleteBlock {
+			assertHTTPStatus(t, err, http.StatusConflict)
+			assert.Len(t, f.entries.entries, 2)
+		} else {
+			assert.NoError(t, err)
+			assert.Empty(t, f.entries.entries)
+		}
+	}
+}
+
+// entryCountExecutor returns the number of entries it is given.
+type entryCountExecutor struct{}
+
+func (e *entryCountExecutor) Execute(ctx context.Context, input feature.Input, config theme.FeatureConfig) (feature.AnalysisResult, error) {
+	return feature.AnalysisResult{"count": len(input.Entries)}, nil
+}
+func (e *entryCountExecutor) Describe() feature.Feature { return feature.Feature{} }
+
+func TestExecuteFeature_SharedTheme(t *testing.T) {
+	f := newSharingFixture()
+	registry := feature.NewInMemoryExecutorRegistry()
+	assert.NoError(t, registry.RegisterExecutor("entry_count", &entryCountExecutor{}))
+	f.uc = NewUseCase(f.themes, f.entries, registry, feature.NewInMemoryResultCache(time.Minute), nil)
+	f.th.SupportedFeatures = []theme.SupportedFeature{{Name: "entry_count"}}
+	ctx := context.Background()
+	contributor, viewer := uuid.New(), uuid.New()
+	f.join(t, contributor, theme.RoleContributor)
+	f.join(t, viewer, theme.RoleViewer)
+
+	add := func(userID uuid.UUID, date string) {
+		_, err := f.uc.CreateEntry(ctx, entry.Entry{UserID: userID, ThemeID: f.th.ThemeID, EntryDate: date,
+			Data: map[string]interface{}{"title": "Standup"}})
+		assert.NoError(t, err)
+	}
+	count := func(userID uuid.UUID) interface{} {
+		start, end := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+		result, err := f.uc.ExecuteFeature(ctx, userID, f.th.ThemeID, "entry_count", &start, &end)
+		assert.NoError(t, err)
+		return result["count"]
+	}
+	add(f.owner, "2025-03-01")
+	add(contributor, "2025-03-02")
+
+	// Every member, including viewers, runs the feature over the entries of all members
+	assert.Equal(t, 2, count(f.owner))
+	assert.Equal(t, 2, count(viewer))
+
+	// A write by one member drops the result the others share
+	add(contributor, "2025-03-03")
+	assert.Equal(t, 3, count(f.owner))
+	assert.Equal(t, 3, count(viewer))
+}
+
+func TestExecuteFeature_SharedThemeMembershipChanges(t *testing.T) {
+	f := newSharingFixture()
+	registry := feature.NewInMemoryExecutorRegistry()
+	assert.NoError(t, registry.RegisterExecutor("entry_count", &entryCountExecutor{}))
+	f.uc = NewUseCase(f.themes, f.entries, registry, feature.NewInMemoryResultCache(time.Minute), nil)
+	f.th.SupportedFeatures = []theme.SupportedFeature{{Name: "entry_count"}}
+	ctx := context.Background()
+	member := uuid.New()
+
+	count := func() interface{} {
+		start, end := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+		result, err := f.uc.ExecuteFeature(ctx, f.owner, f.th.ThemeID, "entry_count", &start, &end)
+		assert.NoError(t, err)
+		return result["count"]
+	}
+	_, err := f.uc.CreateEntry(ctx, entry.Entry{UserID: f.owner, ThemeID: f.th.ThemeID, EntryDate: "2025-03-01",
+		Data: map[string]interface{}{"title": "Standup"}})
+	assert.NoError(t, err)
+	// The member's entry was written while they were in the theme before, e.g. before leaving it
+	kept := entry.Entry{EntryID: uuid.New(), UserID: member, ThemeID: f.th.ThemeID, EntryDate: "2025-03-02", SchemaVersion: 1,
+		Data: map[string]interface{}{"title": "Review"}}
+	f.entries.entries[kept.EntryID] = kept
+	assert.Equal(t, 1, count())
+
+	// Joining adds the member's entries to the cached result
+	f.join(t, member, theme.RoleContributor)
+	assert.Equal(t, 2, count())
+
+	// Leaving takes them out again, as does being removed by the owner
+	assert.NoError(t, f.uc.RemoveThemeMember(ctx, member, f.th.ThemeID, member))
+	assert.Equal(t, 1, count())
+	f.join(t, member, theme.RoleContributor)
+	assert.Equal(t, 2, count())
+	assert.NoError(t, f.uc.RemoveThemeMember(ctx, f.owner, f.th.ThemeID, member))
+	assert.Equal(t, 1, count())
+}
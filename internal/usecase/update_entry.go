@@ -16,10 +16,11 @@ import (
 )
 
 // UpdateEntry handles the logic for updating an entry.
+// Editors of a shared theme can also update the entries other members wrote; these stay the writer's.
 // Accepts IDs and domain entry, returns domain entry
 func (uc *UseCase) UpdateEntry(ctx context.Context, userID uuid.UUID, entryID uuid.UUID, updatedDomainEntry entry.Entry) (*entry.Entry, error) {
 	// 1. Get existing entry to find ThemeID and validate ownership/existence
-	existingEntry, err := uc.entryRepo.GetEntryByID(ctx, userID, entryID)
+	existingEntry, err := uc.findAccessibleEntry(ctx, userID, entryID)
 	if err != nil {
 		if errors.Is(err, domain.ErrEntryNotFound) {
 			return nil, echo.NewHTTPError(http.StatusNotFound, api.Error{Message: "Entry not found"})
@@ -44,23 +45,38 @@ func (uc *UseCase) UpdateEntry(ctx context.Context, userID uuid.UUID, entryID uu
 		log.Printf("Error validating theme %s for entry %s update: %v", existingEntry.ThemeID, entryID, err)
 		return nil, echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to validate associated theme"})
 	}
+	// Members whose role was lowered to viewer keep their entries, but can no longer change them
+	if !th.Role.CanAddEntries() {
+		return nil, echo.NewHTTPError(http.StatusForbidden, api.Error{Message: "Viewers cannot change entries of the theme"})
+	}
+	if !canChangeEntry(userID, th.Role, existingEntry) {
+		return nil, echo.NewHTTPError(http.StatusForbidden, api.Error{Message: "Only editors can change entries of other members"})
+	}
 
 	// 3. Prepare updated entry domain model (already have it as updatedDomainEntry)
 	// Ensure non-updatable fields are preserved from existingEntry
 	entryToUpdate := entry.Entry{
 		EntryID:   entryID,
 		ThemeID:   existingEntry.ThemeID, // Theme cannot be changed
-		UserID:    userID,                // UserID should match the authenticated user
+		UserID:    existingEntry.UserID,  // The entry stays the writer's
 		EntryDate: updatedDomainEntry.EntryDate,
 		Data:      updatedDomainEntry.Data,
 		CreatedAt: existingEntry.CreatedAt, // Preserve original creation time
 		// UpdatedAt, PK, SK handled by repository
 	}
 
-	// 4. Validate new data against theme fields using domain method
+	// 4. Recompute computed fields and validate new data against theme fields using domain method
+	if err := computeFields(ctx, &entryToUpdate, th.Fields); err != nil {
+		return nil, err
+	}
 	if err := entryToUpdate.ValidateDataAgainstTheme(th.Fields); err != nil {
 		return nil, echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: fmt.Sprintf("Entry data validation failed: %v", err)})
 	}
+	if err := uc.checkReferences(ctx, &entryToUpdate, th.Fields); err != nil {
+		return nil, err
+	}
+	entryToUpdate.IndexGeohashes(th.Fields)
+	entryToUpdate.SchemaVersion = th.SchemaVersion // The data is now written with the current fields
 
 	// 5. Call repository to update entry
 	err = uc.entryRepo.UpdateEntry(ctx, &entryToUpdate)
@@ -76,9 +92,11 @@ func (uc *UseCase) UpdateEntry(ctx context.Context, userID uuid.UUID, entryID uu
 		log.Printf("Error updating entry %s in repository: %v", entryID, err)
 		return nil, echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to update entry"})
 	}
+	// Results covering the old and the new date both change
+	uc.invalidateFeatureResults(ctx, userID, th, existingEntry.EntryDate, entryToUpdate.EntryDate)
 
 	// 6. Fetch the updated entry to return the full object with updated timestamp
-	finalEntry, err := uc.entryRepo.GetEntryByID(ctx, userID, entryID)
+	finalEntry, err := uc.entryRepo.GetEntryByID(ctx, existingEntry.UserID, entryID)
 	if err != nil {
 		// Log the inconsistency, but return the data we sent for update as approximation
 		log.Printf("WARN: Failed to fetch updated entry %s after successful update: %v", entryID, err)
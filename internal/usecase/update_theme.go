@@ -11,15 +11,26 @@ import (
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/soranjiro/axicalendar/internal/domain"
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
+	"github.com/soranjiro/axicalendar/internal/domain/formula"
 	"github.com/soranjiro/axicalendar/internal/domain/theme"
 	"github.com/soranjiro/axicalendar/internal/presentation/api"
 	// No longer need validation package here
 )
 
 // UpdateTheme handles the logic for updating an existing theme.
-// Accepts a domain theme object.
+// Accepts a domain theme object and the steps migrating existing entries to its fields.
+// The owner and members with the editor role can update a theme; the owner is kept.
+// If the fields change shape the schema version is bumped, and the theme's entries are then
+// migrated in batches (see migrateThemeEntries). Each update is recorded as a new revision.
 // Returns the updated domain theme object.
-func (uc *UseCase) UpdateTheme(ctx context.Context, userID uuid.UUID, themeID uuid.UUID, updatedThemeData theme.Theme) (*theme.Theme, error) {
+func (uc *UseCase) UpdateTheme(ctx context.Context, userID uuid.UUID, themeID uuid.UUID, updatedThemeData theme.Theme, migration []theme.MigrationStep) (*theme.Theme, error) {
+	return uc.updateTheme(ctx, userID, themeID, updatedThemeData, migration, 0)
+}
+
+// updateTheme validates and saves a theme definition as the theme's next revision.
+// restoredFrom is the revision the definition was restored from, or 0 for regular updates.
+func (uc *UseCase) updateTheme(ctx context.Context, userID uuid.UUID, themeID uuid.UUID, updatedThemeData theme.Theme, migration []theme.MigrationStep, restoredFrom int) (*theme.Theme, error) {
 	// 1. Basic ID checks
 	if themeID == uuid.Nil || userID == uuid.Nil {
 		return nil, echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: "Invalid theme ID or user ID"})
@@ -27,17 +38,25 @@ func (uc *UseCase) UpdateTheme(ctx context.Context, userID uuid.UUID, themeID uu
 	if themeID != updatedThemeData.ThemeID {
 		return nil, echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: "Theme ID mismatch between path and request body"})
 	}
-	if updatedThemeData.OwnerUserID == nil || *updatedThemeData.OwnerUserID != userID {
-		// Ensure the OwnerUserID in the provided data matches the authenticated user
-		return nil, echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: "Owner user ID mismatch"})
-	}
 
 	// 2. Validate the incoming domain theme object itself
 	if err := updatedThemeData.Validate(); err != nil {
 		return nil, echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: fmt.Sprintf("Theme data validation failed: %v", err)})
 	}
+	// Supported features must be registered and their field requirements met by the theme
+	if err := feature.ValidateSupportedFeatures(uc.featureRegistry, updatedThemeData); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: fmt.Sprintf("Theme data validation failed: %v", err)})
+	}
+	// Formulas of computed fields must type-check against the theme's other fields
+	if _, err := formula.CompileComputedFields(updatedThemeData.Fields); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: fmt.Sprintf("Theme data validation failed: %v", err)})
+	}
+	// Reference fields can only target themes the user can access
+	if err := uc.checkReferenceTargetThemes(ctx, userID, updatedThemeData.Fields); err != nil {
+		return nil, err
+	}
 
-	// 3. Check if theme exists, is owned by user, and is not default *before* attempting update
+	// 3. Check if theme exists, is editable by user, and is not default *before* attempting update
 	existingTheme, err := uc.themeRepo.GetThemeByID(ctx, userID, themeID)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrForbidden) {
@@ -49,29 +68,60 @@ func (uc *UseCase) UpdateTheme(ctx context.Context, userID uuid.UUID, themeID uu
 	if existingTheme.IsDefault {
 		return nil, echo.NewHTTPError(http.StatusForbidden, api.Error{Message: "Cannot modify a default theme"})
 	}
-	// Ownership is implicitly checked by GetThemeByID returning the theme for the given userID
+	// GetThemeByID returns the user's role on the theme: its own, or the one it was shared with
+	if !existingTheme.Role.CanEditTheme() {
+		return nil, echo.NewHTTPError(http.StatusForbidden, api.Error{Message: "Only the owner and editors can modify the theme"})
+	}
 
 	// 4. Prepare the theme object for the repository update
 	// Preserve fields that cannot be updated
-	updateInput := updatedThemeData                 // Copy the validated input data
-	updateInput.IsDefault = existingTheme.IsDefault // Ensure IsDefault is not changed
-	updateInput.CreatedAt = existingTheme.CreatedAt // Preserve original creation time
+	updateInput := updatedThemeData                     // Copy the validated input data
+	updateInput.IsDefault = existingTheme.IsDefault     // Ensure IsDefault is not changed
+	updateInput.OwnerUserID = existingTheme.OwnerUserID // Editors update themes they do not own
+	updateInput.CreatedAt = existingTheme.CreatedAt     // Preserve original creation time
 	// UpdatedAt will be set by the repository
+	if err := updateInput.PlanSchemaChange(existingTheme, migration); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, api.Error{Message: fmt.Sprintf("Theme migration validation failed: %v", err)})
+	}
+	// The update becomes the revision after the stored one. Themes stored before revisions existed
+	// first get their current state recorded as the initial revision.
+	currentRevision := existingTheme.Revision
+	if currentRevision < theme.InitialRevision {
+		baseline := existingTheme.NewRevision()
+		baseline.Revision = theme.InitialRevision
+		if err := uc.themeRepo.PutThemeRevision(ctx, &baseline); err != nil && !errors.Is(err, domain.ErrAlreadyExists) {
+			log.Printf("Error recording initial revision of theme %s: %v", themeID, err)
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to update theme"})
+		}
+		currentRevision = theme.InitialRevision
+	}
+	updateInput.Revision = currentRevision + 1
+	updateInput.UpdatedBy = &userID
+	updateInput.RestoredFrom = restoredFrom
 
 	// 5. Call repository to update theme
 	if err := uc.themeRepo.UpdateTheme(ctx, &updateInput); err != nil {
 		// The repository's UpdateTheme might return ErrForbidden or ErrNotFound
+		if errors.Is(err, domain.ErrConflict) {
+			return nil, echo.NewHTTPError(http.StatusConflict, api.Error{Message: "Theme was modified concurrently; reload it and try again"})
+		}
 		if errors.Is(err, domain.ErrForbidden) || errors.Is(err, domain.ErrNotFound) {
 			// This could happen if deleted/changed between Get and Update, or repo internal check failed
 			log.Printf("Forbidden/NotFound error during theme update %s: %v", themeID, err)
 			// Return NotFound as the theme is either gone or inaccessible for update
-			return nil, echo.NewHTTPError(http.StatusNotFound, api.Error{Message: "Failed to update theme: not found, is default, or not owned by user"})
+			return nil, echo.NewHTTPError(http.StatusNotFound, api.Error{Message: "Failed to update theme: not found, is default, or not editable by user"})
 		}
 		log.Printf("Error updating theme %s in repository: %v", themeID, err)
 		return nil, echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Failed to update theme"})
 	}
 
-	// 6. Fetch the updated theme to return the full object with updated timestamp
+	// 6. Bring the stored entries of every member up to the new schema version
+	if err := uc.migrateMemberEntries(ctx, &updateInput); err != nil {
+		log.Printf("Error migrating entries of theme %s to schema version %d: %v", themeID, updateInput.SchemaVersion, err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, api.Error{Message: "Theme was updated, but migrating its entries failed; entries are migrated when read, and repeating the update resumes the migration"})
+	}
+
+	// 7. Fetch the updated theme to return the full object with updated timestamp
 	finalTheme, err := uc.themeRepo.GetThemeByID(ctx, userID, themeID)
 	if err != nil {
 		// Log the inconsistency, but return the data we attempted to save
@@ -80,6 +130,6 @@ func (uc *UseCase) UpdateTheme(ctx context.Context, userID uuid.UUID, themeID uu
 		return &updateInput, nil
 	}
 
-	// 7. Return the fetched domain theme
+	// 8. Return the fetched domain theme
 	return finalTheme, nil
 }
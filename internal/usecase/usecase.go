@@ -2,19 +2,26 @@ package usecase
 
 import (
 	dynamodbrepo "github.com/soranjiro/axicalendar/internal/adapter/persistence/dynamodb"
+	"github.com/soranjiro/axicalendar/internal/domain/feature"
 )
 
 // UseCase implements the UseCaseInterface.
 type UseCase struct {
-	themeRepo dynamodbrepo.ThemeRepository
-	entryRepo dynamodbrepo.EntryRepository
+	themeRepo       dynamodbrepo.ThemeRepository
+	entryRepo       dynamodbrepo.EntryRepository
+	featureRegistry feature.ExecutorRegistry
+	featureCache    feature.ResultCache
+	snapshotRepo    feature.SnapshotRepository
 	// Add other repositories or services as needed
 }
 
 // NewUseCase creates a new UseCase with dependencies.
-func NewUseCase(themeRepo dynamodbrepo.ThemeRepository, entryRepo dynamodbrepo.EntryRepository) *UseCase {
+func NewUseCase(themeRepo dynamodbrepo.ThemeRepository, entryRepo dynamodbrepo.EntryRepository, featureRegistry feature.ExecutorRegistry, featureCache feature.ResultCache, snapshotRepo feature.SnapshotRepository) *UseCase {
 	return &UseCase{
-		themeRepo: themeRepo,
-		entryRepo: entryRepo,
+		themeRepo:       themeRepo,
+		entryRepo:       entryRepo,
+		featureRegistry: featureRegistry,
+		featureCache:    featureCache,
+		snapshotRepo:    snapshotRepo,
 	}
 }